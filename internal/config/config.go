@@ -1,8 +1,12 @@
 package config
 
 import (
+	"fmt"
+	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -11,24 +15,121 @@ import (
 type Config struct {
 	Server   ServerConfig   `json:"server"`
 	Database DatabaseConfig `json:"database"`
+	Log      LogConfig      `json:"log"`
+	Sync     SyncConfig     `json:"sync"`
+	API      APIConfig      `json:"api"`
+	// DefaultSink 创建 Alert 时未提供任何 Sink 配置时使用的默认投递目标
+	DefaultSink DefaultSinkConfig `json:"default_sink"`
+	// DefaultAlertStatus 创建 Alert 时未提供状态使用的默认状态（ENABLED 或 DISABLED）
+	DefaultAlertStatus string      `json:"default_alert_status"`
+	Admin              AdminConfig `json:"admin"`
+	// MigrateOnly 只执行数据库迁移后退出
+	MigrateOnly bool `json:"migrate_only"`
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
 	Port int    `json:"port"`
 	Mode string `json:"mode"`
+	// HTTP 超时，0 表示不限制；流式导出和同步接口不受写超时限制
+	ReadTimeout       time.Duration `json:"read_timeout"`
+	ReadHeaderTimeout time.Duration `json:"read_header_timeout"`
+	WriteTimeout      time.Duration `json:"write_timeout"`
+	IdleTimeout       time.Duration `json:"idle_timeout"`
+}
+
+// AdminConfig 管理接口配置
+type AdminConfig struct {
+	APIKey          string `json:"-"`                // 管理接口的 API Key，为空时禁用管理接口
+	MaintenanceMode bool   `json:"maintenance_mode"` // 启动时是否处于维护模式（只读）
 }
 
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
+	Driver       string `json:"driver"` // mysql、postgres 或 sqlite
 	Host         string `json:"host"`
 	Port         int    `json:"port"`
 	Username     string `json:"username"`
 	Password     string `json:"password"`
 	Database     string `json:"database"`
 	Charset      string `json:"charset"`
+	SSLMode      string `json:"ssl_mode"` // 仅 postgres 使用
 	MaxIdleConns int    `json:"max_idle_conns"`
 	MaxOpenConns int    `json:"max_open_conns"`
+	// 死锁重试配置
+	DeadlockMaxRetries     int `json:"deadlock_max_retries"`
+	DeadlockRetryBackoffMS int `json:"deadlock_retry_backoff_ms"`
+	// 启动时是否执行 AutoMigrate，以及 Schema 校验模式（off、warn 或 fail）
+	AutoMigrate bool   `json:"auto_migrate"`
+	SchemaCheck string `json:"schema_check"`
+}
+
+// LogConfig 日志配置
+type LogConfig struct {
+	Format string `json:"format"` // text 或 json
+	Level  string `json:"level"`  // debug、info、warn 或 error
+}
+
+// SyncConfig 同步配置
+type SyncConfig struct {
+	PreserveCreatedAt bool          `json:"preserve_created_at"` // 从 SLS 导入时使用 SLS 的创建时间作为 created_at
+	MaxDuration       time.Duration `json:"max_duration"`        // 单次同步的最长执行时间，0 表示不限制
+	DeepCompare       bool          `json:"deep_compare"`        // 判断是否需要更新时忽略 SLS 最后修改时间，始终比较完整配置
+	Concurrency       int           `json:"concurrency"`         // SLS 到数据库同步时并发处理 Alert 的 worker 数量
+	Interval          time.Duration `json:"interval"`            // 后台定时同步 SLS 到数据库的间隔，0 表示不启用
+	WebhookURL        string        `json:"-"`                   // 每次同步结束后 POST 结果的 Webhook 地址，为空表示不通知
+	WebhookTimeout    time.Duration `json:"webhook_timeout"`     // 单次 Webhook 请求的超时
+	PlanSecret        string        `json:"-"`                   // 同步计划 HMAC 签名的密钥，为空时使用进程内随机密钥
+}
+
+// APIConfig API 配置
+type APIConfig struct {
+	FieldCase string `json:"field_case"` // JSON 字段命名风格：snake 或 camel
+	// GzipMinSize 响应体达到该字节数时 gzip 压缩，小于 0 时不压缩
+	GzipMinSize int `json:"gzip_min_size"`
+}
+
+// DefaultSinkConfig 默认 Sink 配置，全部关闭时不做任何处理
+type DefaultSinkConfig struct {
+	Alerthub bool `json:"alerthub"`
+	Cms      bool `json:"cms"`
+}
+
+// Enabled 是否配置了任一默认 Sink
+func (c DefaultSinkConfig) Enabled() bool {
+	return c.Alerthub || c.Cms
+}
+
+// Validate 检查数据库必填配置：驱动必须是 mysql、postgres 或 sqlite；sqlite 只需要数据库文件路径，
+// 其他驱动需要主机、端口、库名和用户名；返回的错误列出全部缺失项
+func (c *DatabaseConfig) Validate() error {
+	var problems []string
+	switch c.Driver {
+	case "sqlite":
+		if strings.TrimSpace(c.Database) == "" {
+			problems = append(problems, "DB_DATABASE (sqlite database file) is required")
+		}
+	case "mysql", "postgres", "":
+		if strings.TrimSpace(c.Host) == "" {
+			problems = append(problems, "DB_HOST is required")
+		}
+		if c.Port <= 0 || c.Port > 65535 {
+			problems = append(problems, fmt.Sprintf("DB_PORT %d is not a valid port", c.Port))
+		}
+		if strings.TrimSpace(c.Database) == "" {
+			problems = append(problems, "DB_DATABASE is required")
+		}
+		if strings.TrimSpace(c.Username) == "" {
+			problems = append(problems, "DB_USERNAME is required")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("DB_DRIVER %q must be one of mysql, postgres, sqlite", c.Driver))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid database config: %s", strings.Join(problems, "; "))
+	}
+	return nil
 }
 
 // LoadConfig 从环境变量加载配置
@@ -41,21 +142,64 @@ func LoadConfig() *Config {
 		}
 	}
 
+	dbDriver := strings.ToLower(getEnv("DB_DRIVER", "mysql"))
+
 	config := &Config{
 		Server: ServerConfig{
 			Port: getEnvAsInt("SERVER_PORT", 8080),
 			Mode: getEnv("GIN_MODE", "debug"),
+
+			ReadTimeout:       getEnvAsDuration("HTTP_READ_TIMEOUT", 30*time.Second),
+			ReadHeaderTimeout: getEnvAsDuration("HTTP_READ_HEADER_TIMEOUT", 10*time.Second),
+			WriteTimeout:      getEnvAsDuration("HTTP_WRITE_TIMEOUT", 60*time.Second),
+			IdleTimeout:       getEnvAsDuration("HTTP_IDLE_TIMEOUT", 120*time.Second),
 		},
 		Database: DatabaseConfig{
+			Driver:       dbDriver,
 			Host:         getEnv("DB_HOST", "localhost"),
-			Port:         getEnvAsInt("DB_PORT", 3306),
+			Port:         getEnvAsInt("DB_PORT", defaultDBPort(dbDriver)),
 			Username:     getEnv("DB_USERNAME", "root"),
-			Password:     getEnv("DB_PASSWORD", ""),
-			Database:     getEnv("DB_DATABASE", "sls_migrate"),
+			Password:     getEnvOrFile("DB_PASSWORD", ""),
+			Database:     getEnv("DB_DATABASE", defaultDBName(dbDriver)),
 			Charset:      getEnv("DB_CHARSET", "utf8mb4"),
+			SSLMode:      getEnv("DB_SSLMODE", "disable"),
 			MaxIdleConns: getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
 			MaxOpenConns: getEnvAsInt("DB_MAX_OPEN_CONNS", 100),
+
+			DeadlockMaxRetries:     getEnvAsInt("DB_DEADLOCK_MAX_RETRIES", 3),
+			DeadlockRetryBackoffMS: getEnvAsInt("DB_DEADLOCK_RETRY_BACKOFF_MS", 50),
+
+			AutoMigrate: getEnvAsBool("DB_AUTO_MIGRATE", true),
+			SchemaCheck: getEnv("DB_SCHEMA_CHECK", "warn"),
+		},
+		Log: LogConfig{
+			Format: getEnv("LOG_FORMAT", "text"),
+			Level:  getEnv("LOG_LEVEL", "info"),
 		},
+		Sync: SyncConfig{
+			PreserveCreatedAt: getEnvAsBool("SYNC_PRESERVE_CREATED_AT", false),
+			MaxDuration:       getEnvAsDuration("SYNC_MAX_DURATION", 0),
+			DeepCompare:       getEnvAsBool("SYNC_DEEP_COMPARE", false),
+			Concurrency:       getEnvAsInt("SYNC_CONCURRENCY", 4),
+			Interval:          getEnvAsDuration("SYNC_INTERVAL", 0),
+			WebhookURL:        os.Getenv("SYNC_WEBHOOK_URL"),
+			WebhookTimeout:    getEnvAsDuration("SYNC_WEBHOOK_TIMEOUT", 5*time.Second),
+			PlanSecret:        getEnvOrFile("SYNC_PLAN_SECRET", ""),
+		},
+		API: APIConfig{
+			FieldCase:   getEnv("API_FIELD_CASE", "snake"),
+			GzipMinSize: getEnvAsInt("API_GZIP_MIN_SIZE", 1024),
+		},
+		DefaultSink: DefaultSinkConfig{
+			Alerthub: getEnvAsBool("DEFAULT_SINK_ALERTHUB", false),
+			Cms:      getEnvAsBool("DEFAULT_SINK_CMS", false),
+		},
+		DefaultAlertStatus: strings.ToUpper(getEnv("DEFAULT_ALERT_STATUS", "ENABLED")),
+		Admin: AdminConfig{
+			APIKey:          getEnvOrFile("ADMIN_API_KEY", ""),
+			MaintenanceMode: getEnvAsBool("MAINTENANCE_MODE", false),
+		},
+		MigrateOnly: getEnvAsBool("MIGRATE_ONLY", false),
 	}
 	return config
 }
@@ -68,6 +212,35 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// defaultDBPort 返回数据库驱动的默认端口
+func defaultDBPort(driver string) int {
+	if driver == "postgres" {
+		return 5432
+	}
+	return 3306
+}
+
+// defaultDBName 返回数据库驱动的默认库名，sqlite 为数据库文件路径
+func defaultDBName(driver string) string {
+	if driver == "sqlite" {
+		return "sls_migrate.db"
+	}
+	return "sls_migrate"
+}
+
+// getEnvOrFile 获取敏感配置：设置了 <key>_FILE 时从该文件读取（优先于 <key> 本身，去掉末尾换行），
+// 便于挂载 Docker/Kubernetes Secret；文件读取失败时记录警告并回退到环境变量
+func getEnvOrFile(key, defaultValue string) string {
+	if path := os.Getenv(key + "_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return strings.TrimRight(string(data), "\r\n")
+		}
+		log.Printf("Warning: failed to read %s_FILE %s, falling back to %s: %v", key, path, key, err)
+	}
+	return getEnv(key, defaultValue)
+}
+
 // getEnvAsInt 获取环境变量并转换为整数
 func getEnvAsInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
@@ -77,3 +250,23 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvAsBool 获取环境变量并转换为布尔值
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsDuration 获取环境变量并解析为时间间隔（如 30s、10m）
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeSecret 在临时目录中写入 Secret 文件并返回路径
+func writeSecret(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write secret: %v", err)
+	}
+	return path
+}
+
+func TestGetEnvOrFile(t *testing.T) {
+	tests := []struct {
+		name   string
+		inline string // 为空时不设置 <key>
+		file   string // 为空时不设置 <key>_FILE；"missing" 表示指向不存在的文件
+		want   string
+	}{
+		{name: "default", want: "default"},
+		{name: "inline", inline: "from-env", want: "from-env"},
+		{name: "file", file: "from-file\n", want: "from-file"},
+		{name: "file takes precedence", inline: "from-env", file: "from-file\r\n", want: "from-file"},
+		{name: "inner whitespace kept", file: " p@ss word \n", want: " p@ss word "},
+		{name: "unreadable file falls back", inline: "from-env", file: "missing", want: "from-env"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_SECRET", tt.inline)
+			switch tt.file {
+			case "":
+				t.Setenv("TEST_SECRET_FILE", "")
+			case "missing":
+				t.Setenv("TEST_SECRET_FILE", filepath.Join(t.TempDir(), "missing"))
+			default:
+				t.Setenv("TEST_SECRET_FILE", writeSecret(t, tt.file))
+			}
+
+			if got := getEnvOrFile("TEST_SECRET", "default"); got != tt.want {
+				t.Errorf("getEnvOrFile = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigReadsSecretFiles(t *testing.T) {
+	t.Setenv("DB_PASSWORD", "inline-db")
+	t.Setenv("DB_PASSWORD_FILE", writeSecret(t, "file-db\n"))
+	t.Setenv("SLS_ACCESS_KEY_SECRET", "inline-sk")
+	t.Setenv("SLS_ACCESS_KEY_SECRET_FILE", writeSecret(t, "file-sk\n"))
+
+	if got := LoadConfig().Database.Password; got != "file-db" {
+		t.Errorf("database password = %q, want file-db", got)
+	}
+	if got := LoadSLSConfig().AccessKeySecret; got != "file-sk" {
+		t.Errorf("SLS access key secret = %q, want file-sk", got)
+	}
+}
+
+func TestLoadConfigDefaultAlertStatus(t *testing.T) {
+	tests := []struct {
+		env  string
+		want string
+	}{
+		{env: "", want: "ENABLED"},
+		{env: "disabled", want: "DISABLED"},
+		{env: "ENABLED", want: "ENABLED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv("DEFAULT_ALERT_STATUS", tt.env)
+			if got := LoadConfig().DefaultAlertStatus; got != tt.want {
+				t.Errorf("DefaultAlertStatus = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigDeadlockRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		retries     string
+		backoff     string
+		wantRetries int
+		wantBackoff int
+	}{
+		{name: "defaults", wantRetries: 3, wantBackoff: 50},
+		{name: "configured", retries: "5", backoff: "200", wantRetries: 5, wantBackoff: 200},
+		{name: "disabled", retries: "0", backoff: "0", wantRetries: 0, wantBackoff: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DB_DEADLOCK_MAX_RETRIES", tt.retries)
+			t.Setenv("DB_DEADLOCK_RETRY_BACKOFF_MS", tt.backoff)
+
+			database := LoadConfig().Database
+			if database.DeadlockMaxRetries != tt.wantRetries || database.DeadlockRetryBackoffMS != tt.wantBackoff {
+				t.Errorf("retries = %d, backoff = %dms, want %d and %dms",
+					database.DeadlockMaxRetries, database.DeadlockRetryBackoffMS, tt.wantRetries, tt.wantBackoff)
+			}
+		})
+	}
+}
@@ -1,40 +1,121 @@
 package config
 
 import (
+	"fmt"
+	"strings"
+
 	openapi "github.com/alibabacloud-go/darabonba-openapi/v2/client"
 	"github.com/alibabacloud-go/tea/tea"
 	credential "github.com/aliyun/credentials-go/credentials"
 )
 
+// SLS 凭据类型
+const (
+	CredentialTypeAccessKey  = "access_key"   // 固定的 AccessKey
+	CredentialTypeSTS        = "sts"          // AccessKey 加 STS 临时安全令牌
+	CredentialTypeECSRAMRole = "ecs_ram_role" // 从 ECS 实例元数据获取 RAM 角色的临时凭据
+)
+
 // SLSConfig SLS 配置
 type SLSConfig struct {
 	Endpoint        string `json:"endpoint"`
+	CredentialType  string `json:"credential_type"` // access_key、sts 或 ecs_ram_role
 	AccessKeyID     string `json:"access_key_id"`
 	AccessKeySecret string `json:"access_key_secret"`
+	SecurityToken   string `json:"security_token"` // sts 类型的安全令牌
+	RoleName        string `json:"role_name"`      // ecs_ram_role 类型的角色名，为空时从实例元数据获取
 	Project         string `json:"project"`
 	LogStore        string `json:"log_store"`
+	// HealthCheckInterval SLS 连通性探测间隔（秒）
+	HealthCheckInterval int `json:"health_check_interval"`
+	// 限流或服务不可用时的最大重试次数，以及指数退避的基数（毫秒）
+	MaxRetries     int `json:"max_retries"`
+	RetryBackoffMS int `json:"retry_backoff_ms"`
+	// 单次 SLS SDK 调用的读超时和连接超时（毫秒），0 使用 SDK 默认值
+	ReadTimeoutMS    int `json:"read_timeout_ms"`
+	ConnectTimeoutMS int `json:"connect_timeout_ms"`
+	// DeduplicateTags 从 SLS 导入时按 (类型, 键) 合并重复的标签，保留最后一个值
+	DeduplicateTags bool `json:"deduplicate_tags"`
+	// PreserveRawConfig 导入时保存 SLS 返回的原始 configuration JSON，推送时以它为基线保留 SDK 未表示的字段
+	PreserveRawConfig bool `json:"preserve_raw_config"`
 }
 
 // LoadSLSConfig 从环境变量加载 SLS 配置
 func LoadSLSConfig() *SLSConfig {
 	return &SLSConfig{
 		Endpoint:        getEnv("SLS_ENDPOINT", "cn-qingdao.log.aliyuncs.com"),
-		AccessKeyID:     getEnv("SLS_ACCESS_KEY_ID", ""),
-		AccessKeySecret: getEnv("SLS_ACCESS_KEY_SECRET", ""),
+		CredentialType:  getEnv("SLS_CREDENTIAL_TYPE", CredentialTypeAccessKey),
+		AccessKeyID:     getEnvOrFile("SLS_ACCESS_KEY_ID", ""),
+		AccessKeySecret: getEnvOrFile("SLS_ACCESS_KEY_SECRET", ""),
+		SecurityToken:   getEnvOrFile("SLS_SECURITY_TOKEN", ""),
+		RoleName:        getEnv("SLS_ROLE_NAME", ""),
 		Project:         getEnv("SLS_PROJECT", ""),
 		LogStore:        getEnv("SLS_LOG_STORE", ""),
+
+		HealthCheckInterval: getEnvAsInt("SLS_HEALTH_CHECK_INTERVAL", 30),
+		MaxRetries:          getEnvAsInt("SLS_MAX_RETRIES", 3),
+		RetryBackoffMS:      getEnvAsInt("SLS_RETRY_BACKOFF_MS", 200),
+		ReadTimeoutMS:       getEnvAsInt("SLS_READ_TIMEOUT_MS", 10000),
+		ConnectTimeoutMS:    getEnvAsInt("SLS_CONNECT_TIMEOUT_MS", 5000),
+		DeduplicateTags:     getEnvAsBool("SLS_DEDUPLICATE_TAGS", true),
+		PreserveRawConfig:   getEnvAsBool("SLS_PRESERVE_RAW_CONFIG", false),
 	}
 }
 
-// CreateSLSClient 创建 SLS 客户端配置
+// Region 从 Endpoint 推导 Project 所在地域，如 cn-qingdao.log.aliyuncs.com 对应 cn-qingdao，
+// 内网和跨域共享 Endpoint 的后缀会被去掉；无法识别时返回空字符串
+func (c *SLSConfig) Region() string {
+	host := strings.TrimPrefix(strings.TrimPrefix(c.Endpoint, "https://"), "http://")
+	region, _, found := strings.Cut(host, ".log.aliyuncs.com")
+	if !found || region == "" {
+		return ""
+	}
+	for _, suffix := range []string{"-intranet", "-share"} {
+		region = strings.TrimSuffix(region, suffix)
+	}
+	return region
+}
+
+// Validate 检查 SLS 必填配置：Endpoint 和 Project 不能为空，access_key 和 sts 类型的 AccessKey ID 和 Secret 必须同时设置，
+// sts 类型还需要安全令牌；返回的错误列出全部缺失项
+func (c *SLSConfig) Validate() error {
+	var problems []string
+	if strings.TrimSpace(c.Endpoint) == "" {
+		problems = append(problems, "SLS_ENDPOINT is required")
+	}
+	if strings.TrimSpace(c.Project) == "" {
+		problems = append(problems, "SLS_PROJECT is required")
+	}
+
+	switch credentialType := strings.ToLower(strings.TrimSpace(c.CredentialType)); credentialType {
+	case "", CredentialTypeAccessKey, CredentialTypeSTS:
+		if c.AccessKeyID == "" || c.AccessKeySecret == "" {
+			problems = append(problems, "SLS_ACCESS_KEY_ID and SLS_ACCESS_KEY_SECRET must both be set")
+		}
+		if credentialType == CredentialTypeSTS && c.SecurityToken == "" {
+			problems = append(problems, "SLS_SECURITY_TOKEN is required for credential type sts")
+		}
+	case CredentialTypeECSRAMRole:
+		// 凭据从 ECS 实例元数据获取，不需要 AccessKey
+	default:
+		problems = append(problems, fmt.Sprintf("SLS_CREDENTIAL_TYPE %q must be one of %s, %s, %s",
+			c.CredentialType, CredentialTypeAccessKey, CredentialTypeSTS, CredentialTypeECSRAMRole))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid SLS config: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// CreateSLSClient 创建 SLS 客户端配置，按 CredentialType 构造凭据，未设置时使用 AccessKey
 func CreateSLSClient(cfg *SLSConfig) (*openapi.Config, error) {
-	// 使用配置的 SLS 凭据
-	cred, err := credential.NewCredential(&credential.Config{
-		Type:            tea.String("access_key"),
-		AccessKeyId:     tea.String(cfg.AccessKeyID),
-		AccessKeySecret: tea.String(cfg.AccessKeySecret),
-		SecurityToken:   tea.String(""), // 明确指定不使用 STS token
-	})
+	credentialConfig, err := cfg.credentialConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := credential.NewCredential(credentialConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -42,9 +123,43 @@ func CreateSLSClient(cfg *SLSConfig) (*openapi.Config, error) {
 	config := &openapi.Config{
 		Credential: cred,
 		Endpoint:   tea.String(cfg.Endpoint),
-		// 禁用 ECS 角色获取
-		Type: tea.String("access_key"),
+		Type:       credentialConfig.Type,
 	}
 
 	return config, nil
 }
+
+// credentialConfig 按凭据类型生成 credentials-go 的配置，只传入该类型需要的字段
+func (c *SLSConfig) credentialConfig() (*credential.Config, error) {
+	credentialType := strings.ToLower(strings.TrimSpace(c.CredentialType))
+	if credentialType == "" {
+		credentialType = CredentialTypeAccessKey
+	}
+
+	switch credentialType {
+	case CredentialTypeAccessKey:
+		return &credential.Config{
+			Type:            tea.String(CredentialTypeAccessKey),
+			AccessKeyId:     tea.String(c.AccessKeyID),
+			AccessKeySecret: tea.String(c.AccessKeySecret),
+			SecurityToken:   tea.String(""), // 明确指定不使用 STS token
+		}, nil
+	case CredentialTypeSTS:
+		if c.SecurityToken == "" {
+			return nil, fmt.Errorf("SLS_SECURITY_TOKEN is required for credential type %s", CredentialTypeSTS)
+		}
+		return &credential.Config{
+			Type:            tea.String(CredentialTypeSTS),
+			AccessKeyId:     tea.String(c.AccessKeyID),
+			AccessKeySecret: tea.String(c.AccessKeySecret),
+			SecurityToken:   tea.String(c.SecurityToken),
+		}, nil
+	case CredentialTypeECSRAMRole:
+		return &credential.Config{
+			Type:     tea.String(CredentialTypeECSRAMRole),
+			RoleName: tea.String(c.RoleName),
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported SLS credential type %q: must be one of %s, %s, %s",
+		c.CredentialType, CredentialTypeAccessKey, CredentialTypeSTS, CredentialTypeECSRAMRole)
+}
@@ -1,23 +1,44 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Ghostbaby/sls-migrate/internal/config"
 	"github.com/Ghostbaby/sls-migrate/internal/models"
 	"github.com/Ghostbaby/sls-migrate/internal/service"
+	"github.com/Ghostbaby/sls-migrate/internal/store"
 	"github.com/gin-gonic/gin"
 )
 
 // AlertHandler Alert 处理器
 type AlertHandler struct {
 	alertService service.AlertService
+	slsService   service.SLSService // 未配置 SLS 时为 nil
+	fieldCase    string
 }
 
-// NewAlertHandler 创建新的 AlertHandler 实例
-func NewAlertHandler(alertService service.AlertService) *AlertHandler {
+// ExportAlertsRequest 按名称导出 Alert 的请求
+type ExportAlertsRequest struct {
+	Names  []string `json:"names" binding:"required"`
+	Format string   `json:"format"`
+}
+
+// NewAlertHandler 创建新的 AlertHandler 实例，slsService 为 nil 时不支持将变更同步到 SLS
+func NewAlertHandler(alertService service.AlertService, slsService service.SLSService, apiConfig *config.APIConfig) *AlertHandler {
 	return &AlertHandler{
 		alertService: alertService,
+		slsService:   slsService,
+		fieldCase:    normalizeFieldCase(apiConfig.FieldCase),
 	}
 }
 
@@ -27,30 +48,101 @@ func NewAlertHandler(alertService service.AlertService) *AlertHandler {
 // @Tags Alert
 // @Accept json
 // @Produce json
-// @Param alert body models.Alert true "Alert 信息"
-// @Success 201 {object} models.Alert
+// @Param alert body AlertDTO true "Alert 信息"
+// @Success 201 {object} AlertDTO
+// @Header 201 {string} Location "新建 Alert 的资源路径"
 // @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /alerts [post]
 func (h *AlertHandler) CreateAlert(c *gin.Context) {
-	var alert models.Alert
-	if err := c.ShouldBindJSON(&alert); err != nil {
+	var dto AlertDTO
+	if err := bindFieldCase(c, h.fieldCase, &dto); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"message": err.Error(),
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
 		})
 		return
 	}
 
-	if err := h.alertService.CreateAlert(c.Request.Context(), &alert); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to create alert",
-			"message": err.Error(),
+	alert := dto.toModel()
+	if err := h.alertService.CreateAlert(c.Request.Context(), alert); err != nil {
+		respondError(c, err, "Failed to create alert")
+		return
+	}
+
+	// 创建过程会分步修改 alert 的嵌套结构，重新读取以返回实际持久化的状态
+	created, err := h.alertService.GetAlertByID(c.Request.Context(), alert.ID)
+	if err != nil {
+		respondError(c, err, "Failed to load created alert")
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/api/v1/alerts/%d", created.ID))
+	renderFieldCase(c, http.StatusCreated, h.fieldCase, toAlertDTO(created))
+}
+
+// CreateAlertsRequest 批量创建 Alert 的请求
+type CreateAlertsRequest struct {
+	Alerts []AlertDTO `json:"alerts" binding:"required"`
+}
+
+// UnmarshalJSON 同时支持 {"alerts": [...]} 和 GET /alerts/export?view=api 输出的 JSON 数组
+func (r *CreateAlertsRequest) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return json.Unmarshal(trimmed, &r.Alerts)
+	}
+
+	type plain CreateAlertsRequest
+	return json.Unmarshal(data, (*plain)(r))
+}
+
+// CreateAlerts 批量创建 Alert
+// @Summary 批量创建 Alert
+// @Description 先校验全部 Alert，再在同一个事务中创建，任一失败时整批回滚。请求体可以是 {"alerts": [...]}，也可以直接是 Alert 数组（如 GET /alerts/export?view=api 的输出）。返回每个 Alert 的状态（created/skipped-duplicate/error）和计数，请求中存在重名时返回 400
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param request body CreateAlertsRequest true "Alert 列表"
+// @Success 200 {object} service.BatchCreateResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /alerts/batch [post]
+func (h *AlertHandler) CreateAlerts(c *gin.Context) {
+	var req CreateAlertsRequest
+	if err := bindFieldCase(c, h.fieldCase, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
 		})
 		return
 	}
+	if len(req.Alerts) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    "alerts cannot be empty",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	alerts := make([]*models.Alert, len(req.Alerts))
+	for i := range req.Alerts {
+		alerts[i] = req.Alerts[i].toModel()
+	}
+
+	result, err := h.alertService.CreateAlerts(c.Request.Context(), alerts)
+	if err != nil {
+		respondError(c, err, "Failed to create alerts")
+		return
+	}
 
-	c.JSON(http.StatusCreated, alert)
+	renderFieldCase(c, http.StatusOK, h.fieldCase, result)
 }
 
 // GetAlertByID 根据 ID 获取 Alert
@@ -60,7 +152,7 @@ func (h *AlertHandler) CreateAlert(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Alert ID"
-// @Success 200 {object} models.Alert
+// @Success 200 {object} AlertDTO
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Router /alerts/{id} [get]
@@ -69,22 +161,21 @@ func (h *AlertHandler) GetAlertByID(c *gin.Context) {
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid alert ID",
-			"message": "ID must be a valid integer",
+			"error":      "Invalid alert ID",
+			"code":       ErrorCodeValidation,
+			"message":    "ID must be a valid integer",
+			"request_id": requestID(c),
 		})
 		return
 	}
 
 	alert, err := h.alertService.GetAlertByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Alert not found",
-			"message": err.Error(),
-		})
+		respondError(c, err, "Failed to get alert")
 		return
 	}
 
-	c.JSON(http.StatusOK, alert)
+	renderFieldCase(c, http.StatusOK, h.fieldCase, toAlertDTO(alert))
 }
 
 // GetAlertByName 根据名称获取 Alert
@@ -94,7 +185,7 @@ func (h *AlertHandler) GetAlertByID(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param name path string true "Alert 名称"
-// @Success 200 {object} models.Alert
+// @Success 200 {object} AlertDTO
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Router /alerts/name/{name} [get]
@@ -102,171 +193,1513 @@ func (h *AlertHandler) GetAlertByName(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid alert name",
-			"message": "Name cannot be empty",
+			"error":      "Invalid alert name",
+			"code":       ErrorCodeValidation,
+			"message":    "Name cannot be empty",
+			"request_id": requestID(c),
 		})
 		return
 	}
 
 	alert, err := h.alertService.GetAlertByName(c.Request.Context(), name)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Alert not found",
-			"message": err.Error(),
-		})
+		respondError(c, err, "Failed to get alert")
 		return
 	}
 
-	c.JSON(http.StatusOK, alert)
+	renderFieldCase(c, http.StatusOK, h.fieldCase, toAlertDTO(alert))
 }
 
 // UpdateAlert 更新 Alert
 // @Summary 更新 Alert
-// @Description 更新 Alert 信息
+// @Description 更新 Alert 信息。请求体带 expected_last_modified_time 时启用乐观锁：存储的 last_modified_time 已变化时不做任何修改并返回 409，成功时 last_modified_time 推进到当前时间
 // @Tags Alert
 // @Accept json
 // @Produce json
 // @Param id path int true "Alert ID"
-// @Param alert body models.Alert true "Alert 更新信息"
-// @Success 200 {object} models.Alert
+// @Param alert body AlertDTO true "Alert 更新信息"
+// @Param sections query string false "只更新指定分区，逗号分隔 (base,configuration,schedule,tags,queries)，默认全部"
+// @Success 200 {object} AlertDTO
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Router /alerts/{id} [put]
 func (h *AlertHandler) UpdateAlert(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid alert ID",
-			"message": "ID must be a valid integer",
+			"error":      "Invalid alert ID",
+			"code":       ErrorCodeValidation,
+			"message":    "ID must be a valid integer",
+			"request_id": requestID(c),
 		})
 		return
 	}
 
-	var alert models.Alert
-	if err := c.ShouldBindJSON(&alert); err != nil {
+	var dto AlertDTO
+	if err := bindFieldCase(c, h.fieldCase, &dto); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"message": err.Error(),
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
 		})
 		return
 	}
 
+	alert := dto.toModel()
 	alert.ID = uint(id)
-	if err := h.alertService.UpdateAlert(c.Request.Context(), &alert); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to update alert",
-			"message": err.Error(),
+	if sectionsParam := c.Query("sections"); sectionsParam != "" {
+		var sections []string
+		for _, section := range strings.Split(sectionsParam, ",") {
+			if section = strings.TrimSpace(section); section != "" {
+				sections = append(sections, section)
+			}
+		}
+		err = h.alertService.UpdateAlertSections(c.Request.Context(), alert, sections)
+	} else {
+		err = h.alertService.UpdateAlert(c.Request.Context(), alert)
+	}
+	if err != nil {
+		respondError(c, err, "Failed to update alert")
+		return
+	}
+
+	renderFieldCase(c, http.StatusOK, h.fieldCase, toAlertDTO(alert))
+}
+
+// PatchAlert 部分更新 Alert
+// @Summary 部分更新 Alert
+// @Description 只更新请求中出现的 display_name、description、status、schedule，配置、标签和查询保持不变
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Param patch body AlertPatchDTO true "需要更新的字段"
+// @Success 200 {object} AlertDTO
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /alerts/{id} [patch]
+func (h *AlertHandler) PatchAlert(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid alert ID",
+			"code":       ErrorCodeValidation,
+			"message":    "ID must be a valid integer",
+			"request_id": requestID(c),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, alert)
+	var dto AlertPatchDTO
+	if err := bindFieldCase(c, h.fieldCase, &dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	alert, err := h.alertService.PatchAlert(c.Request.Context(), uint(id), dto.toPatch())
+	if err != nil {
+		respondError(c, err, "Failed to patch alert")
+		return
+	}
+
+	renderFieldCase(c, http.StatusOK, h.fieldCase, toAlertDTO(alert))
 }
 
 // DeleteAlert 删除 Alert
 // @Summary 删除 Alert
-// @Description 根据 ID 删除 Alert
+// @Description 根据 ID 软删除 Alert，关联数据保留，可通过 POST /alerts/{id}/restore 恢复；hard=true 时永久删除 Alert 及其全部关联数据（包括已软删除的 Alert）。
+// @Description propagate=true 时在本地删除后同时删除 SLS 中的同名 Alert，SLS 中不存在时视为成功
 // @Tags Alert
 // @Accept json
 // @Produce json
 // @Param id path int true "Alert ID"
+// @Param hard query bool false "永久删除"
+// @Param propagate query bool false "同时删除 SLS 中的 Alert"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
 // @Router /alerts/{id} [delete]
 func (h *AlertHandler) DeleteAlert(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid alert ID",
-			"message": "ID must be a valid integer",
+			"error":      "Invalid alert ID",
+			"code":       ErrorCodeValidation,
+			"message":    "ID must be a valid integer",
+			"request_id": requestID(c),
 		})
 		return
 	}
 
-	if err := h.alertService.DeleteAlert(c.Request.Context(), uint(id)); err != nil {
+	hard := c.Query("hard") == "true"
+	propagate := c.Query("propagate") == "true"
+	if propagate && h.slsService == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to delete alert",
-			"message": err.Error(),
+			"error":      "SLS service not available",
+			"code":       ErrorCodeInternal,
+			"message":    "SLS service is not initialized",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	alert, err := h.alertService.DeleteAlert(c.Request.Context(), uint(id), hard)
+	if err != nil {
+		respondError(c, err, "Failed to delete alert")
+		return
+	}
+
+	message := "Alert deleted successfully"
+	if hard {
+		message = "Alert permanently deleted"
+	}
+	if !propagate {
+		c.JSON(http.StatusOK, gin.H{
+			"message": message,
 		})
 		return
 	}
 
+	// SLS 中已不存在同名 Alert 时本地删除仍然成功，sls_deleted 为 false
+	slsDeleted := true
+	if err := h.slsService.DeleteAlert(c.Request.Context(), "", alert.Name); err != nil {
+		if !errors.Is(err, service.ErrSLSNotFound) {
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error":      "Failed to delete alert from SLS",
+				"code":       ErrorCodeUpstream,
+				"message":    fmt.Sprintf("alert was deleted from the database but not from SLS: %v", err),
+				"request_id": requestID(c),
+			})
+			return
+		}
+		slsDeleted = false
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Alert deleted successfully",
+		"message":     message,
+		"sls_deleted": slsDeleted,
 	})
 }
 
-// ListAlerts 获取 Alert 列表
-// @Summary 获取 Alert 列表
-// @Description 分页获取 Alert 列表
+// RestoreAlert 恢复已软删除的 Alert
+// @Summary 恢复 Alert
+// @Description 恢复已软删除的 Alert 及其保留的关联数据
 // @Tags Alert
 // @Accept json
 // @Produce json
-// @Param page query int false "页码 (默认: 1)"
-// @Param page_size query int false "每页大小 (默认: 20, 最大: 100)"
-// @Success 200 {object} map[string]interface{}
+// @Param id path int true "Alert ID"
+// @Success 200 {object} AlertDTO
 // @Failure 400 {object} map[string]interface{}
-// @Router /alerts [get]
-func (h *AlertHandler) ListAlerts(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /alerts/{id}/restore [post]
+func (h *AlertHandler) RestoreAlert(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid alert ID",
+			"code":       ErrorCodeValidation,
+			"message":    "ID must be a valid integer",
+			"request_id": requestID(c),
+		})
+		return
+	}
 
-	alerts, total, err := h.alertService.ListAlerts(c.Request.Context(), page, pageSize)
+	alert, err := h.alertService.RestoreAlert(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get alerts",
-			"message": err.Error(),
+		respondError(c, err, "Failed to restore alert")
+		return
+	}
+
+	renderFieldCase(c, http.StatusOK, h.fieldCase, toAlertDTO(alert))
+}
+
+// ImportAlertsRequest 导入 Alert 的请求，与 POST /alerts/export 和 GET /alerts/export 的输出格式兼容
+type ImportAlertsRequest struct {
+	Alerts []*models.Alert `json:"alerts" binding:"required"`
+}
+
+// UnmarshalJSON 同时支持 {"alerts": [...]} 和 GET /alerts/export 输出的 JSON 数组
+func (r *ImportAlertsRequest) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return json.Unmarshal(trimmed, &r.Alerts)
+	}
+
+	type plain ImportAlertsRequest
+	return json.Unmarshal(data, (*plain)(r))
+}
+
+// bindImportAlerts 从 multipart 上传的 file 字段或请求体中解析待导入的 Alert。
+// format=yaml、YAML Content-Type 或 .yaml/.yml 文件按 YAML 解析，其余按 JSON 解析
+func bindImportAlerts(c *gin.Context) ([]*models.Alert, error) {
+	format := c.Query("format")
+	if !validFormat(format) {
+		return nil, fmt.Errorf("format must be json or yaml")
+	}
+	if strings.Contains(c.ContentType(), "yaml") {
+		format = formatYAML
+	}
+
+	var req ImportAlertsRequest
+	if !strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		if format != formatYAML {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				return nil, err
+			}
+			return req.Alerts, nil
+		}
+
+		data, err := c.GetRawData()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		return decodeImportAlerts(data, format)
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	if strings.HasSuffix(header.Filename, ".yaml") || strings.HasSuffix(header.Filename, ".yml") {
+		format = formatYAML
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	alerts, err := decodeImportAlerts(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse uploaded file: %w", err)
+	}
+	return alerts, nil
+}
+
+// decodeImportAlerts 按格式解析导入内容，内容中没有任何 Alert 时返回错误
+func decodeImportAlerts(data []byte, format string) ([]*models.Alert, error) {
+	var req ImportAlertsRequest
+	var err error
+	if format == formatYAML {
+		err = fromYAML(data, &req)
+	} else {
+		err = json.Unmarshal(data, &req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if req.Alerts == nil {
+		return nil, fmt.Errorf("no alerts found")
+	}
+	return req.Alerts, nil
+}
+
+// ImportAlerts 导入 Alert
+// @Summary 导入 Alert
+// @Description 按名称与数据库比对，将每个 Alert 分类为 create/update/identical/skipped（update 时返回字段差异）。请求体可以是 {"alerts": [...]}、Alert 数组，或 multipart 上传的 file 字段。JSON 不合法时返回 400 且不写入数据库。mode=skip 时跳过已存在的名称；continue_on_error=false（默认）时在第一个失败处停止。dry_run=true 时只返回预览，不写入数据库
+// @Tags Alert
+// @Accept json,mpfd
+// @Accept application/x-yaml
+// @Produce json
+// @Param dry_run query bool false "仅预览导入结果"
+// @Param mode query string false "已存在同名 Alert 时的处理方式：overwrite（默认）或 skip"
+// @Param continue_on_error query bool false "单个 Alert 失败后继续导入其余 Alert"
+// @Param format query string false "请求内容格式：json（默认）或 yaml，YAML Content-Type 或 .yaml/.yml 文件自动按 YAML 解析"
+// @Param request body ImportAlertsRequest false "导入的 Alert 列表"
+// @Param file formData file false "导入文件（JSON）"
+// @Success 200 {object} service.ImportResult
+// @Failure 400 {object} map[string]interface{}
+// @Router /alerts/import [post]
+func (h *AlertHandler) ImportAlerts(c *gin.Context) {
+	alerts, err := bindImportAlerts(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": alerts,
-		"pagination": gin.H{
-			"page":        page,
-			"page_size":   pageSize,
-			"total":       total,
-			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
-		},
-	})
+	opts := service.ImportOptions{
+		DryRun:          c.Query("dry_run") == "true",
+		Mode:            c.Query("mode"),
+		ContinueOnError: c.Query("continue_on_error") == "true",
+	}
+	result, err := h.alertService.ImportAlerts(c.Request.Context(), alerts, opts)
+	if err != nil {
+		respondError(c, err, "Failed to import alerts")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
 }
 
-// ListAlertsByStatus 根据状态获取 Alert 列表
-// @Summary 根据状态获取 Alert 列表
-// @Description 根据状态分页获取 Alert 列表
+// FreezeAlertRequest 冻结 Alert 的请求
+type FreezeAlertRequest struct {
+	Until int64 `json:"until"` // 冻结截止时间（Unix 秒），不晚于当前时间表示解除冻结
+}
+
+// FreezeAlert 冻结或解除冻结 Alert
+// @Summary 冻结 Alert
+// @Description 冻结期内迁移工具不会更新、删除或推送该 Alert（与 mute 不同，不影响告警触发）
 // @Tags Alert
 // @Accept json
 // @Produce json
-// @Param status query string true "Alert 状态 (ENABLED/DISABLED)"
-// @Param page query int false "页码 (默认: 1)"
-// @Param page_size query int false "每页大小 (默认: 20, 最大: 100)"
-// @Success 200 {object} map[string]interface{}
+// @Param id path int true "Alert ID"
+// @Param request body FreezeAlertRequest true "冻结截止时间"
+// @Success 200 {object} models.Alert
 // @Failure 400 {object} map[string]interface{}
-// @Router /alerts/status/{status} [get]
-func (h *AlertHandler) ListAlertsByStatus(c *gin.Context) {
-	status := c.Param("status")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+// @Failure 404 {object} map[string]interface{}
+// @Router /alerts/{id}/freeze [post]
+func (h *AlertHandler) FreezeAlert(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid alert ID",
+			"code":       ErrorCodeValidation,
+			"message":    "ID must be a valid integer",
+			"request_id": requestID(c),
+		})
+		return
+	}
 
-	alerts, total, err := h.alertService.ListAlertsByStatus(c.Request.Context(), status, page, pageSize)
+	var req FreezeAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	alert, err := h.alertService.FreezeAlert(c.Request.Context(), uint(id), req.Until)
+	if err != nil {
+		respondError(c, err, "Failed to freeze alert")
+		return
+	}
+
+	c.JSON(http.StatusOK, alert)
+}
+
+// EnableAlert 启用 Alert
+// @Summary 启用 Alert
+// @Description 只将状态更新为 ENABLED 并刷新最后修改时间；sync=true 时同时更新 SLS 中的 Alert
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Param sync query bool false "同时更新 SLS"
+// @Success 200 {object} AlertDTO
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /alerts/{id}/enable [post]
+func (h *AlertHandler) EnableAlert(c *gin.Context) {
+	h.setAlertStatus(c, service.AlertStatusEnabled)
+}
+
+// DisableAlert 停用 Alert
+// @Summary 停用 Alert
+// @Description 只将状态更新为 DISABLED 并刷新最后修改时间；sync=true 时同时更新 SLS 中的 Alert
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Param sync query bool false "同时更新 SLS"
+// @Success 200 {object} AlertDTO
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /alerts/{id}/disable [post]
+func (h *AlertHandler) DisableAlert(c *gin.Context) {
+	h.setAlertStatus(c, service.AlertStatusDisabled)
+}
+
+// setAlertStatus 更新 Alert 状态，sync=true 时将更新后的 Alert 推送到 SLS
+func (h *AlertHandler) setAlertStatus(c *gin.Context, status string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid alert ID",
+			"code":       ErrorCodeValidation,
+			"message":    "ID must be a valid integer",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	syncToSLS := c.Query("sync") == "true"
+	if syncToSLS && h.slsService == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get alerts",
-			"message": err.Error(),
+			"error":      "SLS service not available",
+			"code":       ErrorCodeInternal,
+			"message":    "SLS service is not initialized",
+			"request_id": requestID(c),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": alerts,
-		"pagination": gin.H{
-			"page":        page,
-			"page_size":   pageSize,
-			"total":       total,
-			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
-		},
+	alert, err := h.alertService.SetAlertStatus(c.Request.Context(), uint(id), status)
+	if err != nil {
+		respondError(c, err, "Failed to update alert status")
+		return
+	}
+
+	if syncToSLS {
+		if _, err := h.slsService.UpdateAlert(c.Request.Context(), alert); err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error":      "Failed to update alert in SLS",
+				"code":       ErrorCodeUpstream,
+				"message":    fmt.Sprintf("status was updated in the database but not in SLS: %v", err),
+				"request_id": requestID(c),
+			})
+			return
+		}
+	}
+
+	renderFieldCase(c, http.StatusOK, h.fieldCase, toAlertDTO(alert))
+}
+
+// 批量设置状态的单项状态
+const (
+	BulkStatusUpdated       = "updated"
+	BulkStatusSkippedFrozen = "skipped-frozen"
+	BulkStatusError         = "error"
+)
+
+// BulkAlertStatusRequest 批量启用或停用 Alert 的请求
+type BulkAlertStatusRequest struct {
+	IDs    []uint `json:"ids" binding:"required"`
+	Status string `json:"status" binding:"required"` // ENABLED 或 DISABLED
+}
+
+// BulkAlertStatusItem 批量设置状态中单个 Alert 的结果
+type BulkAlertStatusItem struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name,omitempty"`
+	Status    string `json:"status"`
+	Pushed    bool   `json:"pushed"`               // 状态已推送到 SLS
+	Error     string `json:"error,omitempty"`      // 数据库更新失败或被跳过的原因
+	PushError string `json:"push_error,omitempty"` // 数据库已更新但推送到 SLS 失败的原因
+}
+
+// BulkAlertStatusResult 批量设置状态的结果
+type BulkAlertStatusResult struct {
+	Total      int                   `json:"total"`
+	Updated    int                   `json:"updated"`
+	Skipped    int                   `json:"skipped"`
+	Failed     int                   `json:"failed"`
+	Pushed     int                   `json:"pushed"`
+	PushFailed int                   `json:"push_failed"`
+	Items      []BulkAlertStatusItem `json:"items"`
+}
+
+// BulkSetAlertStatus 批量启用或停用 Alert
+// @Summary 批量启用或停用 Alert
+// @Description 逐个将 ids 中的 Alert 设置为 status，只更新状态和最后修改时间；冻结期内的 Alert 跳过。push=true 时把数据库中已更新的 Alert 的状态通过 EnableAlert/DisableAlert 推送到 SLS，不修改 SLS 中的配置。返回每个 Alert 的结果
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param push query bool false "同时将状态推送到 SLS"
+// @Param request body BulkAlertStatusRequest true "Alert ID 列表和目标状态"
+// @Success 200 {object} BulkAlertStatusResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /alerts/status [post]
+func (h *AlertHandler) BulkSetAlertStatus(c *gin.Context) {
+	var req BulkAlertStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+	if len(req.IDs) == 0 || !service.IsValidAlertStatus(req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    "ids cannot be empty and status must be ENABLED or DISABLED",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	push := c.Query("push") == "true"
+	if push && h.slsService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "SLS service not available",
+			"code":       ErrorCodeInternal,
+			"message":    "SLS service is not initialized",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	result := &BulkAlertStatusResult{Total: len(req.IDs), Items: make([]BulkAlertStatusItem, 0, len(req.IDs))}
+	for _, id := range req.IDs {
+		item := BulkAlertStatusItem{ID: id}
+
+		alert, err := h.alertService.SetAlertStatus(ctx, id, req.Status)
+		var frozen *service.AlertFrozenError
+		switch {
+		case errors.As(err, &frozen):
+			item.Name = frozen.Name
+			item.Status = BulkStatusSkippedFrozen
+			item.Error = err.Error()
+			result.Skipped++
+		case err != nil:
+			item.Status = BulkStatusError
+			item.Error = err.Error()
+			result.Failed++
+		default:
+			item.Name = alert.Name
+			item.Status = BulkStatusUpdated
+			result.Updated++
+
+			if push {
+				if err := h.slsService.SetAlertStatus(ctx, alert.Name, req.Status); err != nil {
+					item.PushError = err.Error()
+					result.PushFailed++
+				} else {
+					item.Pushed = true
+					result.Pushed++
+				}
+			}
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	renderFieldCase(c, http.StatusOK, h.fieldCase, result)
+}
+
+// MuteAlertRequest 屏蔽 Alert 的请求，duration 和 until 必须且只能提供一个
+type MuteAlertRequest struct {
+	Duration *string `json:"duration"` // 从当前时间起屏蔽的时长，如 30m、2h
+	Until    *int64  `json:"until"`    // 屏蔽截止时间（Unix 毫秒）
+}
+
+// MuteAlert 屏蔽 Alert
+// @Summary 屏蔽 Alert
+// @Description 按 duration 或 until 计算并写入配置的 mute_until（Unix 秒，与 SLS 一致），屏蔽期内 SLS 不发送通知；已停用的 Alert 返回 409；sync=true 时同时更新 SLS 中的 Alert
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Param sync query bool false "同时更新 SLS"
+// @Param request body MuteAlertRequest true "屏蔽时长或截止时间"
+// @Success 200 {object} AlertDTO
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /alerts/{id}/mute [post]
+func (h *AlertHandler) MuteAlert(c *gin.Context) {
+	var req MuteAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	h.setAlertMute(c, func(ctx context.Context, id uint) (*models.Alert, error) {
+		return h.alertService.MuteAlert(ctx, id, &service.AlertMute{Duration: req.Duration, UntilMS: req.Until})
+	})
+}
+
+// UnmuteAlert 取消屏蔽 Alert
+// @Summary 取消屏蔽 Alert
+// @Description 清空配置的 mute_until；sync=true 时同时更新 SLS 中的 Alert
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Param sync query bool false "同时更新 SLS"
+// @Success 200 {object} AlertDTO
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /alerts/{id}/unmute [post]
+func (h *AlertHandler) UnmuteAlert(c *gin.Context) {
+	h.setAlertMute(c, h.alertService.UnmuteAlert)
+}
+
+// setAlertMute 执行屏蔽或取消屏蔽，sync=true 时将更新后的 Alert 推送到 SLS
+func (h *AlertHandler) setAlertMute(c *gin.Context, apply func(ctx context.Context, id uint) (*models.Alert, error)) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid alert ID",
+			"code":       ErrorCodeValidation,
+			"message":    "ID must be a valid integer",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	syncToSLS := c.Query("sync") == "true"
+	if syncToSLS && h.slsService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "SLS service not available",
+			"code":       ErrorCodeInternal,
+			"message":    "SLS service is not initialized",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	alert, err := apply(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, err, "Failed to update alert mute")
+		return
+	}
+
+	if syncToSLS {
+		if _, err := h.slsService.UpdateAlert(c.Request.Context(), alert); err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error":      "Failed to update alert in SLS",
+				"code":       ErrorCodeUpstream,
+				"message":    fmt.Sprintf("mute_until was updated in the database but not in SLS: %v", err),
+				"request_id": requestID(c),
+			})
+			return
+		}
+	}
+
+	renderFieldCase(c, http.StatusOK, h.fieldCase, toAlertDTO(alert))
+}
+
+// RebuildAlert 重建 Alert 的关联数据
+// @Summary 重建 Alert 的关联数据
+// @Description 在事务中删除并重新创建 Alert 的配置、调度、标签和查询，清理孤立记录并修复外键
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Success 200 {object} AlertDTO
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /alerts/{id}/rebuild [post]
+func (h *AlertHandler) RebuildAlert(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid alert ID",
+			"code":       ErrorCodeValidation,
+			"message":    "ID must be a valid integer",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	alert, err := h.alertService.RebuildAlert(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, err, "Failed to rebuild alert")
+		return
+	}
+
+	renderFieldCase(c, http.StatusOK, h.fieldCase, toAlertDTO(alert))
+}
+
+// RollbackAlert 将 Alert 回滚到审计记录的变更前快照
+// @Summary 回滚 Alert
+// @Description 用审计记录 audit_id 的 before_json 整体替换 Alert 的主记录、配置、调度、标签和查询（名称保持不变），并写入一条 rollback 审计记录；
+// @Description 审计记录必须属于该 Alert 且包含变更前快照（create 记录不能回滚）
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Param audit_id path int true "审计记录 ID"
+// @Success 200 {object} AlertDTO
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /alerts/{id}/rollback/{audit_id} [post]
+func (h *AlertHandler) RollbackAlert(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid alert ID",
+			"code":       ErrorCodeValidation,
+			"message":    "ID must be a valid integer",
+			"request_id": requestID(c),
+		})
+		return
+	}
+	auditID, err := strconv.ParseUint(c.Param("audit_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid audit log ID",
+			"code":       ErrorCodeValidation,
+			"message":    "audit_id must be a valid integer",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	alert, err := h.alertService.RollbackAlert(c.Request.Context(), uint(id), uint(auditID))
+	if err != nil {
+		respondError(c, err, "Failed to roll back alert")
+		return
+	}
+
+	renderFieldCase(c, http.StatusOK, h.fieldCase, toAlertDTO(alert))
+}
+
+// ListAlerts 获取 Alert 列表
+// @Summary 获取 Alert 列表
+// @Description 分页获取 Alert 列表
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param page query int false "页码 (默认: 1)"
+// @Param page_size query int false "每页大小 (默认: 20, 最大: 100)"
+// @Param synced_before query string false "只返回在该时间之前同步过或从未同步过的 Alert (RFC3339 或 Unix 秒)"
+// @Param tag query string false "标签查询，同时匹配 label 和 annotation，子句用分号分隔：key=value、key=*（存在）、key in (a,b)、key=prefix*"
+// @Param include_deleted query bool false "包含已软删除的 Alert（不能与 tag、synced_before 同时使用）"
+// @Param If-Modified-Since header string false "上次获取时的 Last-Modified，未变化时返回 304"
+// @Success 200 {object} map[string]interface{}
+// @Success 304 "自 If-Modified-Since 以来没有 Alert 变化"
+// @Header 200 {string} Last-Modified "所有 Alert 中最大的 updated_at"
+// @Failure 400 {object} map[string]interface{}
+// @Router /alerts [get]
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	var syncedBefore *time.Time
+	if value := c.Query("synced_before"); value != "" {
+		before, err := parseTimeParam(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "Invalid synced_before",
+				"code":       ErrorCodeValidation,
+				"message":    err.Error(),
+				"request_id": requestID(c),
+			})
+			return
+		}
+		syncedBefore = &before
+	}
+
+	tagQuery, err := tagQueryParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid tag query",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+	if tagQuery != "" && syncedBefore != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid query",
+			"code":       ErrorCodeValidation,
+			"message":    "tag and synced_before cannot be combined",
+			"request_id": requestID(c),
+		})
+		return
+	}
+	includeDeleted := c.Query("include_deleted") == "true"
+	if includeDeleted && (tagQuery != "" || syncedBefore != nil) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid query",
+			"code":       ErrorCodeValidation,
+			"message":    "include_deleted cannot be combined with tag or synced_before",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	// 按同步时间过滤时，同步操作也会改变结果集
+	lastModified, err := h.alertService.GetAlertsLastModified(c.Request.Context(), syncedBefore != nil)
+	if err != nil {
+		respondError(c, err, "Failed to get alerts")
+		return
+	}
+	if notModified(c, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	var alerts []*models.Alert
+	var total int64
+	switch {
+	case syncedBefore != nil:
+		alerts, total, err = h.alertService.ListAlertsSyncedBefore(c.Request.Context(), *syncedBefore, page, pageSize)
+	case tagQuery != "":
+		alerts, total, err = h.alertService.ListAlertsByTags(c.Request.Context(), tagQuery, page, pageSize)
+	default:
+		alerts, total, err = h.alertService.ListAlerts(c.Request.Context(), page, pageSize, includeDeleted)
+	}
+	if err != nil {
+		respondError(c, err, "Failed to get alerts")
+		return
+	}
+
+	renderFieldCase(c, http.StatusOK, h.fieldCase, gin.H{
+		"data": toAlertDTOs(alerts),
+		"pagination": gin.H{
+			"page":        page,
+			"page_size":   pageSize,
+			"total":       total,
+			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+// SearchAlerts 搜索 Alert
+// @Summary 搜索 Alert
+// @Description 按名称子串、状态和标签键值分页搜索 Alert，多个条件同时满足；标签同时匹配 label 和 annotation
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param name query string false "名称子串"
+// @Param status query string false "状态 (ENABLED/DISABLED)"
+// @Param tag_key query string false "标签键"
+// @Param tag_value query string false "标签值，需要和 tag_key 一起使用"
+// @Param page query int false "页码 (默认: 1)"
+// @Param page_size query int false "每页大小 (默认: 20, 最大: 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /alerts/search [get]
+func (h *AlertHandler) SearchAlerts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	filter := store.SearchFilter{
+		Name:     c.Query("name"),
+		Status:   c.Query("status"),
+		TagKey:   c.Query("tag_key"),
+		TagValue: c.Query("tag_value"),
+	}
+	alerts, total, err := h.alertService.SearchAlerts(c.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		respondError(c, err, "Failed to search alerts")
+		return
+	}
+
+	renderFieldCase(c, http.StatusOK, h.fieldCase, gin.H{
+		"data": toAlertDTOs(alerts),
+		"pagination": gin.H{
+			"page":        page,
+			"page_size":   pageSize,
+			"total":       total,
+			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+// tagQueryParam 读取 tag 查询参数，多个 tag 参数按分号合并。
+// 标准库解析查询串时会丢弃包含未编码分号的参数，这里直接解析原始查询串
+func tagQueryParam(c *gin.Context) (string, error) {
+	var clauses []string
+	for _, part := range strings.Split(c.Request.URL.RawQuery, "&") {
+		key, value, _ := strings.Cut(part, "=")
+		if key != "tag" {
+			continue
+		}
+		decoded, err := url.QueryUnescape(value)
+		if err != nil {
+			return "", err
+		}
+		if decoded = strings.TrimSpace(decoded); decoded != "" {
+			clauses = append(clauses, decoded)
+		}
+	}
+	return strings.Join(clauses, ";"), nil
+}
+
+// notModified 设置 Last-Modified 响应头，并判断客户端的 If-Modified-Since 是否仍然有效
+// HTTP 日期只精确到秒，比较前先截断
+func notModified(c *gin.Context, lastModified *time.Time) bool {
+	if lastModified == nil {
+		return false
+	}
+
+	modified := lastModified.UTC().Truncate(time.Second)
+	c.Header("Last-Modified", modified.Format(http.TimeFormat))
+
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
+
+// ListAlertsByStatus 根据状态获取 Alert 列表
+// @Summary 根据状态获取 Alert 列表
+// @Description 根据状态分页获取 Alert 列表
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param status query string true "Alert 状态 (ENABLED/DISABLED)"
+// @Param page query int false "页码 (默认: 1)"
+// @Param page_size query int false "每页大小 (默认: 20, 最大: 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /alerts/status/{status} [get]
+func (h *AlertHandler) ListAlertsByStatus(c *gin.Context) {
+	status := c.Param("status")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	alerts, total, err := h.alertService.ListAlertsByStatus(c.Request.Context(), status, page, pageSize)
+	if err != nil {
+		respondError(c, err, "Failed to get alerts")
+		return
+	}
+
+	renderFieldCase(c, http.StatusOK, h.fieldCase, gin.H{
+		"data": toAlertDTOs(alerts),
+		"pagination": gin.H{
+			"page":        page,
+			"page_size":   pageSize,
+			"total":       total,
+			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+// ListAlertTags 分页获取 Alert 的标签
+// @Summary 获取 Alert 的标签列表
+// @Description 分页获取 Alert 的标签，可按类型过滤
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Param type query string false "标签类型 (label/annotation)"
+// @Param page query int false "页码 (默认: 1)"
+// @Param page_size query int false "每页大小 (默认: 20, 最大: 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /alerts/{id}/tags [get]
+func (h *AlertHandler) ListAlertTags(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid alert ID",
+			"code":       ErrorCodeValidation,
+			"message":    "ID must be a valid integer",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	tags, total, err := h.alertService.ListAlertTags(c.Request.Context(), uint(id), c.Query("type"), page, pageSize)
+	if err != nil {
+		respondError(c, err, "Failed to get alert tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": tags,
+		"pagination": gin.H{
+			"page":        page,
+			"page_size":   pageSize,
+			"total":       total,
+			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+// GetAlertHistory 分页获取 Alert 的变更审计记录
+// @Summary 获取 Alert 的变更历史
+// @Description 按时间倒序返回 Alert 的创建、更新、回滚、软删除、恢复和永久删除记录，包含操作人（请求头 X-Actor）和变更前后的 Alert 快照（模型字段）；Alert 永久删除后仍可查询
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Param page query int false "页码 (默认: 1)"
+// @Param page_size query int false "每页大小 (默认: 20, 最大: 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /alerts/{id}/history [get]
+func (h *AlertHandler) GetAlertHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid alert ID",
+			"code":       ErrorCodeValidation,
+			"message":    "ID must be a valid integer",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	logs, total, err := h.alertService.ListAlertHistory(c.Request.Context(), uint(id), page, pageSize)
+	if err != nil {
+		respondError(c, err, "Failed to get alert history")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": toAuditLogDTOs(logs),
+		"pagination": gin.H{
+			"page":        page,
+			"page_size":   pageSize,
+			"total":       total,
+			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+// CreateAlertTag 为 Alert 添加标签
+// @Summary 为 Alert 添加标签
+// @Description 为 Alert 添加单个 label 或 annotation
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Param tag body models.AlertTag true "标签信息"
+// @Success 201 {object} models.AlertTag
+// @Failure 400 {object} map[string]interface{}
+// @Router /alerts/{id}/tags [post]
+func (h *AlertHandler) CreateAlertTag(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid alert ID",
+			"code":       ErrorCodeValidation,
+			"message":    "ID must be a valid integer",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	var tag models.AlertTag
+	if err := c.ShouldBindJSON(&tag); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	if err := h.alertService.AddAlertTag(c.Request.Context(), uint(id), &tag); err != nil {
+		respondError(c, err, "Failed to create alert tag")
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// DeleteAlertTag 删除 Alert 的标签
+// @Summary 删除 Alert 的标签
+// @Description 根据标签 ID 删除 Alert 的单个标签
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Param tag_id path int true "标签 ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /alerts/{id}/tags/{tag_id} [delete]
+func (h *AlertHandler) DeleteAlertTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid alert ID",
+			"code":       ErrorCodeValidation,
+			"message":    "ID must be a valid integer",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	tagID, err := strconv.ParseUint(c.Param("tag_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid tag ID",
+			"code":       ErrorCodeValidation,
+			"message":    "Tag ID must be a valid integer",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	if err := h.alertService.DeleteAlertTag(c.Request.Context(), uint(id), uint(tagID)); err != nil {
+		respondError(c, err, "Failed to delete alert tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Alert tag deleted successfully",
+	})
+}
+
+// AutocompleteAlerts 按名称前缀自动补全 Alert
+// @Summary Alert 名称自动补全
+// @Description 按名称前缀匹配 Alert，仅返回 id/name/display_name
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param q query string true "名称前缀"
+// @Param limit query int false "返回数量 (默认: 10, 最大: 50)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /alerts/autocomplete [get]
+func (h *AlertHandler) AutocompleteAlerts(c *gin.Context) {
+	prefix := c.Query("q")
+	if prefix == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid query",
+			"code":       ErrorCodeValidation,
+			"message":    "q cannot be empty",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	suggestions, err := h.alertService.AutocompleteAlerts(c.Request.Context(), prefix, limit)
+	if err != nil {
+		respondError(c, err, "Failed to autocomplete alerts")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": suggestions,
+	})
+}
+
+// GetAlertGraph 获取 Alert 依赖关系图
+// @Summary 获取 Alert 依赖关系图
+// @Description 返回 Alert、策略和日志库节点，以及 Alert 指向策略（PolicyConfiguration）和日志库（查询语句）的边
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Success 200 {object} service.AlertGraph
+// @Failure 500 {object} map[string]interface{}
+// @Router /alerts/graph [get]
+func (h *AlertHandler) GetAlertGraph(c *gin.Context) {
+	graph, err := h.alertService.BuildAlertGraph(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to build alert graph")
+		return
+	}
+
+	c.JSON(http.StatusOK, graph)
+}
+
+// GetDuplicateAlerts 查找内容重复的 Alert
+// @Summary 查找内容重复的 Alert
+// @Description 按查询语句（含目标日志库）、触发条件和阈值的内容哈希对 Alert 分组，返回成员多于一个的组，用于清理名称不同但内容相同的 Alert
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /alerts/duplicates [get]
+func (h *AlertHandler) GetDuplicateAlerts(c *gin.Context) {
+	groups, err := h.alertService.FindDuplicateAlerts(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to find duplicate alerts")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"total":  len(groups),
+		"groups": groups,
+	})
+}
+
+// GetAlertCountTrend 获取 Alert 数量的每日趋势
+// @Summary 获取 Alert 数量趋势
+// @Description 返回最近 days 天（含今天，UTC）每日快照中的 Alert 总数和各状态数量，按日期升序；快照由后台任务每小时覆盖写入当天的记录，服务未运行的日期没有数据
+// @Tags Alert
+// @Produce json
+// @Param days query int false "天数 (默认: 30, 最大: 365)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /alerts/stats/trend [get]
+func (h *AlertHandler) GetAlertCountTrend(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(service.DefaultTrendDays)))
+	if err != nil || days < 1 || days > service.MaxTrendDays {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid days",
+			"code":       ErrorCodeValidation,
+			"message":    fmt.Sprintf("days must be an integer between 1 and %d", service.MaxTrendDays),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	snapshots, err := h.alertService.GetAlertCountTrend(c.Request.Context(), days)
+	if err != nil {
+		respondError(c, err, "Failed to get alert count trend")
+		return
+	}
+
+	renderFieldCase(c, http.StatusOK, h.fieldCase, gin.H{
+		"days": days,
+		"data": snapshots,
+	})
+}
+
+// GetAlertSummary 获取 Alert 数量汇总
+// @Summary 获取 Alert 数量汇总
+// @Description 以单次 GROUP BY status 查询返回 Alert 总数、启用和停用数量，other 中给出其他状态的数量；不含已软删除的 Alert
+// @Tags Alert
+// @Produce json
+// @Success 200 {object} service.AlertSummary
+// @Failure 500 {object} map[string]interface{}
+// @Router /alerts/summary [get]
+func (h *AlertHandler) GetAlertSummary(c *gin.Context) {
+	summary, err := h.alertService.GetAlertSummary(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to get alert summary")
+		return
+	}
+
+	renderFieldCase(c, http.StatusOK, h.fieldCase, summary)
+}
+
+// maxExportPageInterval 流式导出时两页之间的最大等待时间
+const maxExportPageInterval = 5 * time.Second
+
+// 流式导出的输出格式
+const (
+	ExportViewModel = "model"
+	ExportViewAPI   = "api"
+)
+
+// StreamExportAlerts 以 JSON 或 YAML 数组流式导出全部 Alert
+// @Summary 流式导出全部 Alert
+// @Description 按 ID 升序逐页读取并以附件形式写出 Alert，内存占用与页大小相关。中途出错时追加 {"error": ..., "resume_after_id": ...} 元素后正常结束数组，可通过 after_id 续传。view=api 时输出 API 字段格式，可直接作为 POST /alerts/batch 的请求体重新导入；format=yaml 的输出可由 POST /alerts/import?format=yaml 导入
+// @Tags Alert
+// @Produce json
+// @Produce application/x-yaml
+// @Param status query string false "只导出该状态的 Alert（ENABLED 或 DISABLED）"
+// @Param format query string false "输出格式：json（默认）或 yaml"
+// @Param view query string false "输出格式：model（数据库模型字段，默认）或 api（与创建接口相同的 API 字段）"
+// @Param after_id query int false "从该 ID 之后开始导出（续传）"
+// @Param page_size query int false "每页读取数量 (默认: 100, 最大: 100)"
+// @Param page_interval_ms query int false "两页之间的等待时间，用于限制数据库压力 (默认: 0, 最大: 5000)"
+// @Success 200 {array} models.Alert
+// @Failure 400 {object} map[string]interface{}
+// @Router /alerts/export [get]
+func (h *AlertHandler) StreamExportAlerts(c *gin.Context) {
+	afterID, err := strconv.ParseUint(c.DefaultQuery("after_id", "0"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid after_id",
+			"code":       ErrorCodeValidation,
+			"message":    "after_id must be a valid integer",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "100"))
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 100
+	}
+	intervalMS, _ := strconv.Atoi(c.DefaultQuery("page_interval_ms", "0"))
+	interval := time.Duration(intervalMS) * time.Millisecond
+	if interval < 0 {
+		interval = 0
+	}
+	if interval > maxExportPageInterval {
+		interval = maxExportPageInterval
+	}
+
+	status := strings.ToUpper(c.Query("status"))
+	if status != "" && !service.IsValidAlertStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid status",
+			"code":       ErrorCodeValidation,
+			"message":    "status must be ENABLED or DISABLED",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	view := c.DefaultQuery("view", ExportViewModel)
+	if view != ExportViewModel && view != ExportViewAPI {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid view",
+			"code":       ErrorCodeValidation,
+			"message":    "view must be model or api",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", formatJSON)
+	if !validFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid format",
+			"code":       ErrorCodeValidation,
+			"message":    "format must be json or yaml",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	filename := "alerts"
+	if status != "" {
+		filename = fmt.Sprintf("alerts-%s", strings.ToLower(status))
+	}
+
+	ctx := c.Request.Context()
+	stream := newArrayStream(c.Writer, format)
+	c.Header("Content-Type", stream.contentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, stream.extension()))
+	c.Status(http.StatusOK)
+
+	lastID := uint(afterID)
+	// 出错时写入错误标记并结束数组，保证输出仍是合法的 JSON/YAML
+	writeError := func(err error) {
+		stream.element(gin.H{
+			"error":           err.Error(),
+			"resume_after_id": lastID,
+		})
+		stream.end()
+	}
+
+	stream.begin()
+	for {
+		alerts, err := h.alertService.ListAlertsAfterID(ctx, lastID, status, pageSize)
+		if err != nil {
+			writeError(err)
+			return
+		}
+
+		for _, alert := range alerts {
+			obj, err := h.exportedAlert(alert, view)
+			if err == nil {
+				err = stream.element(obj)
+			}
+			if err != nil {
+				writeError(fmt.Errorf("failed to encode alert %s: %w", alert.Name, err))
+				return
+			}
+			lastID = alert.ID
+		}
+		c.Writer.Flush()
+
+		if len(alerts) < pageSize {
+			break
+		}
+
+		if interval > 0 {
+			select {
+			case <-ctx.Done():
+				writeError(ctx.Err())
+				return
+			case <-time.After(interval):
+			}
+		}
+	}
+	stream.end()
+}
+
+// exportedAlert 返回单个 Alert 按导出格式输出的对象，api 格式遵循 API_FIELD_CASE
+func (h *AlertHandler) exportedAlert(alert *models.Alert, view string) (interface{}, error) {
+	if view != ExportViewAPI {
+		return alert, nil
+	}
+
+	var obj interface{} = toAlertDTO(alert)
+	if h.fieldCase == FieldCaseCamel {
+		return convertFieldCase(obj, snakeToCamel)
+	}
+	return obj, nil
+}
+
+// ExportAlertsByNames 按名称列表导出 Alert
+// @Summary 按名称列表导出 Alert
+// @Description 导出指定名称的 Alert（包含完整嵌套配置），并报告未找到的名称
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Produce application/x-yaml
+// @Param request body ExportAlertsRequest true "导出请求"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /alerts/export [post]
+func (h *AlertHandler) ExportAlertsByNames(c *gin.Context) {
+	var req ExportAlertsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	if !validFormat(req.Format) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid format",
+			"code":       ErrorCodeValidation,
+			"message":    "format must be json or yaml",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	alerts, missing, err := h.alertService.ExportAlertsByNames(c.Request.Context(), req.Names)
+	if err != nil {
+		respondError(c, err, "Failed to export alerts")
+		return
+	}
+
+	renderFormatted(c, http.StatusOK, req.Format, gin.H{
+		"alerts":  alerts,
+		"count":   len(alerts),
+		"missing": missing,
 	})
 }
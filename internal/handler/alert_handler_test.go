@@ -0,0 +1,727 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/internal/service"
+	"github.com/Ghostbaby/sls-migrate/pkg/database"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateAlertReturnsPersistedState(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	created := server.createTestAlert(t, "persisted")
+	if created.ID == 0 {
+		t.Fatalf("created alert has no ID")
+	}
+
+	recorder := server.do(t, http.MethodGet, fmt.Sprintf("/api/v1/alerts/%d", created.ID), nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GET status = %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var fetched AlertDTO
+	decodeBody(t, recorder, &fetched)
+
+	// 创建响应与随后的 GET 完全一致，嵌套 ID 和时间戳都来自数据库
+	createdJSON, _ := json.Marshal(created)
+	fetchedJSON, _ := json.Marshal(&fetched)
+	if string(createdJSON) != string(fetchedJSON) {
+		t.Errorf("create response differs from GET:\ncreate: %s\nget:    %s", createdJSON, fetchedJSON)
+	}
+	if len(created.Tags) != 1 || created.Tags[0].ID == 0 || len(created.Queries) != 1 || created.Queries[0].ID == 0 {
+		t.Errorf("nested rows missing persisted IDs: tags=%+v queries=%+v", created.Tags, created.Queries)
+	}
+}
+
+func TestAutocompleteAlerts(t *testing.T) {
+	server := newTestServer(t, nil)
+	for _, name := range []string{"cpu-high", "cpu-low", "mem-high"} {
+		server.createTestAlert(t, name)
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantNames  []string
+	}{
+		{name: "matches prefix", query: "q=cpu", wantStatus: http.StatusOK, wantNames: []string{"cpu-high", "cpu-low"}},
+		{name: "limit", query: "q=cpu&limit=1", wantStatus: http.StatusOK, wantNames: []string{"cpu-high"}},
+		{name: "no match", query: "q=disk", wantStatus: http.StatusOK, wantNames: []string{}},
+		{name: "missing q", query: "", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := server.do(t, http.MethodGet, "/api/v1/alerts/autocomplete?"+tt.query, nil)
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Data []map[string]interface{} `json:"data"`
+			}
+			decodeBody(t, recorder, &body)
+			names := []string{}
+			for _, item := range body.Data {
+				names = append(names, item["name"].(string))
+				if len(item) != 3 {
+					t.Errorf("suggestion %v should only contain id, name and display_name", item)
+				}
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tt.wantNames) {
+				t.Errorf("names = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestCreateAlertSetsLocation(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	recorder := server.do(t, http.MethodPost, "/api/v1/alerts", testAlertBody("located"))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var created AlertDTO
+	decodeBody(t, recorder, &created)
+
+	location := recorder.Header().Get("Location")
+	if want := fmt.Sprintf("/api/v1/alerts/%d", created.ID); location != want {
+		t.Fatalf("Location = %q, want %q", location, want)
+	}
+	if got := server.do(t, http.MethodGet, location, nil); got.Code != http.StatusOK {
+		t.Errorf("GET %s status = %d, want 200", location, got.Code)
+	}
+
+	// 创建失败时不返回 Location
+	conflict := server.do(t, http.MethodPost, "/api/v1/alerts", testAlertBody("located"))
+	if conflict.Code != http.StatusConflict {
+		t.Fatalf("duplicate create status = %d, want 409", conflict.Code)
+	}
+	if location := conflict.Header().Get("Location"); location != "" {
+		t.Errorf("Location = %q on a failed create", location)
+	}
+}
+
+func TestExportAlertsByNames(t *testing.T) {
+	server := newTestServer(t, nil)
+	for _, name := range []string{"cpu", "mem", "disk"} {
+		server.createTestAlert(t, name)
+	}
+
+	tests := []struct {
+		name        string
+		body        map[string]interface{}
+		wantStatus  int
+		wantNames   []string
+		wantMissing []string
+	}{
+		{
+			name:        "found and missing, duplicates ignored",
+			body:        map[string]interface{}{"names": []string{"mem", "nope", "cpu", "mem", "gone"}},
+			wantStatus:  http.StatusOK,
+			wantNames:   []string{"cpu", "mem"},
+			wantMissing: []string{"nope", "gone"},
+		},
+		{
+			name:        "all missing",
+			body:        map[string]interface{}{"names": []string{"nope"}, "format": "json"},
+			wantStatus:  http.StatusOK,
+			wantNames:   []string{},
+			wantMissing: []string{"nope"},
+		},
+		{name: "empty names", body: map[string]interface{}{"names": []string{}}, wantStatus: http.StatusBadRequest},
+		{name: "unknown format", body: map[string]interface{}{"names": []string{"cpu"}, "format": "xml"}, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := server.do(t, http.MethodPost, "/api/v1/alerts/export", tt.body)
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Alerts []struct {
+					Name  string        `json:"name"`
+					Tags  []interface{} `json:"tags"`
+					Query []interface{} `json:"queries"`
+				} `json:"alerts"`
+				Count   int      `json:"count"`
+				Missing []string `json:"missing"`
+			}
+			decodeBody(t, recorder, &body)
+			names := []string{}
+			for _, alert := range body.Alerts {
+				names = append(names, alert.Name)
+				if len(alert.Tags) == 0 || len(alert.Query) == 0 {
+					t.Errorf("exported %s without nested data", alert.Name)
+				}
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tt.wantNames) || body.Count != len(tt.wantNames) {
+				t.Errorf("alerts = %v (count %d), want %v", names, body.Count, tt.wantNames)
+			}
+			if fmt.Sprint(body.Missing) != fmt.Sprint(tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", body.Missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestExportAlertsByNamesYAML(t *testing.T) {
+	server := newTestServer(t, nil)
+	server.createTestAlert(t, "cpu")
+
+	recorder := server.do(t, http.MethodPost, "/api/v1/alerts/export", map[string]interface{}{"names": []string{"cpu", "nope"}, "format": "yaml"})
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if contentType := recorder.Header().Get("Content-Type"); !strings.Contains(contentType, "yaml") {
+		t.Errorf("Content-Type = %q, want yaml", contentType)
+	}
+	body := recorder.Body.String()
+	for _, want := range []string{"name: cpu", "missing:", "- nope", "count: 1"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("yaml export missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestListAlertsSyncedBefore(t *testing.T) {
+	server := newTestServer(t, nil)
+	server.createTestAlert(t, "stale")
+	server.createTestAlert(t, "fresh")
+	if err := server.alertStore.MarkSynced(context.Background(), "stale", service.SyncDirectionSLSToDB, time.Now().Add(-48*time.Hour)); err != nil {
+		t.Fatalf("MarkSynced: %v", err)
+	}
+	if err := server.alertStore.MarkSynced(context.Background(), "fresh", service.SyncDirectionDBToSLS, time.Now()); err != nil {
+		t.Fatalf("MarkSynced: %v", err)
+	}
+	dayAgo := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantNames  []string
+	}{
+		{name: "rfc3339", query: "synced_before=" + url.QueryEscape(dayAgo.Format(time.RFC3339)), wantStatus: http.StatusOK, wantNames: []string{"stale"}},
+		{name: "unix seconds", query: fmt.Sprintf("synced_before=%d", dayAgo.Unix()), wantStatus: http.StatusOK, wantNames: []string{"stale"}},
+		{name: "invalid", query: "synced_before=yesterday", wantStatus: http.StatusBadRequest},
+		{name: "combined with tag", query: "synced_before=1&tag=team=ops", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := server.do(t, http.MethodGet, "/api/v1/alerts?"+tt.query, nil)
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Data []AlertDTO `json:"data"`
+			}
+			decodeBody(t, recorder, &body)
+			names := []string{}
+			for _, alert := range body.Data {
+				names = append(names, alert.Name)
+				if alert.LastSyncedAt == nil || alert.LastSyncDirection == nil {
+					t.Errorf("%s response is missing last sync fields", alert.Name)
+				}
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tt.wantNames) {
+				t.Errorf("names = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestGetAlertGraph(t *testing.T) {
+	server := newTestServer(t, nil)
+	server.createTestAlert(t, "cpu")
+	server.createTestAlert(t, "mem")
+
+	recorder := server.do(t, http.MethodGet, "/api/v1/alerts/graph", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	var graph service.AlertGraph
+	decodeBody(t, recorder, &graph)
+	// 两个 Alert 查询同一个日志库，共享一个日志库节点
+	if len(graph.Nodes) != 3 {
+		t.Errorf("nodes = %+v, want two alerts and one shared store", graph.Nodes)
+	}
+	want := []service.GraphEdge{
+		{Source: "alert:cpu", Target: "store:log:/app-log", Type: service.GraphEdgeQueriesStore},
+		{Source: "alert:mem", Target: "store:log:/app-log", Type: service.GraphEdgeQueriesStore},
+	}
+	if fmt.Sprint(graph.Edges) != fmt.Sprint(want) {
+		t.Errorf("edges = %+v, want %+v", graph.Edges, want)
+	}
+}
+
+// failingPageService 第 failAt 次读取分页时返回错误，其余调用转发给真实的 AlertService
+type failingPageService struct {
+	service.AlertService
+	failAt int
+	calls  int
+}
+
+func (s *failingPageService) ListAlertsAfterID(ctx context.Context, afterID uint, status string, pageSize int) ([]*models.Alert, error) {
+	s.calls++
+	if s.calls == s.failAt {
+		return nil, errors.New("database went away")
+	}
+	return s.AlertService.ListAlertsAfterID(ctx, afterID, status, pageSize)
+}
+
+func TestStreamExportAlerts(t *testing.T) {
+	server := newTestServer(t, nil)
+	const total = 250
+	for i := 0; i < total; i++ {
+		if err := server.alertService.CreateAlert(context.Background(), &models.Alert{
+			Name:        fmt.Sprintf("alert-%03d", i),
+			DisplayName: fmt.Sprintf("Alert %d", i),
+			Status:      service.AlertStatusEnabled,
+		}); err != nil {
+			t.Fatalf("CreateAlert: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		failAt    int // 0 表示不注入错误
+		query     string
+		wantCount int // 不含错误标记的元素数量
+	}{
+		{name: "all pages", query: "", wantCount: total},
+		{name: "small pages", query: "page_size=7", wantCount: total},
+		{name: "resume after id", query: "after_id=200", wantCount: total - 200},
+		{name: "error on second page", failAt: 2, query: "", wantCount: 100},
+		{name: "error on first page", failAt: 1, query: "", wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := server.router
+			if tt.failAt > 0 {
+				handler := NewAlertHandler(&failingPageService{AlertService: server.alertService, failAt: tt.failAt}, nil, &server.cfg.API)
+				router = gin.New()
+				router.GET("/api/v1/alerts/export", handler.StreamExportAlerts)
+			}
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/alerts/export?"+tt.query, nil))
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", recorder.Code, recorder.Body.String())
+			}
+			if !json.Valid(recorder.Body.Bytes()) {
+				t.Fatalf("streamed body is not valid JSON: %.200s", recorder.Body.String())
+			}
+
+			var elements []map[string]interface{}
+			decodeBody(t, recorder, &elements)
+			alerts := elements
+			if tt.failAt > 0 {
+				if len(elements) == 0 {
+					t.Fatalf("missing trailing error marker")
+				}
+				marker := elements[len(elements)-1]
+				alerts = elements[:len(elements)-1]
+				if marker["error"] != "database went away" {
+					t.Errorf("error marker = %v, want the page error", marker)
+				}
+				wantResume := float64(0)
+				if len(alerts) > 0 {
+					wantResume = alerts[len(alerts)-1]["id"].(float64)
+				}
+				if marker["resume_after_id"] != wantResume {
+					t.Errorf("resume_after_id = %v, want %v", marker["resume_after_id"], wantResume)
+				}
+			}
+			if len(alerts) != tt.wantCount {
+				t.Fatalf("alerts = %d, want %d", len(alerts), tt.wantCount)
+			}
+			for i := 1; i < len(alerts); i++ {
+				if alerts[i]["id"].(float64) <= alerts[i-1]["id"].(float64) {
+					t.Fatalf("alerts are not in ascending ID order at %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestFrozenAlertIsRejected(t *testing.T) {
+	server := newTestServer(t, nil)
+	created := server.createTestAlert(t, "frozen")
+	base := fmt.Sprintf("/api/v1/alerts/%d", created.ID)
+	until := time.Now().Add(time.Hour).Unix()
+
+	recorder := server.do(t, http.MethodPost, base+"/freeze", map[string]int64{"until": until})
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("freeze: status %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var frozen struct {
+		FreezeUntil *int64 `json:"freeze_until"`
+	}
+	decodeBody(t, recorder, &frozen)
+	if frozen.FreezeUntil == nil || *frozen.FreezeUntil != until {
+		t.Fatalf("freeze_until = %v, want %d", frozen.FreezeUntil, until)
+	}
+
+	update := testAlertBody("frozen")
+	update["display_name"] = "changed during freeze"
+	mutations := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+	}{
+		{name: "update", method: http.MethodPut, path: base, body: update},
+		{name: "patch", method: http.MethodPatch, path: base, body: map[string]interface{}{"display_name": "patched"}},
+		{name: "disable", method: http.MethodPost, path: base + "/disable"},
+		{name: "delete", method: http.MethodDelete, path: base},
+	}
+	for _, m := range mutations {
+		t.Run(m.name, func(t *testing.T) {
+			recorder := server.do(t, m.method, m.path, m.body)
+			if recorder.Code != http.StatusConflict {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusConflict, recorder.Body.String())
+			}
+			var body map[string]interface{}
+			decodeBody(t, recorder, &body)
+			if body["error"] != "Alert is frozen" || !strings.Contains(fmt.Sprint(body["message"]), "is frozen until") {
+				t.Errorf("body = %v, want an Alert is frozen error", body)
+			}
+		})
+	}
+
+	current, err := server.alertService.GetAlertByID(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("GetAlertByID: %v", err)
+	}
+	if current.DisplayName != "Alert frozen" || current.Status != service.AlertStatusEnabled {
+		t.Errorf("frozen alert was modified: display_name=%q status=%q", current.DisplayName, current.Status)
+	}
+
+	// 截止时间不晚于当前时间时解除冻结，之后可以正常更新
+	recorder = server.do(t, http.MethodPost, base+"/freeze", map[string]int64{"until": 0})
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("unfreeze: status %d: %s", recorder.Code, recorder.Body.String())
+	}
+	decodeBody(t, recorder, &frozen)
+	if frozen.FreezeUntil != nil {
+		t.Errorf("freeze_until = %d after unfreeze, want null", *frozen.FreezeUntil)
+	}
+	if recorder := server.do(t, http.MethodPut, base, update); recorder.Code != http.StatusOK {
+		t.Errorf("update after unfreeze: status %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+// backdateAlerts 把所有 Alert 的 updated_at 和审计时间改为 at，使之后的修改可以在秒级的 Last-Modified 中区分
+func backdateAlerts(t *testing.T, at time.Time) {
+	t.Helper()
+
+	if err := database.DB.Exec("UPDATE alerts SET updated_at = ?", at).Error; err != nil {
+		t.Fatalf("backdate alerts: %v", err)
+	}
+	if err := database.DB.Exec("UPDATE alert_audit_logs SET created_at = ?", at).Error; err != nil {
+		t.Fatalf("backdate audit logs: %v", err)
+	}
+}
+
+func TestListAlertsNotModified(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	recorder := server.do(t, http.MethodGet, "/api/v1/alerts", nil)
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Last-Modified") != "" {
+		t.Fatalf("empty list: status %d, Last-Modified %q, want 200 without header", recorder.Code, recorder.Header().Get("Last-Modified"))
+	}
+
+	server.createTestAlert(t, "a")
+	server.createTestAlert(t, "b")
+	base := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	backdateAlerts(t, base)
+
+	recorder = server.do(t, http.MethodGet, "/api/v1/alerts", nil)
+	lastModified := recorder.Header().Get("Last-Modified")
+	if recorder.Code != http.StatusOK || lastModified != base.Format(http.TimeFormat) {
+		t.Fatalf("status %d, Last-Modified %q, want 200 with %q", recorder.Code, lastModified, base.Format(http.TimeFormat))
+	}
+
+	tests := []struct {
+		name       string
+		since      string
+		wantStatus int
+	}{
+		{name: "unchanged", since: lastModified, wantStatus: http.StatusNotModified},
+		{name: "later than last change", since: base.Add(time.Minute).Format(http.TimeFormat), wantStatus: http.StatusNotModified},
+		{name: "earlier than last change", since: base.Add(-time.Second).Format(http.TimeFormat), wantStatus: http.StatusOK},
+		{name: "invalid header", since: "yesterday", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := server.do(t, http.MethodGet, "/api/v1/alerts", nil, "If-Modified-Since", tt.since)
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusNotModified && recorder.Body.Len() != 0 {
+				t.Errorf("304 body = %q, want empty", recorder.Body.String())
+			}
+			if got := recorder.Header().Get("Last-Modified"); got != lastModified {
+				t.Errorf("Last-Modified = %q, want %q", got, lastModified)
+			}
+		})
+	}
+}
+
+func TestListAlertsModifiedAfterChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *testing.T, server *testServer, alert *AlertDTO) *httptest.ResponseRecorder
+	}{
+		{name: "tag added", change: func(t *testing.T, server *testServer, alert *AlertDTO) *httptest.ResponseRecorder {
+			return server.do(t, http.MethodPost, fmt.Sprintf("/api/v1/alerts/%d/tags", alert.ID), map[string]string{"tag_type": "label", "tag_key": "env", "tag_value": "prod"})
+		}},
+		{name: "tag deleted", change: func(t *testing.T, server *testServer, alert *AlertDTO) *httptest.ResponseRecorder {
+			return server.do(t, http.MethodDelete, fmt.Sprintf("/api/v1/alerts/%d/tags/%d", alert.ID, alert.Tags[0].ID), nil)
+		}},
+		{name: "soft deleted", change: func(t *testing.T, server *testServer, alert *AlertDTO) *httptest.ResponseRecorder {
+			return server.do(t, http.MethodDelete, fmt.Sprintf("/api/v1/alerts/%d", alert.ID), nil)
+		}},
+		{name: "hard deleted", change: func(t *testing.T, server *testServer, alert *AlertDTO) *httptest.ResponseRecorder {
+			return server.do(t, http.MethodDelete, fmt.Sprintf("/api/v1/alerts/%d?hard=true", alert.ID), nil)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+			server.createTestAlert(t, "kept")
+			alert := server.createTestAlert(t, "changed")
+			backdateAlerts(t, time.Now().Add(-time.Hour))
+
+			lastModified := server.do(t, http.MethodGet, "/api/v1/alerts", nil).Header().Get("Last-Modified")
+			if lastModified == "" {
+				t.Fatal("missing Last-Modified")
+			}
+
+			if recorder := tt.change(t, server, alert); recorder.Code >= http.StatusBadRequest {
+				t.Fatalf("change status = %d: %s", recorder.Code, recorder.Body.String())
+			}
+
+			recorder := server.do(t, http.MethodGet, "/api/v1/alerts", nil, "If-Modified-Since", lastModified)
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 after the change", recorder.Code)
+			}
+			if got := recorder.Header().Get("Last-Modified"); got == lastModified {
+				t.Errorf("Last-Modified = %q, want it to advance", got)
+			}
+		})
+	}
+}
+
+func TestCreateAlertInvalidSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule map[string]interface{}
+		want     int
+	}{
+		{name: "valid cron", schedule: map[string]interface{}{"type": "Cron", "cron_expression": "0 */2 * * *"}, want: http.StatusCreated},
+		{name: "malformed cron", schedule: map[string]interface{}{"type": "Cron", "cron_expression": "0 */2 * *"}, want: http.StatusBadRequest},
+		{name: "invalid interval", schedule: map[string]interface{}{"type": "FixedRate", "interval": "often"}, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+
+			body := testAlertBody("scheduled")
+			body["schedule"] = tt.schedule
+			recorder := server.do(t, http.MethodPost, "/api/v1/alerts", body)
+			if recorder.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, tt.want, recorder.Body.String())
+			}
+		})
+	}
+}
+
+// stubStatusSLSService 记录状态推送的 SLSService，failNames 中的 Alert 推送失败，只实现 SetAlertStatus
+type stubStatusSLSService struct {
+	service.SLSService
+	failNames map[string]bool
+	pushed    map[string]string
+}
+
+func (s *stubStatusSLSService) SetAlertStatus(ctx context.Context, name, status string) error {
+	if s.failNames[name] {
+		return fmt.Errorf("failed to set alert status in SLS: %w", service.ErrSLSUnavailable)
+	}
+	s.pushed[name] = status
+	return nil
+}
+
+func TestBulkSetAlertStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		push       bool
+		wantPushed map[string]string
+		wantItems  map[string]BulkAlertStatusItem // 以 Alert 名称为键，不存在的 ID 以 "missing" 为键
+	}{
+		{
+			name:       "database only",
+			wantPushed: map[string]string{},
+			wantItems: map[string]BulkAlertStatusItem{
+				"plain":       {Status: BulkStatusUpdated},
+				"sls-down":    {Status: BulkStatusUpdated},
+				"frozen-bulk": {Status: BulkStatusSkippedFrozen},
+				"missing":     {Status: BulkStatusError},
+			},
+		},
+		{
+			name:       "push",
+			push:       true,
+			wantPushed: map[string]string{"plain": service.AlertStatusDisabled},
+			wantItems: map[string]BulkAlertStatusItem{
+				"plain":       {Status: BulkStatusUpdated, Pushed: true},
+				"sls-down":    {Status: BulkStatusUpdated, PushError: "failed to set alert status in SLS"},
+				"frozen-bulk": {Status: BulkStatusSkippedFrozen},
+				"missing":     {Status: BulkStatusError},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+			sls := &stubStatusSLSService{failNames: map[string]bool{"sls-down": true}, pushed: map[string]string{}}
+			server.router = SetupRouter(NewAlertHandler(server.alertService, sls, &server.cfg.API), NewSLSHandler(nil, nil, nil), server.cfg)
+
+			plain := server.createTestAlert(t, "plain")
+			down := server.createTestAlert(t, "sls-down")
+			frozen := server.createTestAlert(t, "frozen-bulk")
+			freeze := server.do(t, http.MethodPost, fmt.Sprintf("/api/v1/alerts/%d/freeze", frozen.ID), map[string]int64{"until": time.Now().Add(time.Hour).Unix()})
+			if freeze.Code != http.StatusOK {
+				t.Fatalf("freeze: status %d: %s", freeze.Code, freeze.Body.String())
+			}
+			missingID := frozen.ID + 100
+
+			path := "/api/v1/alerts/status"
+			if tt.push {
+				path += "?push=true"
+			}
+			recorder := server.do(t, http.MethodPost, path, map[string]interface{}{
+				"ids":    []uint{plain.ID, down.ID, frozen.ID, missingID},
+				"status": service.AlertStatusDisabled,
+			})
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", recorder.Code, recorder.Body.String())
+			}
+			var result BulkAlertStatusResult
+			decodeBody(t, recorder, &result)
+
+			wantUpdated, wantPushed, wantPushFailed := 2, 0, 0
+			if tt.push {
+				wantPushed, wantPushFailed = 1, 1
+			}
+			if result.Total != 4 || result.Updated != wantUpdated || result.Skipped != 1 || result.Failed != 1 ||
+				result.Pushed != wantPushed || result.PushFailed != wantPushFailed {
+				t.Errorf("counts = %+v, want total 4, updated %d, skipped 1, failed 1, pushed %d, push_failed %d",
+					result, wantUpdated, wantPushed, wantPushFailed)
+			}
+			if len(result.Items) != 4 {
+				t.Fatalf("items = %+v, want one per ID", result.Items)
+			}
+
+			for _, item := range result.Items {
+				key := item.Name
+				if item.ID == missingID {
+					key = "missing"
+				}
+				want, ok := tt.wantItems[key]
+				if !ok {
+					t.Errorf("unexpected item %+v", item)
+					continue
+				}
+				if item.Status != want.Status || item.Pushed != want.Pushed || !strings.Contains(item.PushError, want.PushError) ||
+					(want.PushError == "" && item.PushError != "") {
+					t.Errorf("item %s = %+v, want %+v", key, item, want)
+				}
+				if (item.Status == BulkStatusUpdated) == (item.Error != "") {
+					t.Errorf("item %s error = %q, want an error only when not updated", key, item.Error)
+				}
+			}
+
+			if len(sls.pushed) != len(tt.wantPushed) {
+				t.Errorf("pushed = %v, want %v", sls.pushed, tt.wantPushed)
+			}
+			for name, status := range tt.wantPushed {
+				if sls.pushed[name] != status {
+					t.Errorf("pushed[%s] = %q, want %q", name, sls.pushed[name], status)
+				}
+			}
+
+			// 冻结的 Alert 保持原状态，其余 Alert 已在数据库中停用
+			for _, alert := range []*AlertDTO{plain, down, frozen} {
+				stored, err := server.alertService.GetAlertByID(context.Background(), alert.ID)
+				if err != nil {
+					t.Fatalf("GetAlertByID(%d): %v", alert.ID, err)
+				}
+				want := service.AlertStatusDisabled
+				if alert.ID == frozen.ID {
+					want = service.AlertStatusEnabled
+				}
+				if stored.Status != want {
+					t.Errorf("%s status = %s, want %s", alert.Name, stored.Status, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBulkSetAlertStatusRejectsInvalidRequests(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	tests := []struct {
+		name string
+		path string
+		body interface{}
+		want int
+	}{
+		{name: "empty ids", path: "/api/v1/alerts/status", body: map[string]interface{}{"ids": []uint{}, "status": "ENABLED"}, want: http.StatusBadRequest},
+		{name: "invalid status", path: "/api/v1/alerts/status", body: map[string]interface{}{"ids": []uint{1}, "status": "PAUSED"}, want: http.StatusBadRequest},
+		{name: "push without SLS", path: "/api/v1/alerts/status?push=true", body: map[string]interface{}{"ids": []uint{1}, "status": "ENABLED"}, want: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := server.do(t, http.MethodPost, tt.path, tt.body)
+			if recorder.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, tt.want, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,543 @@
+package handler
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/internal/service"
+)
+
+// AlertDTO Alert 的 API 表示，字段名与数据库模型解耦
+type AlertDTO struct {
+	ID                uint              `json:"id"`
+	Name              string            `json:"name"`
+	DisplayName       string            `json:"display_name"`
+	Description       *string           `json:"description"`
+	Status            string            `json:"status"`
+	CreateTime        *int64            `json:"create_time"`
+	LastModifiedTime  *int64            `json:"last_modified_time"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+	LastSyncedAt      *time.Time        `json:"last_synced_at"`
+	LastSyncDirection *string           `json:"last_sync_direction"`
+	FreezeUntil       *int64            `json:"freeze_until"`
+	DeletedAt         *time.Time        `json:"deleted_at,omitempty"`
+	Configuration     *ConfigurationDTO `json:"configuration"`
+	Schedule          *ScheduleDTO      `json:"schedule"`
+	Tags              []TagDTO          `json:"tags"`
+	Queries           []QueryDTO        `json:"queries"`
+
+	// 只用于更新请求：读取时的 last_modified_time，存储的值已变化时更新返回 409（值为空时传 0）
+	ExpectedLastModifiedTime *int64 `json:"expected_last_modified_time,omitempty"`
+}
+
+// ConfigurationDTO Alert 配置的 API 表示
+type ConfigurationDTO struct {
+	AutoAnnotation *bool         `json:"auto_annotation"`
+	Dashboard      *string       `json:"dashboard"`
+	MuteUntil      *int64        `json:"mute_until"`
+	FireOnNoData   *bool         `json:"fire_on_no_data"`
+	NoDataSeverity *int32        `json:"no_data_severity"`
+	Threshold      *int32        `json:"threshold"`
+	Type           *string       `json:"type"`
+	Version        *string       `json:"version"`
+	SendResolved   *bool         `json:"send_resolved"`
+	Condition      *ConditionDTO `json:"condition"`
+	Group          *GroupDTO     `json:"group"`
+	Policy         *PolicyDTO    `json:"policy"`
+	Template       *TemplateDTO  `json:"template"`
+	Severities     []SeverityDTO `json:"severities"`
+	Joins          []JoinDTO     `json:"joins"`
+	Sinks          *SinksDTO     `json:"sinks"`
+	// RawConfig 导入时 SLS 返回的完整配置，推送时作为基线保留模型未表示的字段
+	RawConfig json.RawMessage `json:"raw_config,omitempty"`
+}
+
+// ConditionDTO 条件配置的 API 表示
+type ConditionDTO struct {
+	Condition      *string `json:"condition"`
+	CountCondition *string `json:"count_condition"`
+}
+
+// GroupDTO 分组配置的 API 表示
+type GroupDTO struct {
+	Fields []string `json:"fields"`
+	Type   *string  `json:"type"`
+}
+
+// PolicyDTO 策略配置的 API 表示
+type PolicyDTO struct {
+	ActionPolicyID *string `json:"action_policy_id"`
+	AlertPolicyID  *string `json:"alert_policy_id"`
+	RepeatInterval *string `json:"repeat_interval"`
+}
+
+// TemplateDTO 模板配置的 API 表示，annotations 和 tokens 以 JSON 对象原样透传
+type TemplateDTO struct {
+	ID          *string         `json:"id"`
+	Lang        *string         `json:"lang"`
+	Type        *string         `json:"type"`
+	Version     *string         `json:"version"`
+	Annotations json.RawMessage `json:"annotations,omitempty"`
+	Tokens      json.RawMessage `json:"tokens,omitempty"`
+}
+
+// SeverityDTO 严重程度配置的 API 表示
+type SeverityDTO struct {
+	Severity  *int32        `json:"severity"`
+	Condition *ConditionDTO `json:"condition"`
+}
+
+// JoinDTO 关联配置的 API 表示
+type JoinDTO struct {
+	Type   *string         `json:"type"`
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// SinksDTO 告警投递配置的 API 表示
+type SinksDTO struct {
+	Alerthub   *SinkDTO           `json:"alerthub"`
+	Cms        *SinkDTO           `json:"cms"`
+	EventStore *EventStoreSinkDTO `json:"event_store"`
+}
+
+// SinkDTO 只有开关的投递配置
+type SinkDTO struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// EventStoreSinkDTO 事件存储投递配置的 API 表示
+type EventStoreSinkDTO struct {
+	Enabled    *bool   `json:"enabled"`
+	Endpoint   *string `json:"endpoint"`
+	EventStore *string `json:"event_store"`
+	Project    *string `json:"project"`
+	RoleArn    *string `json:"role_arn"`
+}
+
+// ScheduleDTO 调度配置的 API 表示
+type ScheduleDTO struct {
+	Type           string  `json:"type"`
+	CronExpression *string `json:"cron_expression"`
+	Delay          *int32  `json:"delay"`
+	Interval       *string `json:"interval"`
+	RunImmediately *bool   `json:"run_immediately"`
+	TimeZone       *string `json:"time_zone"`
+}
+
+// AlertPatchDTO 部分更新 Alert 的请求，只更新出现的字段
+type AlertPatchDTO struct {
+	DisplayName *string      `json:"display_name"`
+	Description *string      `json:"description"` // 空字符串表示清空描述
+	Status      *string      `json:"status"`
+	Schedule    *ScheduleDTO `json:"schedule"` // 整体替换调度配置
+}
+
+// toPatch 转换为服务层的部分更新
+func (d *AlertPatchDTO) toPatch() *service.AlertPatch {
+	patch := &service.AlertPatch{
+		DisplayName: d.DisplayName,
+		Description: d.Description,
+		Status:      d.Status,
+	}
+	if schedule := d.Schedule; schedule != nil {
+		patch.Schedule = &models.AlertSchedule{
+			Type:           schedule.Type,
+			CronExpression: schedule.CronExpression,
+			Delay:          schedule.Delay,
+			Interval:       schedule.Interval,
+			RunImmediately: schedule.RunImmediately,
+			TimeZone:       schedule.TimeZone,
+		}
+	}
+	return patch
+}
+
+// TagDTO 标签的 API 表示
+type TagDTO struct {
+	ID    uint    `json:"id"`
+	Type  string  `json:"type"`
+	Key   string  `json:"key"`
+	Value *string `json:"value"`
+}
+
+// QueryDTO 查询的 API 表示
+type QueryDTO struct {
+	ID           uint    `json:"id"`
+	Query        string  `json:"query"`
+	ChartTitle   *string `json:"chart_title"`
+	DashboardID  *string `json:"dashboard_id"`
+	Start        *string `json:"start"`
+	End          *string `json:"end"`
+	PowerSQL     *string `json:"power_sql"`
+	Project      *string `json:"project"`
+	Region       *string `json:"region"`
+	RoleArn      *string `json:"role_arn"`
+	Store        *string `json:"store"`
+	StoreType    *string `json:"store_type"`
+	TimeSpanType *string `json:"time_span_type"`
+	UI           *string `json:"ui"`
+}
+
+// toAlertDTO 将数据库模型转换为 API 表示
+func toAlertDTO(alert *models.Alert) *AlertDTO {
+	if alert == nil {
+		return nil
+	}
+
+	dto := &AlertDTO{
+		ID:                alert.ID,
+		Name:              alert.Name,
+		DisplayName:       alert.DisplayName,
+		Description:       alert.Description,
+		Status:            alert.Status,
+		CreateTime:        alert.CreateTime,
+		LastModifiedTime:  alert.LastModifiedTime,
+		CreatedAt:         alert.CreatedAt,
+		UpdatedAt:         alert.UpdatedAt,
+		LastSyncedAt:      alert.LastSyncedAt,
+		LastSyncDirection: alert.LastSyncDirection,
+		FreezeUntil:       alert.FreezeUntil,
+		Configuration:     toConfigurationDTO(alert.Configuration),
+		Tags:              make([]TagDTO, 0, len(alert.Tags)),
+		Queries:           make([]QueryDTO, 0, len(alert.Queries)),
+	}
+	if alert.DeletedAt.Valid {
+		deletedAt := alert.DeletedAt.Time
+		dto.DeletedAt = &deletedAt
+	}
+
+	if schedule := alert.Schedule; schedule != nil {
+		dto.Schedule = &ScheduleDTO{
+			Type:           schedule.Type,
+			CronExpression: schedule.CronExpression,
+			Delay:          schedule.Delay,
+			Interval:       schedule.Interval,
+			RunImmediately: schedule.RunImmediately,
+			TimeZone:       schedule.TimeZone,
+		}
+	}
+
+	for _, tag := range alert.Tags {
+		dto.Tags = append(dto.Tags, TagDTO{
+			ID:    tag.ID,
+			Type:  tag.TagType,
+			Key:   tag.TagKey,
+			Value: tag.TagValue,
+		})
+	}
+
+	for _, query := range alert.Queries {
+		dto.Queries = append(dto.Queries, QueryDTO{
+			ID:           query.ID,
+			Query:        query.Query,
+			ChartTitle:   query.ChartTitle,
+			DashboardID:  query.DashboardId,
+			Start:        query.Start,
+			End:          query.End,
+			PowerSQL:     query.PowerSqlMode,
+			Project:      query.Project,
+			Region:       query.Region,
+			RoleArn:      query.RoleArn,
+			Store:        query.Store,
+			StoreType:    query.StoreType,
+			TimeSpanType: query.TimeSpanType,
+			UI:           query.Ui,
+		})
+	}
+
+	return dto
+}
+
+// toAlertDTOs 批量转换数据库模型
+func toAlertDTOs(alerts []*models.Alert) []*AlertDTO {
+	dtos := make([]*AlertDTO, 0, len(alerts))
+	for _, alert := range alerts {
+		dtos = append(dtos, toAlertDTO(alert))
+	}
+	return dtos
+}
+
+// toConfigurationDTO 将配置模型转换为 API 表示
+func toConfigurationDTO(config *models.AlertConfiguration) *ConfigurationDTO {
+	if config == nil {
+		return nil
+	}
+
+	dto := &ConfigurationDTO{
+		AutoAnnotation: config.AutoAnnotation,
+		Dashboard:      config.Dashboard,
+		MuteUntil:      config.MuteUntil,
+		FireOnNoData:   config.NoDataFire,
+		NoDataSeverity: config.NoDataSeverity,
+		Threshold:      config.Threshold,
+		Type:           config.Type,
+		Version:        config.Version,
+		SendResolved:   config.SendResolved,
+		Condition:      toConditionDTO(config.ConditionConfig),
+		RawConfig:      rawJSON(config.RawConfig),
+	}
+
+	if group := config.GroupConfig; group != nil {
+		dto.Group = &GroupDTO{Type: group.Type, Fields: group.Fields}
+	}
+
+	if policy := config.PolicyConfig; policy != nil {
+		dto.Policy = &PolicyDTO{
+			ActionPolicyID: policy.ActionPolicyId,
+			AlertPolicyID:  policy.AlertPolicyId,
+			RepeatInterval: policy.RepeatInterval,
+		}
+	}
+
+	if template := config.TemplateConfig; template != nil {
+		dto.Template = &TemplateDTO{
+			ID:          template.TemplateId,
+			Lang:        template.Lang,
+			Type:        template.Type,
+			Version:     template.Version,
+			Annotations: rawJSON(template.Aonotations),
+			Tokens:      rawJSON(template.Tokens),
+		}
+	}
+
+	for _, severity := range config.SeverityConfigs {
+		dto.Severities = append(dto.Severities, SeverityDTO{
+			Severity:  severity.Severity,
+			Condition: toConditionDTO(severity.EvalCondition),
+		})
+	}
+
+	for _, join := range config.JoinConfigs {
+		dto.Joins = append(dto.Joins, JoinDTO{
+			Type:   join.JoinType,
+			Config: rawJSON(join.JoinConfig),
+		})
+	}
+
+	if config.SinkAlerthubConfig != nil || config.SinkCmsConfig != nil || config.SinkEventStoreConfig != nil {
+		dto.Sinks = &SinksDTO{}
+		if config.SinkAlerthubConfig != nil {
+			dto.Sinks.Alerthub = &SinkDTO{Enabled: config.SinkAlerthubConfig.Enabled}
+		}
+		if config.SinkCmsConfig != nil {
+			dto.Sinks.Cms = &SinkDTO{Enabled: config.SinkCmsConfig.Enabled}
+		}
+		if eventStore := config.SinkEventStoreConfig; eventStore != nil {
+			dto.Sinks.EventStore = &EventStoreSinkDTO{
+				Enabled:    eventStore.Enabled,
+				Endpoint:   eventStore.Endpoint,
+				EventStore: eventStore.EventStore,
+				Project:    eventStore.Project,
+				RoleArn:    eventStore.RoleArn,
+			}
+		}
+	}
+
+	return dto
+}
+
+// toConditionDTO 将条件模型转换为 API 表示
+func toConditionDTO(condition *models.ConditionConfiguration) *ConditionDTO {
+	if condition == nil {
+		return nil
+	}
+	return &ConditionDTO{
+		Condition:      condition.Condition,
+		CountCondition: condition.CountCondition,
+	}
+}
+
+// toModel 将 API 表示转换为数据库模型，只读字段（时间戳、同步信息、冻结状态）不会被带入
+func (d *AlertDTO) toModel() *models.Alert {
+	alert := &models.Alert{
+		ID:               d.ID,
+		Name:             d.Name,
+		DisplayName:      d.DisplayName,
+		Description:      d.Description,
+		Status:           d.Status,
+		CreateTime:       d.CreateTime,
+		LastModifiedTime: d.LastModifiedTime,
+		Configuration:    d.Configuration.toModel(),
+
+		ExpectedLastModifiedTime: d.ExpectedLastModifiedTime,
+	}
+
+	if schedule := d.Schedule; schedule != nil {
+		alert.Schedule = &models.AlertSchedule{
+			Type:           schedule.Type,
+			CronExpression: schedule.CronExpression,
+			Delay:          schedule.Delay,
+			Interval:       schedule.Interval,
+			RunImmediately: schedule.RunImmediately,
+			TimeZone:       schedule.TimeZone,
+		}
+	}
+
+	for _, tag := range d.Tags {
+		alert.Tags = append(alert.Tags, models.AlertTag{
+			TagType:  tag.Type,
+			TagKey:   tag.Key,
+			TagValue: tag.Value,
+		})
+	}
+
+	for _, query := range d.Queries {
+		alert.Queries = append(alert.Queries, models.AlertQuery{
+			Query:        query.Query,
+			ChartTitle:   query.ChartTitle,
+			DashboardId:  query.DashboardID,
+			Start:        query.Start,
+			End:          query.End,
+			PowerSqlMode: query.PowerSQL,
+			Project:      query.Project,
+			Region:       query.Region,
+			RoleArn:      query.RoleArn,
+			Store:        query.Store,
+			StoreType:    query.StoreType,
+			TimeSpanType: query.TimeSpanType,
+			Ui:           query.UI,
+		})
+	}
+
+	return alert
+}
+
+// toModel 将配置的 API 表示转换为数据库模型
+func (d *ConfigurationDTO) toModel() *models.AlertConfiguration {
+	if d == nil {
+		return nil
+	}
+
+	config := &models.AlertConfiguration{
+		AutoAnnotation:  d.AutoAnnotation,
+		Dashboard:       d.Dashboard,
+		MuteUntil:       d.MuteUntil,
+		NoDataFire:      d.FireOnNoData,
+		NoDataSeverity:  d.NoDataSeverity,
+		Threshold:       d.Threshold,
+		Type:            d.Type,
+		Version:         d.Version,
+		SendResolved:    d.SendResolved,
+		ConditionConfig: d.Condition.toModel(),
+		RawConfig:       jsonString(d.RawConfig),
+	}
+
+	if group := d.Group; group != nil {
+		config.GroupConfig = &models.GroupConfiguration{Type: group.Type, Fields: group.Fields}
+	}
+
+	if policy := d.Policy; policy != nil {
+		config.PolicyConfig = &models.PolicyConfiguration{
+			ActionPolicyId: policy.ActionPolicyID,
+			AlertPolicyId:  policy.AlertPolicyID,
+			RepeatInterval: policy.RepeatInterval,
+		}
+	}
+
+	if template := d.Template; template != nil {
+		config.TemplateConfig = &models.TemplateConfiguration{
+			TemplateId:  template.ID,
+			Lang:        template.Lang,
+			Type:        template.Type,
+			Version:     template.Version,
+			Aonotations: jsonString(template.Annotations),
+			Tokens:      jsonString(template.Tokens),
+		}
+	}
+
+	for _, severity := range d.Severities {
+		config.SeverityConfigs = append(config.SeverityConfigs, models.SeverityConfiguration{
+			Severity:      severity.Severity,
+			EvalCondition: severity.Condition.toModel(),
+		})
+	}
+
+	for _, join := range d.Joins {
+		config.JoinConfigs = append(config.JoinConfigs, models.JoinConfiguration{
+			JoinType:   join.Type,
+			JoinConfig: jsonString(join.Config),
+		})
+	}
+
+	if sinks := d.Sinks; sinks != nil {
+		if sinks.Alerthub != nil {
+			config.SinkAlerthubConfig = &models.SinkAlerthubConfiguration{Enabled: sinks.Alerthub.Enabled}
+		}
+		if sinks.Cms != nil {
+			config.SinkCmsConfig = &models.SinkCmsConfiguration{Enabled: sinks.Cms.Enabled}
+		}
+		if eventStore := sinks.EventStore; eventStore != nil {
+			config.SinkEventStoreConfig = &models.SinkEventStoreConfiguration{
+				Enabled:    eventStore.Enabled,
+				Endpoint:   eventStore.Endpoint,
+				EventStore: eventStore.EventStore,
+				Project:    eventStore.Project,
+				RoleArn:    eventStore.RoleArn,
+			}
+		}
+	}
+
+	return config
+}
+
+// toModel 将条件的 API 表示转换为数据库模型
+func (d *ConditionDTO) toModel() *models.ConditionConfiguration {
+	if d == nil {
+		return nil
+	}
+	return &models.ConditionConfiguration{
+		Condition:      d.Condition,
+		CountCondition: d.CountCondition,
+	}
+}
+
+// AuditLogDTO 审计记录的 API 表示，快照以 JSON 对象而不是字符串输出
+type AuditLogDTO struct {
+	ID         uint            `json:"id"`
+	AlertID    uint            `json:"alert_id"`
+	Action     string          `json:"action"`
+	Actor      string          `json:"actor"`
+	BeforeJSON json.RawMessage `json:"before_json"`
+	AfterJSON  json.RawMessage `json:"after_json"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// toAuditLogDTOs 批量转换审计记录
+func toAuditLogDTOs(logs []models.AlertAuditLog) []AuditLogDTO {
+	dtos := make([]AuditLogDTO, 0, len(logs))
+	for _, log := range logs {
+		dtos = append(dtos, AuditLogDTO{
+			ID:         log.ID,
+			AlertID:    log.AlertID,
+			Action:     log.Action,
+			Actor:      log.Actor,
+			BeforeJSON: rawJSON(log.BeforeJSON),
+			AfterJSON:  rawJSON(log.AfterJSON),
+			CreatedAt:  log.CreatedAt,
+		})
+	}
+	return dtos
+}
+
+// rawJSON 将数据库中存储的 JSON 字符串作为原始 JSON 输出，非法内容按普通字符串输出
+func rawJSON(value *string) json.RawMessage {
+	if value == nil || *value == "" {
+		return nil
+	}
+	if json.Valid([]byte(*value)) {
+		return json.RawMessage(*value)
+	}
+	quoted, _ := json.Marshal(*value)
+	return quoted
+}
+
+// jsonString 将原始 JSON 转换为数据库存储的字符串
+func jsonString(raw json.RawMessage) *string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	value := string(raw)
+	return &value
+}
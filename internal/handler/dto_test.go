@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// apiShapeBody 使用给定键名创建 Alert 的请求体，key 把 snake_case 键转换为对应风格
+func apiShapeBody(name string, key func(string) string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":              name,
+		key("display_name"): "Alert " + name,
+		"status":            "ENABLED",
+		"configuration": map[string]interface{}{
+			"threshold":            1,
+			"type":                 "default",
+			"version":              "2.0",
+			key("fire_on_no_data"): true,
+			"condition":            map[string]interface{}{"condition": "cnt > 0"},
+			"template": map[string]interface{}{
+				"annotations": map[string]interface{}{"summary_text": "cpu high"},
+			},
+		},
+		"schedule": map[string]interface{}{"type": "FixedRate", "interval": "1m"},
+		"queries": []map[string]interface{}{
+			{"query": "* | select count(*) as cnt", "store": "app-log", key("power_sql"): "enable"},
+		},
+	}
+}
+
+// assertAPIShape 检查 Alert 的 JSON 使用 API 字段名，且不暴露数据库模型的字段名
+func assertAPIShape(t *testing.T, alert map[string]interface{}, key func(string) string) {
+	t.Helper()
+
+	if alert[key("display_name")] == nil {
+		t.Errorf("missing %s: %v", key("display_name"), alert)
+	}
+	configuration, _ := alert["configuration"].(map[string]interface{})
+	if configuration[key("fire_on_no_data")] != true {
+		t.Errorf("configuration.%s = %v, want true", key("fire_on_no_data"), configuration[key("fire_on_no_data")])
+	}
+	template, _ := configuration["template"].(map[string]interface{})
+	// annotations 是用户自定义 JSON，内部的键不随命名风格转换
+	if want := map[string]interface{}{"summary_text": "cpu high"}; !reflect.DeepEqual(template["annotations"], want) {
+		t.Errorf("template.annotations = %v, want %v", template["annotations"], want)
+	}
+	queries, _ := alert["queries"].([]interface{})
+	if len(queries) != 1 {
+		t.Fatalf("queries = %v, want one", alert["queries"])
+	}
+	query := queries[0].(map[string]interface{})
+	if query[key("power_sql")] != "enable" {
+		t.Errorf("queries[0].%s = %v, want enable", key("power_sql"), query[key("power_sql")])
+	}
+
+	for _, internal := range []string{"no_data_fire", "noDataFire", "template_config", "templateConfig", "aonotations"} {
+		if _, ok := configuration[internal]; ok {
+			t.Errorf("configuration exposes internal field %q", internal)
+		}
+		if _, ok := template[internal]; ok {
+			t.Errorf("template exposes internal field %q", internal)
+		}
+	}
+	for _, internal := range []string{"power_sql_mode", "powerSqlMode", "alert_id", "alertId"} {
+		if _, ok := query[internal]; ok {
+			t.Errorf("query exposes internal field %q", internal)
+		}
+	}
+}
+
+func TestAlertAPIFieldNames(t *testing.T) {
+	tests := []struct {
+		fieldCase string
+		key       func(string) string
+	}{
+		{fieldCase: FieldCaseSnake, key: func(key string) string { return key }},
+		{fieldCase: FieldCaseCamel, key: snakeToCamel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fieldCase, func(t *testing.T) {
+			server := newTestServer(t, func(cfg *config.Config) { cfg.API.FieldCase = tt.fieldCase })
+
+			recorder := server.do(t, http.MethodPost, "/api/v1/alerts", apiShapeBody("shape", tt.key))
+			if recorder.Code != http.StatusCreated {
+				t.Fatalf("create status = %d: %s", recorder.Code, recorder.Body.String())
+			}
+			var created map[string]interface{}
+			decodeBody(t, recorder, &created)
+			assertAPIShape(t, created, tt.key)
+
+			// 请求体中的 API 字段名映射到数据库模型
+			stored, err := server.alertStore.GetByName(context.Background(), "shape")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+			if !tea.BoolValue(stored.Configuration.NoDataFire) {
+				t.Errorf("stored no_data_fire = %v, want true", stored.Configuration.NoDataFire)
+			}
+			if got := tea.StringValue(stored.Queries[0].PowerSqlMode); got != "enable" {
+				t.Errorf("stored power_sql_mode = %q, want enable", got)
+			}
+
+			recorder = server.do(t, http.MethodGet, fmt.Sprintf("/api/v1/alerts/%d", stored.ID), nil)
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("get status = %d: %s", recorder.Code, recorder.Body.String())
+			}
+			var fetched map[string]interface{}
+			decodeBody(t, recorder, &fetched)
+			assertAPIShape(t, fetched, tt.key)
+
+			update := apiShapeBody("shape", tt.key)
+			update[tt.key("display_name")] = "Renamed"
+			recorder = server.do(t, http.MethodPut, fmt.Sprintf("/api/v1/alerts/%d", stored.ID), update)
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("update status = %d: %s", recorder.Code, recorder.Body.String())
+			}
+			var updated map[string]interface{}
+			decodeBody(t, recorder, &updated)
+			assertAPIShape(t, updated, tt.key)
+			if updated[tt.key("display_name")] != "Renamed" {
+				t.Errorf("updated %s = %v, want Renamed", tt.key("display_name"), updated[tt.key("display_name")])
+			}
+
+			recorder = server.do(t, http.MethodGet, "/api/v1/alerts", nil)
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("list status = %d: %s", recorder.Code, recorder.Body.String())
+			}
+			var list struct {
+				Data       []map[string]interface{} `json:"data"`
+				Pagination map[string]interface{}   `json:"pagination"`
+			}
+			decodeBody(t, recorder, &list)
+			if len(list.Data) != 1 {
+				t.Fatalf("list data = %v, want one alert", list.Data)
+			}
+			assertAPIShape(t, list.Data[0], tt.key)
+			if _, ok := list.Pagination[tt.key("page_size")]; !ok {
+				t.Errorf("pagination = %v, want %s", list.Pagination, tt.key("page_size"))
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Ghostbaby/sls-migrate/internal/service"
+	"github.com/Ghostbaby/sls-migrate/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// 错误响应中 code 字段的取值，客户端应依据 code 而不是 error 文案判断错误类型
+const (
+	ErrorCodeNotFound   = "not_found"
+	ErrorCodeValidation = "validation_failed"
+	ErrorCodeConflict   = "conflict"
+	ErrorCodeInternal   = "internal_error"
+	ErrorCodeTimeout    = "timeout"
+
+	ErrorCodeUnauthorized = "unauthorized"
+	ErrorCodeForbidden    = "forbidden"
+	ErrorCodeUpstream     = "upstream_error" // 数据库变更已完成，但推送到 SLS 失败
+	ErrorCodeUnavailable  = "unavailable"
+)
+
+// errorTitles 具体服务层错误对应的 error 文案，未列出的错误使用调用方提供的文案
+var errorTitles = []struct {
+	err   error
+	title string
+}{
+	{service.ErrAlertNotFound, "Alert not found"},
+	{service.ErrAuditLogNotFound, "Audit log not found"},
+	{service.ErrAlertExists, "Alert already exists"},
+	{service.ErrAlertModified, "Alert was modified"},
+	{service.ErrAlertDeleted, "Alert name is held by a deleted alert"},
+	{service.ErrAlertNotDeleted, "Alert is not deleted"},
+	{service.ErrAlertDisabled, "Alert is disabled"},
+	{service.ErrInvalidRepeatInterval, "Invalid repeat_interval"},
+	{service.ErrInvalidSchedule, "Invalid schedule"},
+	{service.ErrInvalidUpdateSections, "Invalid sections"},
+	{service.ErrInvalidAlertPatch, "Invalid patch"},
+	{service.ErrInvalidMute, "Invalid mute"},
+	{service.ErrInvalidRollback, "Invalid rollback"},
+	{service.ErrInvalidTagQuery, "Invalid tag query"},
+	{service.ErrInvalidSearchFilter, "Invalid search filter"},
+	{service.ErrInvalidSLSTarget, "Invalid SLS target"},
+	{service.ErrPlanChecksumMismatch, "Invalid sync plan"},
+	{service.ErrSLSNotFound, "Alert not found in SLS"},
+}
+
+// errorStatus 根据服务层错误分类返回 HTTP 状态码和错误码
+func errorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound, ErrorCodeNotFound
+	case errors.Is(err, service.ErrValidation):
+		return http.StatusBadRequest, ErrorCodeValidation
+	case errors.Is(err, service.ErrConflict):
+		return http.StatusConflict, ErrorCodeConflict
+	case errors.Is(err, service.ErrSyncTimedOut):
+		return http.StatusGatewayTimeout, ErrorCodeTimeout
+	}
+	return http.StatusInternalServerError, ErrorCodeInternal
+}
+
+// requestID 返回当前请求的请求 ID，写入错误响应的 request_id 字段，便于按 ID 查找对应日志
+func requestID(c *gin.Context) string {
+	return logger.RequestID(c.Request.Context())
+}
+
+// errorBody 生成错误响应：具体错误有固定文案时使用该文案，否则使用 title
+func errorBody(c *gin.Context, err error, title string) gin.H {
+	var frozenErr *service.AlertFrozenError
+	if errors.As(err, &frozenErr) {
+		title = "Alert is frozen"
+	} else {
+		for _, t := range errorTitles {
+			if errors.Is(err, t.err) {
+				title = t.title
+				break
+			}
+		}
+	}
+
+	_, code := errorStatus(err)
+	return gin.H{
+		"error":      title,
+		"code":       code,
+		"message":    err.Error(),
+		"request_id": requestID(c),
+	}
+}
+
+// respondError 按服务层错误分类输出错误响应：ErrNotFound→404、ErrValidation→400、ErrConflict→409、
+// 同步超时→504，其余为 500；title 为未归类或没有固定文案的错误使用的 error 文案
+func respondError(c *gin.Context, err error, title string) {
+	status, _ := errorStatus(err)
+	c.JSON(status, errorBody(c, err, title))
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API 字段命名风格
+const (
+	FieldCaseSnake = "snake"
+	FieldCaseCamel = "camel"
+)
+
+// opaqueFieldKeys 值为用户自定义 JSON 的字段，转换命名风格时不处理其内部的键
+var opaqueFieldKeys = map[string]bool{
+	"annotations": true,
+	"tokens":      true,
+	"config":      true,
+	"raw_config":  true,
+	"rawConfig":   true,
+}
+
+// normalizeFieldCase 规范化字段命名风格，未知值回退为 snake
+func normalizeFieldCase(fieldCase string) string {
+	if strings.EqualFold(fieldCase, FieldCaseCamel) {
+		return FieldCaseCamel
+	}
+	return FieldCaseSnake
+}
+
+// renderFieldCase 按配置的命名风格输出 JSON 响应
+func renderFieldCase(c *gin.Context, status int, fieldCase string, obj interface{}) {
+	if fieldCase != FieldCaseCamel {
+		c.JSON(status, obj)
+		return
+	}
+
+	converted, err := convertFieldCase(obj, snakeToCamel)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to encode response",
+			"code":       ErrorCodeInternal,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	c.JSON(status, converted)
+}
+
+// bindFieldCase 按配置的命名风格解析请求体，camel 风格的键会先转换为 snake
+func bindFieldCase(c *gin.Context, fieldCase string, obj interface{}) error {
+	if fieldCase != FieldCaseCamel {
+		return c.ShouldBindJSON(obj)
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(body, &generic); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(convertKeys(generic, camelToSnake))
+	if err != nil {
+		return fmt.Errorf("failed to convert request body: %w", err)
+	}
+
+	return json.Unmarshal(data, obj)
+}
+
+// convertFieldCase 经 JSON 中转后转换对象中所有键的命名风格
+func convertFieldCase(obj interface{}, convert func(string) string) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal json: %w", err)
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal json: %w", err)
+	}
+
+	return convertKeys(generic, convert), nil
+}
+
+// convertKeys 递归转换 map 的键，opaqueFieldKeys 中字段的值保持原样
+func convertKeys(value interface{}, convert func(string) string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if opaqueFieldKeys[key] {
+				converted[convert(key)] = item
+				continue
+			}
+			converted[convert(key)] = convertKeys(item, convert)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertKeys(item, convert)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// snakeToCamel 将 snake_case 转换为 camelCase
+func snakeToCamel(key string) string {
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// camelToSnake 将 camelCase 转换为 snake_case，已经是 snake_case 的键保持不变
+func camelToSnake(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// 导出格式
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+// validFormat 检查导出格式是否受支持，空值视为 json
+func validFormat(format string) bool {
+	return format == "" || format == formatJSON || format == formatYAML
+}
+
+// renderFormatted 按指定格式输出响应
+func renderFormatted(c *gin.Context, status int, format string, obj interface{}) {
+	if format != formatYAML {
+		c.JSON(status, obj)
+		return
+	}
+
+	data, err := toYAML(obj)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to encode response",
+			"code":       ErrorCodeInternal,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	c.Data(status, "application/x-yaml; charset=utf-8", data)
+}
+
+// toYAML 经 JSON 中转后序列化为 YAML，使字段名与 JSON 输出保持一致
+func toYAML(obj interface{}) ([]byte, error) {
+	generic, err := toGeneric(obj)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(generic)
+}
+
+// fromYAML 将 YAML 转换为 JSON 后按 json 标签解析到 obj，null 和空字符串保持区分
+func fromYAML(data []byte, obj interface{}) error {
+	var generic interface{}
+	if err := yaml.Unmarshal(data, &generic); err != nil {
+		return fmt.Errorf("failed to parse yaml: %w", err)
+	}
+
+	jsonData, err := json.Marshal(generic)
+	if err != nil {
+		return fmt.Errorf("failed to convert yaml to json: %w", err)
+	}
+	return json.Unmarshal(jsonData, obj)
+}
+
+// toGeneric 经 JSON 中转得到通用结构；整数保持为 int64，避免 YAML 中出现 1.7e+12 之类的浮点表示
+func toGeneric(obj interface{}) (interface{}, error) {
+	jsonData, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal json: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal json: %w", err)
+	}
+	return normalizeNumbers(generic), nil
+}
+
+// normalizeNumbers 将 json.Number 转换为 int64 或 float64
+func normalizeNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeNumbers(item)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return value
+}
+
+// arrayStream 以 JSON 数组或 YAML 序列的形式逐个写出元素，用于流式导出
+type arrayStream struct {
+	w       io.Writer
+	format  string
+	written bool
+}
+
+// newArrayStream 创建流式数组写入器，format 为空时使用 json
+func newArrayStream(w io.Writer, format string) *arrayStream {
+	if format == "" {
+		format = formatJSON
+	}
+	return &arrayStream{w: w, format: format}
+}
+
+// contentType 返回输出格式对应的 Content-Type
+func (s *arrayStream) contentType() string {
+	if s.format == formatYAML {
+		return "application/x-yaml; charset=utf-8"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// extension 返回输出格式对应的文件扩展名
+func (s *arrayStream) extension() string {
+	return s.format
+}
+
+// begin 写出数组开头
+func (s *arrayStream) begin() {
+	if s.format == formatJSON {
+		io.WriteString(s.w, "[")
+	}
+}
+
+// element 写出单个元素；YAML 下每个元素是一个 "- " 开头的序列项
+func (s *arrayStream) element(obj interface{}) error {
+	var data []byte
+	var err error
+	if s.format == formatYAML {
+		var generic interface{}
+		if generic, err = toGeneric(obj); err == nil {
+			data, err = yaml.Marshal([]interface{}{generic})
+		}
+	} else {
+		data, err = json.Marshal(obj)
+	}
+	if err != nil {
+		return err
+	}
+
+	if s.written && s.format == formatJSON {
+		io.WriteString(s.w, ",")
+	}
+	s.written = true
+	_, err = s.w.Write(data)
+	return err
+}
+
+// end 写出数组结尾，YAML 下没有任何元素时输出空序列
+func (s *arrayStream) end() {
+	switch {
+	case s.format == formatJSON:
+		io.WriteString(s.w, "]")
+	case !s.written:
+		io.WriteString(s.w, "[]\n")
+	}
+}
+
+// parseTimeParam 解析时间参数，支持 RFC3339 和 Unix 秒
+func parseTimeParam(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("time must be RFC3339 or unix seconds: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArrayStream(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		elements []interface{}
+		want     string
+	}{
+		{name: "json empty", format: formatJSON, want: "[]"},
+		{name: "json elements", format: formatJSON, elements: []interface{}{map[string]int{"a": 1}, map[string]int{"b": 2}}, want: `[{"a":1},{"b":2}]`},
+		{name: "yaml empty", format: formatYAML, want: "[]\n"},
+		{name: "yaml elements", format: formatYAML, elements: []interface{}{map[string]int{"a": 1}, map[string]int{"b": 2}}, want: "- a: 1\n- b: 2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			stream := newArrayStream(&out, tt.format)
+			stream.begin()
+			for _, element := range tt.elements {
+				if err := stream.element(element); err != nil {
+					t.Fatalf("element: %v", err)
+				}
+			}
+			stream.end()
+
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/service"
+	"github.com/Ghostbaby/sls-migrate/pkg/database"
+	"github.com/gin-gonic/gin"
+)
+
+// readinessCheckTimeout 就绪检查中单个组件的超时
+const readinessCheckTimeout = 3 * time.Second
+
+// 健康检查状态
+const (
+	healthStatusOK          = "ok"
+	healthStatusUnavailable = "unavailable"
+	componentStatusUp       = "up"
+	componentStatusDown     = "down"
+)
+
+// ComponentHealth 单个依赖组件的检查结果
+type ComponentHealth struct {
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// HealthHandler 存活和就绪检查处理器
+type HealthHandler struct {
+	slsService service.SLSService
+}
+
+// NewHealthHandler 创建新的 HealthHandler 实例，slsService 为 nil 表示未配置 SLS
+func NewHealthHandler(slsService service.SLSService) *HealthHandler {
+	return &HealthHandler{slsService: slsService}
+}
+
+// Live 存活检查
+// @Summary 存活检查
+// @Description 只表示进程在运行，不检查任何依赖，适合作为 liveness probe
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /health/live [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  healthStatusOK,
+		"message": "SLS Migrate Service is running",
+	})
+}
+
+// Ready 就绪检查
+// @Summary 就绪检查
+// @Description 检查数据库连通性，sls=true 时同时检查 SLS 连通性；任一组件不可用时返回 503，响应中给出每个组件的状态和耗时
+// @Tags Health
+// @Produce json
+// @Param sls query bool false "同时检查 SLS 连通性"
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	components := map[string]ComponentHealth{
+		"database": checkComponent(c.Request.Context(), database.Ping),
+	}
+	if c.Query("sls") == "true" {
+		if h.slsService == nil {
+			components["sls"] = ComponentHealth{Status: componentStatusDown, Error: "SLS service is not configured"}
+		} else {
+			components["sls"] = checkComponent(c.Request.Context(), h.slsService.Ping)
+		}
+	}
+
+	status, code := healthStatusOK, http.StatusOK
+	for _, component := range components {
+		if component.Status != componentStatusUp {
+			status, code = healthStatusUnavailable, http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	c.JSON(code, gin.H{
+		"status":     status,
+		"components": components,
+	})
+}
+
+// checkComponent 在超时内执行一次检查并记录耗时
+func checkComponent(ctx context.Context, check func(ctx context.Context) error) ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := ComponentHealth{
+		Status:    componentStatusUp,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = componentStatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
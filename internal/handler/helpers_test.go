@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/Ghostbaby/sls-migrate/internal/service"
+	"github.com/Ghostbaby/sls-migrate/internal/store"
+	"github.com/Ghostbaby/sls-migrate/pkg/database"
+	"github.com/gin-gonic/gin"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// testServer 基于内存 SQLite 的完整路由，不连接 SLS
+type testServer struct {
+	router       *gin.Engine
+	alertService service.AlertService
+	alertStore   store.AlertStore
+	cfg          *config.Config
+}
+
+// newTestServer 创建测试路由，configure 可在创建路由前修改配置
+func newTestServer(t *testing.T, configure func(cfg *config.Config)) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	dsn := fmt.Sprintf("file:handler_%s?mode=memory&cache=shared", name)
+	if err := database.InitDatabase(&config.DatabaseConfig{Driver: database.DriverSQLite, Database: dsn}); err != nil {
+		t.Fatalf("InitDatabase: %v", err)
+	}
+	database.DB.Logger = gormlogger.Default.LogMode(gormlogger.Silent)
+	if err := database.AutoMigrate(); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	t.Cleanup(func() {
+		database.CloseDatabase()
+	})
+
+	cfg := config.LoadConfig()
+	if configure != nil {
+		configure(cfg)
+	}
+
+	alertStore := store.NewAlertStore(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	alertService := service.NewAlertService(alertStore, &cfg.DefaultSink, service.AlertStatusEnabled)
+	router := SetupRouter(NewAlertHandler(alertService, nil, &cfg.API), NewSLSHandler(nil, nil, nil), cfg)
+	return &testServer{router: router, alertService: alertService, alertStore: alertStore, cfg: cfg}
+}
+
+// do 发送请求，body 为 string 时原样发送，其他值编码为 JSON；headers 为交替的名称和值
+func (s *testServer) do(t *testing.T, method, path string, body interface{}, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = strings.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	recorder := httptest.NewRecorder()
+	s.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// decodeBody 解码 JSON 响应体
+func decodeBody(t *testing.T, recorder *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+
+	if err := json.Unmarshal(recorder.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode body %q: %v", recorder.Body.String(), err)
+	}
+}
+
+// testAlertBody 创建 Alert 的请求体，包含配置、调度、标签和查询
+func testAlertBody(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":         name,
+		"display_name": "Alert " + name,
+		"status":       "ENABLED",
+		"configuration": map[string]interface{}{
+			"threshold": 1,
+			"type":      "default",
+			"version":   "2.0",
+			"condition": map[string]interface{}{"condition": "cnt > 0"},
+			"severities": []map[string]interface{}{
+				{"severity": 6, "condition": map[string]interface{}{"condition": "cnt > 10"}},
+			},
+		},
+		"schedule": map[string]interface{}{"type": "FixedRate", "interval": "1m"},
+		"tags": []map[string]interface{}{
+			{"type": "label", "key": "team", "value": "ops"},
+		},
+		"queries": []map[string]interface{}{
+			{"query": "* | select count(*) as cnt", "store": "app-log", "store_type": "log"},
+		},
+	}
+}
+
+// createTestAlert 通过 API 创建 Alert 并返回响应
+func (s *testServer) createTestAlert(t *testing.T, name string) *AlertDTO {
+	t.Helper()
+
+	recorder := s.do(t, http.MethodPost, "/api/v1/alerts", testAlertBody(name))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("create %s: status %d: %s", name, recorder.Code, recorder.Body.String())
+	}
+	var created AlertDTO
+	decodeBody(t, recorder, &created)
+	return &created
+}
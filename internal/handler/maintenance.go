@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiKeyHeader 管理接口的 API Key 请求头
+const apiKeyHeader = "X-API-Key"
+
+// adminPathPrefix 管理接口路径前缀，维护模式下仍可访问以便关闭维护模式
+const adminPathPrefix = "/api/v1/admin"
+
+// MaintenanceMode 运行时可切换的维护模式状态
+type MaintenanceMode struct {
+	enabled atomic.Bool
+}
+
+// NewMaintenanceMode 创建新的 MaintenanceMode 实例
+func NewMaintenanceMode(enabled bool) *MaintenanceMode {
+	m := &MaintenanceMode{}
+	m.enabled.Store(enabled)
+	return m
+}
+
+// Enabled 是否处于维护模式
+func (m *MaintenanceMode) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// Set 开启或关闭维护模式
+func (m *MaintenanceMode) Set(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+// Middleware 维护模式下拒绝所有写请求（包括同步接口），GET/HEAD/OPTIONS 和管理接口不受影响
+func (m *MaintenanceMode) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.Enabled() || isReadOnlyMethod(c.Request.Method) || strings.HasPrefix(c.Request.URL.Path, adminPathPrefix) {
+			c.Next()
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error":      "Maintenance mode",
+			"code":       ErrorCodeUnavailable,
+			"message":    "The API is in read-only maintenance mode; write requests are temporarily rejected",
+			"request_id": requestID(c),
+		})
+	}
+}
+
+// isReadOnlyMethod 是否为不修改数据的 HTTP 方法
+func isReadOnlyMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// RequireAPIKey 校验请求头中的 API Key，未配置 API Key 时管理接口不可用
+func RequireAPIKey(apiKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if apiKey == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":      "Admin API disabled",
+				"code":       ErrorCodeForbidden,
+				"message":    "ADMIN_API_KEY is not configured",
+				"request_id": requestID(c),
+			})
+			return
+		}
+
+		provided := c.GetHeader(apiKeyHeader)
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":      "Unauthorized",
+				"code":       ErrorCodeUnauthorized,
+				"message":    "Invalid or missing " + apiKeyHeader + " header",
+				"request_id": requestID(c),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AdminHandler 管理接口处理器
+type AdminHandler struct {
+	maintenance *MaintenanceMode
+}
+
+// NewAdminHandler 创建新的 AdminHandler 实例
+func NewAdminHandler(maintenance *MaintenanceMode) *AdminHandler {
+	return &AdminHandler{
+		maintenance: maintenance,
+	}
+}
+
+// SetMaintenanceRequest 切换维护模式的请求
+type SetMaintenanceRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// GetMaintenance 获取维护模式状态
+// @Summary 获取维护模式状态
+// @Description 返回 API 当前是否处于只读维护模式，需要 X-API-Key 头
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/maintenance [get]
+func (h *AdminHandler) GetMaintenance(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"maintenance_mode": h.maintenance.Enabled(),
+	})
+}
+
+// SetMaintenance 开启或关闭维护模式
+// @Summary 开启或关闭维护模式
+// @Description 维护模式下所有 POST/PUT/PATCH/DELETE 接口（包括同步接口）返回 503，GET 接口不受影响，需要 X-API-Key 头
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body SetMaintenanceRequest true "维护模式开关"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/maintenance [put]
+func (h *AdminHandler) SetMaintenance(c *gin.Context) {
+	var req SetMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	h.maintenance.Set(*req.Enabled)
+	log.Printf("Maintenance mode set to %v by %s", *req.Enabled, c.ClientIP())
+
+	c.JSON(http.StatusOK, gin.H{
+		"maintenance_mode": h.maintenance.Enabled(),
+	})
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+)
+
+const testAPIKey = "test-admin-key"
+
+func TestMaintenanceModeBlocksWritesAndAllowsReads(t *testing.T) {
+	server := newTestServer(t, func(cfg *config.Config) { cfg.Admin.APIKey = testAPIKey })
+	alert := server.createTestAlert(t, "maintenance")
+	alertPath := fmt.Sprintf("/api/v1/alerts/%d", alert.ID)
+
+	recorder := server.do(t, http.MethodPut, "/api/v1/admin/maintenance", map[string]interface{}{"enabled": true}, apiKeyHeader, testAPIKey)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("enable maintenance status = %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	writes := []struct {
+		method string
+		path   string
+		body   interface{}
+	}{
+		{http.MethodPost, "/api/v1/alerts", testAlertBody("blocked")},
+		{http.MethodPut, alertPath, testAlertBody("maintenance")},
+		{http.MethodDelete, alertPath, nil},
+		{http.MethodPost, alertPath + "/tags", map[string]interface{}{"tag_type": "label", "tag_key": "env", "tag_value": "prod"}},
+		{http.MethodPost, "/api/v1/sls/sync", nil},
+		{http.MethodPost, "/api/v1/sls/sync/db-to-sls", nil},
+		{http.MethodPost, "/api/v1/sls/sync/apply-plan", map[string]interface{}{}},
+	}
+	for _, write := range writes {
+		recorder := server.do(t, write.method, write.path, write.body)
+		if recorder.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s %s status = %d, want 503", write.method, write.path, recorder.Code)
+			continue
+		}
+		var body map[string]interface{}
+		decodeBody(t, recorder, &body)
+		if body["code"] != ErrorCodeUnavailable || body["message"] == "" {
+			t.Errorf("%s %s body = %v, want the maintenance error", write.method, write.path, body)
+		}
+	}
+
+	for _, path := range []string{"/api/v1/alerts", alertPath, alertPath + "/tags"} {
+		if recorder := server.do(t, http.MethodGet, path, nil); recorder.Code != http.StatusOK {
+			t.Errorf("GET %s status = %d, want 200: %s", path, recorder.Code, recorder.Body.String())
+		}
+	}
+	if _, err := server.alertStore.GetByName(context.Background(), "maintenance"); err != nil {
+		t.Errorf("alert deleted during maintenance: %v", err)
+	}
+
+	// 管理接口在维护模式下仍可访问，关闭后写请求恢复
+	recorder = server.do(t, http.MethodPut, "/api/v1/admin/maintenance", map[string]interface{}{"enabled": false}, apiKeyHeader, testAPIKey)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("disable maintenance status = %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder := server.do(t, http.MethodPost, "/api/v1/alerts", testAlertBody("allowed")); recorder.Code != http.StatusCreated {
+		t.Errorf("create after maintenance status = %d, want 201: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestMaintenanceModeFromConfig(t *testing.T) {
+	server := newTestServer(t, func(cfg *config.Config) { cfg.Admin.MaintenanceMode = true })
+
+	if recorder := server.do(t, http.MethodPost, "/api/v1/alerts", testAlertBody("blocked")); recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("create status = %d, want 503", recorder.Code)
+	}
+	if recorder := server.do(t, http.MethodGet, "/api/v1/alerts", nil); recorder.Code != http.StatusOK {
+		t.Errorf("list status = %d, want 200", recorder.Code)
+	}
+}
+
+func TestMaintenanceToggleRequiresAPIKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiKey   string // 服务端配置的 API Key
+		headers  []string
+		wantCode int
+	}{
+		{name: "admin API disabled", headers: []string{apiKeyHeader, testAPIKey}, wantCode: http.StatusForbidden},
+		{name: "missing key", apiKey: testAPIKey, wantCode: http.StatusUnauthorized},
+		{name: "wrong key", apiKey: testAPIKey, headers: []string{apiKeyHeader, "wrong"}, wantCode: http.StatusUnauthorized},
+		{name: "valid key", apiKey: testAPIKey, headers: []string{apiKeyHeader, testAPIKey}, wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, func(cfg *config.Config) { cfg.Admin.APIKey = tt.apiKey })
+
+			recorder := server.do(t, http.MethodPut, "/api/v1/admin/maintenance", map[string]interface{}{"enabled": true}, tt.headers...)
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantCode, recorder.Body.String())
+			}
+			wantBlocked := tt.wantCode == http.StatusOK
+			if recorder := server.do(t, http.MethodPost, "/api/v1/alerts", testAlertBody("after-toggle")); (recorder.Code == http.StatusServiceUnavailable) != wantBlocked {
+				t.Errorf("create status = %d, want blocked = %v", recorder.Code, wantBlocked)
+			}
+		})
+	}
+}
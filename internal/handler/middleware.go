@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/store"
+	"github.com/Ghostbaby/sls-migrate/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// ActorHeader 标识操作人的请求头，写入 Alert 变更审计记录
+const ActorHeader = "X-Actor"
+
+// anonymousActor 请求没有携带 X-Actor 时记录的操作人
+const anonymousActor = "anonymous"
+
+// maxActorLength 操作人的最大长度，与 alert_audit_logs.actor 列一致
+const maxActorLength = 255
+
+// RequestIDHeader 请求 ID 的请求头和响应头
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength 沿用客户端请求 ID 的最大长度
+const maxRequestIDLength = 128
+
+// accessLogEntry JSON 格式的访问日志
+type accessLogEntry struct {
+	Time      string `json:"time"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Status    int    `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	ClientIP  string `json:"client_ip"`
+	UserAgent string `json:"user_agent"`
+	BodySize  int    `json:"body_size"`
+	RequestID string `json:"request_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// AccessLogger 根据日志格式返回访问日志中间件，需要放在 RequestID 之后
+func AccessLogger(format string) gin.HandlerFunc {
+	if format != logger.FormatJSON {
+		return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+			return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v | %s\n%s",
+				param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+				param.StatusCode,
+				param.Latency,
+				param.ClientIP,
+				param.Method,
+				param.Path,
+				logger.RequestID(param.Request.Context()),
+				param.ErrorMessage,
+			)
+		})
+	}
+
+	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+		entry := accessLogEntry{
+			Time:      param.TimeStamp.Format(time.RFC3339),
+			Method:    param.Method,
+			Path:      param.Path,
+			Status:    param.StatusCode,
+			LatencyMs: param.Latency.Milliseconds(),
+			ClientIP:  param.ClientIP,
+			UserAgent: param.Request.UserAgent(),
+			BodySize:  param.BodySize,
+			RequestID: logger.RequestID(param.Request.Context()),
+			Error:     param.ErrorMessage,
+		}
+
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return ""
+		}
+		return string(line) + "\n"
+	})
+}
+
+// RequestID 沿用请求头 X-Request-ID（不合法时忽略）或生成新的请求 ID，写入响应头和请求上下文，
+// 访问日志、错误响应以及服务层和存储层带 context 的日志都会带上该 ID
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := strings.TrimSpace(c.GetHeader(RequestIDHeader))
+		if !validRequestID(id) {
+			id = logger.NewRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// validRequestID 请求 ID 非空、不超过 128 个字符，且只包含字母、数字和 -_.:
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.' || r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NoWriteTimeout 取消当前请求的写超时，用于流式导出和同步等长时间运行的接口，
+// 其余接口仍受 HTTP_WRITE_TIMEOUT 限制
+func NoWriteTimeout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+			log.Printf("Failed to clear write deadline for %s: %v", c.Request.URL.Path, err)
+		}
+		c.Next()
+	}
+}
+
+// ActorContext 从 X-Actor 请求头读取操作人并放入请求上下文，未提供时记为 anonymous
+func ActorContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := strings.TrimSpace(c.GetHeader(ActorHeader))
+		if actor == "" {
+			actor = anonymousActor
+		}
+		if len(actor) > maxActorLength {
+			actor = strings.ToValidUTF8(actor[:maxActorLength], "")
+		}
+		c.Request = c.Request.WithContext(store.WithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
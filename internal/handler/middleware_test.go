@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// captureAccessLog 在 gin.DefaultWriter 被替换期间创建访问日志中间件，返回记录输出的缓冲区
+func captureAccessLog(t *testing.T, format string) (*gin.Engine, *bytes.Buffer) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	previous := gin.DefaultWriter
+	gin.DefaultWriter = &buf
+	t.Cleanup(func() { gin.DefaultWriter = previous })
+
+	router := gin.New()
+	router.Use(RequestID(), AccessLogger(format))
+	router.GET("/api/v1/alerts", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return router, &buf
+}
+
+func TestAccessLoggerJSON(t *testing.T) {
+	router, buf := captureAccessLog(t, "json")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/alerts?limit=1", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	req.Header.Set("User-Agent", "test-agent")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("access log lines = %q, want one JSON line", buf.String())
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("access log %q is not JSON: %v", lines[0], err)
+	}
+
+	want := map[string]interface{}{
+		"method":     "GET",
+		"path":       "/api/v1/alerts?limit=1",
+		"status":     float64(http.StatusOK),
+		"user_agent": "test-agent",
+		"request_id": "req-123",
+		"body_size":  float64(2),
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %v", key, entry[key], value)
+		}
+	}
+	for _, key := range []string{"time", "latency_ms", "client_ip"} {
+		if _, ok := entry[key]; !ok {
+			t.Errorf("access log is missing %s", key)
+		}
+	}
+}
+
+func TestAccessLoggerText(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+	}{
+		{name: "default", format: ""},
+		{name: "text", format: "text"},
+		{name: "unknown falls back to text", format: "xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, buf := captureAccessLog(t, tt.format)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/alerts", nil)
+			req.Header.Set(RequestIDHeader, "req-456")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			line := buf.String()
+			if !strings.HasPrefix(line, "[GIN] ") || !strings.Contains(line, "req-456") || !strings.Contains(line, `"/api/v1/alerts"`) {
+				t.Errorf("text access log = %q", line)
+			}
+			if json.Valid([]byte(strings.TrimSpace(line))) {
+				t.Errorf("text format produced JSON: %q", line)
+			}
+		})
+	}
+}
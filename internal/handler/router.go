@@ -1,43 +1,96 @@
 package handler
 
 import (
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/Ghostbaby/sls-migrate/pkg/metrics"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 // SetupRouter 设置路由
-func SetupRouter(alertHandler *AlertHandler, slsHandler *SLSHandler) *gin.Engine {
-	router := gin.Default()
+func SetupRouter(alertHandler *AlertHandler, slsHandler *SLSHandler, cfg *config.Config) *gin.Engine {
+	router := gin.New()
 
 	// 添加中间件
-	router.Use(gin.Logger())
+	router.Use(RequestID())
+	router.Use(AccessLogger(cfg.Log.Format))
 	router.Use(gin.Recovery())
+	router.Use(ActorContext())
 
-	// API 路由组
-	api := router.Group("/api/v1")
+	// 维护模式下拒绝写请求
+	maintenance := NewMaintenanceMode(cfg.Admin.MaintenanceMode)
+	router.Use(maintenance.Middleware())
+	adminHandler := NewAdminHandler(maintenance)
+	healthHandler := NewHealthHandler(slsHandler.slsService)
+
+	// API 路由组，响应按 API_GZIP_MIN_SIZE 压缩；Swagger 和指标接口不压缩
+	api := router.Group("/api/v1", Gzip(cfg.API.GzipMinSize))
 	{
 		// Alert 相关路由
 		alerts := api.Group("/alerts")
 		{
-			alerts.POST("", alertHandler.CreateAlert)                      // 创建 Alert
-			alerts.GET("", alertHandler.ListAlerts)                        // 获取 Alert 列表
-			alerts.GET("/:id", alertHandler.GetAlertByID)                  // 根据 ID 获取 Alert
-			alerts.GET("/name/:name", alertHandler.GetAlertByName)         // 根据名称获取 Alert
-			alerts.PUT("/:id", alertHandler.UpdateAlert)                   // 更新 Alert
-			alerts.DELETE("/:id", alertHandler.DeleteAlert)                // 删除 Alert
-			alerts.GET("/status/:status", alertHandler.ListAlertsByStatus) // 根据状态获取 Alert 列表
+			alerts.POST("", alertHandler.CreateAlert)                                // 创建 Alert
+			alerts.POST("/batch", alertHandler.CreateAlerts)                         // 批量创建 Alert
+			alerts.GET("", alertHandler.ListAlerts)                                  // 获取 Alert 列表
+			alerts.GET("/autocomplete", alertHandler.AutocompleteAlerts)             // Alert 名称自动补全
+			alerts.GET("/search", alertHandler.SearchAlerts)                         // 按名称、状态和标签搜索 Alert
+			alerts.GET("/export", NoWriteTimeout(), alertHandler.StreamExportAlerts) // 流式导出全部 Alert
+			alerts.GET("/graph", alertHandler.GetAlertGraph)                         // Alert 依赖关系图
+			alerts.GET("/duplicates", alertHandler.GetDuplicateAlerts)               // 查找内容重复的 Alert
+			alerts.GET("/stats/trend", alertHandler.GetAlertCountTrend)              // Alert 数量的每日趋势
+			alerts.GET("/summary", alertHandler.GetAlertSummary)                     // Alert 总数和各状态数量
+			alerts.POST("/import", alertHandler.ImportAlerts)                        // 导入 Alert（dry_run 预览）
+			alerts.POST("/export", alertHandler.ExportAlertsByNames)                 // 按名称列表导出 Alert
+			alerts.POST("/status", alertHandler.BulkSetAlertStatus)                  // 批量启用或停用 Alert（push=true 时推送到 SLS）
+			alerts.GET("/:id", alertHandler.GetAlertByID)                            // 根据 ID 获取 Alert
+			alerts.GET("/name/:name", alertHandler.GetAlertByName)                   // 根据名称获取 Alert
+			alerts.PUT("/:id", alertHandler.UpdateAlert)                             // 更新 Alert
+			alerts.PATCH("/:id", alertHandler.PatchAlert)                            // 部分更新 Alert
+			alerts.DELETE("/:id", alertHandler.DeleteAlert)                          // 删除 Alert
+			alerts.POST("/:id/freeze", alertHandler.FreezeAlert)                     // 冻结或解除冻结 Alert
+			alerts.POST("/:id/enable", alertHandler.EnableAlert)                     // 启用 Alert
+			alerts.POST("/:id/disable", alertHandler.DisableAlert)                   // 停用 Alert
+			alerts.POST("/:id/mute", alertHandler.MuteAlert)                         // 按时长或截止时间屏蔽 Alert
+			alerts.POST("/:id/unmute", alertHandler.UnmuteAlert)                     // 取消屏蔽 Alert
+			alerts.POST("/:id/rebuild", alertHandler.RebuildAlert)                   // 重建 Alert 的关联数据
+			alerts.POST("/:id/restore", alertHandler.RestoreAlert)                   // 恢复已软删除的 Alert
+			alerts.GET("/:id/history", alertHandler.GetAlertHistory)                 // Alert 的变更审计记录
+			alerts.POST("/:id/rollback/:audit_id", alertHandler.RollbackAlert)       // 回滚到审计记录的变更前快照
+			alerts.GET("/status/:status", alertHandler.ListAlertsByStatus)           // 根据状态获取 Alert 列表
+			alerts.GET("/:id/tags", alertHandler.ListAlertTags)                      // 获取 Alert 的标签列表
+			alerts.POST("/:id/tags", alertHandler.CreateAlertTag)                    // 为 Alert 添加标签
+			alerts.DELETE("/:id/tags/:tag_id", alertHandler.DeleteAlertTag)          // 删除 Alert 的标签
+		}
+
+		// 管理接口，需要 X-API-Key
+		admin := api.Group("/admin", RequireAPIKey(cfg.Admin.APIKey))
+		{
+			admin.GET("/maintenance", adminHandler.GetMaintenance) // 获取维护模式状态
+			admin.PUT("/maintenance", adminHandler.SetMaintenance) // 开启或关闭维护模式
 		}
 
 		// SLS 相关路由
 		sls := api.Group("/sls")
 		{
-			sls.GET("/alerts", slsHandler.GetSLSAlerts)                 // 从 SLS 获取所有 Alert
-			sls.GET("/alerts/name/:name", slsHandler.GetSLSAlertByName) // 从 SLS 根据名称获取 Alert
-			sls.POST("/sync", slsHandler.SyncSLSAlerts)                 // 同步 SLS Alert 到数据库
-			sls.POST("/sync/db-to-sls", slsHandler.SyncDatabaseToSLS)   // 同步数据库 Alert 到 SLS
-			sls.GET("/sync/status", slsHandler.GetSyncStatus)           // 获取同步状态
-			sls.GET("/status", slsHandler.GetSLSStatus)                 // 获取 SLS 连接状态
+			sls.POST("/alerts/validate", slsHandler.ValidateSLSAlert) // 试运行 Alert 到 SLS 的转换
+			sls.GET("/sync/status", slsHandler.GetSyncStatus)         // 获取同步状态
+			sls.GET("/sync/lag", slsHandler.GetSyncLag)               // 获取各 Project 的同步延迟
+			sls.GET("/sync/history", slsHandler.GetSyncHistory)       // 获取同步执行记录
+			sls.GET("/status", slsHandler.GetSLSStatus)               // 获取 SLS 连接状态
+
+			// 需要访问 SLS 的接口，SLS 不可用时快速失败
+			slsGated := sls.Group("", slsHandler.RequireSLSAvailable())
+			slsGated.GET("/alerts", slsHandler.GetSLSAlerts)                                 // 从 SLS 获取所有 Alert
+			slsGated.GET("/alerts/name/:name", slsHandler.GetSLSAlertByName)                 // 从 SLS 根据名称获取 Alert
+			slsGated.DELETE("/alerts/name/:name", slsHandler.DeleteSLSAlertByName)           // 从 SLS 根据名称删除 Alert
+			slsGated.GET("/alerts/name/:name/diff", slsHandler.DiffSLSAlert)                 // 比较数据库与 SLS 中的 Alert
+			slsGated.GET("/diff", slsHandler.DiffSLSInventory)                               // 比较数据库与 SLS 中的全部 Alert
+			slsGated.GET("/reconcile", slsHandler.ReconcileSLS)                              // 对账数据库与 SLS 中的全部 Alert
+			slsGated.POST("/alerts/copy", slsHandler.CopySLSAlert)                           // 跨 Project 复制 Alert
+			slsGated.POST("/sync", NoWriteTimeout(), slsHandler.SyncSLSAlerts)               // 同步 SLS Alert 到数据库
+			slsGated.POST("/sync/db-to-sls", NoWriteTimeout(), slsHandler.SyncDatabaseToSLS) // 同步数据库 Alert 到 SLS
+			slsGated.POST("/sync/apply-plan", NoWriteTimeout(), slsHandler.ApplySyncPlan)    // 执行同步计划
 		}
 	}
 
@@ -45,12 +98,12 @@ func SetupRouter(alertHandler *AlertHandler, slsHandler *SLSHandler) *gin.Engine
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// 健康检查
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "ok",
-			"message": "SLS Migrate Service is running",
-		})
-	})
+	router.GET("/health", healthHandler.Live)        // 兼容旧的存活检查
+	router.GET("/health/live", healthHandler.Live)   // 存活检查，只表示进程在运行
+	router.GET("/health/ready", healthHandler.Ready) // 就绪检查，检查数据库和（可选）SLS 连通性
+
+	// Prometheus 指标
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
 
 	return router
 }
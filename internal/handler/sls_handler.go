@@ -1,41 +1,174 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
+	"github.com/Ghostbaby/sls-migrate/internal/models"
 	"github.com/Ghostbaby/sls-migrate/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
 // SLSHandler SLS 处理器
 type SLSHandler struct {
-	slsService  service.SLSService
-	syncService service.SyncService
+	slsService    service.SLSService
+	syncService   service.SyncService
+	healthChecker service.SLSHealthChecker
 }
 
 // NewSLSHandler 创建新的 SLSHandler 实例
-func NewSLSHandler(slsService service.SLSService, syncService service.SyncService) *SLSHandler {
+func NewSLSHandler(slsService service.SLSService, syncService service.SyncService, healthChecker service.SLSHealthChecker) *SLSHandler {
 	return &SLSHandler{
-		slsService:  slsService,
-		syncService: syncService,
+		slsService:    slsService,
+		syncService:   syncService,
+		healthChecker: healthChecker,
 	}
 }
 
+// RequireSLSAvailable 根据缓存的连通性状态在 SLS 不可用时直接返回 503，调用成功后刷新状态为可用
+func (h *SLSHandler) RequireSLSAvailable() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.healthChecker == nil {
+			c.Next()
+			return
+		}
+
+		if !h.healthChecker.Available() {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error":      "SLS unavailable",
+				"code":       ErrorCodeUnavailable,
+				"message":    h.healthChecker.LastError(),
+				"request_id": requestID(c),
+			})
+			return
+		}
+
+		c.Next()
+
+		if c.Writer.Status() < http.StatusInternalServerError {
+			h.healthChecker.MarkAvailable()
+		}
+	}
+}
+
+// targetServices 按查询参数 project/endpoint 返回本次请求使用的 SLSService 和 SyncService，
+// 未指定时返回默认实例；参数不合法时写入 400 响应并返回 false
+func (h *SLSHandler) targetServices(c *gin.Context) (service.SLSService, service.SyncService, bool) {
+	target := service.SLSTarget{
+		Project:  c.Query("project"),
+		Endpoint: c.Query("endpoint"),
+	}
+	if target.IsZero() || h.slsService == nil {
+		return h.slsService, h.syncService, true
+	}
+
+	slsService, err := h.slsService.WithTarget(target)
+	if err != nil {
+		respondError(c, err, "Invalid SLS target")
+		return nil, nil, false
+	}
+
+	syncService := h.syncService
+	if syncService != nil {
+		syncService = syncService.WithSLSService(slsService)
+	}
+	return slsService, syncService, true
+}
+
 // GetSLSAlerts 从阿里云 SLS 获取所有 Alert 规则
 // @Summary 从阿里云 SLS 获取所有 Alert 规则
 // @Description 从阿里云 SLS 获取所有 Alert 规则
 // @Tags SLS
 // @Accept json
 // @Produce json
+// @Param offset query int false "SLS 原生分页偏移量，提供 offset/size/limit/logstore 任一参数时按 SLS 分页返回"
+// @Param size query int false "SLS 原生分页大小 (默认: 10, 范围: 1-200，超出范围返回 400)"
+// @Param limit query int false "同 size，两者都提供时以 limit 为准"
+// @Param logstore query string false "按日志库过滤（由 SLS 服务端执行）"
+// @Param name query string false "不支持：SLS ListAlerts 不能按名称过滤，提供时返回 400，请使用 /sls/alerts/name/{name}"
+// @Param project query string false "SLS Project，默认使用 SLS_PROJECT"
+// @Param endpoint query string false "SLS Endpoint（如 cn-hangzhou.log.aliyuncs.com），默认使用 SLS_ENDPOINT"
 // @Success 200 {array} models.Alert
+// @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /sls/alerts [get]
 func (h *SLSHandler) GetSLSAlerts(c *gin.Context) {
-	alerts, err := h.slsService.GetAlerts(c.Request.Context())
+	// SLS ListAlerts 不支持按名称过滤，不在本地静默忽略该参数
+	if _, hasName := c.GetQuery("name"); hasName {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Unsupported filter",
+			"code":       ErrorCodeValidation,
+			"message":    "SLS ListAlerts cannot filter by name; use GET /api/v1/sls/alerts/name/{name}",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	slsService, _, ok := h.targetServices(c)
+	if !ok {
+		return
+	}
+
+	// 提供 offset/size/limit/logstore 任一参数时按 SLS 分页返回一页，否则读取全部
+	_, hasOffset := c.GetQuery("offset")
+	sizeParam, hasSize := c.GetQuery("size")
+	if limit, hasLimit := c.GetQuery("limit"); hasLimit {
+		sizeParam, hasSize = limit, true
+	}
+	logstore := c.Query("logstore")
+	if hasOffset || hasSize || logstore != "" {
+		if !hasSize {
+			sizeParam = "10"
+		}
+		offset, errOffset := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		size, errSize := strconv.Atoi(sizeParam)
+		if errOffset != nil || errSize != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "Invalid pagination",
+				"code":       ErrorCodeValidation,
+				"message":    "offset and size (or limit) must be valid integers",
+				"request_id": requestID(c),
+			})
+			return
+		}
+		if offset < 0 || size < 1 || size > service.SLSListAlertsMaxSize {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      "Invalid pagination",
+				"code":       ErrorCodeValidation,
+				"message":    fmt.Sprintf("offset must not be negative and size (or limit) must be between 1 and %d", service.SLSListAlertsMaxSize),
+				"request_id": requestID(c),
+			})
+			return
+		}
+
+		page, err := slsService.ListAlertsPage(c.Request.Context(), service.SLSAlertPageQuery{
+			Offset:   offset,
+			Size:     size,
+			Logstore: logstore,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":      "Failed to get alerts from SLS",
+				"code":       ErrorCodeInternal,
+				"message":    err.Error(),
+				"request_id": requestID(c),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, page)
+		return
+	}
+
+	alerts, err := slsService.GetAlerts(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get alerts from SLS",
-			"message": err.Error(),
+			"error":      "Failed to get alerts from SLS",
+			"code":       ErrorCodeInternal,
+			"message":    err.Error(),
+			"request_id": requestID(c),
 		})
 		return
 	}
@@ -53,6 +186,8 @@ func (h *SLSHandler) GetSLSAlerts(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param name path string true "Alert 名称"
+// @Param project query string false "SLS Project，默认使用 SLS_PROJECT"
+// @Param endpoint query string false "SLS Endpoint（如 cn-hangzhou.log.aliyuncs.com），默认使用 SLS_ENDPOINT"
 // @Success 200 {object} models.Alert
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -61,85 +196,519 @@ func (h *SLSHandler) GetSLSAlertByName(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid alert name",
-			"message": "Name cannot be empty",
+			"error":      "Invalid alert name",
+			"code":       ErrorCodeValidation,
+			"message":    "Name cannot be empty",
+			"request_id": requestID(c),
 		})
 		return
 	}
 
-	alert, err := h.slsService.GetAlertByName(c.Request.Context(), name)
+	slsService, _, ok := h.targetServices(c)
+	if !ok {
+		return
+	}
+
+	alert, err := slsService.GetAlertByName(c.Request.Context(), name)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Alert not found in SLS",
-			"message": err.Error(),
-		})
+		respondError(c, err, "Failed to get alert from SLS")
 		return
 	}
 
 	c.JSON(http.StatusOK, alert)
 }
 
+// DeleteSLSAlertByName 根据名称直接删除阿里云 SLS 中的 Alert 规则
+// @Summary 根据名称删除 SLS 中的 Alert
+// @Description 直接删除 SLS 中的 Alert，不修改数据库；SLS 中不存在该 Alert 时返回 404
+// @Tags SLS
+// @Accept json
+// @Produce json
+// @Param name path string true "Alert 名称"
+// @Param project query string false "SLS Project，默认使用 SLS_PROJECT"
+// @Param endpoint query string false "SLS Endpoint（如 cn-hangzhou.log.aliyuncs.com），默认使用 SLS_ENDPOINT"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /sls/alerts/name/{name} [delete]
+func (h *SLSHandler) DeleteSLSAlertByName(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid alert name",
+			"code":       ErrorCodeValidation,
+			"message":    "Name cannot be empty",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	slsService, _, ok := h.targetServices(c)
+	if !ok {
+		return
+	}
+
+	if err := slsService.DeleteAlert(c.Request.Context(), slsService.Project(), name); err != nil {
+		respondError(c, err, "Failed to delete alert from SLS")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Alert deleted from SLS",
+		"name":    name,
+		"project": slsService.Project(),
+	})
+}
+
+// DiffSLSAlert 比较数据库与 SLS 中同名 Alert 的字段差异
+// @Summary 比较数据库与 SLS 中的 Alert
+// @Description 返回显示名称、描述、状态、调度、触发条件、严重程度和查询列表的字段级差异，每个差异标记为 only_in_sls、only_in_db 或 changed
+// @Tags SLS
+// @Accept json
+// @Produce json
+// @Param name path string true "Alert 名称"
+// @Param project query string false "SLS Project，默认使用 SLS_PROJECT"
+// @Param endpoint query string false "SLS Endpoint（如 cn-hangzhou.log.aliyuncs.com），默认使用 SLS_ENDPOINT"
+// @Success 200 {object} service.AlertSLSDiff
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /sls/alerts/name/{name}/diff [get]
+func (h *SLSHandler) DiffSLSAlert(c *gin.Context) {
+	if h.syncService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Sync service not available",
+			"code":       ErrorCodeInternal,
+			"message":    "Sync service is not initialized",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	name := c.Param("name")
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid alert name",
+			"code":       ErrorCodeValidation,
+			"message":    "Name cannot be empty",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	_, syncService, ok := h.targetServices(c)
+	if !ok {
+		return
+	}
+
+	diff, err := syncService.DiffAlertWithSLS(c.Request.Context(), name)
+	if err != nil {
+		respondError(c, err, "Failed to diff alert")
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// DiffSLSInventory 比较数据库与 SLS 中的全部 Alert
+// @Summary 比较数据库与 SLS 中的全部 Alert
+// @Description 默认返回 sls_only、db_only、divergent 三个完整列表；指定 action 时只返回该分类并分页，divergent 条目附带字段差异
+// @Tags SLS
+// @Accept json
+// @Produce json
+// @Param action query string false "差异分类（sls_only、db_only 或 divergent）"
+// @Param page query int false "页码（指定 action 时有效）" default(1)
+// @Param page_size query int false "每页数量（指定 action 时有效）" default(20)
+// @Param project query string false "SLS Project，默认使用 SLS_PROJECT"
+// @Param endpoint query string false "SLS Endpoint（如 cn-hangzhou.log.aliyuncs.com），默认使用 SLS_ENDPOINT"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /sls/diff [get]
+func (h *SLSHandler) DiffSLSInventory(c *gin.Context) {
+	if h.syncService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Sync service not available",
+			"code":       ErrorCodeInternal,
+			"message":    "Sync service is not initialized",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	action := c.Query("action")
+	if action != "" && !service.IsInventoryAction(action) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid action",
+			"code":       ErrorCodeValidation,
+			"message":    "action must be one of sls_only, db_only, divergent",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	_, syncService, ok := h.targetServices(c)
+	if !ok {
+		return
+	}
+
+	diff, err := syncService.DiffInventory(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to diff alerts",
+			"code":       ErrorCodeInternal,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	if action == "" {
+		c.JSON(http.StatusOK, gin.H{
+			"sls_only":        diff.SLSOnly,
+			"db_only":         diff.DBOnly,
+			"divergent":       diff.Divergent,
+			"sls_only_count":  len(diff.SLSOnly),
+			"db_only_count":   len(diff.DBOnly),
+			"divergent_count": len(diff.Divergent),
+		})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	items := diff.Items(action)
+	total := len(items)
+	start := (page - 1) * pageSize
+	if start > total {
+		start = total
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"action": action,
+		"data":   items[start:end],
+		"pagination": gin.H{
+			"page":        page,
+			"page_size":   pageSize,
+			"total":       total,
+			"total_pages": (total + pageSize - 1) / pageSize,
+		},
+	})
+}
+
+// ReconcileSLS 对账数据库与 SLS 中的全部 Alert
+// @Summary 对账数据库与 SLS 中的全部 Alert
+// @Description 只读操作，返回 only_in_sls、only_in_db、in_sync、drifted 四个按名称排序的列表；两侧都存在的 Alert 按同步使用的更新判断分为 in_sync 和 drifted，drifted 条目列出变化的字段
+// @Tags SLS
+// @Accept json
+// @Produce json
+// @Param project query string false "SLS Project，默认使用 SLS_PROJECT"
+// @Param endpoint query string false "SLS Endpoint（如 cn-hangzhou.log.aliyuncs.com），默认使用 SLS_ENDPOINT"
+// @Success 200 {object} service.ReconcileReport
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /sls/reconcile [get]
+func (h *SLSHandler) ReconcileSLS(c *gin.Context) {
+	if h.syncService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Sync service not available",
+			"code":       ErrorCodeInternal,
+			"message":    "Sync service is not initialized",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	_, syncService, ok := h.targetServices(c)
+	if !ok {
+		return
+	}
+
+	report, err := syncService.Reconcile(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to reconcile alerts",
+			"code":       ErrorCodeInternal,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 // SyncSLSAlerts 同步阿里云 SLS 的 Alert 规则到本地数据库
 // @Summary 同步阿里云 SLS 的 Alert 规则到本地数据库
 // @Description 同步阿里云 SLS 的 Alert 规则到本地数据库
 // @Tags SLS
 // @Accept json
 // @Produce json
+// @Param dry_run query bool false "仅生成同步计划（create/update 及变化字段/skip），不写入数据库和 SLS"
+// @Param project query string false "SLS Project，默认使用 SLS_PROJECT"
+// @Param endpoint query string false "SLS Endpoint（如 cn-hangzhou.log.aliyuncs.com），默认使用 SLS_ENDPOINT"
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /sls/sync [post]
 func (h *SLSHandler) SyncSLSAlerts(c *gin.Context) {
 	if h.syncService == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Sync service not available",
-			"message": "Sync service is not initialized",
+			"error":      "Sync service not available",
+			"code":       ErrorCodeInternal,
+			"message":    "Sync service is not initialized",
+			"request_id": requestID(c),
 		})
 		return
 	}
 
-	err := h.syncService.SyncSLSToDatabase(c.Request.Context())
+	_, syncService, ok := h.targetServices(c)
+	if !ok {
+		return
+	}
+
+	if c.Query("dry_run") == "true" {
+		plan, err := syncService.PlanSLSToDatabase(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":      "Failed to plan sync from SLS",
+				"code":       ErrorCodeInternal,
+				"message":    err.Error(),
+				"request_id": requestID(c),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, plan)
+		return
+	}
+
+	result, err := syncService.SyncSLSToDatabase(c.Request.Context())
+	setSyncResultHeaders(c, result)
 	if err != nil {
+		status, _ := errorStatus(err)
+		body := errorBody(c, err, "Failed to sync alerts from SLS")
+		body["result"] = result
+		c.JSON(status, body)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Successfully synced alerts from SLS",
+		"result":  result,
+	})
+}
+
+// ApplySyncPlan 执行之前生成的同步计划
+// @Summary 执行同步计划
+// @Description 重新校验 dry-run 生成的同步计划，状态未漂移时执行该计划
+// @Tags SLS
+// @Accept json
+// @Produce json
+// @Param plan body service.SyncPlan true "同步计划"
+// @Param project query string false "SLS Project，默认使用 SLS_PROJECT"
+// @Param endpoint query string false "SLS Endpoint（如 cn-hangzhou.log.aliyuncs.com），默认使用 SLS_ENDPOINT"
+// @Success 200 {object} service.SyncResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /sls/sync/apply-plan [post]
+func (h *SLSHandler) ApplySyncPlan(c *gin.Context) {
+	if h.syncService == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to sync alerts from SLS",
-			"message": err.Error(),
+			"error":      "Sync service not available",
+			"code":       ErrorCodeInternal,
+			"message":    "Sync service is not initialized",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	var plan service.SyncPlan
+	if err := c.ShouldBindJSON(&plan); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	_, syncService, ok := h.targetServices(c)
+	if !ok {
+		return
+	}
+
+	result, err := syncService.ApplySyncPlan(c.Request.Context(), &plan)
+	setSyncResultHeaders(c, result)
+	if err != nil {
+		var driftErr *service.PlanDriftError
+		switch {
+		case errors.As(err, &driftErr):
+			body := errorBody(c, err, "Sync plan is stale")
+			body["drifted"] = driftErr.Names
+			c.JSON(http.StatusConflict, body)
+		case errors.Is(err, service.ErrPlanChecksumMismatch):
+			respondError(c, err, "Invalid sync plan")
+		default:
+			status, _ := errorStatus(err)
+			body := errorBody(c, err, "Failed to apply sync plan")
+			body["result"] = result
+			c.JSON(status, body)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ValidateSLSAlert 试运行 Alert 到 SLS 模型的转换，返回有损转换警告
+// @Summary 试运行 Alert 到 SLS 模型的转换
+// @Description 不调用 SLS API，返回被丢弃或有损转换的字段以及疑似错误的查询语句
+// @Tags SLS
+// @Accept json
+// @Produce json
+// @Param alert body models.Alert true "Alert 信息"
+// @Param project query string false "SLS Project，默认使用 SLS_PROJECT"
+// @Param endpoint query string false "SLS Endpoint（如 cn-hangzhou.log.aliyuncs.com），默认使用 SLS_ENDPOINT"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /sls/alerts/validate [post]
+func (h *SLSHandler) ValidateSLSAlert(c *gin.Context) {
+	var alert models.Alert
+	if err := c.ShouldBindJSON(&alert); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	slsService, _, ok := h.targetServices(c)
+	if !ok {
+		return
+	}
+
+	warnings, err := slsService.ValidateAlert(c.Request.Context(), &alert)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Failed to convert alert",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
 		})
 		return
 	}
 
+	if warnings == nil {
+		warnings = []service.Warning{}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Successfully synced alerts from SLS",
+		"valid":    len(warnings) == 0,
+		"warnings": warnings,
 	})
 }
 
+// CopySLSAlertRequest 跨 Project 复制 Alert 请求
+type CopySLSAlertRequest struct {
+	SourceProject string `json:"source_project" binding:"required"`
+	TargetProject string `json:"target_project" binding:"required"`
+	Name          string `json:"name" binding:"required"`
+	Overwrite     bool   `json:"overwrite"`
+}
+
+// CopySLSAlert 将 Alert 从一个 SLS Project 复制到另一个 Project
+// @Summary 跨 Project 复制 SLS Alert
+// @Description 从源 Project 读取 Alert 并在目标 Project 中创建；目标已存在同名 Alert 时需要 overwrite 为 true 才会更新，否则返回 409。不经过数据库
+// @Tags SLS
+// @Accept json
+// @Produce json
+// @Param request body CopySLSAlertRequest true "复制请求"
+// @Success 200 {object} service.CopyAlertResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /sls/alerts/copy [post]
+func (h *SLSHandler) CopySLSAlert(c *gin.Context) {
+	var req CopySLSAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Invalid request body",
+			"code":       ErrorCodeValidation,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	result, err := service.CopyAlertBetweenProjects(c.Request.Context(), h.slsService, req.SourceProject, req.TargetProject, req.Name, req.Overwrite)
+	if err != nil {
+		status, _ := errorStatus(err)
+		body := errorBody(c, err, "Failed to copy alert")
+		body["result"] = result
+		c.JSON(status, body)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // SyncDatabaseToSLS 同步本地数据库的 Alert 规则到阿里云 SLS
 // @Summary 同步本地数据库的 Alert 规则到阿里云 SLS
 // @Description 同步本地数据库的 Alert 规则到阿里云 SLS
 // @Tags SLS
 // @Accept json
 // @Produce json
+// @Param project query string false "SLS Project，默认使用 SLS_PROJECT"
+// @Param endpoint query string false "SLS Endpoint（如 cn-hangzhou.log.aliyuncs.com），默认使用 SLS_ENDPOINT"
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /sls/sync/db-to-sls [post]
 func (h *SLSHandler) SyncDatabaseToSLS(c *gin.Context) {
 	if h.syncService == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Sync service not available",
-			"message": "Sync service is not initialized",
+			"error":      "Sync service not available",
+			"code":       ErrorCodeInternal,
+			"message":    "Sync service is not initialized",
+			"request_id": requestID(c),
 		})
 		return
 	}
 
-	err := h.syncService.SyncDatabaseToSLS(c.Request.Context())
+	_, syncService, ok := h.targetServices(c)
+	if !ok {
+		return
+	}
+
+	result, err := syncService.SyncDatabaseToSLS(c.Request.Context())
+	setSyncResultHeaders(c, result)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to sync alerts to SLS",
-			"message": err.Error(),
-		})
+		status, _ := errorStatus(err)
+		body := errorBody(c, err, "Failed to sync alerts to SLS")
+		body["result"] = result
+		c.JSON(status, body)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Successfully synced alerts to SLS",
+		"result":  result,
 	})
 }
 
@@ -149,23 +718,34 @@ func (h *SLSHandler) SyncDatabaseToSLS(c *gin.Context) {
 // @Tags SLS
 // @Accept json
 // @Produce json
+// @Param project query string false "SLS Project，默认使用 SLS_PROJECT"
+// @Param endpoint query string false "SLS Endpoint（如 cn-hangzhou.log.aliyuncs.com），默认使用 SLS_ENDPOINT"
 // @Success 200 {object} service.SyncStatus
 // @Failure 500 {object} map[string]interface{}
 // @Router /sls/sync/status [get]
 func (h *SLSHandler) GetSyncStatus(c *gin.Context) {
 	if h.syncService == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Sync service not available",
-			"message": "Sync service is not initialized",
+			"error":      "Sync service not available",
+			"code":       ErrorCodeInternal,
+			"message":    "Sync service is not initialized",
+			"request_id": requestID(c),
 		})
 		return
 	}
 
-	status, err := h.syncService.GetSyncStatus(c.Request.Context())
+	_, syncService, ok := h.targetServices(c)
+	if !ok {
+		return
+	}
+
+	status, err := syncService.GetSyncStatus(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get sync status",
-			"message": err.Error(),
+			"error":      "Failed to get sync status",
+			"code":       ErrorCodeInternal,
+			"message":    err.Error(),
+			"request_id": requestID(c),
 		})
 		return
 	}
@@ -173,28 +753,130 @@ func (h *SLSHandler) GetSyncStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, status)
 }
 
+// GetSyncHistory 获取同步执行记录
+// @Summary 获取同步执行记录
+// @Description 按开始时间倒序返回最近的同步执行记录（方向、起止时间、计数、结果和错误）
+// @Tags SLS
+// @Accept json
+// @Produce json
+// @Param limit query int false "返回条数（最大 100）" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /sls/sync/history [get]
+func (h *SLSHandler) GetSyncHistory(c *gin.Context) {
+	if h.syncService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Sync service not available",
+			"code":       ErrorCodeInternal,
+			"message":    "Sync service is not initialized",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	runs, err := h.syncService.GetSyncHistory(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get sync history",
+			"code":       ErrorCodeInternal,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  runs,
+		"count": len(runs),
+	})
+}
+
+// GetSyncLag 获取各 Project 的同步延迟
+// @Summary 获取同步延迟
+// @Description 按 SLS Project 返回距最近一次成功同步的秒数（基于各 Alert 的 last_synced_at），format=prometheus 时以 Prometheus 文本格式输出 sync_lag_seconds 指标
+// @Tags SLS
+// @Accept json
+// @Produce json
+// @Produce plain
+// @Param format query string false "输出格式（json 或 prometheus）"
+// @Success 200 {object} service.SyncLag
+// @Failure 500 {object} map[string]interface{}
+// @Router /sls/sync/lag [get]
+func (h *SLSHandler) GetSyncLag(c *gin.Context) {
+	if h.syncService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Sync service not available",
+			"code":       ErrorCodeInternal,
+			"message":    "Sync service is not initialized",
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	lag, err := h.syncService.GetSyncLag(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "Failed to get sync lag",
+			"code":       ErrorCodeInternal,
+			"message":    err.Error(),
+			"request_id": requestID(c),
+		})
+		return
+	}
+
+	if c.Query("format") == "prometheus" {
+		c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(lag.Prometheus()))
+		return
+	}
+
+	c.JSON(http.StatusOK, lag)
+}
+
 // GetSLSStatus 获取 SLS 连接状态
 // @Summary 获取 SLS 连接状态
 // @Description 获取 SLS 连接状态
 // @Tags SLS
 // @Accept json
 // @Produce json
+// @Param project query string false "SLS Project，默认使用 SLS_PROJECT"
+// @Param endpoint query string false "SLS Endpoint（如 cn-hangzhou.log.aliyuncs.com），默认使用 SLS_ENDPOINT"
 // @Success 200 {object} map[string]interface{}
 // @Router /sls/status [get]
 func (h *SLSHandler) GetSLSStatus(c *gin.Context) {
+	slsService, _, ok := h.targetServices(c)
+	if !ok {
+		return
+	}
+
 	// 尝试获取一个 alert 来测试连接
-	_, err := h.slsService.GetAlerts(c.Request.Context())
+	_, err := slsService.GetAlerts(c.Request.Context())
 
-	status := "connected"
-	message := "SLS connection is healthy"
+	response := gin.H{
+		"status":  "connected",
+		"message": "SLS connection is healthy",
+	}
 
 	if err != nil {
-		status = "disconnected"
-		message = "SLS connection failed: " + err.Error()
+		response["status"] = "disconnected"
+		response["message"] = "SLS connection failed: " + err.Error()
+		if kind := service.SLSErrorKind(err); kind != "" {
+			response["reason"] = kind
+		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":  status,
-		"message": message,
-	})
+	c.JSON(http.StatusOK, response)
+}
+
+// setSyncResultHeaders 将同步结果计数写入响应头，便于脚本快速检查，响应体仍以 JSON 为准
+func setSyncResultHeaders(c *gin.Context, result *service.SyncResult) {
+	if result == nil {
+		return
+	}
+
+	c.Header("X-Sync-Total", strconv.Itoa(result.Total))
+	c.Header("X-Sync-Created", strconv.Itoa(result.Created))
+	c.Header("X-Sync-Updated", strconv.Itoa(result.Updated))
+	c.Header("X-Sync-Skipped", strconv.Itoa(result.Skipped))
+	c.Header("X-Sync-Failed", strconv.Itoa(result.Failed))
 }
@@ -0,0 +1,383 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetSLSAlertsRejectsNameFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		target string
+	}{
+		{name: "with value", target: "/sls/alerts?name=cpu-high"},
+		{name: "empty value", target: "/sls/alerts?name="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/sls/alerts", NewSLSHandler(nil, nil, nil).GetSLSAlerts)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", recorder.Code, http.StatusBadRequest)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body["code"] != ErrorCodeValidation {
+				t.Errorf("code = %v, want %s", body["code"], ErrorCodeValidation)
+			}
+		})
+	}
+}
+
+// stubHealthChecker 返回固定连通性状态的 SLSHealthChecker
+type stubHealthChecker struct {
+	available bool
+	lastError string
+	marked    int
+}
+
+func (s *stubHealthChecker) Start(ctx context.Context) {}
+func (s *stubHealthChecker) Available() bool           { return s.available }
+func (s *stubHealthChecker) LastError() string         { return s.lastError }
+func (s *stubHealthChecker) MarkAvailable()            { s.available = true; s.marked++ }
+
+func TestRequireSLSAvailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		available  bool
+		status     int // 下游处理器返回的状态码
+		wantStatus int
+		wantCalled bool
+		wantMarked int
+	}{
+		{name: "probe reports down", available: false, status: http.StatusOK, wantStatus: http.StatusServiceUnavailable},
+		{name: "available and call succeeds", available: true, status: http.StatusOK, wantStatus: http.StatusOK, wantCalled: true, wantMarked: 1},
+		{name: "available and call fails", available: true, status: http.StatusBadGateway, wantStatus: http.StatusBadGateway, wantCalled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &stubHealthChecker{available: tt.available, lastError: "dial tcp: i/o timeout"}
+			called := false
+			router := gin.New()
+			router.GET("/sls/alerts", NewSLSHandler(nil, nil, checker).RequireSLSAvailable(), func(c *gin.Context) {
+				called = true
+				c.Status(tt.status)
+			})
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/sls/alerts", nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("handler called = %v, want %v", called, tt.wantCalled)
+			}
+			if checker.marked != tt.wantMarked {
+				t.Errorf("MarkAvailable calls = %d, want %d", checker.marked, tt.wantMarked)
+			}
+			if tt.wantStatus != http.StatusServiceUnavailable {
+				return
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body["error"] != "SLS unavailable" || body["code"] != ErrorCodeUnavailable || body["message"] != "dial tcp: i/o timeout" {
+				t.Errorf("body = %v, want SLS unavailable with the probe error", body)
+			}
+		})
+	}
+}
+
+// stubSyncService 返回预设结果的 SyncService，只实现两个方向的同步
+type stubSyncService struct {
+	service.SyncService
+	result *service.SyncResult
+	err    error
+}
+
+func (s *stubSyncService) SyncSLSToDatabase(ctx context.Context) (*service.SyncResult, error) {
+	return s.result, s.err
+}
+
+func (s *stubSyncService) SyncDatabaseToSLS(ctx context.Context) (*service.SyncResult, error) {
+	return s.result, s.err
+}
+
+func TestSyncResultHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newResult := func() *service.SyncResult {
+		result := service.NewSyncResult(service.SyncDirectionSLSToDB)
+		result.RecordCreated("a")
+		result.RecordCreated("b")
+		result.RecordUpdated("c")
+		result.RecordSkipped("d")
+		result.RecordFailed("e", errors.New("boom"))
+		return result
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		err        error
+		wantStatus int
+	}{
+		{name: "sls to db", path: "/sls/sync", wantStatus: http.StatusOK},
+		{name: "db to sls", path: "/sls/sync/db-to-sls", wantStatus: http.StatusOK},
+		{name: "partial failure", path: "/sls/sync", err: errors.New("1 alert failed"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewSLSHandler(nil, &stubSyncService{result: newResult(), err: tt.err}, nil)
+			router := gin.New()
+			router.POST("/sls/sync", h.SyncSLSAlerts)
+			router.POST("/sls/sync/db-to-sls", h.SyncDatabaseToSLS)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, tt.path, nil))
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+
+			var body struct {
+				Result struct {
+					Total   int `json:"total"`
+					Created int `json:"created"`
+					Updated int `json:"updated"`
+					Skipped int `json:"skipped"`
+					Failed  int `json:"failed"`
+				} `json:"result"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body.Result.Total != 5 {
+				t.Fatalf("body total = %d, want 5", body.Result.Total)
+			}
+			headers := map[string]int{
+				"X-Sync-Total":   body.Result.Total,
+				"X-Sync-Created": body.Result.Created,
+				"X-Sync-Updated": body.Result.Updated,
+				"X-Sync-Skipped": body.Result.Skipped,
+				"X-Sync-Failed":  body.Result.Failed,
+			}
+			for header, want := range headers {
+				if got := recorder.Header().Get(header); got != strconv.Itoa(want) {
+					t.Errorf("%s = %q, want %d (body count)", header, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSyncResultHeadersDryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	plan := &stubPlanSyncService{}
+	router := gin.New()
+	router.POST("/sls/sync", NewSLSHandler(nil, plan, nil).SyncSLSAlerts)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/sls/sync?dry_run=true", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if got := recorder.Header().Get("X-Sync-Total"); got != "" {
+		t.Errorf("X-Sync-Total = %q on a dry run, want unset", got)
+	}
+}
+
+// stubPlanSyncService 只实现 PlanSLSToDatabase，返回空计划
+type stubPlanSyncService struct {
+	service.SyncService
+}
+
+func (s *stubPlanSyncService) PlanSLSToDatabase(ctx context.Context) (*service.SyncPlan, error) {
+	return &service.SyncPlan{}, nil
+}
+
+// stubDiffSyncService 只实现 DiffInventory，返回预设的差异
+type stubDiffSyncService struct {
+	service.SyncService
+	diff *service.InventoryDiff
+}
+
+func (s *stubDiffSyncService) DiffInventory(ctx context.Context) (*service.InventoryDiff, error) {
+	return s.diff, nil
+}
+
+func TestDiffSLSInventoryFiltersByAction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	items := func(names ...string) []service.InventoryDiffItem {
+		result := make([]service.InventoryDiffItem, 0, len(names))
+		for _, name := range names {
+			result = append(result, service.InventoryDiffItem{Name: name})
+		}
+		return result
+	}
+	diff := &service.InventoryDiff{
+		SLSOnly:   items("sls-a", "sls-b"),
+		DBOnly:    items("db-a"),
+		Divergent: items("div-a", "div-b", "div-c"),
+	}
+	router := gin.New()
+	router.GET("/sls/diff", NewSLSHandler(nil, &stubDiffSyncService{diff: diff}, nil).DiffSLSInventory)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantNames []string
+		wantTotal int
+	}{
+		{name: "sls only", query: "action=sls_only", wantNames: []string{"sls-a", "sls-b"}, wantTotal: 2},
+		{name: "db only", query: "action=db_only", wantNames: []string{"db-a"}, wantTotal: 1},
+		{name: "divergent first page", query: "action=divergent&page_size=2", wantNames: []string{"div-a", "div-b"}, wantTotal: 3},
+		{name: "divergent second page", query: "action=divergent&page=2&page_size=2", wantNames: []string{"div-c"}, wantTotal: 3},
+		{name: "page past the end", query: "action=divergent&page=5&page_size=2", wantNames: []string{}, wantTotal: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/sls/diff?"+tt.query, nil))
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", recorder.Code, recorder.Body.String())
+			}
+
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			// 只返回请求的分类
+			for _, key := range []string{"sls_only", "db_only", "divergent"} {
+				if _, ok := body[key]; ok {
+					t.Errorf("body contains %q, want only the requested category", key)
+				}
+			}
+			var data []service.InventoryDiffItem
+			var pagination struct {
+				Total int `json:"total"`
+			}
+			if err := json.Unmarshal(body["data"], &data); err != nil {
+				t.Fatalf("decode data: %v", err)
+			}
+			if err := json.Unmarshal(body["pagination"], &pagination); err != nil {
+				t.Fatalf("decode pagination: %v", err)
+			}
+			names := make([]string, 0, len(data))
+			for _, item := range data {
+				names = append(names, item.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.wantNames, ",") || pagination.Total != tt.wantTotal {
+				t.Errorf("data = %v (total %d), want %v (total %d)", names, pagination.Total, tt.wantNames, tt.wantTotal)
+			}
+		})
+	}
+
+	t.Run("combined without action", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/sls/diff", nil))
+		var body map[string]interface{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if body["sls_only_count"] != 2.0 || body["db_only_count"] != 1.0 || body["divergent_count"] != 3.0 {
+			t.Errorf("body = %v, want all three categories", body)
+		}
+	})
+
+	t.Run("invalid action", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/sls/diff?action=identical", nil))
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("status = %d, want 400", recorder.Code)
+		}
+	})
+}
+
+// stubPageSLSService 记录分页查询的 SLSService，只实现 ListAlertsPage
+type stubPageSLSService struct {
+	service.SLSService
+	queries []service.SLSAlertPageQuery
+}
+
+func (s *stubPageSLSService) ListAlertsPage(ctx context.Context, query service.SLSAlertPageQuery) (*service.SLSAlertPage, error) {
+	s.queries = append(s.queries, query)
+	return &service.SLSAlertPage{Offset: query.Offset, Size: query.Size, Limit: query.Size}, nil
+}
+
+func TestGetSLSAlertsPageSizeBounds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantSize   int
+	}{
+		{name: "default size", target: "/sls/alerts?offset=0", wantStatus: http.StatusOK, wantSize: 10},
+		{name: "minimum size", target: "/sls/alerts?size=1", wantStatus: http.StatusOK, wantSize: 1},
+		{name: "maximum size", target: "/sls/alerts?size=200", wantStatus: http.StatusOK, wantSize: 200},
+		{name: "maximum limit", target: "/sls/alerts?limit=200", wantStatus: http.StatusOK, wantSize: 200},
+		{name: "zero size", target: "/sls/alerts?size=0", wantStatus: http.StatusBadRequest},
+		{name: "size above maximum", target: "/sls/alerts?size=201", wantStatus: http.StatusBadRequest},
+		{name: "limit above maximum", target: "/sls/alerts?limit=201", wantStatus: http.StatusBadRequest},
+		{name: "negative offset", target: "/sls/alerts?offset=-1", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sls := &stubPageSLSService{}
+			router := gin.New()
+			router.GET("/sls/alerts", NewSLSHandler(sls, nil, nil).GetSLSAlerts)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				if len(sls.queries) != 0 {
+					t.Errorf("queries = %+v, want SLS not called for an invalid page", sls.queries)
+				}
+				var body map[string]interface{}
+				if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+					t.Fatalf("decode body: %v", err)
+				}
+				if body["code"] != ErrorCodeValidation || !strings.Contains(body["message"].(string), "between 1 and 200") {
+					t.Errorf("body = %v, want a validation error naming the size range", body)
+				}
+				return
+			}
+			if len(sls.queries) != 1 || sls.queries[0].Size != tt.wantSize {
+				t.Errorf("queries = %+v, want one query with size %d", sls.queries, tt.wantSize)
+			}
+		})
+	}
+}
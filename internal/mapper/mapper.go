@@ -0,0 +1,496 @@
+package mapper
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	sls20201230 "github.com/alibabacloud-go/sls-20201230/v6/client"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// Warning 非致命的校验或转换警告
+type Warning struct {
+	Alert   string `json:"alert,omitempty"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// normalizeTimestamp 将缺失或不大于 0 的 SLS 时间戳统一为 nil
+func normalizeTimestamp(ts *int64) *int64 {
+	if ts == nil || *ts <= 0 {
+		return nil
+	}
+	value := *ts
+	return &value
+}
+
+// SLSToModel 将 SLS 的 Alert 转换为本地模型，只做字段映射，不合并重复标签、不计算内容哈希。
+// CreateTime 和 LastModifiedTime 由 SLS 维护（Unix 秒），原样保存，缺失或为 0 时保存为 nil，
+// 同步比较时 nil 表示未知，会保守地更新
+func SLSToModel(slsAlert *sls20201230.Alert) *models.Alert {
+	if slsAlert == nil {
+		return nil
+	}
+
+	alert := &models.Alert{
+		Name:             tea.StringValue(slsAlert.Name),
+		DisplayName:      tea.StringValue(slsAlert.DisplayName),
+		Description:      slsAlert.Description,
+		Status:           tea.StringValue(slsAlert.Status),
+		CreateTime:       normalizeTimestamp(slsAlert.CreateTime),
+		LastModifiedTime: normalizeTimestamp(slsAlert.LastModifiedTime),
+	}
+
+	// 转换 Configuration
+	if slsAlert.Configuration != nil {
+		alert.Configuration = &models.AlertConfiguration{
+			AutoAnnotation: slsAlert.Configuration.AutoAnnotation,
+			Dashboard:      slsAlert.Configuration.Dashboard,
+			MuteUntil:      slsAlert.Configuration.MuteUntil,
+			NoDataFire:     slsAlert.Configuration.NoDataFire,
+			NoDataSeverity: slsAlert.Configuration.NoDataSeverity,
+			Threshold:      slsAlert.Configuration.Threshold,
+			Type:           slsAlert.Configuration.Type,
+			Version:        slsAlert.Configuration.Version,
+			SendResolved:   slsAlert.Configuration.SendResolved,
+		}
+
+		// 转换 ConditionConfiguration
+		if condition := slsAlert.Configuration.ConditionConfiguration; condition != nil &&
+			(condition.Condition != nil || condition.CountCondition != nil) {
+			conditionConfig := &models.ConditionConfiguration{
+				Condition:      slsAlert.Configuration.ConditionConfiguration.Condition,
+				CountCondition: slsAlert.Configuration.ConditionConfiguration.CountCondition,
+			}
+			alert.Configuration.ConditionConfig = conditionConfig
+		}
+
+		// 转换 GroupConfiguration
+		if slsAlert.Configuration.GroupConfiguration != nil {
+			// 字段按数组保存，字段名中的逗号不会被拆开
+			var fields models.StringSlice
+			for _, field := range slsAlert.Configuration.GroupConfiguration.Fields {
+				if field != nil {
+					fields = append(fields, *field)
+				}
+			}
+
+			groupConfig := &models.GroupConfiguration{
+				Fields: fields,
+				Type:   slsAlert.Configuration.GroupConfiguration.Type,
+			}
+			alert.Configuration.GroupConfig = groupConfig
+		}
+
+		// 转换 PolicyConfiguration
+		if slsAlert.Configuration.PolicyConfiguration != nil {
+			policyConfig := &models.PolicyConfiguration{
+				AlertPolicyId:  slsAlert.Configuration.PolicyConfiguration.AlertPolicyId,
+				ActionPolicyId: slsAlert.Configuration.PolicyConfiguration.ActionPolicyId,
+				RepeatInterval: slsAlert.Configuration.PolicyConfiguration.RepeatInterval,
+			}
+			alert.Configuration.PolicyConfig = policyConfig
+		}
+
+		// 转换 TemplateConfiguration
+		if slsAlert.Configuration.TemplateConfiguration != nil {
+			// 处理 Aonotations 和 Tokens 的 JSON 转换
+			var aonotationsJSON, tokensJSON *string
+
+			if slsAlert.Configuration.TemplateConfiguration.Aonotations != nil {
+				if aonotationsBytes, err := json.Marshal(slsAlert.Configuration.TemplateConfiguration.Aonotations); err == nil {
+					aonotationsJSON = tea.String(string(aonotationsBytes))
+				}
+			}
+
+			if slsAlert.Configuration.TemplateConfiguration.Tokens != nil {
+				if tokensBytes, err := json.Marshal(slsAlert.Configuration.TemplateConfiguration.Tokens); err == nil {
+					tokensJSON = tea.String(string(tokensBytes))
+				}
+			}
+
+			templateConfig := &models.TemplateConfiguration{
+				TemplateId:  slsAlert.Configuration.TemplateConfiguration.Id,
+				Lang:        slsAlert.Configuration.TemplateConfiguration.Lang,
+				Type:        slsAlert.Configuration.TemplateConfiguration.Type,
+				Version:     slsAlert.Configuration.TemplateConfiguration.Version,
+				Aonotations: aonotationsJSON,
+				Tokens:      tokensJSON,
+			}
+			alert.Configuration.TemplateConfig = templateConfig
+		}
+
+		// 转换 SeverityConfigurations
+		if slsAlert.Configuration.SeverityConfigurations != nil {
+			for _, slsSeverity := range slsAlert.Configuration.SeverityConfigurations {
+				severityConfig := &models.SeverityConfiguration{
+					Severity: slsSeverity.Severity,
+				}
+
+				// 处理 EvalCondition
+				if slsSeverity.EvalCondition != nil {
+					evalCondition := &models.ConditionConfiguration{
+						Condition:      slsSeverity.EvalCondition.Condition,
+						CountCondition: slsSeverity.EvalCondition.CountCondition,
+					}
+					severityConfig.EvalCondition = evalCondition
+				}
+
+				alert.Configuration.SeverityConfigs = append(alert.Configuration.SeverityConfigs, *severityConfig)
+			}
+		}
+
+		// 转换 QueryList
+		if slsAlert.Configuration.QueryList != nil {
+			for _, slsQuery := range slsAlert.Configuration.QueryList {
+				query := &models.AlertQuery{
+					ChartTitle:   slsQuery.ChartTitle,
+					DashboardId:  slsQuery.DashboardId,
+					End:          slsQuery.End,
+					PowerSqlMode: slsQuery.PowerSqlMode,
+					Project:      slsQuery.Project,
+					Query:        tea.StringValue(slsQuery.Query),
+					Region:       slsQuery.Region,
+					RoleArn:      slsQuery.RoleArn,
+					Start:        slsQuery.Start,
+					Store:        slsQuery.Store,
+					StoreType:    slsQuery.StoreType,
+					TimeSpanType: slsQuery.TimeSpanType,
+					Ui:           slsQuery.Ui,
+				}
+				alert.Queries = append(alert.Queries, *query)
+			}
+		}
+
+		// 转换 Labels，SLS 中 Labels 是键值对
+		for _, slsLabel := range slsAlert.Configuration.Labels {
+			if slsLabel == nil {
+				continue
+			}
+			value := tea.StringValue(slsLabel.Value)
+			alert.Tags = append(alert.Tags, models.AlertTag{
+				TagType:  "label",
+				TagKey:   tea.StringValue(slsLabel.Key),
+				TagValue: &value,
+			})
+		}
+
+		// 转换 Tags，SLS 中 Tags 是字符串数组，保存为没有值的 label
+		for _, slsTag := range slsAlert.Configuration.Tags {
+			alert.Tags = append(alert.Tags, models.AlertTag{
+				TagType:  "label",
+				TagKey:   tea.StringValue(slsTag),
+				TagValue: nil,
+			})
+		}
+
+		// 转换 Sink 配置
+		if slsAlert.Configuration.SinkAlerthub != nil {
+			sinkAlerthubConfig := &models.SinkAlerthubConfiguration{
+				Enabled: slsAlert.Configuration.SinkAlerthub.Enabled,
+			}
+			alert.Configuration.SinkAlerthubConfig = sinkAlerthubConfig
+		}
+
+		if slsAlert.Configuration.SinkCms != nil {
+			sinkCmsConfig := &models.SinkCmsConfiguration{
+				Enabled: slsAlert.Configuration.SinkCms.Enabled,
+			}
+			alert.Configuration.SinkCmsConfig = sinkCmsConfig
+		}
+
+		if slsAlert.Configuration.SinkEventStore != nil {
+			sinkEventStoreConfig := &models.SinkEventStoreConfiguration{
+				Enabled:    slsAlert.Configuration.SinkEventStore.Enabled,
+				Endpoint:   slsAlert.Configuration.SinkEventStore.Endpoint,
+				EventStore: slsAlert.Configuration.SinkEventStore.EventStore,
+				Project:    slsAlert.Configuration.SinkEventStore.Project,
+				RoleArn:    slsAlert.Configuration.SinkEventStore.RoleArn,
+			}
+			alert.Configuration.SinkEventStoreConfig = sinkEventStoreConfig
+		}
+
+		// 转换 JoinConfigurations
+		if slsAlert.Configuration.JoinConfigurations != nil {
+			for _, slsJoinConfig := range slsAlert.Configuration.JoinConfigurations {
+				// 将 Condition 和 Type 组合到 JoinConfig 字段中
+				var joinConfigStr *string
+				if slsJoinConfig.Condition != nil || slsJoinConfig.Type != nil {
+					joinData := map[string]interface{}{
+						"condition": slsJoinConfig.Condition,
+						"type":      slsJoinConfig.Type,
+					}
+					if joinBytes, err := json.Marshal(joinData); err == nil {
+						joinConfigStr = tea.String(string(joinBytes))
+					}
+				}
+
+				joinConfig := &models.JoinConfiguration{
+					JoinType:   slsJoinConfig.Type,
+					JoinConfig: joinConfigStr,
+				}
+				alert.Configuration.JoinConfigs = append(alert.Configuration.JoinConfigs, *joinConfig)
+			}
+		}
+
+		// 转换 Annotations
+		if slsAlert.Configuration.Annotations != nil {
+			for _, slsAnnotation := range slsAlert.Configuration.Annotations {
+				annotation := &models.AlertTag{
+					TagType:  "annotation",
+					TagKey:   tea.StringValue(slsAnnotation.Key),
+					TagValue: slsAnnotation.Value,
+				}
+				alert.Tags = append(alert.Tags, *annotation)
+			}
+		}
+	}
+
+	// 转换 Schedule
+	if slsAlert.Schedule != nil {
+		alert.Schedule = &models.AlertSchedule{
+			CronExpression: slsAlert.Schedule.CronExpression,
+			Delay:          slsAlert.Schedule.Delay,
+			Interval:       slsAlert.Schedule.Interval,
+			RunImmediately: slsAlert.Schedule.RunImmediately,
+			TimeZone:       slsAlert.Schedule.TimeZone,
+			Type:           tea.StringValue(slsAlert.Schedule.Type),
+		}
+	}
+
+	return alert
+}
+
+// ModelToSLS 将本地模型转换为 SLS SDK 模型，无法完整转换的字段以警告形式返回。
+// CreateTime 和 LastModifiedTime 为 0 时按 nil 处理；创建和更新请求不携带这两个字段，
+// 它们只用于与 SLS 中已有规则的比较
+func ModelToSLS(alert *models.Alert) (*sls20201230.Alert, []Warning, error) {
+	if alert == nil {
+		return nil, nil, fmt.Errorf("alert is nil")
+	}
+
+	var warnings []Warning
+	warn := func(field, message string) {
+		warnings = append(warnings, Warning{Alert: alert.Name, Field: field, Message: message})
+	}
+
+	slsAlert := &sls20201230.Alert{
+		Name:             tea.String(alert.Name),
+		DisplayName:      tea.String(alert.DisplayName),
+		Description:      alert.Description,
+		Status:           tea.String(alert.Status),
+		CreateTime:       normalizeTimestamp(alert.CreateTime),
+		LastModifiedTime: normalizeTimestamp(alert.LastModifiedTime),
+	}
+
+	// 转换 Configuration
+	if alert.Configuration != nil {
+		slsConfig := &sls20201230.AlertConfiguration{
+			AutoAnnotation: alert.Configuration.AutoAnnotation,
+			Dashboard:      alert.Configuration.Dashboard,
+			MuteUntil:      alert.Configuration.MuteUntil,
+			NoDataFire:     alert.Configuration.NoDataFire,
+			NoDataSeverity: alert.Configuration.NoDataSeverity,
+			Threshold:      alert.Configuration.Threshold,
+			Type:           alert.Configuration.Type,
+			Version:        alert.Configuration.Version,
+			SendResolved:   alert.Configuration.SendResolved,
+		}
+
+		// 转换 ConditionConfiguration
+		if alert.Configuration.ConditionConfig != nil {
+			slsConfig.ConditionConfiguration = &sls20201230.ConditionConfiguration{
+				Condition:      alert.Configuration.ConditionConfig.Condition,
+				CountCondition: alert.Configuration.ConditionConfig.CountCondition,
+			}
+		}
+
+		// 转换 GroupConfiguration
+		if alert.Configuration.GroupConfig != nil {
+			var fields []*string
+			for _, field := range alert.Configuration.GroupConfig.Fields {
+				fields = append(fields, tea.String(field))
+			}
+
+			slsConfig.GroupConfiguration = &sls20201230.GroupConfiguration{
+				Fields: fields,
+				Type:   alert.Configuration.GroupConfig.Type,
+			}
+		}
+
+		// 转换 PolicyConfiguration
+		if alert.Configuration.PolicyConfig != nil {
+			slsConfig.PolicyConfiguration = &sls20201230.PolicyConfiguration{
+				ActionPolicyId: alert.Configuration.PolicyConfig.ActionPolicyId,
+				AlertPolicyId:  alert.Configuration.PolicyConfig.AlertPolicyId,
+				RepeatInterval: alert.Configuration.PolicyConfig.RepeatInterval,
+			}
+		}
+
+		// 转换 TemplateConfiguration
+		if alert.Configuration.TemplateConfig != nil {
+			// 存储的 JSON 无法解析时返回错误，不推送空对象覆盖 SLS 中的模板变量
+			var aonotations, tokens map[string]interface{}
+			var err error
+			if alert.Configuration.TemplateConfig.Aonotations != nil {
+				if aonotations, err = DecodeTemplateJSON(*alert.Configuration.TemplateConfig.Aonotations); err != nil {
+					return nil, warnings, fmt.Errorf("invalid configuration.template_config.aonotations: %w", err)
+				}
+			}
+			if alert.Configuration.TemplateConfig.Tokens != nil {
+				if tokens, err = DecodeTemplateJSON(*alert.Configuration.TemplateConfig.Tokens); err != nil {
+					return nil, warnings, fmt.Errorf("invalid configuration.template_config.tokens: %w", err)
+				}
+			}
+
+			slsConfig.TemplateConfiguration = &sls20201230.TemplateConfiguration{
+				Id:          alert.Configuration.TemplateConfig.TemplateId,
+				Lang:        alert.Configuration.TemplateConfig.Lang,
+				Type:        alert.Configuration.TemplateConfig.Type,
+				Version:     alert.Configuration.TemplateConfig.Version,
+				Aonotations: aonotations,
+				Tokens:      tokens,
+			}
+		}
+
+		// 转换 QueryList
+		if len(alert.Queries) > 0 {
+			var slsQueries []*sls20201230.AlertQuery
+			for _, query := range alert.Queries {
+				slsQuery := &sls20201230.AlertQuery{
+					ChartTitle:   query.ChartTitle,
+					DashboardId:  query.DashboardId,
+					End:          query.End,
+					PowerSqlMode: query.PowerSqlMode,
+					Project:      query.Project,
+					Query:        tea.String(query.Query),
+					Region:       query.Region,
+					RoleArn:      query.RoleArn,
+					Start:        query.Start,
+					Store:        query.Store,
+					StoreType:    query.StoreType,
+					TimeSpanType: query.TimeSpanType,
+					Ui:           query.Ui,
+				}
+				slsQueries = append(slsQueries, slsQuery)
+			}
+			slsConfig.QueryList = slsQueries
+		}
+
+		// 转换 SeverityConfigurations
+		for _, severity := range alert.Configuration.SeverityConfigs {
+			slsSeverity := &sls20201230.SeverityConfiguration{
+				Severity: severity.Severity,
+			}
+			if severity.EvalCondition != nil {
+				slsSeverity.EvalCondition = &sls20201230.ConditionConfiguration{
+					Condition:      severity.EvalCondition.Condition,
+					CountCondition: severity.EvalCondition.CountCondition,
+				}
+			}
+			slsConfig.SeverityConfigurations = append(slsConfig.SeverityConfigurations, slsSeverity)
+		}
+
+		// 转换 JoinConfigurations，JoinConfig 中保存了 SLS 的 condition 和 type
+		for i, join := range alert.Configuration.JoinConfigs {
+			slsJoin := &sls20201230.JoinConfiguration{
+				Type: join.JoinType,
+			}
+			if join.JoinConfig != nil {
+				var joinData struct {
+					Condition *string `json:"condition"`
+					Type      *string `json:"type"`
+				}
+				if err := json.Unmarshal([]byte(*join.JoinConfig), &joinData); err != nil {
+					warn(fmt.Sprintf("configuration.join_configs[%d].join_config", i), fmt.Sprintf("invalid JSON, condition dropped: %v", err))
+				} else {
+					slsJoin.Condition = joinData.Condition
+					if joinData.Type != nil {
+						slsJoin.Type = joinData.Type
+					}
+				}
+			}
+			slsConfig.JoinConfigurations = append(slsConfig.JoinConfigurations, slsJoin)
+		}
+
+		// 转换 Labels 和 Tags：有值的 label 作为键值对写入 Labels，没有值的 label 作为字符串写入 Tags
+		if len(alert.Tags) > 0 {
+			var slsLabels []*sls20201230.AlertTag
+			var slsTags []*string
+			for i, tag := range alert.Tags {
+				switch tag.TagType {
+				case "label":
+					if tag.TagValue != nil {
+						slsLabels = append(slsLabels, &sls20201230.AlertTag{
+							Key:   tea.String(tag.TagKey),
+							Value: tea.String(*tag.TagValue),
+						})
+					} else {
+						slsTags = append(slsTags, tea.String(tag.TagKey))
+					}
+				case "annotation":
+					// annotation 在下面单独转换
+				default:
+					warn(fmt.Sprintf("tags[%d].tag_type", i), fmt.Sprintf("unknown tag type %q dropped", tag.TagType))
+				}
+			}
+			slsConfig.Labels = slsLabels
+			slsConfig.Tags = slsTags
+		}
+
+		// 转换 Annotations
+		if len(alert.Tags) > 0 {
+			var slsAnnotations []*sls20201230.AlertTag
+			for _, tag := range alert.Tags {
+				if tag.TagType == "annotation" {
+					slsAnnotation := &sls20201230.AlertTag{
+						Key:   tea.String(tag.TagKey),
+						Value: tag.TagValue,
+					}
+					slsAnnotations = append(slsAnnotations, slsAnnotation)
+				}
+			}
+			slsConfig.Annotations = slsAnnotations
+		}
+
+		// 转换 Sink 配置
+		if alert.Configuration.SinkAlerthubConfig != nil {
+			slsConfig.SinkAlerthub = &sls20201230.SinkAlerthubConfiguration{
+				Enabled: tea.Bool(tea.BoolValue(alert.Configuration.SinkAlerthubConfig.Enabled)),
+			}
+		}
+
+		if alert.Configuration.SinkCmsConfig != nil {
+			slsConfig.SinkCms = &sls20201230.SinkCmsConfiguration{
+				Enabled: tea.Bool(tea.BoolValue(alert.Configuration.SinkCmsConfig.Enabled)),
+			}
+		}
+
+		if alert.Configuration.SinkEventStoreConfig != nil {
+			slsConfig.SinkEventStore = &sls20201230.SinkEventStoreConfiguration{
+				Enabled:    tea.Bool(tea.BoolValue(alert.Configuration.SinkEventStoreConfig.Enabled)),
+				Endpoint:   alert.Configuration.SinkEventStoreConfig.Endpoint,
+				EventStore: alert.Configuration.SinkEventStoreConfig.EventStore,
+				Project:    alert.Configuration.SinkEventStoreConfig.Project,
+				RoleArn:    alert.Configuration.SinkEventStoreConfig.RoleArn,
+			}
+		}
+
+		slsAlert.Configuration = slsConfig
+	}
+
+	// 转换 Schedule
+	if alert.Schedule != nil {
+		slsAlert.Schedule = &sls20201230.Schedule{
+			CronExpression: alert.Schedule.CronExpression,
+			Delay:          alert.Schedule.Delay,
+			Interval:       alert.Schedule.Interval,
+			RunImmediately: alert.Schedule.RunImmediately,
+			TimeZone:       alert.Schedule.TimeZone,
+			Type:           tea.String(alert.Schedule.Type),
+		}
+	}
+
+	return slsAlert, warnings, nil
+}
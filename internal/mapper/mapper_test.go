@@ -0,0 +1,387 @@
+package mapper
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	sls20201230 "github.com/alibabacloud-go/sls-20201230/v6/client"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// newTestAlert 返回可以无损转换的 Alert
+func newTestAlert(name string) *models.Alert {
+	return &models.Alert{
+		Name:        name,
+		DisplayName: "Alert " + name,
+		Status:      "ENABLED",
+		Configuration: &models.AlertConfiguration{
+			Threshold: tea.Int32(1),
+			Type:      tea.String("default"),
+			Version:   tea.String("2.0"),
+			ConditionConfig: &models.ConditionConfiguration{
+				Condition: tea.String("cnt > 0"),
+			},
+		},
+		Schedule: &models.AlertSchedule{
+			Type:     "FixedRate",
+			Interval: tea.String("1m"),
+		},
+		Tags: []models.AlertTag{
+			{TagType: "label", TagKey: "team", TagValue: tea.String("ops")},
+		},
+		Queries: []models.AlertQuery{
+			{Query: "* | select count(*) as cnt", Store: tea.String("app-log"), StoreType: tea.String("log")},
+		},
+	}
+}
+
+func TestModelToSLSWarnings(t *testing.T) {
+	tests := []struct {
+		name      string
+		modify    func(alert *models.Alert)
+		wantField string // 为空表示不应产生警告
+		wantMsg   string
+	}{
+		{
+			name:   "lossless alert",
+			modify: func(alert *models.Alert) {},
+		},
+		{
+			name: "invalid join config JSON",
+			modify: func(alert *models.Alert) {
+				alert.Configuration.JoinConfigs = []models.JoinConfiguration{
+					{JoinType: tea.String("cross_join"), JoinConfig: tea.String("{not json")},
+				}
+			},
+			wantField: "configuration.join_configs[0].join_config",
+			wantMsg:   "invalid JSON, condition dropped",
+		},
+		{
+			name: "unknown tag type",
+			modify: func(alert *models.Alert) {
+				alert.Tags = append(alert.Tags, models.AlertTag{TagType: "foo", TagKey: "k", TagValue: tea.String("v")})
+			},
+			wantField: "tags[1].tag_type",
+			wantMsg:   `unknown tag type "foo" dropped`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := newTestAlert("lossy")
+			tt.modify(alert)
+
+			slsAlert, warnings, err := ModelToSLS(alert)
+			if err != nil {
+				t.Fatalf("ModelToSLS: %v", err)
+			}
+			if slsAlert == nil {
+				t.Fatal("ModelToSLS returned nil alert")
+			}
+
+			if tt.wantField == "" {
+				if len(warnings) != 0 {
+					t.Fatalf("warnings = %+v, want none", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 {
+				t.Fatalf("warnings = %+v, want exactly one", warnings)
+			}
+			w := warnings[0]
+			if w.Alert != "lossy" || w.Field != tt.wantField || !strings.Contains(w.Message, tt.wantMsg) {
+				t.Errorf("warning = %+v, want alert %q field %q message containing %q", w, "lossy", tt.wantField, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestModelToSLSInvalidJoinConfigKeepsType(t *testing.T) {
+	alert := newTestAlert("join")
+	alert.Configuration.JoinConfigs = []models.JoinConfiguration{
+		{JoinType: tea.String("cross_join"), JoinConfig: tea.String("{not json")},
+	}
+
+	slsAlert, _, err := ModelToSLS(alert)
+	if err != nil {
+		t.Fatalf("ModelToSLS: %v", err)
+	}
+	joins := slsAlert.Configuration.JoinConfigurations
+	if len(joins) != 1 {
+		t.Fatalf("join configurations = %d, want 1", len(joins))
+	}
+	if tea.StringValue(joins[0].Type) != "cross_join" || joins[0].Condition != nil {
+		t.Errorf("join = type %q condition %v, want type cross_join without condition", tea.StringValue(joins[0].Type), joins[0].Condition)
+	}
+}
+
+func TestModelToSLSErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		alert   func() *models.Alert
+		wantErr string
+	}{
+		{
+			name:    "nil alert",
+			alert:   func() *models.Alert { return nil },
+			wantErr: "alert is nil",
+		},
+		{
+			name: "invalid template annotations",
+			alert: func() *models.Alert {
+				alert := newTestAlert("template")
+				alert.Configuration.TemplateConfig = &models.TemplateConfiguration{Aonotations: tea.String("[1, 2]")}
+				return alert
+			},
+			wantErr: "invalid configuration.template_config.aonotations",
+		},
+		{
+			name: "invalid template tokens",
+			alert: func() *models.Alert {
+				alert := newTestAlert("template")
+				alert.Configuration.TemplateConfig = &models.TemplateConfiguration{Tokens: tea.String(`{"a":1}x`)}
+				return alert
+			},
+			wantErr: "invalid configuration.template_config.tokens",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slsAlert, _, err := ModelToSLS(tt.alert())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+			}
+			if slsAlert != nil {
+				t.Errorf("alert = %+v, want nil on error", slsAlert)
+			}
+		})
+	}
+}
+
+func TestModelToSLSSeverityAndJoinConfigurations(t *testing.T) {
+	alert := newTestAlert("tiers")
+	alert.Configuration.SeverityConfigs = []models.SeverityConfiguration{
+		{Severity: tea.Int32(6), EvalCondition: &models.ConditionConfiguration{Condition: tea.String("cnt > 10"), CountCondition: tea.String("__count__ > 0")}},
+		{Severity: tea.Int32(10), EvalCondition: &models.ConditionConfiguration{Condition: tea.String("cnt > 100")}},
+	}
+	alert.Configuration.JoinConfigs = []models.JoinConfiguration{
+		{JoinType: tea.String("left_join"), JoinConfig: tea.String(`{"condition":"$0.host == $1.host","type":"left_join"}`)},
+	}
+
+	slsAlert, warnings, err := ModelToSLS(alert)
+	if err != nil {
+		t.Fatalf("ModelToSLS: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("warnings = %+v, want none", warnings)
+	}
+
+	severities := slsAlert.Configuration.SeverityConfigurations
+	if len(severities) != 2 {
+		t.Fatalf("severity configurations = %d, want 2", len(severities))
+	}
+	for i, want := range alert.Configuration.SeverityConfigs {
+		got := severities[i]
+		if tea.Int32Value(got.Severity) != tea.Int32Value(want.Severity) || got.EvalCondition == nil {
+			t.Fatalf("severities[%d] = %+v, want severity %d with an eval condition", i, got, tea.Int32Value(want.Severity))
+		}
+		if tea.StringValue(got.EvalCondition.Condition) != tea.StringValue(want.EvalCondition.Condition) ||
+			tea.StringValue(got.EvalCondition.CountCondition) != tea.StringValue(want.EvalCondition.CountCondition) {
+			t.Errorf("severities[%d].eval_condition = %+v, want %+v", i, got.EvalCondition, want.EvalCondition)
+		}
+	}
+
+	joins := slsAlert.Configuration.JoinConfigurations
+	if len(joins) != 1 {
+		t.Fatalf("join configurations = %d, want 1", len(joins))
+	}
+	if tea.StringValue(joins[0].Type) != "left_join" || tea.StringValue(joins[0].Condition) != "$0.host == $1.host" {
+		t.Errorf("join = type %q condition %q, want left_join on $0.host == $1.host", tea.StringValue(joins[0].Type), tea.StringValue(joins[0].Condition))
+	}
+
+	// 再转换回模型后严重程度和 join 条件保持不变
+	roundTrip := SLSToModel(slsAlert).Configuration
+	if len(roundTrip.SeverityConfigs) != 2 || tea.StringValue(roundTrip.SeverityConfigs[1].EvalCondition.Condition) != "cnt > 100" {
+		t.Errorf("round-trip severities = %+v, want both tiers", roundTrip.SeverityConfigs)
+	}
+	if len(roundTrip.JoinConfigs) != 1 || !strings.Contains(tea.StringValue(roundTrip.JoinConfigs[0].JoinConfig), `$0.host == $1.host`) {
+		t.Errorf("round-trip joins = %+v, want the left_join condition", roundTrip.JoinConfigs)
+	}
+}
+
+func TestGroupConfigFieldsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []string
+	}{
+		{name: "plain fields", fields: []string{"host", "service"}},
+		{name: "field with comma", fields: []string{"region,zone", "host"}},
+		{name: "field with spaces and quotes", fields: []string{` padded `, `say "hi"`}},
+		{name: "no fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := newTestAlert("group")
+			alert.Configuration.GroupConfig = &models.GroupConfiguration{Fields: tt.fields, Type: tea.String("custom")}
+
+			slsAlert, warnings, err := ModelToSLS(alert)
+			if err != nil {
+				t.Fatalf("ModelToSLS: %v", err)
+			}
+			if len(warnings) != 0 {
+				t.Fatalf("warnings = %+v, want none", warnings)
+			}
+			group := slsAlert.Configuration.GroupConfiguration
+			if got := tea.StringSliceValue(group.Fields); len(got) != len(tt.fields) || (len(got) > 0 && !reflect.DeepEqual(got, tt.fields)) {
+				t.Errorf("SLS group fields = %q, want %q", got, tt.fields)
+			}
+
+			roundTrip := SLSToModel(slsAlert).Configuration.GroupConfig
+			if roundTrip == nil {
+				t.Fatal("round-trip group config = nil")
+			}
+			if got := []string(roundTrip.Fields); len(got) != len(tt.fields) || (len(got) > 0 && !reflect.DeepEqual(got, tt.fields)) {
+				t.Errorf("round-trip fields = %q, want %q", got, tt.fields)
+			}
+			if tea.StringValue(roundTrip.Type) != "custom" {
+				t.Errorf("round-trip type = %q, want custom", tea.StringValue(roundTrip.Type))
+			}
+		})
+	}
+}
+
+// tagStrings 返回 "类型:键=值" 形式的标签，值为 nil 时为 "类型:键"
+func tagStrings(tags []models.AlertTag) []string {
+	var values []string
+	for _, tag := range tags {
+		value := tag.TagType + ":" + tag.TagKey
+		if tag.TagValue != nil {
+			value += "=" + *tag.TagValue
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func TestTagsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		tags       []models.AlertTag
+		wantLabels map[string]string // SLS labels，键值对
+		wantTags   []string          // SLS tags，没有值的 label
+		wantAnnots map[string]string // SLS annotations
+	}{
+		{
+			name:       "label with value",
+			tags:       []models.AlertTag{{TagType: "label", TagKey: "env", TagValue: tea.String("prod")}},
+			wantLabels: map[string]string{"env": "prod"},
+		},
+		{
+			name:       "label with empty value",
+			tags:       []models.AlertTag{{TagType: "label", TagKey: "env", TagValue: tea.String("")}},
+			wantLabels: map[string]string{"env": ""},
+		},
+		{
+			name:     "label without value",
+			tags:     []models.AlertTag{{TagType: "label", TagKey: "critical"}},
+			wantTags: []string{"critical"},
+		},
+		{
+			name:       "annotation",
+			tags:       []models.AlertTag{{TagType: "annotation", TagKey: "summary", TagValue: tea.String("cpu high")}},
+			wantAnnots: map[string]string{"summary": "cpu high"},
+		},
+		{
+			name: "mixed",
+			tags: []models.AlertTag{
+				{TagType: "label", TagKey: "env", TagValue: tea.String("prod")},
+				{TagType: "label", TagKey: "team", TagValue: tea.String("ops")},
+				{TagType: "label", TagKey: "critical"},
+				{TagType: "annotation", TagKey: "summary", TagValue: tea.String("cpu high")},
+			},
+			wantLabels: map[string]string{"env": "prod", "team": "ops"},
+			wantTags:   []string{"critical"},
+			wantAnnots: map[string]string{"summary": "cpu high"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := newTestAlert("tags")
+			alert.Tags = tt.tags
+
+			slsAlert, warnings, err := ModelToSLS(alert)
+			if err != nil {
+				t.Fatalf("ModelToSLS: %v", err)
+			}
+			if len(warnings) != 0 {
+				t.Fatalf("warnings = %+v, want none", warnings)
+			}
+
+			configuration := slsAlert.Configuration
+			labels := map[string]string{}
+			for _, label := range configuration.Labels {
+				labels[tea.StringValue(label.Key)] = tea.StringValue(label.Value)
+			}
+			if len(labels) != len(tt.wantLabels) || (len(labels) > 0 && !reflect.DeepEqual(labels, tt.wantLabels)) {
+				t.Errorf("SLS labels = %v, want %v", labels, tt.wantLabels)
+			}
+			if got := tea.StringSliceValue(configuration.Tags); len(got) != len(tt.wantTags) || (len(got) > 0 && !reflect.DeepEqual(got, tt.wantTags)) {
+				t.Errorf("SLS tags = %v, want %v", got, tt.wantTags)
+			}
+			annotations := map[string]string{}
+			for _, annotation := range configuration.Annotations {
+				annotations[tea.StringValue(annotation.Key)] = tea.StringValue(annotation.Value)
+			}
+			if len(annotations) != len(tt.wantAnnots) || (len(annotations) > 0 && !reflect.DeepEqual(annotations, tt.wantAnnots)) {
+				t.Errorf("SLS annotations = %v, want %v", annotations, tt.wantAnnots)
+			}
+
+			// 转换回模型后标签类型、键和值（包括 nil 与空字符串的区别）保持不变
+			if got, want := tagStrings(SLSToModel(slsAlert).Tags), tagStrings(tt.tags); !reflect.DeepEqual(got, want) {
+				t.Errorf("round-trip tags = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTimestamps(t *testing.T) {
+	tests := []struct {
+		name string
+		ts   *int64
+		want *int64
+	}{
+		{name: "nil", ts: nil, want: nil},
+		{name: "zero", ts: tea.Int64(0), want: nil},
+		{name: "negative", ts: tea.Int64(-1), want: nil},
+		{name: "set", ts: tea.Int64(1700000000), want: tea.Int64(1700000000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := SLSToModel(&sls20201230.Alert{Name: tea.String("ts"), CreateTime: tt.ts, LastModifiedTime: tt.ts})
+			if !reflect.DeepEqual(model.CreateTime, tt.want) || !reflect.DeepEqual(model.LastModifiedTime, tt.want) {
+				t.Errorf("SLSToModel times = %v, %v; want %v", model.CreateTime, model.LastModifiedTime, tt.want)
+			}
+
+			alert := newTestAlert("ts")
+			alert.CreateTime = tt.ts
+			alert.LastModifiedTime = tt.ts
+			slsAlert, _, err := ModelToSLS(alert)
+			if err != nil {
+				t.Fatalf("ModelToSLS: %v", err)
+			}
+			if !reflect.DeepEqual(slsAlert.CreateTime, tt.want) || !reflect.DeepEqual(slsAlert.LastModifiedTime, tt.want) {
+				t.Errorf("ModelToSLS times = %v, %v; want %v", slsAlert.CreateTime, slsAlert.LastModifiedTime, tt.want)
+			}
+			// 返回的是副本，修改结果不影响输入
+			if tt.ts != nil && model.LastModifiedTime != nil && model.LastModifiedTime == tt.ts {
+				t.Error("SLSToModel shares the timestamp pointer with the SLS alert")
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package mapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	sls20201230 "github.com/alibabacloud-go/sls-20201230/v6/client"
+)
+
+// MergeRawConfiguration 以 SLS 返回的原始 configuration JSON 为基线，逐键合并模型转换出的 Configuration，
+// 返回推送给 SLS 的配置对象。SDK 结构体表示的字段以模型为准（模型中缺失即表示清空），
+// SDK 未表示的字段沿用原始值；嵌套对象逐键合并，长度相同的对象数组逐个元素合并，其余数组以模型为准
+func MergeRawConfiguration(raw string, modeled *sls20201230.AlertConfiguration) (map[string]interface{}, error) {
+	var baseline map[string]interface{}
+	if err := decodeJSONObject([]byte(raw), &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse raw configuration: %w", err)
+	}
+
+	data, err := json.Marshal(modeled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	var fields map[string]interface{}
+	if err := decodeJSONObject(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal configuration: %w", err)
+	}
+
+	return mergeJSONObject(baseline, fields, reflect.TypeOf(sls20201230.AlertConfiguration{})), nil
+}
+
+// decodeJSONObject 解析 JSON 对象，数字按 json.Number 保留原始文本
+func decodeJSONObject(data []byte, value *map[string]interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(value)
+}
+
+// mergeJSONObject 合并同一 SDK 结构体 modelType 对应的原始对象和模型对象
+func mergeJSONObject(raw, modeled map[string]interface{}, modelType reflect.Type) map[string]interface{} {
+	known := jsonFieldTypes(modelType)
+
+	merged := make(map[string]interface{}, len(raw)+len(modeled))
+	for key, value := range raw {
+		if _, ok := known[key]; !ok {
+			merged[key] = value
+		}
+	}
+	for key, value := range modeled {
+		merged[key] = mergeJSONValue(raw[key], value, known[key])
+	}
+	return merged
+}
+
+// mergeJSONValue 合并一个字段的原始值和模型值，字段类型不是结构体（或结构体数组）时以模型为准
+func mergeJSONValue(raw, modeled interface{}, fieldType reflect.Type) interface{} {
+	fieldType = indirectType(fieldType)
+	if fieldType == nil {
+		return modeled
+	}
+
+	switch value := modeled.(type) {
+	case map[string]interface{}:
+		if rawObject, ok := raw.(map[string]interface{}); ok && fieldType.Kind() == reflect.Struct {
+			return mergeJSONObject(rawObject, value, fieldType)
+		}
+	case []interface{}:
+		rawItems, ok := raw.([]interface{})
+		if !ok || len(rawItems) != len(value) || fieldType.Kind() != reflect.Slice {
+			return modeled
+		}
+		if item := indirectType(fieldType.Elem()); item == nil || item.Kind() != reflect.Struct {
+			return modeled
+		}
+		merged := make([]interface{}, len(value))
+		for i := range value {
+			merged[i] = mergeJSONValue(rawItems[i], value[i], fieldType.Elem())
+		}
+		return merged
+	}
+	return modeled
+}
+
+// jsonFieldTypes 返回结构体各字段的 JSON 名称及其类型
+func jsonFieldTypes(structType reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, structType.NumField())
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// indirectType 去掉指针返回元素类型，nil 时返回 nil
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
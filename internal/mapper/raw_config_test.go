@@ -0,0 +1,103 @@
+package mapper
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	sls20201230 "github.com/alibabacloud-go/sls-20201230/v6/client"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+func TestMergeRawConfiguration(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		modeled *sls20201230.AlertConfiguration
+		want    string
+	}{
+		{
+			name:    "unknown top-level field kept",
+			raw:     `{"threshold":1,"futureField":{"mode":"strict"}}`,
+			modeled: &sls20201230.AlertConfiguration{Threshold: tea.Int32(5)},
+			want:    `{"threshold":5,"futureField":{"mode":"strict"}}`,
+		},
+		{
+			name: "unknown nested field kept",
+			raw:  `{"conditionConfiguration":{"condition":"a > 1","futureMode":"any"}}`,
+			modeled: &sls20201230.AlertConfiguration{
+				ConditionConfiguration: &sls20201230.ConditionConfiguration{Condition: tea.String("a > 2")},
+			},
+			want: `{"conditionConfiguration":{"condition":"a > 2","futureMode":"any"}}`,
+		},
+		{
+			name:    "known field missing from the model is cleared",
+			raw:     `{"threshold":1,"muteUntil":1700000000,"dashboard":"ops"}`,
+			modeled: &sls20201230.AlertConfiguration{Threshold: tea.Int32(1)},
+			want:    `{"threshold":1}`,
+		},
+		{
+			name: "arrays of the same length merged element by element",
+			raw:  `{"queryList":[{"query":"* | select 1","futureHint":"x"},{"query":"b"}]}`,
+			modeled: &sls20201230.AlertConfiguration{
+				QueryList: []*sls20201230.AlertQuery{{Query: tea.String("* | select 2")}, {Query: tea.String("b")}},
+			},
+			want: `{"queryList":[{"query":"* | select 2","futureHint":"x"},{"query":"b"}]}`,
+		},
+		{
+			name: "arrays of different lengths taken from the model",
+			raw:  `{"queryList":[{"query":"a","futureHint":"x"}]}`,
+			modeled: &sls20201230.AlertConfiguration{
+				QueryList: []*sls20201230.AlertQuery{{Query: tea.String("a")}, {Query: tea.String("b")}},
+			},
+			want: `{"queryList":[{"query":"a"},{"query":"b"}]}`,
+		},
+		{
+			name: "free-form maps taken from the model",
+			raw:  `{"templateConfiguration":{"id":"t","tokens":{"old":"1"},"futureLang":"en"}}`,
+			modeled: &sls20201230.AlertConfiguration{
+				TemplateConfiguration: &sls20201230.TemplateConfiguration{
+					Id:     tea.String("t"),
+					Tokens: map[string]interface{}{"new": "2"},
+				},
+			},
+			want: `{"templateConfiguration":{"id":"t","tokens":{"new":"2"},"futureLang":"en"}}`,
+		},
+		{
+			name:    "large numbers keep their text",
+			raw:     `{"futureLimit":12345678901234567890}`,
+			modeled: &sls20201230.AlertConfiguration{},
+			want:    `{"futureLimit":12345678901234567890}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := MergeRawConfiguration(tt.raw, tt.modeled)
+			if err != nil {
+				t.Fatalf("MergeRawConfiguration: %v", err)
+			}
+
+			got, err := json.Marshal(merged)
+			if err != nil {
+				t.Fatalf("marshal merged configuration: %v", err)
+			}
+			var gotValue, wantValue map[string]interface{}
+			if err := decodeJSONObject(got, &gotValue); err != nil {
+				t.Fatalf("decode merged configuration: %v", err)
+			}
+			if err := decodeJSONObject([]byte(tt.want), &wantValue); err != nil {
+				t.Fatalf("decode want: %v", err)
+			}
+			if !reflect.DeepEqual(gotValue, wantValue) {
+				t.Errorf("merged = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeRawConfigurationInvalidJSON(t *testing.T) {
+	if _, err := MergeRawConfiguration("not json", &sls20201230.AlertConfiguration{}); err == nil {
+		t.Fatal("MergeRawConfiguration succeeded, want an error for invalid raw JSON")
+	}
+}
@@ -0,0 +1,45 @@
+package mapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+// DecodeTemplateJSON 解析模板配置中以 JSON 字符串存储的 aonotations/tokens，必须是 JSON 对象（或 null）。
+// 数字按 json.Number 保留原始文本，嵌套对象和数组原样保留，再次序列化时不丢失精度
+func DecodeTemplateJSON(raw string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.UseNumber()
+
+	var value map[string]interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unexpected data after JSON object")
+	}
+	return value, nil
+}
+
+// ValidateTemplateConfig 检查模板配置的 aonotations 和 tokens 是否为合法的 JSON 对象
+func ValidateTemplateConfig(config *models.TemplateConfiguration) error {
+	if config == nil {
+		return nil
+	}
+	if config.Aonotations != nil {
+		if _, err := DecodeTemplateJSON(*config.Aonotations); err != nil {
+			return fmt.Errorf("invalid template aonotations: %w", err)
+		}
+	}
+	if config.Tokens != nil {
+		if _, err := DecodeTemplateJSON(*config.Tokens); err != nil {
+			return fmt.Errorf("invalid template tokens: %w", err)
+		}
+	}
+	return nil
+}
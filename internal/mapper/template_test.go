@@ -0,0 +1,123 @@
+package mapper
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+func TestDecodeTemplateJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr string // 为空表示合法
+	}{
+		{name: "object", raw: `{"summary":"cpu high"}`},
+		{name: "nested", raw: `{"owner":{"team":"ops","oncall":["a","b"]},"limits":[1,2.5,{"max":9007199254740993}]}`},
+		{name: "empty object", raw: `{}`},
+		{name: "null", raw: `null`},
+		{name: "surrounding whitespace", raw: " {\"a\":1}\n"},
+		{name: "array", raw: `[1, 2]`, wantErr: "cannot unmarshal array"},
+		{name: "string", raw: `"text"`, wantErr: "cannot unmarshal string"},
+		{name: "number", raw: `42`, wantErr: "cannot unmarshal number"},
+		{name: "malformed", raw: `{"a":`, wantErr: "unexpected EOF"},
+		{name: "empty", raw: ``, wantErr: "EOF"},
+		{name: "trailing data", raw: `{"a":1} {"b":2}`, wantErr: "unexpected data after JSON object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeTemplateJSON(tt.raw)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("DecodeTemplateJSON(%q): %v", tt.raw, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTemplateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *models.TemplateConfiguration
+		wantErr string
+	}{
+		{name: "nil config"},
+		{name: "unset fields", config: &models.TemplateConfiguration{}},
+		{name: "valid", config: &models.TemplateConfiguration{Aonotations: tea.String(`{"a":"b"}`), Tokens: tea.String(`{"k":[1]}`)}},
+		{name: "invalid aonotations", config: &models.TemplateConfiguration{Aonotations: tea.String(`[]`)}, wantErr: "invalid template aonotations"},
+		{name: "invalid tokens", config: &models.TemplateConfiguration{Tokens: tea.String(`{bad}`)}, wantErr: "invalid template tokens"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplateConfig(tt.config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateTemplateConfig: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTemplateTokensRoundTrip(t *testing.T) {
+	// 嵌套对象、数组和超出 float64 精度的整数在往返转换后保持不变
+	tokens := `{"ids":[9007199254740993,1.5],"owner":{"oncall":["a","b"],"team":"ops"},"empty":{}}`
+	aonotations := `{"summary":"cpu high","links":[{"title":"runbook","url":"https://example.com"}]}`
+
+	alert := newTestAlert("template")
+	alert.Configuration.TemplateConfig = &models.TemplateConfiguration{
+		TemplateId:  tea.String("sls.builtin"),
+		Aonotations: tea.String(aonotations),
+		Tokens:      tea.String(tokens),
+	}
+
+	slsAlert, _, err := ModelToSLS(alert)
+	if err != nil {
+		t.Fatalf("ModelToSLS: %v", err)
+	}
+	roundTrip := SLSToModel(slsAlert).Configuration.TemplateConfig
+	if roundTrip == nil {
+		t.Fatal("round-trip template config = nil")
+	}
+
+	for field, pair := range map[string][2]*string{
+		"tokens":      {tea.String(tokens), roundTrip.Tokens},
+		"aonotations": {tea.String(aonotations), roundTrip.Aonotations},
+	} {
+		if got, want := canonicalJSON(t, pair[1]), canonicalJSON(t, pair[0]); got != want {
+			t.Errorf("round-trip %s = %s, want %s", field, got, want)
+		}
+	}
+}
+
+// canonicalJSON 以键排序、保留数字原文的形式重新序列化 JSON 对象，便于比较
+func canonicalJSON(t *testing.T, raw *string) string {
+	t.Helper()
+
+	if raw == nil {
+		return "<nil>"
+	}
+	value, err := DecodeTemplateJSON(*raw)
+	if err != nil {
+		t.Fatalf("DecodeTemplateJSON(%s): %v", *raw, err)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(data)
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Alert 主表模型
@@ -18,6 +20,21 @@ type Alert struct {
 	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt        time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
+	// 软删除时间，非空时默认查询不会返回该 Alert，关联数据保留以便恢复
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+
+	// 同步信息
+	LastSyncedAt      *time.Time `json:"last_synced_at" gorm:"index"`
+	LastSyncDirection *string    `json:"last_sync_direction" gorm:"type:varchar(20)"`
+	// 最近一次从 SLS 同步时 SLS 侧内容的哈希，SLS 最后修改时间没有变化时用于发现内容变化
+	ContentHash *string `json:"content_hash" gorm:"type:varchar(64)"`
+
+	// 冻结截止时间（Unix 秒），在此之前迁移工具不会更新、删除或推送该 Alert
+	FreezeUntil *int64 `json:"freeze_until" gorm:"type:bigint"`
+
+	// 更新时的乐观锁条件：非 nil 时只有存储的 last_modified_time 等于该值（0 匹配空值）才会更新，不持久化
+	ExpectedLastModifiedTime *int64 `json:"expected_last_modified_time,omitempty" gorm:"-"`
+
 	// 关联关系
 	Configuration *AlertConfiguration `json:"configuration" gorm:"foreignKey:ConfigurationID"`
 	Schedule      *AlertSchedule      `json:"schedule" gorm:"foreignKey:ScheduleID"`
@@ -30,6 +47,11 @@ func (Alert) TableName() string {
 	return "alerts"
 }
 
+// IsFrozen 判断 Alert 在指定时间是否处于冻结期
+func (a *Alert) IsFrozen(now time.Time) bool {
+	return a.FreezeUntil != nil && *a.FreezeUntil > now.Unix()
+}
+
 // AlertConfiguration 配置表模型 - 完全匹配 SLS SDK
 type AlertConfiguration struct {
 	ID                     uint      `json:"id" gorm:"primaryKey;autoIncrement"`
@@ -50,6 +72,7 @@ type AlertConfiguration struct {
 	SinkAlerthubConfigID   *uint     `json:"sink_alerthub_config_id"`
 	SinkCmsConfigID        *uint     `json:"sink_cms_config_id"`
 	SinkEventStoreConfigID *uint     `json:"sink_event_store_config_id"`
+	RawConfig              *string   `json:"raw_config" gorm:"type:json"` // 导入时 SLS 返回的完整 Configuration JSON，推送时作为基线保留模型未表示的字段
 	CreatedAt              time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt              time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
@@ -97,7 +120,7 @@ func (AlertSchedule) TableName() string {
 type AlertTag struct {
 	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
 	AlertID   uint      `json:"alert_id" gorm:"not null"`
-	TagType   string    `json:"tag_type" gorm:"type:enum('annotation','label');not null"`
+	TagType   string    `json:"tag_type" gorm:"type:varchar(20);not null;check:chk_alert_tags_tag_type,tag_type IN ('annotation','label')"`
 	TagKey    string    `json:"tag_key" gorm:"type:varchar(255);not null"`
 	TagValue  *string   `json:"tag_value" gorm:"type:text"`
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
@@ -160,12 +183,12 @@ func (ConditionConfiguration) TableName() string {
 
 // GroupConfiguration 分组配置表模型 - 完全匹配 SLS SDK
 type GroupConfiguration struct {
-	ID            uint      `json:"id" gorm:"primaryKey;autoIncrement"`
-	AlertConfigID uint      `json:"alert_config_id" gorm:"not null"`
-	Fields        *string   `json:"fields" gorm:"type:text"` // 存储为逗号分隔的字符串
-	Type          *string   `json:"type" gorm:"type:varchar(100)"`
-	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID            uint        `json:"id" gorm:"primaryKey;autoIncrement"`
+	AlertConfigID uint        `json:"alert_config_id" gorm:"not null"`
+	Fields        StringSlice `json:"fields" gorm:"type:text"` // 存储为 JSON 数组
+	Type          *string     `json:"type" gorm:"type:varchar(100)"`
+	CreatedAt     time.Time   `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time   `json:"updated_at" gorm:"autoUpdateTime"`
 
 	// 关联关系
 	AlertConfig AlertConfiguration `json:"alert_config" gorm:"foreignKey:AlertConfigID"`
@@ -308,3 +331,54 @@ type SinkEventStoreConfiguration struct {
 func (SinkEventStoreConfiguration) TableName() string {
 	return "sink_event_store_configurations"
 }
+
+// AlertCountSnapshot 每日 Alert 数量快照，同一天只保留一条记录
+type AlertCountSnapshot struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Day       string    `json:"day" gorm:"type:varchar(10);not null;uniqueIndex"` // 快照日期（UTC），格式 YYYY-MM-DD
+	Total     int64     `json:"total" gorm:"not null;default:0"`
+	Enabled   int64     `json:"enabled" gorm:"not null;default:0"`
+	Disabled  int64     `json:"disabled" gorm:"not null;default:0"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (AlertCountSnapshot) TableName() string {
+	return "alert_count_snapshots"
+}
+
+// AlertAuditLog Alert 变更审计记录，与变更在同一个事务中写入；Alert 永久删除后记录仍然保留
+type AlertAuditLog struct {
+	ID         uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	AlertID    uint      `json:"alert_id" gorm:"not null;index"`
+	Action     string    `json:"action" gorm:"type:varchar(20);not null"`
+	Actor      string    `json:"actor" gorm:"type:varchar(255);not null"`
+	BeforeJSON *string   `json:"before_json" gorm:"type:json"` // 变更前的 Alert 快照，创建时为空
+	AfterJSON  *string   `json:"after_json" gorm:"type:json"`  // 变更后的 Alert 快照，永久删除时为空
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (AlertAuditLog) TableName() string {
+	return "alert_audit_logs"
+}
+
+// SyncRun 一次同步的执行记录，在同步结束时写入
+type SyncRun struct {
+	ID         uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Direction  string    `json:"direction" gorm:"type:varchar(20);not null"`
+	StartedAt  time.Time `json:"started_at" gorm:"not null;index"`
+	FinishedAt time.Time `json:"finished_at" gorm:"not null"`
+	Created    int       `json:"created" gorm:"not null;default:0"`
+	Updated    int       `json:"updated" gorm:"not null;default:0"`
+	Skipped    int       `json:"skipped" gorm:"not null;default:0"`
+	Failed     int       `json:"failed" gorm:"not null;default:0"`
+	Status     string    `json:"status" gorm:"type:varchar(20);not null"` // success、failed 或 timed_out
+	Error      *string   `json:"error" gorm:"type:text"`
+}
+
+// TableName 指定表名
+func (SyncRun) TableName() string {
+	return "sync_runs"
+}
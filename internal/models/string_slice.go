@@ -0,0 +1,75 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// StringSlice 以 JSON 数组存储的字符串列表，元素中的逗号等字符原样保留。
+// 读取数据库和解析 JSON 时兼容旧版的逗号分隔字符串
+type StringSlice []string
+
+// Value 写入数据库时编码为 JSON 数组，空列表存为 NULL
+func (s StringSlice) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal([]string(s))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal string slice: %w", err)
+	}
+	return string(data), nil
+}
+
+// Scan 从数据库读取 JSON 数组，不是 JSON 数组的旧数据按逗号分隔解析
+func (s *StringSlice) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case []byte:
+		return s.parse(string(v))
+	case string:
+		return s.parse(v)
+	}
+	return fmt.Errorf("cannot scan %T into StringSlice", value)
+}
+
+// UnmarshalJSON 接受 JSON 数组，也接受旧版导出文件中的逗号分隔字符串
+func (s *StringSlice) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return s.parse(text)
+	}
+
+	var items []string
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("string slice must be a JSON array of strings: %w", err)
+	}
+	*s = items
+	return nil
+}
+
+// parse 解析数据库或旧版 JSON 中的文本：以 [ 开头时按 JSON 数组解析，否则按逗号分隔并去掉空白和空项
+func (s *StringSlice) parse(text string) error {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "[") {
+		var items []string
+		if err := json.Unmarshal([]byte(text), &items); err != nil {
+			return fmt.Errorf("failed to unmarshal string slice: %w", err)
+		}
+		*s = items
+		return nil
+	}
+
+	var items []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*s = items
+	return nil
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestStringSliceValueAndScan(t *testing.T) {
+	tests := []struct {
+		name      string
+		slice     StringSlice
+		wantValue interface{}
+	}{
+		{name: "empty", wantValue: nil},
+		{name: "fields", slice: StringSlice{"host", "service"}, wantValue: `["host","service"]`},
+		{name: "field with comma", slice: StringSlice{"region,zone", "host"}, wantValue: `["region,zone","host"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.slice.Value()
+			if err != nil {
+				t.Fatalf("Value: %v", err)
+			}
+			if value != tt.wantValue {
+				t.Fatalf("Value = %v, want %v", value, tt.wantValue)
+			}
+
+			var scanned StringSlice
+			if err := scanned.Scan(value); err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if !reflect.DeepEqual(scanned, tt.slice) {
+				t.Errorf("Scan = %q, want %q", scanned, tt.slice)
+			}
+		})
+	}
+}
+
+func TestStringSliceLegacyFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		scan    interface{} // 数据库中的值
+		json    string      // 导出文件中的值
+		want    StringSlice
+		wantErr bool
+	}{
+		{name: "comma separated column", scan: []byte("host, service,,"), want: StringSlice{"host", "service"}},
+		{name: "comma separated JSON string", json: `"host,service"`, want: StringSlice{"host", "service"}},
+		{name: "JSON array", json: `["region,zone"]`, want: StringSlice{"region,zone"}},
+		{name: "malformed array column", scan: "[host", wantErr: true},
+		{name: "JSON number", json: `42`, wantErr: true},
+		{name: "unsupported column type", scan: 42, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got StringSlice
+			var err error
+			if tt.json != "" {
+				err = json.Unmarshal([]byte(tt.json), &got)
+			} else {
+				err = got.Scan(tt.scan)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %q, want an error", got)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
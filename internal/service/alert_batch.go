@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/internal/store"
+)
+
+// 批量创建的单项状态
+const (
+	BatchStatusCreated          = "created"
+	BatchStatusSkippedDuplicate = "skipped-duplicate"
+	BatchStatusError            = "error"
+)
+
+// ErrDuplicateBatchName 批量创建的请求中包含重名的 Alert
+var ErrDuplicateBatchName = newError(ErrValidation, "duplicate alert name in batch payload")
+
+// BatchItemResult 批量创建中单个 Alert 的结果
+type BatchItemResult struct {
+	Index   int    `json:"index"`
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	ID      uint   `json:"id,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Section string `json:"section,omitempty"` // 导致整批回滚的 Alert 出错的分区，如 configuration.severity_configs
+}
+
+// BatchCreateResult 批量创建结果
+type BatchCreateResult struct {
+	Total   int               `json:"total"`
+	Created int               `json:"created"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Items   []BatchItemResult `json:"items"`
+}
+
+// CreateAlerts 批量创建 Alert：先校验全部 Alert，已存在的名称跳过，校验失败的记为错误，
+// 其余 Alert 在同一个事务中创建，任一失败时整批回滚。请求中存在重名时直接返回 ErrDuplicateBatchName
+func (s *alertService) CreateAlerts(ctx context.Context, alerts []*models.Alert) (*BatchCreateResult, error) {
+	if len(alerts) == 0 {
+		return nil, validationError("alerts cannot be empty")
+	}
+
+	names := make([]string, 0, len(alerts))
+	seen := make(map[string]bool, len(alerts))
+	for i, alert := range alerts {
+		if alert == nil || alert.Name == "" {
+			continue
+		}
+		if seen[alert.Name] {
+			return nil, fmt.Errorf("%w: alerts[%d] %q", ErrDuplicateBatchName, i, alert.Name)
+		}
+		seen[alert.Name] = true
+		names = append(names, alert.Name)
+	}
+
+	existingAlerts, err := s.alertStore.GetByNames(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts: %w", err)
+	}
+	existing := make(map[string]*models.Alert, len(existingAlerts))
+	for _, alert := range existingAlerts {
+		existing[alert.Name] = alert
+	}
+
+	result := &BatchCreateResult{Total: len(alerts), Items: make([]BatchItemResult, len(alerts))}
+	var pending []*models.Alert
+	var pendingIndexes []int
+	for i, alert := range alerts {
+		item := BatchItemResult{Index: i}
+		switch {
+		case alert == nil:
+			item.Status = BatchStatusError
+			item.Error = "alert is required"
+		default:
+			item.Name = alert.Name
+			if err := s.validateAlert(alert); err != nil {
+				item.Status = BatchStatusError
+				item.Error = err.Error()
+			} else if found, ok := existing[alert.Name]; ok {
+				item.Status = BatchStatusSkippedDuplicate
+				item.ID = found.ID
+			} else {
+				alert.ID = 0
+				s.applyDefaultStatus(alert)
+				s.applyDefaultSink(alert)
+				pending = append(pending, alert)
+				pendingIndexes = append(pendingIndexes, i)
+			}
+		}
+		result.Items[i] = item
+	}
+
+	if len(pending) > 0 {
+		if err := s.alertStore.CreateBatchWithTransaction(ctx, pending); err != nil {
+			// 整批回滚：导致失败的 Alert 记录原始错误，其余待创建的 Alert 记录回滚原因
+			var batchErr *store.BatchCreateError
+			failedIndex := -1
+			if errors.As(err, &batchErr) {
+				failedIndex = pendingIndexes[batchErr.Index]
+			}
+			for _, i := range pendingIndexes {
+				result.Items[i].Status = BatchStatusError
+				if i == failedIndex {
+					result.Items[i].Error = batchErr.Err.Error()
+					var sectionErr *store.CreateSectionError
+					if errors.As(batchErr.Err, &sectionErr) {
+						result.Items[i].Section = sectionErr.Section
+					}
+				} else if failedIndex >= 0 {
+					result.Items[i].Error = fmt.Sprintf("rolled back: alerts[%d] failed", failedIndex)
+				} else {
+					result.Items[i].Error = fmt.Sprintf("rolled back: %v", err)
+				}
+			}
+		} else {
+			for j, i := range pendingIndexes {
+				result.Items[i].Status = BatchStatusCreated
+				result.Items[i].ID = pending[j].ID
+			}
+		}
+	}
+
+	for _, item := range result.Items {
+		switch item.Status {
+		case BatchStatusCreated:
+			result.Created++
+		case BatchStatusSkippedDuplicate:
+			result.Skipped++
+		case BatchStatusError:
+			result.Failed++
+		}
+	}
+
+	return result, nil
+}
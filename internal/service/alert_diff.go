@@ -0,0 +1,137 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+// FieldChange 两个 Alert 之间单个字段的差异
+type FieldChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// diffIgnoredKeys 比较时忽略的存储层字段（主键、外键、时间戳、反向关联和迁移工具自身的状态）
+var diffIgnoredKeys = map[string]bool{
+	"id":                         true,
+	"alert_id":                   true,
+	"alert_config_id":            true,
+	"configuration_id":           true,
+	"schedule_id":                true,
+	"condition_config_id":        true,
+	"group_config_id":            true,
+	"policy_config_id":           true,
+	"template_config_id":         true,
+	"sink_alerthub_config_id":    true,
+	"sink_cms_config_id":         true,
+	"sink_event_store_config_id": true,
+	"eval_condition_id":          true,
+	"created_at":                 true,
+	"updated_at":                 true,
+	"alert":                      true,
+	"alert_config":               true,
+	"last_synced_at":             true,
+	"last_sync_direction":        true,
+	"freeze_until":               true,
+}
+
+// DiffAlerts 比较两个 Alert 的业务内容，返回按路径排序的差异列表；数组整体比较，在数组路径上报告差异
+func DiffAlerts(existing, incoming *models.Alert) ([]FieldChange, error) {
+	oldValue, err := normalizeForDiff(existing)
+	if err != nil {
+		return nil, err
+	}
+	newValue, err := normalizeForDiff(incoming)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []FieldChange
+	diffValues("", oldValue, newValue, &changes)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+// normalizeForDiff 经 JSON 转换为通用结构，并去掉忽略的字段
+func normalizeForDiff(alert *models.Alert) (interface{}, error) {
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal alert: %w", err)
+	}
+	return stripIgnoredKeys(value), nil
+}
+
+// stripIgnoredKeys 递归删除忽略的字段
+func stripIgnoredKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if diffIgnoredKeys[key] {
+				delete(v, key)
+				continue
+			}
+			v[key] = stripIgnoredKeys(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = stripIgnoredKeys(child)
+		}
+	}
+	return value
+}
+
+// diffValues 递归比较对象，记录不同的叶子路径
+func diffValues(path string, oldValue, newValue interface{}, changes *[]FieldChange) {
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		keys := make(map[string]bool, len(oldMap)+len(newMap))
+		for key := range oldMap {
+			keys[key] = true
+		}
+		for key := range newMap {
+			keys[key] = true
+		}
+		for key := range keys {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			diffValues(childPath, oldMap[key], newMap[key], changes)
+		}
+		return
+	}
+
+	if isEmptyDiffValue(oldValue) && isEmptyDiffValue(newValue) {
+		return
+	}
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*changes = append(*changes, FieldChange{Path: path, Old: oldValue, New: newValue})
+	}
+}
+
+// isEmptyDiffValue 判断值是否为空：未设置的布尔值在数据库中存为 false，未设置的列表读回为空列表，
+// 与 nil 视为相同，避免未填写这些字段的导入内容被误判为更新
+func isEmptyDiffValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+// DuplicateAlert 重复组中的 Alert
+type DuplicateAlert struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Status      string `json:"status"`
+}
+
+// DuplicateGroup 内容哈希相同的一组 Alert
+type DuplicateGroup struct {
+	ContentHash string           `json:"content_hash"`
+	Alerts      []DuplicateAlert `json:"alerts"`
+}
+
+// alertContent 参与内容哈希的字段：查询语句（含目标日志库）、触发条件和阈值
+type alertContent struct {
+	Queries        []queryContent `json:"queries"`
+	Condition      *string        `json:"condition"`
+	CountCondition *string        `json:"count_condition"`
+	Threshold      *int32         `json:"threshold"`
+}
+
+// queryContent 参与内容哈希的查询字段，同一语句查询不同的日志库不视为重复
+type queryContent struct {
+	Query     string  `json:"query"`
+	Project   *string `json:"project"`
+	Store     *string `json:"store"`
+	StoreType *string `json:"store_type"`
+}
+
+// FindDuplicateAlerts 按查询语句、触发条件和阈值的内容哈希对 Alert 分组，返回成员多于一个的组。
+// 没有查询语句的 Alert 不参与比较
+func (s *alertService) FindDuplicateAlerts(ctx context.Context) ([]DuplicateGroup, error) {
+	alerts, err := s.alertStore.ListWithConditions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+
+	groups := make(map[string][]DuplicateAlert)
+	for _, alert := range alerts {
+		if len(alert.Queries) == 0 {
+			continue
+		}
+		hash, err := contentHash(alert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash alert %s: %w", alert.Name, err)
+		}
+		groups[hash] = append(groups[hash], DuplicateAlert{
+			ID:          alert.ID,
+			Name:        alert.Name,
+			DisplayName: alert.DisplayName,
+			Status:      alert.Status,
+		})
+	}
+
+	duplicates := []DuplicateGroup{}
+	for hash, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		sort.Slice(members, func(i, j int) bool {
+			return members[i].Name < members[j].Name
+		})
+		duplicates = append(duplicates, DuplicateGroup{ContentHash: hash, Alerts: members})
+	}
+
+	// 成员多的组在前，其次按首个 Alert 名称排序，保证输出稳定
+	sort.Slice(duplicates, func(i, j int) bool {
+		if len(duplicates[i].Alerts) != len(duplicates[j].Alerts) {
+			return len(duplicates[i].Alerts) > len(duplicates[j].Alerts)
+		}
+		return duplicates[i].Alerts[0].Name < duplicates[j].Alerts[0].Name
+	})
+
+	return duplicates, nil
+}
+
+// contentHash 计算 Alert 查询语句、触发条件和阈值的 SHA-256 哈希。
+// 查询保持原有顺序，因为触发条件通过 $0、$1 等序号引用查询结果
+func contentHash(alert *models.Alert) (string, error) {
+	content := alertContent{Queries: make([]queryContent, 0, len(alert.Queries))}
+	for _, query := range alert.Queries {
+		content.Queries = append(content.Queries, queryContent{
+			Query:     query.Query,
+			Project:   query.Project,
+			Store:     query.Store,
+			StoreType: query.StoreType,
+		})
+	}
+	if alert.Configuration != nil {
+		content.Threshold = alert.Configuration.Threshold
+		if condition := alert.Configuration.ConditionConfig; condition != nil {
+			content.Condition = condition.Condition
+			content.CountCondition = condition.CountCondition
+		}
+	}
+
+	payload, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/pkg/database"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+func TestFindDuplicateAlerts(t *testing.T) {
+	ctx := context.Background()
+	alertStore := newTestStore(t)
+	alertService := NewAlertService(alertStore, &config.DefaultSinkConfig{}, AlertStatusEnabled)
+
+	alerts := map[string]func(alert *models.Alert){
+		// 与 cpu-high 内容相同，只有名称、显示名称和标签不同
+		"cpu-high":      func(alert *models.Alert) {},
+		"cpu-high-copy": func(alert *models.Alert) { alert.DisplayName = "Copy"; alert.Tags = nil },
+		"other-threshold": func(alert *models.Alert) {
+			alert.Configuration.Threshold = tea.Int32(5)
+		},
+		"other-condition": func(alert *models.Alert) {
+			alert.Configuration.ConditionConfig.Condition = tea.String("cnt > 1")
+		},
+		"other-store": func(alert *models.Alert) {
+			alert.Queries[0].Store = tea.String("other-log")
+		},
+		"no-queries": func(alert *models.Alert) {
+			alert.Queries = nil
+		},
+		"no-queries-copy": func(alert *models.Alert) {
+			alert.Queries = nil
+		},
+	}
+	for name, modify := range alerts {
+		alert := newTestAlert(name)
+		modify(alert)
+		if err := alertStore.CreateWithTransaction(ctx, alert); err != nil {
+			t.Fatalf("CreateWithTransaction %s: %v", name, err)
+		}
+	}
+
+	// 旧数据的配置上可能没有 condition_config_id，触发条件按 alert_config_id 读取
+	copyAlert, err := alertStore.GetByName(ctx, "cpu-high-copy")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	if err := database.DB.Model(&models.AlertConfiguration{}).Where("id = ?", *copyAlert.ConfigurationID).
+		Update("condition_config_id", nil).Error; err != nil {
+		t.Fatalf("clear condition_config_id: %v", err)
+	}
+
+	groups, err := alertService.FindDuplicateAlerts(ctx)
+	if err != nil {
+		t.Fatalf("FindDuplicateAlerts: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("groups = %+v, want exactly one", groups)
+	}
+	group := groups[0]
+	if len(group.Alerts) != 2 || group.Alerts[0].Name != "cpu-high" || group.Alerts[1].Name != "cpu-high-copy" {
+		t.Errorf("group members = %+v, want cpu-high and cpu-high-copy", group.Alerts)
+	}
+	if group.Alerts[1].DisplayName != "Copy" || group.Alerts[1].ID != copyAlert.ID {
+		t.Errorf("group member = %+v, want the copy's ID and display name", group.Alerts[1])
+	}
+	if len(group.ContentHash) != 64 {
+		t.Errorf("content hash = %q, want a SHA-256 hex digest", group.ContentHash)
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+// 依赖图节点类型
+const (
+	GraphNodeAlert  = "alert"
+	GraphNodePolicy = "policy"
+	GraphNodeStore  = "store"
+)
+
+// 依赖图边类型
+const (
+	GraphEdgeAlertPolicy  = "alert_policy"
+	GraphEdgeActionPolicy = "action_policy"
+	GraphEdgeQueriesStore = "queries_store"
+)
+
+// GraphNode 依赖图节点
+type GraphNode struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+}
+
+// GraphEdge 依赖图边
+type GraphEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Type   string `json:"type"`
+}
+
+// AlertGraph Alert 与策略、日志库之间的依赖关系图
+type AlertGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// graphBuilder 构建依赖图，对节点和边去重
+type graphBuilder struct {
+	graph *AlertGraph
+	nodes map[string]bool
+	edges map[GraphEdge]bool
+}
+
+// newGraphBuilder 创建新的 graphBuilder 实例
+func newGraphBuilder() *graphBuilder {
+	return &graphBuilder{
+		graph: &AlertGraph{Nodes: []GraphNode{}, Edges: []GraphEdge{}},
+		nodes: make(map[string]bool),
+		edges: make(map[GraphEdge]bool),
+	}
+}
+
+// addNode 添加节点，已存在时忽略
+func (b *graphBuilder) addNode(node GraphNode) {
+	if b.nodes[node.ID] {
+		return
+	}
+	b.nodes[node.ID] = true
+	b.graph.Nodes = append(b.graph.Nodes, node)
+}
+
+// addEdge 添加边，已存在时忽略
+func (b *graphBuilder) addEdge(edge GraphEdge) {
+	if b.edges[edge] {
+		return
+	}
+	b.edges[edge] = true
+	b.graph.Edges = append(b.graph.Edges, edge)
+}
+
+// addAlert 添加 Alert 节点及其指向策略和日志库的边
+func (b *graphBuilder) addAlert(alert *models.Alert) {
+	alertID := GraphNodeAlert + ":" + alert.Name
+	label := alert.DisplayName
+	if label == "" {
+		label = alert.Name
+	}
+	b.addNode(GraphNode{ID: alertID, Type: GraphNodeAlert, Label: label})
+
+	if alert.Configuration != nil && alert.Configuration.PolicyConfig != nil {
+		policy := alert.Configuration.PolicyConfig
+		if policy.AlertPolicyId != nil && *policy.AlertPolicyId != "" {
+			b.addPolicy(alertID, *policy.AlertPolicyId, GraphEdgeAlertPolicy)
+		}
+		if policy.ActionPolicyId != nil && *policy.ActionPolicyId != "" {
+			b.addPolicy(alertID, *policy.ActionPolicyId, GraphEdgeActionPolicy)
+		}
+	}
+
+	for _, query := range alert.Queries {
+		if query.Store == nil || *query.Store == "" {
+			continue
+		}
+
+		project := ""
+		if query.Project != nil {
+			project = *query.Project
+		}
+		storeKey := project + "/" + *query.Store
+		if query.StoreType != nil && *query.StoreType != "" {
+			storeKey = *query.StoreType + ":" + storeKey
+		}
+
+		storeID := GraphNodeStore + ":" + storeKey
+		b.addNode(GraphNode{ID: storeID, Type: GraphNodeStore, Label: storeKey})
+		b.addEdge(GraphEdge{Source: alertID, Target: storeID, Type: GraphEdgeQueriesStore})
+	}
+}
+
+// addPolicy 添加策略节点及 Alert 指向策略的边
+func (b *graphBuilder) addPolicy(alertID, policyID, edgeType string) {
+	nodeID := GraphNodePolicy + ":" + policyID
+	b.addNode(GraphNode{ID: nodeID, Type: GraphNodePolicy, Label: policyID})
+	b.addEdge(GraphEdge{Source: alertID, Target: nodeID, Type: edgeType})
+}
+
+// BuildAlertGraph 根据已有关联数据构建 Alert 依赖关系图
+func (s *alertService) BuildAlertGraph(ctx context.Context) (*AlertGraph, error) {
+	alerts, err := s.alertStore.ListWithRelations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+
+	// 按名称排序，保证输出稳定
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Name < alerts[j].Name
+	})
+
+	builder := newGraphBuilder()
+	for _, alert := range alerts {
+		builder.addAlert(alert)
+	}
+
+	return builder.graph, nil
+}
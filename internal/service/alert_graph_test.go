@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+func TestBuildAlertGraph(t *testing.T) {
+	ctx := context.Background()
+	alertService, _ := newTestAlertService(t)
+
+	cpu := newTestAlert("cpu")
+	cpu.Configuration.PolicyConfig = &models.PolicyConfiguration{
+		AlertPolicyId:  tea.String("sls.builtin.dynamic"),
+		ActionPolicyId: tea.String("ops-action"),
+	}
+	cpu.Queries[0].Project = tea.String("prod")
+
+	mem := newTestAlert("mem")
+	mem.Configuration.PolicyConfig = &models.PolicyConfiguration{AlertPolicyId: tea.String("sls.builtin.dynamic")}
+	mem.Queries = append(mem.Queries, models.AlertQuery{
+		Query: "* | select avg(mem) as mem", Store: tea.String("host-metrics"), StoreType: tea.String("metric"),
+	})
+
+	// 没有策略，查询也没有日志库，只产生孤立节点
+	orphan := newTestAlert("orphan")
+	orphan.Queries[0].Store = nil
+
+	for _, alert := range []*models.Alert{mem, orphan, cpu} {
+		if err := alertService.CreateAlert(ctx, alert); err != nil {
+			t.Fatalf("CreateAlert(%s): %v", alert.Name, err)
+		}
+	}
+
+	graph, err := alertService.BuildAlertGraph(ctx)
+	if err != nil {
+		t.Fatalf("BuildAlertGraph: %v", err)
+	}
+
+	wantEdges := []GraphEdge{
+		{Source: "alert:cpu", Target: "policy:sls.builtin.dynamic", Type: GraphEdgeAlertPolicy},
+		{Source: "alert:cpu", Target: "policy:ops-action", Type: GraphEdgeActionPolicy},
+		{Source: "alert:cpu", Target: "store:log:prod/app-log", Type: GraphEdgeQueriesStore},
+		{Source: "alert:mem", Target: "policy:sls.builtin.dynamic", Type: GraphEdgeAlertPolicy},
+		{Source: "alert:mem", Target: "store:log:/app-log", Type: GraphEdgeQueriesStore},
+		{Source: "alert:mem", Target: "store:metric:/host-metrics", Type: GraphEdgeQueriesStore},
+	}
+	if !reflect.DeepEqual(graph.Edges, wantEdges) {
+		t.Errorf("edges = %+v, want %+v", graph.Edges, wantEdges)
+	}
+
+	nodes := map[string]string{}
+	for _, node := range graph.Nodes {
+		if _, dup := nodes[node.ID]; dup {
+			t.Errorf("duplicate node %s", node.ID)
+		}
+		nodes[node.ID] = node.Type
+	}
+	wantNodes := map[string]string{
+		"alert:cpu":                  GraphNodeAlert,
+		"alert:mem":                  GraphNodeAlert,
+		"alert:orphan":               GraphNodeAlert,
+		"policy:sls.builtin.dynamic": GraphNodePolicy,
+		"policy:ops-action":          GraphNodePolicy,
+		"store:log:prod/app-log":     GraphNodeStore,
+		"store:log:/app-log":         GraphNodeStore,
+		"store:metric:/host-metrics": GraphNodeStore,
+	}
+	if !reflect.DeepEqual(nodes, wantNodes) {
+		ids := make([]string, 0, len(nodes))
+		for id := range nodes {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		t.Errorf("nodes = %v, want %d nodes matching %v", ids, len(wantNodes), wantNodes)
+	}
+}
+
+func TestBuildAlertGraphEmpty(t *testing.T) {
+	alertService, _ := newTestAlertService(t)
+
+	graph, err := alertService.BuildAlertGraph(context.Background())
+	if err != nil {
+		t.Fatalf("BuildAlertGraph: %v", err)
+	}
+	if graph.Nodes == nil || graph.Edges == nil || len(graph.Nodes) != 0 || len(graph.Edges) != 0 {
+		t.Errorf("graph = %+v, want empty non-nil nodes and edges", graph)
+	}
+}
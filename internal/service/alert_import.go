@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+// 导入动作
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionIdentical = "identical"
+	ImportActionSkipped   = "skipped"
+	ImportActionFailed    = "failed"
+)
+
+// 导入模式：已存在同名 Alert 时覆盖更新或跳过
+const (
+	ImportModeOverwrite = "overwrite"
+	ImportModeSkip      = "skip"
+)
+
+// ErrInvalidImportMode 导入模式不合法
+var ErrInvalidImportMode = newError(ErrValidation, "invalid import mode")
+
+// ImportOptions 导入选项
+type ImportOptions struct {
+	// DryRun 只返回预览，不写入数据库
+	DryRun bool
+	// Mode 已存在同名 Alert 时的处理方式，默认 overwrite
+	Mode string
+	// ContinueOnError 单个 Alert 失败后继续导入其余 Alert，为 false 时在第一个失败处停止
+	ContinueOnError bool
+}
+
+// ImportItemResult 单个导入 Alert 的分类结果
+type ImportItemResult struct {
+	Name    string        `json:"name"`
+	Action  string        `json:"action"`
+	Changes []FieldChange `json:"changes,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// ImportResult 导入结果，DryRun 时只包含预览，不写入数据库
+type ImportResult struct {
+	DryRun    bool `json:"dry_run"`
+	Total     int  `json:"total"`
+	Create    int  `json:"create"`
+	Update    int  `json:"update"`
+	Identical int  `json:"identical"`
+	Skipped   int  `json:"skipped"`
+	Failed    int  `json:"failed"`
+	// Stopped 因失败提前停止，其余 Alert 未处理
+	Stopped bool               `json:"stopped"`
+	Items   []ImportItemResult `json:"items"`
+}
+
+// record 记录单个 Alert 的结果并更新计数
+func (r *ImportResult) record(item ImportItemResult) {
+	r.Total++
+	switch item.Action {
+	case ImportActionCreate:
+		r.Create++
+	case ImportActionUpdate:
+		r.Update++
+	case ImportActionIdentical:
+		r.Identical++
+	case ImportActionSkipped:
+		r.Skipped++
+	case ImportActionFailed:
+		r.Failed++
+	}
+	r.Items = append(r.Items, item)
+}
+
+// ImportAlerts 按名称将导入的 Alert 与数据库比对，分类为新建、更新、相同或跳过（skip 模式下已存在的 Alert）；
+// 非 DryRun 时执行写入
+func (s *alertService) ImportAlerts(ctx context.Context, alerts []*models.Alert, opts ImportOptions) (*ImportResult, error) {
+	if len(alerts) == 0 {
+		return nil, validationError("alerts cannot be empty")
+	}
+	switch opts.Mode {
+	case "":
+		opts.Mode = ImportModeOverwrite
+	case ImportModeOverwrite, ImportModeSkip:
+	default:
+		return nil, fmt.Errorf("%w: %s (must be overwrite or skip)", ErrInvalidImportMode, opts.Mode)
+	}
+
+	names := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		if alert != nil && alert.Name != "" {
+			names = append(names, alert.Name)
+		}
+	}
+
+	existingAlerts, err := s.alertStore.GetByNames(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts: %w", err)
+	}
+	existingByName := make(map[string]*models.Alert, len(existingAlerts))
+	for _, alert := range existingAlerts {
+		existingByName[alert.Name] = alert
+	}
+
+	result := &ImportResult{DryRun: opts.DryRun, Items: []ImportItemResult{}}
+	seen := make(map[string]bool, len(alerts))
+	for i, alert := range alerts {
+		item := s.importItem(ctx, i, alert, existingByName, seen, opts)
+		result.record(item)
+		if item.Action == ImportActionFailed && !opts.ContinueOnError {
+			result.Stopped = i < len(alerts)-1
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// importItem 分类并（非 DryRun 时）写入单个导入的 Alert
+func (s *alertService) importItem(ctx context.Context, index int, alert *models.Alert, existingByName map[string]*models.Alert, seen map[string]bool, opts ImportOptions) ImportItemResult {
+	if alert == nil || alert.Name == "" {
+		return ImportItemResult{Action: ImportActionFailed, Error: fmt.Sprintf("alerts[%d]: name is required", index)}
+	}
+	if seen[alert.Name] {
+		return ImportItemResult{Name: alert.Name, Action: ImportActionFailed, Error: "duplicate name in import payload"}
+	}
+	seen[alert.Name] = true
+
+	item := ImportItemResult{Name: alert.Name}
+	existing, ok := existingByName[alert.Name]
+	switch {
+	case !ok:
+		item.Action = ImportActionCreate
+	case opts.Mode == ImportModeSkip:
+		item.Action = ImportActionSkipped
+	default:
+		changes, err := DiffAlerts(existing, alert)
+		if err != nil {
+			return ImportItemResult{Name: alert.Name, Action: ImportActionFailed, Error: err.Error()}
+		}
+		if len(changes) == 0 {
+			item.Action = ImportActionIdentical
+		} else {
+			item.Action = ImportActionUpdate
+			item.Changes = changes
+		}
+	}
+
+	if !opts.DryRun {
+		if err := s.applyImportItem(ctx, alert, existing, item.Action); err != nil {
+			item.Action = ImportActionFailed
+			item.Error = err.Error()
+		}
+	}
+	return item
+}
+
+// applyImportItem 按分类结果写入单个 Alert
+func (s *alertService) applyImportItem(ctx context.Context, alert, existing *models.Alert, action string) error {
+	switch action {
+	case ImportActionCreate:
+		alert.ID = 0
+		return s.CreateAlert(ctx, alert)
+	case ImportActionUpdate:
+		alert.ID = existing.ID
+		return s.UpdateAlert(ctx, alert)
+	}
+	return nil
+}
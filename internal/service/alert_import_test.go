@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+func TestImportAlertsDryRunPreview(t *testing.T) {
+	ctx := context.Background()
+	alertService, alertStore := newTestAlertService(t)
+	createTestAlerts(t, alertService, "changed", "same")
+
+	changed := newTestAlert("changed")
+	changed.Configuration.Threshold = tea.Int32(5)
+	payload := []*models.Alert{newTestAlert("fresh"), changed, newTestAlert("same")}
+
+	result, err := alertService.ImportAlerts(ctx, payload, ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportAlerts: %v", err)
+	}
+
+	actions := map[string]string{}
+	for _, item := range result.Items {
+		actions[item.Name] = item.Action
+	}
+	want := map[string]string{"fresh": ImportActionCreate, "changed": ImportActionUpdate, "same": ImportActionIdentical}
+	if !reflect.DeepEqual(actions, want) {
+		t.Fatalf("actions = %v, want %v; items %+v", actions, want, result.Items)
+	}
+	if !result.DryRun || result.Total != 3 || result.Create != 1 || result.Update != 1 || result.Identical != 1 {
+		t.Errorf("result = %+v, want a dry run with one of each classification", result)
+	}
+
+	for _, item := range result.Items {
+		if item.Action != ImportActionUpdate {
+			if len(item.Changes) != 0 {
+				t.Errorf("%s changes = %+v, want none", item.Name, item.Changes)
+			}
+			continue
+		}
+		if len(item.Changes) != 1 || item.Changes[0].Path != "configuration.threshold" {
+			t.Errorf("changes = %+v, want only configuration.threshold", item.Changes)
+		}
+	}
+
+	// 预览不写入数据库
+	if _, err := alertStore.GetByName(ctx, "fresh"); err == nil {
+		t.Errorf("dry run created fresh")
+	}
+	current, err := alertStore.GetByName(ctx, "changed")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	if tea.Int32Value(current.Configuration.Threshold) != 1 {
+		t.Errorf("dry run updated changed: threshold = %d", tea.Int32Value(current.Configuration.Threshold))
+	}
+}
+
+func TestImportAlertsAppliesPreview(t *testing.T) {
+	ctx := context.Background()
+	alertService, alertStore := newTestAlertService(t)
+	createTestAlerts(t, alertService, "changed")
+
+	changed := newTestAlert("changed")
+	changed.Configuration.Threshold = tea.Int32(5)
+	result, err := alertService.ImportAlerts(ctx, []*models.Alert{newTestAlert("fresh"), changed}, ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportAlerts: %v", err)
+	}
+	if result.DryRun || result.Create != 1 || result.Update != 1 {
+		t.Fatalf("result = %+v, want one create and one update", result)
+	}
+
+	if _, err := alertStore.GetByName(ctx, "fresh"); err != nil {
+		t.Errorf("fresh was not created: %v", err)
+	}
+	current, err := alertStore.GetByName(ctx, "changed")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	if tea.Int32Value(current.Configuration.Threshold) != 5 {
+		t.Errorf("threshold = %d, want 5", tea.Int32Value(current.Configuration.Threshold))
+	}
+}
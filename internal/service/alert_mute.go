@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidMute 屏蔽时长或截止时间不合法，或 Alert 没有可写入屏蔽时间的配置
+	ErrInvalidMute = newError(ErrValidation, "invalid mute")
+	// ErrAlertDisabled Alert 已停用，不能屏蔽
+	ErrAlertDisabled = newError(ErrConflict, "alert is disabled")
+)
+
+// AlertMute 屏蔽 Alert 的参数，Duration 和 UntilMS 必须且只能设置一个
+type AlertMute struct {
+	Duration *string // 从当前时间起屏蔽的 Go 时长（如 30m、2h），必须大于 0
+	UntilMS  *int64  // 屏蔽截止时间（Unix 毫秒），必须晚于当前时间
+}
+
+// muteUntil 计算屏蔽截止时间，返回 SLS MuteUntil 使用的 Unix 秒
+func (m *AlertMute) muteUntil(now time.Time) (int64, error) {
+	if m == nil || (m.Duration == nil) == (m.UntilMS == nil) {
+		return 0, fmt.Errorf("%w: exactly one of duration and until is required", ErrInvalidMute)
+	}
+
+	if m.Duration != nil {
+		duration, err := time.ParseDuration(strings.TrimSpace(*m.Duration))
+		if err != nil {
+			return 0, fmt.Errorf("%w: duration %q is not a duration (e.g. 30m, 2h)", ErrInvalidMute, *m.Duration)
+		}
+		if duration <= 0 {
+			return 0, fmt.Errorf("%w: duration %q must be greater than 0", ErrInvalidMute, *m.Duration)
+		}
+		return now.Add(duration).Unix(), nil
+	}
+
+	until := time.UnixMilli(*m.UntilMS)
+	if !until.After(now) {
+		return 0, fmt.Errorf("%w: until %d must be in the future", ErrInvalidMute, *m.UntilMS)
+	}
+	return until.Unix(), nil
+}
+
+// MuteAlert 屏蔽 Alert 到 mute 指定的时间，只更新配置的 mute_until，其余数据保持不变；
+// 已停用的 Alert 返回 ErrAlertDisabled
+func (s *alertService) MuteAlert(ctx context.Context, id uint, mute *AlertMute) (*models.Alert, error) {
+	if id == 0 {
+		return nil, validationError("invalid alert ID")
+	}
+
+	now := time.Now()
+	until, err := mute.muteUntil(now)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.alertStore.GetByID(ctx, id)
+	if err != nil {
+		return nil, alertLookupError(err)
+	}
+	if err := checkNotFrozen(existing); err != nil {
+		return nil, err
+	}
+	if existing.Status == AlertStatusDisabled {
+		return nil, fmt.Errorf("%w: %s", ErrAlertDisabled, existing.Name)
+	}
+
+	return s.setMuteUntil(ctx, existing, &until, now)
+}
+
+// UnmuteAlert 取消 Alert 的屏蔽，清空配置的 mute_until
+func (s *alertService) UnmuteAlert(ctx context.Context, id uint) (*models.Alert, error) {
+	if id == 0 {
+		return nil, validationError("invalid alert ID")
+	}
+
+	existing, err := s.alertStore.GetByID(ctx, id)
+	if err != nil {
+		return nil, alertLookupError(err)
+	}
+	if err := checkNotFrozen(existing); err != nil {
+		return nil, err
+	}
+
+	return s.setMuteUntil(ctx, existing, nil, time.Now())
+}
+
+// setMuteUntil 写入屏蔽截止时间并返回更新后的 Alert
+func (s *alertService) setMuteUntil(ctx context.Context, alert *models.Alert, muteUntil *int64, now time.Time) (*models.Alert, error) {
+	err := s.alertStore.SetMuteUntil(ctx, alert.ID, muteUntil, now.Unix())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: alert %s has no configuration", ErrInvalidMute, alert.Name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set alert mute_until: %w", err)
+	}
+
+	return s.alertStore.GetByID(ctx, alert.ID)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/internal/store"
+)
+
+// ErrInvalidAlertPatch 部分更新为空或字段值不合法
+var ErrInvalidAlertPatch = newError(ErrValidation, "invalid alert patch")
+
+// AlertPatch Alert 的部分更新，只写入非 nil 的字段，其余字段和关联数据保持不变
+type AlertPatch struct {
+	DisplayName *string               // 不能为空
+	Description *string               // 空字符串表示清空描述
+	Status      *string               // ENABLED 或 DISABLED
+	Schedule    *models.AlertSchedule // 整体替换调度配置
+}
+
+// IsEmpty 是否没有设置任何字段
+func (p *AlertPatch) IsEmpty() bool {
+	return p.DisplayName == nil && p.Description == nil && p.Status == nil && p.Schedule == nil
+}
+
+// PatchAlert 部分更新 Alert：只写入 patch 中设置的字段对应的分区（主记录或调度），
+// 配置、标签和查询不会被删除重建
+func (s *alertService) PatchAlert(ctx context.Context, id uint, patch *AlertPatch) (*models.Alert, error) {
+	if id == 0 {
+		return nil, validationError("invalid alert ID")
+	}
+	if patch == nil || patch.IsEmpty() {
+		return nil, fmt.Errorf("%w: at least one of display_name, description, status, schedule is required", ErrInvalidAlertPatch)
+	}
+
+	existing, err := s.alertStore.GetByID(ctx, id)
+	if err != nil {
+		return nil, alertLookupError(err)
+	}
+	if err := checkNotFrozen(existing); err != nil {
+		return nil, err
+	}
+
+	var sections []string
+	if patch.DisplayName != nil || patch.Description != nil || patch.Status != nil {
+		sections = append(sections, store.SectionBase)
+	}
+	if patch.DisplayName != nil {
+		displayName := strings.TrimSpace(*patch.DisplayName)
+		if displayName == "" {
+			return nil, fmt.Errorf("%w: display_name must not be empty", ErrInvalidAlertPatch)
+		}
+		existing.DisplayName = displayName
+	}
+	if patch.Description != nil {
+		if *patch.Description == "" {
+			existing.Description = nil
+		} else {
+			description := *patch.Description
+			existing.Description = &description
+		}
+	}
+	if patch.Status != nil {
+		status := strings.ToUpper(strings.TrimSpace(*patch.Status))
+		if !IsValidAlertStatus(status) {
+			return nil, fmt.Errorf("%w: invalid status: %s", ErrInvalidAlertPatch, *patch.Status)
+		}
+		existing.Status = status
+	}
+	if patch.Schedule != nil {
+		if err := validateSchedule(patch.Schedule); err != nil {
+			return nil, err
+		}
+		existing.Schedule = patch.Schedule
+		sections = append(sections, store.SectionSchedule)
+	}
+
+	if err := s.alertStore.UpdateSectionsWithTransaction(ctx, existing, sections); err != nil {
+		return nil, fmt.Errorf("failed to patch alert: %w", err)
+	}
+
+	return s.alertStore.GetByID(ctx, id)
+}
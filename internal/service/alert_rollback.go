@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAuditLogNotFound 审计记录不存在
+	ErrAuditLogNotFound = newError(ErrNotFound, "audit log not found")
+	// ErrInvalidRollback 审计记录不属于该 Alert，或没有可回滚的变更前快照
+	ErrInvalidRollback = newError(ErrValidation, "invalid rollback")
+)
+
+// RollbackAlert 将 Alert 恢复为审计记录 auditID 的变更前快照：快照整体替换主记录、配置、调度、标签和查询，
+// 名称保持不变，回滚本身写入一条 rollback 审计记录
+func (s *alertService) RollbackAlert(ctx context.Context, id, auditID uint) (*models.Alert, error) {
+	if id == 0 || auditID == 0 {
+		return nil, validationError("invalid alert or audit log ID")
+	}
+
+	existing, err := s.alertStore.GetByID(ctx, id)
+	if err != nil {
+		return nil, alertLookupError(err)
+	}
+	if err := checkNotFrozen(existing); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.alertStore.GetAuditLog(ctx, auditID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: %v", ErrAuditLogNotFound, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %w", err)
+	}
+	if entry.AlertID != id {
+		return nil, fmt.Errorf("%w: audit log %d belongs to alert %d, not %d", ErrInvalidRollback, auditID, entry.AlertID, id)
+	}
+	if entry.BeforeJSON == nil {
+		return nil, fmt.Errorf("%w: audit log %d (%s) has no before snapshot", ErrInvalidRollback, auditID, entry.Action)
+	}
+
+	var snapshot models.Alert
+	if err := json.Unmarshal([]byte(*entry.BeforeJSON), &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode audit snapshot: %w", err)
+	}
+	prepareRollbackSnapshot(&snapshot, existing)
+
+	if err := s.validateAlert(&snapshot); err != nil {
+		return nil, err
+	}
+	if err := s.alertStore.RollbackWithTransaction(ctx, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to roll back alert: %w", err)
+	}
+
+	return s.alertStore.GetByID(ctx, id)
+}
+
+// prepareRollbackSnapshot 让快照指向 Alert 当前的记录：主键、名称和现有配置/调度的外键取当前值
+// （更新时据此删除旧记录），快照中旧的子记录 ID 清零后重新创建
+func prepareRollbackSnapshot(snapshot, existing *models.Alert) {
+	snapshot.ID = existing.ID
+	snapshot.Name = existing.Name
+	snapshot.ConfigurationID = existing.ConfigurationID
+	snapshot.ScheduleID = existing.ScheduleID
+
+	if config := snapshot.Configuration; config != nil {
+		config.ID = 0
+		if config.ConditionConfig != nil {
+			config.ConditionConfig.ID = 0
+		}
+		if config.GroupConfig != nil {
+			config.GroupConfig.ID = 0
+		}
+		if config.PolicyConfig != nil {
+			config.PolicyConfig.ID = 0
+		}
+		if config.TemplateConfig != nil {
+			config.TemplateConfig.ID = 0
+		}
+		if config.SinkAlerthubConfig != nil {
+			config.SinkAlerthubConfig.ID = 0
+		}
+		if config.SinkCmsConfig != nil {
+			config.SinkCmsConfig.ID = 0
+		}
+		if config.SinkEventStoreConfig != nil {
+			config.SinkEventStoreConfig.ID = 0
+		}
+		for i := range config.SeverityConfigs {
+			config.SeverityConfigs[i].ID = 0
+		}
+		for i := range config.JoinConfigs {
+			config.JoinConfigs[i].ID = 0
+		}
+	}
+	if snapshot.Schedule != nil {
+		snapshot.Schedule.ID = 0
+	}
+	for i := range snapshot.Tags {
+		snapshot.Tags[i].ID = 0
+	}
+	for i := range snapshot.Queries {
+		snapshot.Queries[i].ID = 0
+	}
+}
@@ -2,33 +2,132 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/Ghostbaby/sls-migrate/internal/mapper"
 	"github.com/Ghostbaby/sls-migrate/internal/models"
 	"github.com/Ghostbaby/sls-migrate/internal/store"
+	"gorm.io/gorm"
 )
 
+// ErrInvalidUpdateSections 更新分区为空或包含无法识别的分区
+var ErrInvalidUpdateSections = newError(ErrValidation, "invalid update sections")
+
+// ErrAlertNotFound Alert 不存在
+var ErrAlertNotFound = newError(ErrNotFound, "alert not found")
+
+// ErrAlertExists 同名 Alert 已存在；创建前的名称检查只是快速路径，并发创建时以数据库唯一索引（store.ErrAlertExists）为准
+var ErrAlertExists = newError(ErrConflict, "alert already exists")
+
+// ErrAlertModified Alert 在读取后已被修改，expected_last_modified_time 与存储的值不一致
+var ErrAlertModified = newError(ErrConflict, "alert was modified")
+
+// ErrInvalidSearchFilter 搜索条件不合法
+var ErrInvalidSearchFilter = newError(ErrValidation, "invalid search filter")
+
+// ErrAlertDeleted 同名 Alert 已被软删除，名称仍被占用
+var ErrAlertDeleted = newError(ErrConflict, "alert is soft-deleted")
+
+// ErrAlertNotDeleted 恢复的 Alert 没有被软删除
+var ErrAlertNotDeleted = newError(ErrConflict, "alert is not deleted")
+
+// AlertFrozenError Alert 处于冻结期，拒绝变更
+type AlertFrozenError struct {
+	Name        string
+	FreezeUntil int64
+}
+
+// Error 实现 error 接口
+func (e *AlertFrozenError) Error() string {
+	return fmt.Sprintf("alert '%s' is frozen until %s", e.Name, time.Unix(e.FreezeUntil, 0).UTC().Format(time.RFC3339))
+}
+
+// Is 冻结属于 ErrConflict
+func (e *AlertFrozenError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// checkNotFrozen 冻结期内返回 AlertFrozenError
+func checkNotFrozen(alert *models.Alert) error {
+	if alert.IsFrozen(time.Now()) {
+		return &AlertFrozenError{Name: alert.Name, FreezeUntil: *alert.FreezeUntil}
+	}
+	return nil
+}
+
 // AlertService Alert 服务接口
 type AlertService interface {
 	CreateAlert(ctx context.Context, alert *models.Alert) error
+	CreateAlerts(ctx context.Context, alerts []*models.Alert) (*BatchCreateResult, error)
 	GetAlertByID(ctx context.Context, id uint) (*models.Alert, error)
 	GetAlertByName(ctx context.Context, name string) (*models.Alert, error)
 	UpdateAlert(ctx context.Context, alert *models.Alert) error
-	DeleteAlert(ctx context.Context, id uint) error
-	ListAlerts(ctx context.Context, page, pageSize int) ([]*models.Alert, int64, error)
+	UpdateAlertSections(ctx context.Context, alert *models.Alert, sections []string) error
+	PatchAlert(ctx context.Context, id uint, patch *AlertPatch) (*models.Alert, error)
+	DeleteAlert(ctx context.Context, id uint, hard bool) (*models.Alert, error)
+	RestoreAlert(ctx context.Context, id uint) (*models.Alert, error)
+	FreezeAlert(ctx context.Context, id uint, until int64) (*models.Alert, error)
+	SetAlertStatus(ctx context.Context, id uint, status string) (*models.Alert, error)
+	RebuildAlert(ctx context.Context, id uint) (*models.Alert, error)
+	RollbackAlert(ctx context.Context, id, auditID uint) (*models.Alert, error)
+	MuteAlert(ctx context.Context, id uint, mute *AlertMute) (*models.Alert, error)
+	UnmuteAlert(ctx context.Context, id uint) (*models.Alert, error)
+	ListAlerts(ctx context.Context, page, pageSize int, includeDeleted bool) ([]*models.Alert, int64, error)
+	ListAlertsByTags(ctx context.Context, query string, page, pageSize int) ([]*models.Alert, int64, error)
+	SearchAlerts(ctx context.Context, filter store.SearchFilter, page, pageSize int) ([]*models.Alert, int64, error)
 	ListAlertsByStatus(ctx context.Context, status string, page, pageSize int) ([]*models.Alert, int64, error)
+	ListAlertsSyncedBefore(ctx context.Context, before time.Time, page, pageSize int) ([]*models.Alert, int64, error)
+	GetAlertsLastModified(ctx context.Context, includeSync bool) (*time.Time, error)
+	ListAlertTags(ctx context.Context, alertID uint, tagType string, page, pageSize int) ([]models.AlertTag, int64, error)
+	AddAlertTag(ctx context.Context, alertID uint, tag *models.AlertTag) error
+	DeleteAlertTag(ctx context.Context, alertID, tagID uint) error
+	AutocompleteAlerts(ctx context.Context, prefix string, limit int) ([]store.AlertSuggestion, error)
+	ExportAlertsByNames(ctx context.Context, names []string) ([]*models.Alert, []string, error)
+	BuildAlertGraph(ctx context.Context) (*AlertGraph, error)
+	FindDuplicateAlerts(ctx context.Context) ([]DuplicateGroup, error)
+	ListAlertsAfterID(ctx context.Context, afterID uint, status string, pageSize int) ([]*models.Alert, error)
+	ImportAlerts(ctx context.Context, alerts []*models.Alert, opts ImportOptions) (*ImportResult, error)
+	GetAlertCountTrend(ctx context.Context, days int) ([]models.AlertCountSnapshot, error)
+	GetAlertSummary(ctx context.Context) (*AlertSummary, error)
+	ListAlertHistory(ctx context.Context, alertID uint, page, pageSize int) ([]models.AlertAuditLog, int64, error)
+}
+
+// Alert 状态
+const (
+	AlertStatusEnabled  = "ENABLED"
+	AlertStatusDisabled = "DISABLED"
+)
+
+// IsValidAlertStatus 判断是否为合法的 Alert 状态
+func IsValidAlertStatus(status string) bool {
+	return status == AlertStatusEnabled || status == AlertStatusDisabled
 }
 
 // alertService Alert 服务实现
 type alertService struct {
-	alertStore store.AlertStore
+	alertStore    store.AlertStore
+	defaultSink   config.DefaultSinkConfig
+	defaultStatus string
 }
 
-// NewAlertService 创建新的 AlertService 实例
-func NewAlertService(alertStore store.AlertStore) AlertService {
-	return &alertService{
-		alertStore: alertStore,
+// NewAlertService 创建新的 AlertService 实例，defaultStatus 为创建时未提供状态的 Alert 使用的状态，
+// 为空时使用 ENABLED
+func NewAlertService(alertStore store.AlertStore, defaultSink *config.DefaultSinkConfig, defaultStatus string) AlertService {
+	if defaultStatus == "" {
+		defaultStatus = AlertStatusEnabled
 	}
+	s := &alertService{
+		alertStore:    alertStore,
+		defaultStatus: defaultStatus,
+	}
+	if defaultSink != nil {
+		s.defaultSink = *defaultSink
+	}
+	return s
 }
 
 // CreateAlert 创建 Alert
@@ -39,24 +138,80 @@ func (s *alertService) CreateAlert(ctx context.Context, alert *models.Alert) err
 	}
 
 	// 检查名称是否已存在
-	existingAlert, err := s.alertStore.GetByName(ctx, alert.Name)
+	existingAlert, err := s.alertStore.GetByNameUnscoped(ctx, alert.Name)
 	if err == nil && existingAlert != nil {
-		return fmt.Errorf("alert with name '%s' already exists", alert.Name)
+		if existingAlert.DeletedAt.Valid {
+			return fmt.Errorf("%w: alert with name '%s' (id %d) must be restored or deleted with hard=true first", ErrAlertDeleted, alert.Name, existingAlert.ID)
+		}
+		return fmt.Errorf("%w: %s", ErrAlertExists, alert.Name)
+	}
+
+	s.applyDefaultStatus(alert)
+	if !isSLSImport(ctx) {
+		s.applyDefaultSink(alert)
 	}
 
 	// 使用事务创建 Alert 及其关联数据
-	return s.alertStore.CreateWithTransaction(ctx, alert)
+	if err := s.alertStore.CreateWithTransaction(ctx, alert); err != nil {
+		if errors.Is(err, store.ErrAlertExists) {
+			return fmt.Errorf("%w: %v", ErrAlertExists, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// applyDefaultStatus 在创建的 Alert 没有提供状态时使用配置的默认状态，不依赖数据库列默认值
+func (s *alertService) applyDefaultStatus(alert *models.Alert) {
+	if alert.Status == "" {
+		alert.Status = s.defaultStatus
+	}
+}
+
+// applyDefaultSink 在 Alert 没有任何 Sink 配置时应用默认 Sink，显式提供的 Sink（包括 enabled=false）保持不变
+func (s *alertService) applyDefaultSink(alert *models.Alert) {
+	configuration := alert.Configuration
+	if !s.defaultSink.Enabled() || configuration == nil {
+		return
+	}
+	if configuration.SinkAlerthubConfig != nil || configuration.SinkCmsConfig != nil || configuration.SinkEventStoreConfig != nil {
+		return
+	}
+
+	if s.defaultSink.Alerthub {
+		enabled := true
+		configuration.SinkAlerthubConfig = &models.SinkAlerthubConfiguration{Enabled: &enabled}
+	}
+	if s.defaultSink.Cms {
+		enabled := true
+		configuration.SinkCmsConfig = &models.SinkCmsConfiguration{Enabled: &enabled}
+	}
+}
+
+// slsImportContextKey 标记创建的 Alert 来自 SLS 同步
+type slsImportContextKey struct{}
+
+// withSLSImport 标记 ctx 中创建的 Alert 来自 SLS：保持 SLS 中的配置，不应用默认 Sink，
+// 否则数据库与 SLS 不一致，下一次同步到 SLS 时会把默认 Sink 写回 SLS
+func withSLSImport(ctx context.Context) context.Context {
+	return context.WithValue(ctx, slsImportContextKey{}, true)
+}
+
+// isSLSImport ctx 中创建的 Alert 是否来自 SLS
+func isSLSImport(ctx context.Context) bool {
+	imported, _ := ctx.Value(slsImportContextKey{}).(bool)
+	return imported
 }
 
 // GetAlertByID 根据 ID 获取 Alert
 func (s *alertService) GetAlertByID(ctx context.Context, id uint) (*models.Alert, error) {
 	if id == 0 {
-		return nil, fmt.Errorf("invalid alert ID")
+		return nil, validationError("invalid alert ID")
 	}
 
 	alert, err := s.alertStore.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get alert: %w", err)
+		return nil, alertLookupError(err)
 	}
 
 	return alert, nil
@@ -65,12 +220,12 @@ func (s *alertService) GetAlertByID(ctx context.Context, id uint) (*models.Alert
 // GetAlertByName 根据名称获取 Alert
 func (s *alertService) GetAlertByName(ctx context.Context, name string) (*models.Alert, error) {
 	if name == "" {
-		return nil, fmt.Errorf("alert name cannot be empty")
+		return nil, validationError("alert name cannot be empty")
 	}
 
 	alert, err := s.alertStore.GetByName(ctx, name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get alert: %w", err)
+		return nil, alertLookupError(err)
 	}
 
 	return alert, nil
@@ -78,8 +233,20 @@ func (s *alertService) GetAlertByName(ctx context.Context, name string) (*models
 
 // UpdateAlert 更新 Alert
 func (s *alertService) UpdateAlert(ctx context.Context, alert *models.Alert) error {
+	return s.UpdateAlertSections(ctx, alert, store.AllSections)
+}
+
+// UpdateAlertSections 更新 Alert 的指定分区，未列出的分区保持不变
+func (s *alertService) UpdateAlertSections(ctx context.Context, alert *models.Alert, sections []string) error {
+	if len(sections) == 0 {
+		return fmt.Errorf("%w: at least one section is required", ErrInvalidUpdateSections)
+	}
+	if err := store.ValidateSections(sections); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidUpdateSections, err)
+	}
+
 	if alert.ID == 0 {
-		return fmt.Errorf("invalid alert ID")
+		return validationError("invalid alert ID")
 	}
 
 	// 验证必填字段
@@ -87,35 +254,150 @@ func (s *alertService) UpdateAlert(ctx context.Context, alert *models.Alert) err
 		return err
 	}
 
+	// 冻结期内拒绝更新
+	existing, err := s.alertStore.GetByID(ctx, alert.ID)
+	if err != nil {
+		return alertLookupError(err)
+	}
+	if err := checkNotFrozen(existing); err != nil {
+		return err
+	}
+
 	// 检查名称是否已被其他 Alert 使用
 	if alert.Name != "" {
 		existingAlert, err := s.alertStore.GetByName(ctx, alert.Name)
 		if err == nil && existingAlert != nil && existingAlert.ID != alert.ID {
-			return fmt.Errorf("alert with name '%s' already exists", alert.Name)
+			return fmt.Errorf("%w: %s", ErrAlertExists, alert.Name)
 		}
 	}
 
-	// 使用事务更新 Alert 及其关联数据
-	return s.alertStore.UpdateWithTransaction(ctx, alert)
+	// 使用事务更新 Alert 及其关联数据，乐观锁检查以事务内的条件更新为准
+	if err := s.alertStore.UpdateSectionsWithTransaction(ctx, alert, sections); err != nil {
+		if errors.Is(err, store.ErrAlertModified) {
+			return fmt.Errorf("%w: stored last_modified_time is no longer %d", ErrAlertModified, *alert.ExpectedLastModifiedTime)
+		}
+		return err
+	}
+	return nil
 }
 
-// DeleteAlert 删除 Alert
-func (s *alertService) DeleteAlert(ctx context.Context, id uint) error {
+// DeleteAlert 删除 Alert：默认软删除，hard 为 true 时永久删除 Alert 及其关联数据（包括已软删除的 Alert），返回被删除的 Alert
+func (s *alertService) DeleteAlert(ctx context.Context, id uint, hard bool) (*models.Alert, error) {
 	if id == 0 {
-		return fmt.Errorf("invalid alert ID")
+		return nil, validationError("invalid alert ID")
 	}
 
 	// 检查 Alert 是否存在
-	_, err := s.alertStore.GetByID(ctx, id)
+	var existing *models.Alert
+	var err error
+	if hard {
+		existing, err = s.alertStore.GetByIDUnscoped(ctx, id)
+	} else {
+		existing, err = s.alertStore.GetByID(ctx, id)
+	}
+	if err != nil {
+		return nil, alertLookupError(err)
+	}
+	if err := checkNotFrozen(existing); err != nil {
+		return nil, err
+	}
+
+	if hard {
+		err = s.alertStore.Delete(ctx, id)
+	} else {
+		err = s.alertStore.SoftDelete(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// RestoreAlert 恢复已软删除的 Alert
+func (s *alertService) RestoreAlert(ctx context.Context, id uint) (*models.Alert, error) {
+	if id == 0 {
+		return nil, validationError("invalid alert ID")
+	}
+
+	existing, err := s.alertStore.GetByIDUnscoped(ctx, id)
+	if err != nil {
+		return nil, alertLookupError(err)
+	}
+	if !existing.DeletedAt.Valid {
+		return nil, fmt.Errorf("%w: alert '%s'", ErrAlertNotDeleted, existing.Name)
+	}
+
+	if err := s.alertStore.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.alertStore.GetByID(ctx, id)
+}
+
+// FreezeAlert 设置 Alert 的冻结截止时间（Unix 秒），until 不晚于当前时间时解除冻结
+func (s *alertService) FreezeAlert(ctx context.Context, id uint, until int64) (*models.Alert, error) {
+	if id == 0 {
+		return nil, validationError("invalid alert ID")
+	}
+
+	if _, err := s.alertStore.GetByID(ctx, id); err != nil {
+		return nil, alertLookupError(err)
+	}
+
+	var freezeUntil *int64
+	if until > time.Now().Unix() {
+		freezeUntil = &until
+	}
+	if err := s.alertStore.SetFreezeUntil(ctx, id, freezeUntil); err != nil {
+		return nil, fmt.Errorf("failed to freeze alert: %w", err)
+	}
+
+	return s.alertStore.GetByID(ctx, id)
+}
+
+// SetAlertStatus 启用或停用 Alert，只更新状态和最后修改时间，配置、标签和查询保持不变
+func (s *alertService) SetAlertStatus(ctx context.Context, id uint, status string) (*models.Alert, error) {
+	if id == 0 {
+		return nil, validationError("invalid alert ID")
+	}
+	if !IsValidAlertStatus(status) {
+		return nil, validationError("invalid status: %s", status)
+	}
+
+	existing, err := s.alertStore.GetByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("alert not found: %w", err)
+		return nil, alertLookupError(err)
+	}
+	if err := checkNotFrozen(existing); err != nil {
+		return nil, err
 	}
 
-	return s.alertStore.Delete(ctx, id)
+	if err := s.alertStore.SetStatus(ctx, id, status, time.Now().Unix()); err != nil {
+		return nil, fmt.Errorf("failed to set alert status: %w", err)
+	}
+
+	return s.alertStore.GetByID(ctx, id)
 }
 
-// ListAlerts 分页获取 Alert 列表
-func (s *alertService) ListAlerts(ctx context.Context, page, pageSize int) ([]*models.Alert, int64, error) {
+// RebuildAlert 重建 Alert 的全部关联数据，用于修复孤立记录或悬空外键，不改变 Alert 的内容
+func (s *alertService) RebuildAlert(ctx context.Context, id uint) (*models.Alert, error) {
+	if id == 0 {
+		return nil, validationError("invalid alert ID")
+	}
+
+	if _, err := s.alertStore.GetByID(ctx, id); err != nil {
+		return nil, alertLookupError(err)
+	}
+
+	if err := s.alertStore.RebuildAssociations(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to rebuild alert associations: %w", err)
+	}
+
+	return s.alertStore.GetByID(ctx, id)
+}
+
+// ListAlerts 分页获取 Alert 列表，includeDeleted 为 true 时包含已软删除的 Alert
+func (s *alertService) ListAlerts(ctx context.Context, page, pageSize int, includeDeleted bool) ([]*models.Alert, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -124,10 +406,10 @@ func (s *alertService) ListAlerts(ctx context.Context, page, pageSize int) ([]*m
 	}
 
 	offset := (page - 1) * pageSize
-	return s.alertStore.List(ctx, offset, pageSize)
+	return s.alertStore.List(ctx, offset, pageSize, includeDeleted)
 }
 
-// ListAlertsByStatus 根据状态分页获取 Alert 列表
+// ListAlertsByStatus 根据状态分页获取 Alert 列表，status 为空时返回全部 Alert
 func (s *alertService) ListAlertsByStatus(ctx context.Context, status string, page, pageSize int) ([]*models.Alert, int64, error) {
 	if page < 1 {
 		page = 1
@@ -137,27 +419,220 @@ func (s *alertService) ListAlertsByStatus(ctx context.Context, status string, pa
 	}
 
 	// 验证状态值
-	if status != "" && status != "ENABLED" && status != "DISABLED" {
-		return nil, 0, fmt.Errorf("invalid status: %s", status)
+	if status != "" && !IsValidAlertStatus(status) {
+		return nil, 0, validationError("invalid status: %s", status)
 	}
 
 	offset := (page - 1) * pageSize
 	return s.alertStore.ListByStatus(ctx, status, offset, pageSize)
 }
 
-// validateAlert 验证 Alert 数据
+// SearchAlerts 按名称子串、状态和标签键值分页搜索 Alert
+func (s *alertService) SearchAlerts(ctx context.Context, filter store.SearchFilter, page, pageSize int) ([]*models.Alert, int64, error) {
+	filter.Name = strings.TrimSpace(filter.Name)
+	filter.Status = strings.ToUpper(strings.TrimSpace(filter.Status))
+	filter.TagKey = strings.TrimSpace(filter.TagKey)
+	if filter.Status != "" && !IsValidAlertStatus(filter.Status) {
+		return nil, 0, fmt.Errorf("%w: invalid status: %s", ErrInvalidSearchFilter, filter.Status)
+	}
+	if filter.TagValue != "" && filter.TagKey == "" {
+		return nil, 0, fmt.Errorf("%w: tag_value requires tag_key", ErrInvalidSearchFilter)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+	return s.alertStore.Search(ctx, filter, offset, pageSize)
+}
+
+// ListAlertsSyncedBefore 分页获取在指定时间之前同步过或从未同步过的 Alert
+func (s *alertService) ListAlertsSyncedBefore(ctx context.Context, before time.Time, page, pageSize int) ([]*models.Alert, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+	return s.alertStore.ListSyncedBefore(ctx, before, offset, pageSize)
+}
+
+// GetAlertsLastModified 获取 Alert 列表的最后修改时间，includeSync 为 true 时同时考虑同步时间
+func (s *alertService) GetAlertsLastModified(ctx context.Context, includeSync bool) (*time.Time, error) {
+	if includeSync {
+		return s.alertStore.LastModifiedIncludingSync(ctx)
+	}
+	return s.alertStore.LastModified(ctx)
+}
+
+// ListAlertsAfterID 基于 ID 游标分页获取 Alert，用于流式导出；status 非空时只返回该状态的 Alert
+func (s *alertService) ListAlertsAfterID(ctx context.Context, afterID uint, status string, pageSize int) ([]*models.Alert, error) {
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 100
+	}
+
+	if status != "" && !IsValidAlertStatus(status) {
+		return nil, validationError("invalid status: %s", status)
+	}
+
+	return s.alertStore.ListAfterID(ctx, afterID, status, pageSize)
+}
+
+// ListAlertHistory 分页获取 Alert 的变更审计记录，按时间倒序排列；Alert 永久删除后仍可查询
+func (s *alertService) ListAlertHistory(ctx context.Context, alertID uint, page, pageSize int) ([]models.AlertAuditLog, int64, error) {
+	if alertID == 0 {
+		return nil, 0, validationError("invalid alert ID")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+	return s.alertStore.ListAuditLogs(ctx, alertID, offset, pageSize)
+}
+
+// ListAlertTags 分页获取 Alert 的标签
+func (s *alertService) ListAlertTags(ctx context.Context, alertID uint, tagType string, page, pageSize int) ([]models.AlertTag, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	if tagType != "" && tagType != "label" && tagType != "annotation" {
+		return nil, 0, validationError("invalid tag type: %s", tagType)
+	}
+
+	if _, err := s.GetAlertByID(ctx, alertID); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	return s.alertStore.ListTags(ctx, alertID, tagType, offset, pageSize)
+}
+
+// AddAlertTag 为 Alert 添加单个标签
+func (s *alertService) AddAlertTag(ctx context.Context, alertID uint, tag *models.AlertTag) error {
+	if tag.TagType != "label" && tag.TagType != "annotation" {
+		return validationError("invalid tag type: %s", tag.TagType)
+	}
+	if tag.TagKey == "" {
+		return validationError("tag key is required")
+	}
+
+	if _, err := s.GetAlertByID(ctx, alertID); err != nil {
+		return err
+	}
+
+	// 同一 Alert 下 (类型, 键) 唯一
+	existingTag, err := s.alertStore.GetTag(ctx, alertID, tag.TagType, tag.TagKey)
+	if err == nil && existingTag != nil {
+		return fmt.Errorf("%w: %s '%s' already exists", ErrConflict, tag.TagType, tag.TagKey)
+	}
+
+	tag.ID = 0
+	tag.AlertID = alertID
+	return s.alertStore.CreateTag(ctx, tag)
+}
+
+// DeleteAlertTag 删除 Alert 的单个标签
+func (s *alertService) DeleteAlertTag(ctx context.Context, alertID, tagID uint) error {
+	if alertID == 0 || tagID == 0 {
+		return validationError("invalid alert or tag ID")
+	}
+
+	if err := s.alertStore.DeleteTag(ctx, alertID, tagID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: tag %d of alert %d", ErrNotFound, tagID, alertID)
+		}
+		return err
+	}
+	return nil
+}
+
+// AutocompleteAlerts 按名称前缀获取自动补全建议
+func (s *alertService) AutocompleteAlerts(ctx context.Context, prefix string, limit int) ([]store.AlertSuggestion, error) {
+	if prefix == "" {
+		return nil, validationError("prefix cannot be empty")
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	return s.alertStore.Autocomplete(ctx, prefix, limit)
+}
+
+// ExportAlertsByNames 按名称列表导出 Alert，同时返回未找到的名称
+func (s *alertService) ExportAlertsByNames(ctx context.Context, names []string) ([]*models.Alert, []string, error) {
+	if len(names) == 0 {
+		return nil, nil, validationError("names cannot be empty")
+	}
+
+	// 去重并保持请求中的顺序
+	seen := make(map[string]bool, len(names))
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		unique = append(unique, name)
+	}
+
+	alerts, err := s.alertStore.GetByNames(ctx, unique)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get alerts: %w", err)
+	}
+
+	found := make(map[string]bool, len(alerts))
+	for _, alert := range alerts {
+		found[alert.Name] = true
+	}
+
+	missing := []string{}
+	for _, name := range unique {
+		if !found[name] {
+			missing = append(missing, name)
+		}
+	}
+
+	return alerts, missing, nil
+}
+
+// validateAlert 验证 Alert 数据、调度配置和模板配置的 JSON，并就地规范化策略的 repeat_interval
 func (s *alertService) validateAlert(alert *models.Alert) error {
 	if alert.Name == "" {
-		return fmt.Errorf("alert name is required")
+		return validationError("alert name is required")
 	}
 
 	if alert.DisplayName == "" {
-		return fmt.Errorf("alert display name is required")
+		return validationError("alert display name is required")
 	}
 
-	if alert.Status != "" && alert.Status != "ENABLED" && alert.Status != "DISABLED" {
-		return fmt.Errorf("invalid status: %s", alert.Status)
+	if alert.Status != "" && !IsValidAlertStatus(alert.Status) {
+		return validationError("invalid status: %s", alert.Status)
 	}
 
-	return nil
+	if err := validateSchedule(alert.Schedule); err != nil {
+		return err
+	}
+
+	if alert.Configuration != nil {
+		if err := mapper.ValidateTemplateConfig(alert.Configuration.TemplateConfig); err != nil {
+			return validationError("%v", err)
+		}
+	}
+
+	// 规范化后写入，避免不合法的时长直到推送时才被 SLS 拒绝
+	return normalizePolicyRepeatInterval(alert)
 }
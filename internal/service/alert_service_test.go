@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/internal/store"
+)
+
+// newTestAlertService 基于内存数据库创建 AlertService
+func newTestAlertService(t *testing.T) (AlertService, store.AlertStore) {
+	t.Helper()
+
+	alertStore := newTestStore(t)
+	return NewAlertService(alertStore, &config.DefaultSinkConfig{}, AlertStatusEnabled), alertStore
+}
+
+// createTestAlerts 依次创建给定名称的 Alert
+func createTestAlerts(t *testing.T, alertService AlertService, names ...string) {
+	t.Helper()
+
+	for _, name := range names {
+		if err := alertService.CreateAlert(context.Background(), newTestAlert(name)); err != nil {
+			t.Fatalf("CreateAlert(%s): %v", name, err)
+		}
+	}
+}
+
+// alertNames 返回 Alert 的名称列表
+func alertNames(alerts []*models.Alert) []string {
+	names := []string{}
+	for _, alert := range alerts {
+		names = append(names, alert.Name)
+	}
+	return names
+}
+
+func TestListAlertsSyncedBefore(t *testing.T) {
+	ctx := context.Background()
+	alertService, alertStore := newTestAlertService(t)
+	createTestAlerts(t, alertService, "stale", "fresh", "never")
+
+	now := time.Now()
+	if err := alertStore.MarkSynced(ctx, "stale", SyncDirectionSLSToDB, now.Add(-48*time.Hour)); err != nil {
+		t.Fatalf("MarkSynced: %v", err)
+	}
+	if err := alertStore.MarkSynced(ctx, "fresh", SyncDirectionDBToSLS, now); err != nil {
+		t.Fatalf("MarkSynced: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		before time.Time
+		want   []string
+	}{
+		{name: "stale and never synced", before: now.Add(-24 * time.Hour), want: []string{"stale", "never"}},
+		{name: "everything synced before now", before: now.Add(time.Minute), want: []string{"stale", "fresh", "never"}},
+		{name: "only never synced", before: now.Add(-72 * time.Hour), want: []string{"never"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts, total, err := alertService.ListAlertsSyncedBefore(ctx, tt.before, 1, 20)
+			if err != nil {
+				t.Fatalf("ListAlertsSyncedBefore: %v", err)
+			}
+			got := alertNames(alerts)
+			if !sameNames(got, tt.want) || total != int64(len(tt.want)) {
+				t.Errorf("alerts = %v (total %d), want %v", got, total, tt.want)
+			}
+		})
+	}
+}
+
+// sameNames 不考虑顺序比较两个名称列表
+func sameNames(got, want []string) bool {
+	count := map[string]int{}
+	for _, name := range got {
+		count[name]++
+	}
+	for _, name := range want {
+		count[name]--
+	}
+	for _, n := range count {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDefaultSinkAppliedOnlyWithoutSinks(t *testing.T) {
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name         string
+		defaultSink  config.DefaultSinkConfig
+		modify       func(alert *models.Alert)
+		wantAlerthub *bool // nil 表示不应有 alerthub 配置
+		wantCms      *bool
+	}{
+		{
+			name: "defaults disabled",
+		},
+		{
+			name:         "no sink gets alerthub",
+			defaultSink:  config.DefaultSinkConfig{Alerthub: true},
+			wantAlerthub: &enabled,
+		},
+		{
+			name:         "no sink gets alerthub and cms",
+			defaultSink:  config.DefaultSinkConfig{Alerthub: true, Cms: true},
+			wantAlerthub: &enabled,
+			wantCms:      &enabled,
+		},
+		{
+			name:        "explicitly disabled alerthub is kept",
+			defaultSink: config.DefaultSinkConfig{Alerthub: true, Cms: true},
+			modify: func(alert *models.Alert) {
+				alert.Configuration.SinkAlerthubConfig = &models.SinkAlerthubConfiguration{Enabled: &disabled}
+			},
+			wantAlerthub: &disabled,
+		},
+		{
+			name:        "event store sink counts as a sink",
+			defaultSink: config.DefaultSinkConfig{Alerthub: true},
+			modify: func(alert *models.Alert) {
+				alert.Configuration.SinkEventStoreConfig = &models.SinkEventStoreConfiguration{Enabled: &enabled}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, batch := range []bool{false, true} {
+			name := tt.name
+			if batch {
+				name += " (batch)"
+			}
+			t.Run(name, func(t *testing.T) {
+				alertStore := newTestStore(t)
+				alertService := NewAlertService(alertStore, &tt.defaultSink, AlertStatusEnabled)
+
+				alert := newTestAlert("sink")
+				if tt.modify != nil {
+					tt.modify(alert)
+				}
+				if batch {
+					result, err := alertService.CreateAlerts(context.Background(), []*models.Alert{alert})
+					if err != nil || result.Created != 1 {
+						t.Fatalf("CreateAlerts = %+v, %v", result, err)
+					}
+				} else if err := alertService.CreateAlert(context.Background(), alert); err != nil {
+					t.Fatalf("CreateAlert: %v", err)
+				}
+
+				stored, err := alertStore.GetByName(context.Background(), "sink")
+				if err != nil {
+					t.Fatalf("GetByName: %v", err)
+				}
+				var alerthubEnabled, cmsEnabled *bool
+				if alerthub := stored.Configuration.SinkAlerthubConfig; alerthub != nil {
+					alerthubEnabled = alerthub.Enabled
+				}
+				if cms := stored.Configuration.SinkCmsConfig; cms != nil {
+					cmsEnabled = cms.Enabled
+				}
+				assertSink(t, "alerthub", stored.Configuration.SinkAlerthubConfig != nil, alerthubEnabled, tt.wantAlerthub)
+				assertSink(t, "cms", stored.Configuration.SinkCmsConfig != nil, cmsEnabled, tt.wantCms)
+			})
+		}
+	}
+}
+
+func TestDefaultStatusAppliedWithoutStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultStatus string
+		status        string
+		want          string
+	}{
+		{name: "configured disabled", defaultStatus: AlertStatusDisabled, want: AlertStatusDisabled},
+		{name: "configured enabled", defaultStatus: AlertStatusEnabled, want: AlertStatusEnabled},
+		{name: "unset falls back to enabled", want: AlertStatusEnabled},
+		{name: "explicit status kept", defaultStatus: AlertStatusDisabled, status: AlertStatusEnabled, want: AlertStatusEnabled},
+	}
+
+	for _, tt := range tests {
+		for _, batch := range []bool{false, true} {
+			name := tt.name
+			if batch {
+				name += " (batch)"
+			}
+			t.Run(name, func(t *testing.T) {
+				alertStore := newTestStore(t)
+				alertService := NewAlertService(alertStore, &config.DefaultSinkConfig{}, tt.defaultStatus)
+
+				alert := newTestAlert("status")
+				alert.Status = tt.status
+				if batch {
+					result, err := alertService.CreateAlerts(context.Background(), []*models.Alert{alert})
+					if err != nil || result.Created != 1 {
+						t.Fatalf("CreateAlerts = %+v, %v", result, err)
+					}
+				} else if err := alertService.CreateAlert(context.Background(), alert); err != nil {
+					t.Fatalf("CreateAlert: %v", err)
+				}
+
+				stored, err := alertStore.GetByName(context.Background(), "status")
+				if err != nil {
+					t.Fatalf("GetByName: %v", err)
+				}
+				if stored.Status != tt.want {
+					t.Errorf("status = %q, want %q", stored.Status, tt.want)
+				}
+			})
+		}
+	}
+}
+
+// assertSink 检查 Sink 配置是否存在以及开关的值
+func assertSink(t *testing.T, name string, present bool, enabled, want *bool) {
+	t.Helper()
+
+	if want == nil {
+		if present {
+			t.Errorf("%s sink = %v, want none", name, enabled)
+		}
+		return
+	}
+	if !present || enabled == nil || *enabled != *want {
+		t.Errorf("%s sink present=%v enabled=%v, want enabled=%v", name, present, enabled, *want)
+	}
+}
+
+func TestSyncFromSLSDoesNotApplyDefaultSink(t *testing.T) {
+	alertStore := newTestStore(t)
+	alertService := NewAlertService(alertStore, &config.DefaultSinkConfig{Alerthub: true}, AlertStatusEnabled)
+	syncSvc := NewSyncService(newFakeSLS(t, newTestAlert("imported")), alertStore, alertService, nil)
+
+	if _, err := syncSvc.SyncSLSToDatabase(context.Background()); err != nil {
+		t.Fatalf("SyncSLSToDatabase: %v", err)
+	}
+
+	stored, err := alertStore.GetByName(context.Background(), "imported")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	if stored.Configuration.SinkAlerthubConfig != nil {
+		t.Errorf("alert imported from SLS got default sink %+v, want the SLS configuration unchanged", stored.Configuration.SinkAlerthubConfig)
+	}
+}
+
+func TestListAlertsByStatus(t *testing.T) {
+	ctx := context.Background()
+	alertService, _ := newTestAlertService(t)
+	for name, status := range map[string]string{"on-1": AlertStatusEnabled, "on-2": AlertStatusEnabled, "off": AlertStatusDisabled} {
+		alert := newTestAlert(name)
+		alert.Status = status
+		if err := alertService.CreateAlert(ctx, alert); err != nil {
+			t.Fatalf("CreateAlert(%s): %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		status    string
+		wantTotal int64
+		wantErr   bool
+	}{
+		{name: "empty means all", status: "", wantTotal: 3},
+		{name: "enabled", status: AlertStatusEnabled, wantTotal: 2},
+		{name: "disabled", status: AlertStatusDisabled, wantTotal: 1},
+		{name: "invalid", status: "PAUSED", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts, total, err := alertService.ListAlertsByStatus(ctx, tt.status, 1, 20)
+			if tt.wantErr {
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("error = %v, want ErrValidation", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListAlertsByStatus: %v", err)
+			}
+			if total != tt.wantTotal || int64(len(alerts)) != tt.wantTotal {
+				t.Errorf("total = %d, alerts = %d, want %d", total, len(alerts), tt.wantTotal)
+			}
+			for _, alert := range alerts {
+				if tt.status != "" && alert.Status != tt.status {
+					t.Errorf("alert %s status = %s, want %s", alert.Name, alert.Status, tt.status)
+				}
+			}
+		})
+	}
+}
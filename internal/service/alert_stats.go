@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/internal/store"
+	"github.com/Ghostbaby/sls-migrate/pkg/logger"
+)
+
+// snapshotDayLayout 快照日期格式
+const snapshotDayLayout = "2006-01-02"
+
+// 趋势查询的天数范围
+const (
+	DefaultTrendDays = 30
+	MaxTrendDays     = 365
+)
+
+// AlertSummary 当前的 Alert 数量汇总
+type AlertSummary struct {
+	Total    int64            `json:"total"`
+	Enabled  int64            `json:"enabled"`
+	Disabled int64            `json:"disabled"`
+	Other    map[string]int64 `json:"other"` // ENABLED、DISABLED 以外的状态及其数量
+}
+
+// AlertCountSnapshotter 后台定期记录每日 Alert 数量快照
+type AlertCountSnapshotter interface {
+	Start(ctx context.Context)
+	Snapshot(ctx context.Context) error
+}
+
+// alertCountSnapshotter 每日数量快照实现，每个间隔覆盖写入当天的快照
+type alertCountSnapshotter struct {
+	alertStore store.AlertStore
+	interval   time.Duration
+	now        func() time.Time
+}
+
+// NewAlertCountSnapshotter 创建新的 AlertCountSnapshotter 实例，interval 小于等于 0 时每小时写入一次
+func NewAlertCountSnapshotter(alertStore store.AlertStore, interval time.Duration) AlertCountSnapshotter {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &alertCountSnapshotter{
+		alertStore: alertStore,
+		interval:   interval,
+		now:        time.Now,
+	}
+}
+
+// Start 立即写入一次当天的快照，之后按间隔在后台写入，直到 ctx 被取消。
+// 同一天多次写入只会更新当天的那条记录
+func (j *alertCountSnapshotter) Start(ctx context.Context) {
+	j.run(ctx)
+
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				j.run(ctx)
+			}
+		}
+	}()
+}
+
+// run 写入一次快照，失败时只记录日志，等待下一个间隔重试
+func (j *alertCountSnapshotter) run(ctx context.Context) {
+	if err := j.Snapshot(ctx); err != nil && ctx.Err() == nil {
+		logger.Printf(ctx, "Failed to record alert count snapshot: %v", err)
+	}
+}
+
+// Snapshot 统计当前的 Alert 数量并写入当天（UTC）的快照
+func (j *alertCountSnapshotter) Snapshot(ctx context.Context) error {
+	counts, err := j.alertStore.CountByStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count alerts by status: %w", err)
+	}
+
+	snapshot := &models.AlertCountSnapshot{
+		Day:      j.now().UTC().Format(snapshotDayLayout),
+		Enabled:  counts[AlertStatusEnabled],
+		Disabled: counts[AlertStatusDisabled],
+	}
+	for _, count := range counts {
+		snapshot.Total += count
+	}
+
+	if err := j.alertStore.SaveCountSnapshot(ctx, snapshot); err != nil {
+		return fmt.Errorf("failed to save alert count snapshot: %w", err)
+	}
+	return nil
+}
+
+// GetAlertCountTrend 获取最近 days 天（含今天）的每日数量快照，按日期升序，没有快照的日期不返回
+func (s *alertService) GetAlertCountTrend(ctx context.Context, days int) ([]models.AlertCountSnapshot, error) {
+	if days < 1 || days > MaxTrendDays {
+		days = DefaultTrendDays
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -(days - 1)).Format(snapshotDayLayout)
+	return s.alertStore.ListCountSnapshots(ctx, since)
+}
+
+// GetAlertSummary 以一次 GROUP BY status 查询统计当前 Alert 的总数和各状态数量（不含已软删除的 Alert）
+func (s *alertService) GetAlertSummary(ctx context.Context) (*AlertSummary, error) {
+	counts, err := s.alertStore.CountByStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count alerts by status: %w", err)
+	}
+
+	summary := &AlertSummary{Other: make(map[string]int64)}
+	for status, count := range counts {
+		summary.Total += count
+		switch status {
+		case AlertStatusEnabled:
+			summary.Enabled = count
+		case AlertStatusDisabled:
+			summary.Disabled = count
+		default:
+			summary.Other[status] = count
+		}
+	}
+	return summary, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+func TestAlertCountSnapshot(t *testing.T) {
+	ctx := context.Background()
+	alertStore := newTestStore(t)
+	alertService := NewAlertService(alertStore, &config.DefaultSinkConfig{}, AlertStatusEnabled)
+
+	enabled := newTestAlert("enabled")
+	disabled := newTestAlert("disabled")
+	disabled.Status = AlertStatusDisabled
+	for _, alert := range []*models.Alert{enabled, disabled} {
+		if err := alertService.CreateAlert(ctx, alert); err != nil {
+			t.Fatalf("CreateAlert(%s): %v", alert.Name, err)
+		}
+	}
+
+	today := time.Now().UTC()
+	yesterday := today.AddDate(0, 0, -1)
+	snapshotter := NewAlertCountSnapshotter(alertStore, time.Hour).(*alertCountSnapshotter)
+	snapshotAt := func(day time.Time) {
+		t.Helper()
+		snapshotter.now = func() time.Time { return day }
+		if err := snapshotter.Snapshot(ctx); err != nil {
+			t.Fatalf("Snapshot: %v", err)
+		}
+	}
+
+	snapshotAt(yesterday)
+	snapshotAt(today)
+	// 同一天再次写入只更新当天的记录
+	if err := alertService.CreateAlert(ctx, newTestAlert("later")); err != nil {
+		t.Fatalf("CreateAlert(later): %v", err)
+	}
+	snapshotAt(today)
+	snapshotAt(today)
+
+	trend, err := alertService.GetAlertCountTrend(ctx, 30)
+	if err != nil {
+		t.Fatalf("GetAlertCountTrend: %v", err)
+	}
+	want := []models.AlertCountSnapshot{
+		{Day: yesterday.Format(snapshotDayLayout), Total: 2, Enabled: 1, Disabled: 1},
+		{Day: today.Format(snapshotDayLayout), Total: 3, Enabled: 2, Disabled: 1},
+	}
+	if len(trend) != len(want) {
+		t.Fatalf("trend = %+v, want %d snapshots", trend, len(want))
+	}
+	for i, got := range trend {
+		if got.Day != want[i].Day || got.Total != want[i].Total || got.Enabled != want[i].Enabled || got.Disabled != want[i].Disabled {
+			t.Errorf("trend[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	// 只查询今天时不返回昨天的快照
+	trend, err = alertService.GetAlertCountTrend(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetAlertCountTrend: %v", err)
+	}
+	if len(trend) != 1 || trend[0].Day != want[1].Day {
+		t.Errorf("trend for 1 day = %+v, want only %s", trend, want[1].Day)
+	}
+}
+
+func TestAlertCountSnapshotterStartWritesImmediately(t *testing.T) {
+	alertStore := newTestStore(t)
+	snapshotter := NewAlertCountSnapshotter(alertStore, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	snapshotter.Start(ctx)
+	cancel()
+
+	// Start 在返回前同步写入了当天的快照
+	trend, err := NewAlertService(alertStore, &config.DefaultSinkConfig{}, AlertStatusEnabled).GetAlertCountTrend(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetAlertCountTrend: %v", err)
+	}
+	if len(trend) != 1 || trend[0].Total != 0 {
+		t.Errorf("trend = %+v, want one empty snapshot for today", trend)
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+// contentHashIgnoredKeys 计算内容哈希时忽略的字段：数据库主键和外键、本地时间戳、同步状态，
+// 以及 SLS 控制的创建和最后修改时间（哈希用于在时间戳没有变化时发现内容变化）
+var contentHashIgnoredKeys = map[string]bool{
+	"id":                         true,
+	"alert_id":                   true,
+	"alert_config_id":            true,
+	"configuration_id":           true,
+	"schedule_id":                true,
+	"condition_config_id":        true,
+	"group_config_id":            true,
+	"policy_config_id":           true,
+	"template_config_id":         true,
+	"sink_alerthub_config_id":    true,
+	"sink_cms_config_id":         true,
+	"sink_event_store_config_id": true,
+	"eval_condition_id":          true,
+	"alert":                      true, // AlertConfiguration 到 Alert 的反向关联
+	"create_time":                true,
+	"last_modified_time":         true,
+	"created_at":                 true,
+	"updated_at":                 true,
+	"deleted_at":                 true,
+	"last_synced_at":             true,
+	"last_sync_direction":        true,
+	"freeze_until":               true,
+	"content_hash":               true,
+}
+
+// alertContentHash 计算 Alert 内容的 SHA-256（十六进制）：按模型 JSON 去掉 contentHashIgnoredKeys 后，
+// 以键排序的规范 JSON 计算，同样的内容总是得到同样的哈希。raw_config 参与计算，模型未表示的字段变化也能发现
+func alertContentHash(alert *models.Alert) (string, error) {
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return "", err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", err
+	}
+
+	canonical, err := json.Marshal(stripContentHashKeys(value))
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// stripContentHashKeys 递归删除忽略的字段，encoding/json 输出 map 时按键排序
+func stripContentHashKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if contentHashIgnoredKeys[key] {
+				delete(v, key)
+				continue
+			}
+			v[key] = stripContentHashKeys(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = stripContentHashKeys(item)
+		}
+		return v
+	}
+	return value
+}
+
+// contentHashChanged 两侧都有内容哈希且不同时返回 true；任一侧缺失（如升级前同步的 Alert）时无法判断，返回 false
+func contentHashChanged(existing, new *models.Alert) bool {
+	return existing.ContentHash != nil && new.ContentHash != nil && *existing.ContentHash != *new.ContentHash
+}
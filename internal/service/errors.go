@@ -0,0 +1,53 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// 服务层错误分类，handler 据此映射 HTTP 状态码；具体的哨兵错误都归属于其中一类，
+// errors.Is 既能匹配具体错误，也能匹配所属分类
+var (
+	// ErrNotFound 请求的资源不存在
+	ErrNotFound = errors.New("not found")
+	// ErrValidation 请求参数或数据不合法
+	ErrValidation = errors.New("validation failed")
+	// ErrConflict 请求与资源的当前状态冲突
+	ErrConflict = errors.New("conflict")
+)
+
+// kindError 归属于某个分类的哨兵错误
+type kindError struct {
+	kind error
+	msg  string
+}
+
+// newError 创建归属于 kind 分类的哨兵错误
+func newError(kind error, msg string) error {
+	return &kindError{kind: kind, msg: msg}
+}
+
+// Error 实现 error 接口
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+// Is 匹配所属分类
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
+// validationError 生成归属于 ErrValidation 的错误
+func validationError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrValidation, fmt.Sprintf(format, args...))
+}
+
+// alertLookupError 转换查询 Alert 的错误：记录不存在时返回 ErrAlertNotFound，其余视为数据库错误
+func alertLookupError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: %v", ErrAlertNotFound, err)
+	}
+	return fmt.Errorf("failed to get alert: %w", err)
+}
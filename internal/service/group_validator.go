@@ -0,0 +1,82 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+// groupTypeCustom 按自定义字段分组通知
+const groupTypeCustom = "custom"
+
+// selectAllPattern 匹配 select *，此时查询输出的字段无法静态确定
+var selectAllPattern = regexp.MustCompile(`(?i)\bselect\s+\*`)
+
+// checkGroupFields 检查 custom 分组的字段是否出现在查询语句或 label 标签中。
+// 字段是否可用并不总能静态确定（如 select *），因此只返回警告；无法判断时不报告
+func checkGroupFields(alert *models.Alert) []Warning {
+	if alert.Configuration == nil || alert.Configuration.GroupConfig == nil {
+		return nil
+	}
+	group := alert.Configuration.GroupConfig
+	if group.Type == nil || *group.Type != groupTypeCustom {
+		return nil
+	}
+
+	var fields []string
+	for _, field := range group.Fields {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return []Warning{{
+			Alert:   alert.Name,
+			Field:   "configuration.group_config.fields",
+			Message: "custom group type requires at least one field",
+		}}
+	}
+
+	// 没有查询语句或查询输出不可确定时无法判断
+	if len(alert.Queries) == 0 {
+		return nil
+	}
+	for _, query := range alert.Queries {
+		if selectAllPattern.MatchString(query.Query) {
+			return nil
+		}
+	}
+
+	labels := make(map[string]bool)
+	for _, tag := range alert.Tags {
+		if tag.TagType == "label" {
+			labels[tag.TagKey] = true
+		}
+	}
+
+	var warnings []Warning
+	for _, field := range fields {
+		if labels[field] || fieldInQueries(field, alert.Queries) {
+			continue
+		}
+		warnings = append(warnings, Warning{
+			Alert:   alert.Name,
+			Field:   "configuration.group_config.fields",
+			Message: fmt.Sprintf("group field %q is not referenced by any query or label", field),
+		})
+	}
+	return warnings
+}
+
+// fieldInQueries 判断字段名是否作为完整标识符出现在任一查询语句中
+func fieldInQueries(field string, queries []models.AlertQuery) bool {
+	pattern := regexp.MustCompile(`(^|[^\w])` + regexp.QuoteMeta(field) + `([^\w]|$)`)
+	for _, query := range queries {
+		if pattern.MatchString(query.Query) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+func TestCheckGroupFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		groupType string
+		fields    models.StringSlice
+		query     string // 为空时使用 newTestAlert 的查询
+		noQueries bool
+		want      []string // 警告信息
+	}{
+		{
+			name:      "not custom",
+			groupType: "no_group",
+			fields:    models.StringSlice{"missing"},
+		},
+		{
+			name:      "field in query",
+			groupType: groupTypeCustom,
+			fields:    models.StringSlice{"host"},
+			query:     "* | select host, count(*) as cnt group by host",
+		},
+		{
+			name:      "field is a label",
+			groupType: groupTypeCustom,
+			fields:    models.StringSlice{"team"},
+		},
+		{
+			name:      "plausibly missing field",
+			groupType: groupTypeCustom,
+			fields:    models.StringSlice{"team", "region"},
+			want:      []string{`group field "region" is not referenced by any query or label`},
+		},
+		{
+			name:      "field only as a substring",
+			groupType: groupTypeCustom,
+			fields:    models.StringSlice{"host"},
+			query:     "* | select hostname, count(*) as cnt group by hostname",
+			want:      []string{`group field "host" is not referenced by any query or label`},
+		},
+		{
+			name:      "select star is not determinable",
+			groupType: groupTypeCustom,
+			fields:    models.StringSlice{"region"},
+			query:     "* | select * from log",
+		},
+		{
+			name:      "no queries is not determinable",
+			groupType: groupTypeCustom,
+			fields:    models.StringSlice{"region"},
+			noQueries: true,
+		},
+		{
+			name:      "custom without fields",
+			groupType: groupTypeCustom,
+			fields:    models.StringSlice{" "},
+			want:      []string{"custom group type requires at least one field"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := newTestAlert("grouped")
+			alert.Configuration.GroupConfig = &models.GroupConfiguration{Type: tea.String(tt.groupType), Fields: tt.fields}
+			if tt.query != "" {
+				alert.Queries[0].Query = tt.query
+			}
+			if tt.noQueries {
+				alert.Queries = nil
+			}
+
+			var got []string
+			for _, warning := range checkGroupFields(alert) {
+				if warning.Alert != "grouped" || warning.Field != "configuration.group_config.fields" {
+					t.Errorf("warning = %+v, want alert grouped on configuration.group_config.fields", warning)
+				}
+				got = append(got, warning.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("warnings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckGroupFieldsWithoutGroupConfig(t *testing.T) {
+	alert := newTestAlert("ungrouped")
+	if warnings := checkGroupFields(alert); warnings != nil {
+		t.Errorf("warnings = %+v, want none", warnings)
+	}
+	alert.Configuration = nil
+	if warnings := checkGroupFields(alert); warnings != nil {
+		t.Errorf("warnings = %+v, want none without configuration", warnings)
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/internal/store"
+	"github.com/Ghostbaby/sls-migrate/pkg/database"
+	"github.com/alibabacloud-go/tea/tea"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// newTestStore 为当前测试创建独立的内存 SQLite 数据库并返回基于它的 AlertStore
+func newTestStore(t testing.TB) store.AlertStore {
+	t.Helper()
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", name)
+	if err := database.InitDatabase(&config.DatabaseConfig{Driver: database.DriverSQLite, Database: dsn}); err != nil {
+		t.Fatalf("InitDatabase: %v", err)
+	}
+	database.DB.Logger = gormlogger.Default.LogMode(gormlogger.Silent)
+	if err := database.AutoMigrate(); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	// 取消进行中的查询会让连接池丢弃唯一的连接，内存数据库随最后一个连接关闭而销毁；
+	// 额外保持一个连接，保证超时类测试之后数据仍然可读
+	keeper, err := sql.Open("sqlite3", dsn)
+	if err == nil {
+		err = keeper.Ping()
+	}
+	if err != nil {
+		t.Fatalf("open keeper connection: %v", err)
+	}
+	t.Cleanup(func() {
+		database.CloseDatabase()
+		keeper.Close()
+	})
+
+	return store.NewAlertStore(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// newTestAlert 返回带配置、调度、标签和查询的 Alert
+func newTestAlert(name string) *models.Alert {
+	return &models.Alert{
+		Name:        name,
+		DisplayName: "Alert " + name,
+		Status:      AlertStatusEnabled,
+		Configuration: &models.AlertConfiguration{
+			Threshold: tea.Int32(1),
+			Type:      tea.String("default"),
+			Version:   tea.String("2.0"),
+			ConditionConfig: &models.ConditionConfiguration{
+				Condition: tea.String("cnt > 0"),
+			},
+		},
+		Schedule: &models.AlertSchedule{
+			Type:     "FixedRate",
+			Interval: tea.String("1m"),
+		},
+		Tags: []models.AlertTag{
+			{TagType: "label", TagKey: "team", TagValue: tea.String("ops")},
+		},
+		Queries: []models.AlertQuery{
+			{Query: "* | select count(*) as cnt", Store: tea.String("app-log"), StoreType: tea.String("log")},
+		},
+	}
+}
+
+// cloneAlert 经 JSON 深拷贝 Alert，模拟每次从 SLS 读取得到新的对象
+func cloneAlert(t testing.TB, alert *models.Alert) *models.Alert {
+	t.Helper()
+
+	data, err := json.Marshal(alert)
+	if err != nil {
+		t.Fatalf("marshal alert: %v", err)
+	}
+	var clone models.Alert
+	if err := json.Unmarshal(data, &clone); err != nil {
+		t.Fatalf("unmarshal alert: %v", err)
+	}
+	return &clone
+}
+
+// fakeSLS 内存中的 SLSService，只实现同步用到的方法，其余方法调用时 panic
+type fakeSLS struct {
+	SLSService
+
+	t       testing.TB
+	mu      sync.Mutex
+	alerts  map[string]*models.Alert
+	hidden  map[string]bool // 列出时不返回的 Alert，模拟列出之后才被并发创建
+	created []string
+	updated []string
+}
+
+// newFakeSLS 创建包含给定 Alert 的 fakeSLS
+func newFakeSLS(t testing.TB, alerts ...*models.Alert) *fakeSLS {
+	f := &fakeSLS{t: t, alerts: make(map[string]*models.Alert), hidden: make(map[string]bool)}
+	for _, alert := range alerts {
+		f.alerts[alert.Name] = cloneAlert(t, alert)
+	}
+	return f
+}
+
+func (f *fakeSLS) GetAlerts(ctx context.Context) ([]*models.Alert, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	names := make([]string, 0, len(f.alerts))
+	for name := range f.alerts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	alerts := make([]*models.Alert, 0, len(names))
+	for _, name := range names {
+		if !f.hidden[name] {
+			alerts = append(alerts, cloneAlert(f.t, f.alerts[name]))
+		}
+	}
+	return alerts, nil
+}
+
+func (f *fakeSLS) GetAlertsByNames(ctx context.Context, names []string) (map[string]*models.Alert, error) {
+	alerts, err := f.GetAlerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	result := make(map[string]*models.Alert)
+	for _, alert := range alerts {
+		if wanted[alert.Name] {
+			result[alert.Name] = alert
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeSLS) GetAlertByName(ctx context.Context, name string) (*models.Alert, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	alert, ok := f.alerts[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSLSNotFound, name)
+	}
+	return cloneAlert(f.t, alert), nil
+}
+
+func (f *fakeSLS) CreateAlert(ctx context.Context, alert *models.Alert) ([]Warning, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.alerts[alert.Name] = cloneAlert(f.t, alert)
+	f.created = append(f.created, alert.Name)
+	return nil, nil
+}
+
+func (f *fakeSLS) UpdateAlert(ctx context.Context, alert *models.Alert) ([]Warning, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.alerts[alert.Name] = cloneAlert(f.t, alert)
+	f.updated = append(f.updated, alert.Name)
+	return nil, nil
+}
+
+func (f *fakeSLS) CreateOrUpdateAlert(ctx context.Context, alert *models.Alert) (bool, []Warning, error) {
+	f.mu.Lock()
+	_, exists := f.alerts[alert.Name]
+	f.mu.Unlock()
+
+	if exists {
+		warnings, err := f.UpdateAlert(ctx, alert)
+		return true, warnings, err
+	}
+	warnings, err := f.CreateAlert(ctx, alert)
+	return false, warnings, err
+}
+
+func (f *fakeSLS) PatchAlert(ctx context.Context, alert, existing *models.Alert) ([]string, []Warning, error) {
+	if !configurationDiffers(existing, alert) {
+		return nil, nil, nil
+	}
+	warnings, err := f.UpdateAlert(ctx, alert)
+	return []string{"configuration"}, warnings, err
+}
+
+func (f *fakeSLS) Project() string {
+	return "test-project"
+}
+
+// set 替换 SLS 中的 Alert
+func (f *fakeSLS) set(alert *models.Alert) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.alerts[alert.Name] = cloneAlert(f.t, alert)
+}
+
+// newTestSyncService 基于内存数据库和 fakeSLS 创建 SyncService
+func newTestSyncService(t testing.TB, sls SLSService, syncConfig *config.SyncConfig) (*syncService, store.AlertStore) {
+	t.Helper()
+
+	alertStore := newTestStore(t)
+	alertService := NewAlertService(alertStore, &config.DefaultSinkConfig{}, AlertStatusEnabled)
+	return NewSyncService(sls, alertStore, alertService, syncConfig).(*syncService), alertStore
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+// roleArnPattern RAM 角色 ARN 格式：acs:ram::<账号 ID>:role/<角色名>
+var roleArnPattern = regexp.MustCompile(`^acs:ram::\d+:role/[\w.\-]+$`)
+
+// checkQueryScopes 检查跨账号、跨地域查询的配置是否自洽。
+// 设置了 RoleArn 时必须同时指定合法的 Region 和 Project，否则 SLS 无法定位被查询的资源，返回错误拒绝推送；
+// 未设置 RoleArn 但 Region 与当前 Project 所在地域不一致时，查询很可能无权限或查不到数据，只返回警告
+func checkQueryScopes(alert *models.Alert, homeRegion string) ([]Warning, error) {
+	var warnings []Warning
+	var problems []string
+	for i, query := range alert.Queries {
+		roleArn := trimmedValue(query.RoleArn)
+		region := trimmedValue(query.Region)
+		project := trimmedValue(query.Project)
+
+		if roleArn == "" {
+			if region != "" && homeRegion != "" && region != homeRegion {
+				warnings = append(warnings, Warning{
+					Alert:   alert.Name,
+					Field:   fmt.Sprintf("queries[%d].region", i),
+					Message: fmt.Sprintf("region %q differs from project region %q but no role_arn is set for cross-region access", region, homeRegion),
+				})
+			}
+			continue
+		}
+
+		if !roleArnPattern.MatchString(roleArn) {
+			problems = append(problems, fmt.Sprintf("queries[%d].role_arn %q is not a valid RAM role ARN (acs:ram::<uid>:role/<name>)", i, roleArn))
+		}
+		if region == "" {
+			problems = append(problems, fmt.Sprintf("queries[%d].region is required when role_arn is set", i))
+		}
+		if project == "" {
+			problems = append(problems, fmt.Sprintf("queries[%d].project is required when role_arn is set", i))
+		}
+	}
+
+	if len(problems) > 0 {
+		return warnings, fmt.Errorf("invalid cross-account query configuration: %s", strings.Join(problems, "; "))
+	}
+	return warnings, nil
+}
+
+// trimmedValue 返回去除首尾空白后的字符串值，nil 视为空字符串
+func trimmedValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return strings.TrimSpace(*value)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+const testRoleArn = "acs:ram::1234567890:role/sls-cross-account"
+
+// scopedQuery 返回指定了角色、地域和 Project 的查询，参数为空时不设置对应字段
+func scopedQuery(roleArn, region, project string) models.AlertQuery {
+	query := models.AlertQuery{Query: "* | select count(*) as cnt", Store: tea.String("app-log"), StoreType: tea.String("log")}
+	if roleArn != "" {
+		query.RoleArn = tea.String(roleArn)
+	}
+	if region != "" {
+		query.Region = tea.String(region)
+	}
+	if project != "" {
+		query.Project = tea.String(project)
+	}
+	return query
+}
+
+func TestCheckQueryScopes(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       models.AlertQuery
+		wantErr     []string // 错误中应包含的内容，为空表示不应返回错误
+		wantWarning string   // 为空表示不应产生警告
+	}{
+		{
+			name:  "same region without role",
+			query: scopedQuery("", "cn-hangzhou", ""),
+		},
+		{
+			name:  "cross-account query with region and project",
+			query: scopedQuery(testRoleArn, "cn-shanghai", "other-project"),
+		},
+		{
+			name:    "role arn without region",
+			query:   scopedQuery(testRoleArn, "", "other-project"),
+			wantErr: []string{"queries[0].region is required when role_arn is set"},
+		},
+		{
+			name:    "role arn without region and project",
+			query:   scopedQuery(testRoleArn, "  ", ""),
+			wantErr: []string{"queries[0].region is required", "queries[0].project is required"},
+		},
+		{
+			name:    "malformed role arn",
+			query:   scopedQuery("sls-cross-account", "cn-shanghai", "other-project"),
+			wantErr: []string{`queries[0].role_arn "sls-cross-account" is not a valid RAM role ARN`},
+		},
+		{
+			name:        "other region without role",
+			query:       scopedQuery("", "cn-shanghai", ""),
+			wantWarning: "queries[0].region",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := newTestAlert("scope")
+			alert.Queries = []models.AlertQuery{tt.query}
+
+			warnings, err := checkQueryScopes(alert, "cn-hangzhou")
+			if len(tt.wantErr) == 0 && err != nil {
+				t.Fatalf("checkQueryScopes: %v", err)
+			}
+			if len(tt.wantErr) > 0 && err == nil {
+				t.Fatalf("checkQueryScopes error = nil, want %q", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error = %v, want containing %q", err, want)
+				}
+			}
+
+			if tt.wantWarning == "" {
+				if len(warnings) != 0 {
+					t.Errorf("warnings = %+v, want none", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || warnings[0].Field != tt.wantWarning || warnings[0].Alert != "scope" {
+				t.Errorf("warnings = %+v, want one for %s", warnings, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestCreateAlertRejectsRoleArnWithoutRegion(t *testing.T) {
+	stub := &slsStub{handler: func(req stubRequest) (int, interface{}) {
+		return http.StatusOK, nil
+	}}
+	svc := newStubSLSService(t, stub)
+
+	alert := newTestAlert("cross-account")
+	alert.Queries = []models.AlertQuery{scopedQuery(testRoleArn, "", "other-project")}
+	if _, err := svc.CreateAlert(context.Background(), alert); err == nil || !strings.Contains(err.Error(), "region is required") {
+		t.Fatalf("CreateAlert error = %v, want the missing region rejected", err)
+	}
+	if calls := stub.calls(); len(calls) != 0 {
+		t.Errorf("requests = %+v, want none sent for an invalid query scope", calls)
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ghostbaby/sls-migrate/internal/mapper"
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+// Warning 非致命的校验或转换警告，与模型转换的警告是同一类型
+type Warning = mapper.Warning
+
+// QueryValidator Alert 查询语句检查器，返回疑似错误的警告而不是直接拒绝
+type QueryValidator interface {
+	Validate(query string) []string
+}
+
+// sqlPipeOperators 进入 SQL 分析部分的管道操作符，之后的内容不再按管道拆分（SQL 中 || 为字符串拼接）
+var sqlPipeOperators = map[string]bool{
+	"select": true,
+	"with":   true,
+	"set":    true,
+}
+
+// splPipeOperators 可识别的 SPL 管道操作符
+var splPipeOperators = map[string]bool{
+	"where":          true,
+	"extend":         true,
+	"project":        true,
+	"project-away":   true,
+	"project-rename": true,
+	"expand-values":  true,
+	"parse-regexp":   true,
+	"parse-json":     true,
+	"parse-csv":      true,
+	"parse-kv":       true,
+	"pack-fields":    true,
+	"stats":          true,
+	"sort":           true,
+	"limit":          true,
+	"log-to-metric":  true,
+}
+
+// lenientQueryValidator 宽松的 SLS 查询语法检查，只标记明显的问题以避免误报
+type lenientQueryValidator struct{}
+
+// NewLenientQueryValidator 创建新的宽松查询检查器
+func NewLenientQueryValidator() QueryValidator {
+	return &lenientQueryValidator{}
+}
+
+// Validate 检查查询语句：非空、引号和括号配对、管道操作符可识别
+func (v *lenientQueryValidator) Validate(query string) []string {
+	if strings.TrimSpace(query) == "" {
+		return []string{"query is empty"}
+	}
+
+	var warnings []string
+	var quote rune
+	escaped := false
+	depth := 0
+	unbalanced := false
+	inSQL := false
+	segments := []string{}
+	start := 0
+
+	for i, r := range query {
+		if quote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+			continue
+		}
+
+		switch r {
+		case '\'', '"', '`':
+			quote = r
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+			if depth < 0 {
+				unbalanced = true
+				depth = 0
+			}
+		case '|':
+			if inSQL {
+				continue
+			}
+			segments = append(segments, query[start:i])
+			start = i + 1
+			if sqlPipeOperators[firstWord(query[start:])] {
+				inSQL = true
+			}
+		}
+	}
+	segments = append(segments, query[start:])
+
+	if quote != 0 {
+		warnings = append(warnings, fmt.Sprintf("unterminated %c quote", quote))
+	}
+	if unbalanced || depth != 0 {
+		warnings = append(warnings, "unbalanced parentheses or brackets")
+	}
+
+	// 第一段为检索语句，之后每一段以管道操作符开头
+	for _, segment := range segments[1:] {
+		trimmed := strings.TrimSpace(segment)
+		if trimmed == "" {
+			warnings = append(warnings, "empty pipe segment")
+			continue
+		}
+		if strings.HasPrefix(trimmed, "(") {
+			continue
+		}
+		op := firstWord(trimmed)
+		if !sqlPipeOperators[op] && !splPipeOperators[op] {
+			warnings = append(warnings, fmt.Sprintf("unrecognized pipe operator %q", op))
+		}
+	}
+
+	return warnings
+}
+
+// firstWord 返回去掉前导空白后的第一个单词（小写）
+func firstWord(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(fields[0])
+}
+
+// checkAlertQueries 使用检查器检查 Alert 的所有查询语句
+func checkAlertQueries(validator QueryValidator, alert *models.Alert) []Warning {
+	var warnings []Warning
+	for i, query := range alert.Queries {
+		for _, message := range validator.Validate(query.Query) {
+			warnings = append(warnings, Warning{
+				Alert:   alert.Name,
+				Field:   fmt.Sprintf("queries[%d].query", i),
+				Message: message,
+			})
+		}
+	}
+	return warnings
+}
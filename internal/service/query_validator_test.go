@@ -0,0 +1,68 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+func TestLenientQueryValidator(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		// 合法的 SLS 查询不应产生警告
+		{name: "search only", query: "status: 500"},
+		{name: "search and sql", query: "* | select count(*) as cnt"},
+		{name: "sql with string concat", query: "* | select a || b as c from log"},
+		{name: "sql with quoted pipe", query: `* | select count(*) where msg = 'a | b'`},
+		{name: "escaped quote", query: `msg: "say \"hi\"" | select 1`},
+		{name: "spl pipeline", query: "* | where status = 500 | extend x = 1 | stats count(*) by host"},
+		{name: "parenthesized subquery segment", query: "* | (select 1)"},
+		{name: "brackets", query: "* | select arr[1] from log"},
+		{name: "case insensitive operator", query: "* | SELECT 1"},
+
+		// 明显有问题的查询
+		{name: "empty", query: "", want: []string{"query is empty"}},
+		{name: "whitespace", query: "   \n", want: []string{"query is empty"}},
+		{name: "unterminated single quote", query: "* | select 'abc", want: []string{"unterminated ' quote"}},
+		{name: "unterminated double quote", query: `msg: "abc`, want: []string{`unterminated " quote`}},
+		{name: "missing close paren", query: "* | select count(* as cnt", want: []string{"unbalanced parentheses or brackets"}},
+		{name: "extra close paren", query: "* | select count(*)) as cnt", want: []string{"unbalanced parentheses or brackets"}},
+		{name: "empty pipe segment", query: "* | | select 1", want: []string{"empty pipe segment"}},
+		{name: "trailing pipe", query: "status: 500 |", want: []string{"empty pipe segment"}},
+		{name: "unknown operator", query: "* | selct count(*)", want: []string{`unrecognized pipe operator "selct"`}},
+		{
+			name:  "several problems",
+			query: "* | bogus (x | where 'y",
+			want:  []string{"unterminated ' quote", "unbalanced parentheses or brackets", `unrecognized pipe operator "bogus"`},
+		},
+	}
+
+	validator := NewLenientQueryValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validator.Validate(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAlertQueries(t *testing.T) {
+	alert := &models.Alert{
+		Name: "cpu",
+		Queries: []models.AlertQuery{
+			{Query: "* | select 1"},
+			{Query: ""},
+		},
+	}
+
+	got := checkAlertQueries(NewLenientQueryValidator(), alert)
+	want := []Warning{{Alert: "cpu", Field: "queries[1].query", Message: "query is empty"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("warnings = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+// ErrInvalidRepeatInterval 策略的 repeat_interval 不是 SLS 可接受的时长
+var ErrInvalidRepeatInterval = newError(ErrValidation, "invalid repeat_interval")
+
+// repeatIntervalUnits SLS 时长支持的单位及其秒数，按从大到小排列，用于规范化
+var repeatIntervalUnits = []struct {
+	unit    string
+	seconds int64
+}{
+	{"d", 86400},
+	{"h", 3600},
+	{"m", 60},
+	{"s", 1},
+}
+
+// repeatIntervalPattern 一个或多个“整数+单位”片段，如 1h、90m、1h30m
+var repeatIntervalPattern = regexp.MustCompile(`^(\d+[dhms])+$`)
+
+// repeatIntervalPart 匹配单个“整数+单位”片段
+var repeatIntervalPart = regexp.MustCompile(`(\d+)([dhms])`)
+
+// normalizeRepeatInterval 解析 repeat_interval 并规范化为能整除的最大单位，
+// 如 60m 规范化为 1h、1h30m 规范化为 90m、86400s 规范化为 1d；时长必须大于 0
+func normalizeRepeatInterval(value string) (string, error) {
+	text := strings.ToLower(strings.TrimSpace(value))
+	if !repeatIntervalPattern.MatchString(text) {
+		return "", fmt.Errorf("%w: %q must be a positive integer followed by s, m, h or d (e.g. 30m, 1h, 1d)", ErrInvalidRepeatInterval, value)
+	}
+
+	var total int64
+	for _, match := range repeatIntervalPart.FindAllStringSubmatch(text, -1) {
+		seconds := repeatIntervalSeconds(match[2])
+		n, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || n > (math.MaxInt64-total)/seconds {
+			return "", fmt.Errorf("%w: %q is out of range", ErrInvalidRepeatInterval, value)
+		}
+		total += n * seconds
+	}
+	if total <= 0 {
+		return "", fmt.Errorf("%w: %q must be greater than 0", ErrInvalidRepeatInterval, value)
+	}
+
+	for _, u := range repeatIntervalUnits {
+		if total%u.seconds == 0 {
+			return strconv.FormatInt(total/u.seconds, 10) + u.unit, nil
+		}
+	}
+	return strconv.FormatInt(total, 10) + "s", nil
+}
+
+// repeatIntervalSeconds 返回单位对应的秒数
+func repeatIntervalSeconds(unit string) int64 {
+	for _, u := range repeatIntervalUnits {
+		if u.unit == unit {
+			return u.seconds
+		}
+	}
+	return 1
+}
+
+// normalizePolicyRepeatInterval 校验并就地规范化 Alert 策略的 repeat_interval，未设置或为空时保持不变
+func normalizePolicyRepeatInterval(alert *models.Alert) error {
+	if alert.Configuration == nil || alert.Configuration.PolicyConfig == nil {
+		return nil
+	}
+	policy := alert.Configuration.PolicyConfig
+	if policy.RepeatInterval == nil || strings.TrimSpace(*policy.RepeatInterval) == "" {
+		return nil
+	}
+
+	normalized, err := normalizeRepeatInterval(*policy.RepeatInterval)
+	if err != nil {
+		return err
+	}
+	policy.RepeatInterval = &normalized
+	return nil
+}
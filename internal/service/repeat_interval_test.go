@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+func TestNormalizeRepeatInterval(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr string // 为空表示合法
+	}{
+		{value: "1h", want: "1h"},
+		{value: "60m", want: "1h"},
+		{value: "1h30m", want: "90m"},
+		{value: "86400s", want: "1d"},
+		{value: "  2H ", want: "2h"},
+		{value: "45s", want: "45s"},
+		{value: "0m", wantErr: "must be greater than 0"},
+		{value: "1hour", wantErr: "must be a positive integer followed by"},
+		{value: "-5m", wantErr: "must be a positive integer followed by"},
+		{value: "1.5h", wantErr: "must be a positive integer followed by"},
+		{value: "99999999999999999999d", wantErr: "out of range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := normalizeRepeatInterval(tt.value)
+			if tt.wantErr == "" {
+				if err != nil || got != tt.want {
+					t.Errorf("normalizeRepeatInterval(%q) = %q, %v; want %q", tt.value, got, err, tt.want)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidRepeatInterval) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want ErrInvalidRepeatInterval containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateAlertNormalizesRepeatInterval(t *testing.T) {
+	ctx := context.Background()
+	alertService := NewAlertService(newTestStore(t), &config.DefaultSinkConfig{}, AlertStatusEnabled)
+
+	alert := newTestAlert("policy")
+	alert.Configuration.PolicyConfig = &models.PolicyConfiguration{RepeatInterval: tea.String("60m")}
+	if err := alertService.CreateAlert(ctx, alert); err != nil {
+		t.Fatalf("CreateAlert: %v", err)
+	}
+	stored, err := alertService.GetAlertByID(ctx, alert.ID)
+	if err != nil {
+		t.Fatalf("GetAlertByID: %v", err)
+	}
+	if got := tea.StringValue(stored.Configuration.PolicyConfig.RepeatInterval); got != "1h" {
+		t.Errorf("stored repeat_interval = %q, want 1h", got)
+	}
+
+	invalid := newTestAlert("bad-policy")
+	invalid.Configuration.PolicyConfig = &models.PolicyConfiguration{RepeatInterval: tea.String("often")}
+	if err := alertService.CreateAlert(ctx, invalid); !errors.Is(err, ErrInvalidRepeatInterval) || !errors.Is(err, ErrValidation) {
+		t.Errorf("CreateAlert error = %v, want ErrInvalidRepeatInterval as a validation error", err)
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/robfig/cron/v3"
+)
+
+// ErrInvalidSchedule Alert 调度配置不是 SLS 可接受的 cron 表达式或执行间隔
+var ErrInvalidSchedule = newError(ErrValidation, "invalid schedule")
+
+// SLS 调度类型
+const (
+	ScheduleTypeCron      = "Cron"      // 按 cron_expression 执行
+	ScheduleTypeFixedRate = "FixedRate" // 按 interval 固定间隔执行
+)
+
+// validateSchedule 校验调度配置：Cron 类型的 cron_expression 必须是标准的 5 段 cron 表达式，
+// FixedRate 类型的 interval 必须是大于 0 的 Go 时长（如 5m、1h）；其他类型不检查
+func validateSchedule(schedule *models.AlertSchedule) error {
+	if schedule == nil {
+		return nil
+	}
+
+	switch {
+	case strings.EqualFold(schedule.Type, ScheduleTypeCron):
+		if schedule.CronExpression == nil || strings.TrimSpace(*schedule.CronExpression) == "" {
+			return fmt.Errorf("%w: cron_expression is required for schedule type %s", ErrInvalidSchedule, schedule.Type)
+		}
+		if _, err := cron.ParseStandard(*schedule.CronExpression); err != nil {
+			return fmt.Errorf("%w: cron_expression %q: %v", ErrInvalidSchedule, *schedule.CronExpression, err)
+		}
+	case strings.EqualFold(schedule.Type, ScheduleTypeFixedRate):
+		if schedule.Interval == nil || strings.TrimSpace(*schedule.Interval) == "" {
+			return fmt.Errorf("%w: interval is required for schedule type %s", ErrInvalidSchedule, schedule.Type)
+		}
+		interval, err := time.ParseDuration(strings.TrimSpace(*schedule.Interval))
+		if err != nil {
+			return fmt.Errorf("%w: interval %q is not a duration (e.g. 1m, 5m, 1h)", ErrInvalidSchedule, *schedule.Interval)
+		}
+		if interval <= 0 {
+			return fmt.Errorf("%w: interval %q must be greater than 0", ErrInvalidSchedule, *schedule.Interval)
+		}
+	}
+	return nil
+}
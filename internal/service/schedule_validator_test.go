@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule *models.AlertSchedule
+		wantErr  string // 为空表示合法
+	}{
+		{name: "nil schedule"},
+		{name: "cron", schedule: &models.AlertSchedule{Type: ScheduleTypeCron, CronExpression: tea.String("*/5 * * * *")}},
+		{name: "cron descriptor", schedule: &models.AlertSchedule{Type: "cron", CronExpression: tea.String("@hourly")}},
+		{name: "cron with ranges", schedule: &models.AlertSchedule{Type: ScheduleTypeCron, CronExpression: tea.String("0 9-18 * * 1-5")}},
+		{
+			name:     "cron missing expression",
+			schedule: &models.AlertSchedule{Type: ScheduleTypeCron},
+			wantErr:  "cron_expression is required",
+		},
+		{
+			name:     "cron too few fields",
+			schedule: &models.AlertSchedule{Type: ScheduleTypeCron, CronExpression: tea.String("* * *")},
+			wantErr:  `cron_expression "* * *"`,
+		},
+		{
+			name:     "cron out of range",
+			schedule: &models.AlertSchedule{Type: ScheduleTypeCron, CronExpression: tea.String("0 25 * * *")},
+			wantErr:  `cron_expression "0 25 * * *"`,
+		},
+		{
+			name:     "cron garbage",
+			schedule: &models.AlertSchedule{Type: ScheduleTypeCron, CronExpression: tea.String("every minute")},
+			wantErr:  "cron_expression",
+		},
+		{name: "fixed rate", schedule: &models.AlertSchedule{Type: ScheduleTypeFixedRate, Interval: tea.String("5m")}},
+		{
+			name:     "fixed rate missing interval",
+			schedule: &models.AlertSchedule{Type: ScheduleTypeFixedRate, Interval: tea.String(" ")},
+			wantErr:  "interval is required",
+		},
+		{
+			name:     "fixed rate not a duration",
+			schedule: &models.AlertSchedule{Type: ScheduleTypeFixedRate, Interval: tea.String("5 minutes")},
+			wantErr:  "is not a duration",
+		},
+		{
+			name:     "fixed rate not positive",
+			schedule: &models.AlertSchedule{Type: ScheduleTypeFixedRate, Interval: tea.String("-1m")},
+			wantErr:  "must be greater than 0",
+		},
+		{name: "other type unchecked", schedule: &models.AlertSchedule{Type: "Hourly", CronExpression: tea.String("not cron")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSchedule(tt.schedule)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateSchedule: %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidSchedule) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want ErrInvalidSchedule containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateAlertRejectsInvalidCron(t *testing.T) {
+	alertService := NewAlertService(newTestStore(t), &config.DefaultSinkConfig{}, AlertStatusEnabled)
+
+	alert := newTestAlert("bad-cron")
+	alert.Schedule = &models.AlertSchedule{Type: ScheduleTypeCron, CronExpression: tea.String("61 * * * *")}
+	err := alertService.CreateAlert(context.Background(), alert)
+	if !errors.Is(err, ErrInvalidSchedule) || !errors.Is(err, ErrValidation) {
+		t.Fatalf("CreateAlert error = %v, want ErrInvalidSchedule as a validation error", err)
+	}
+	if _, err := alertService.GetAlertByName(context.Background(), "bad-cron"); err == nil {
+		t.Error("alert with an invalid cron expression was stored")
+	}
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"gorm.io/gorm"
+)
+
+// 字段差异类型
+const (
+	DiffKindOnlyInSLS = "only_in_sls"
+	DiffKindOnlyInDB  = "only_in_db"
+	DiffKindChanged   = "changed"
+)
+
+// AlertFieldDiff 数据库与 SLS 之间单个字段的差异
+type AlertFieldDiff struct {
+	Path     string      `json:"path"`
+	Kind     string      `json:"kind"`
+	DBValue  interface{} `json:"db_value,omitempty"`
+	SLSValue interface{} `json:"sls_value,omitempty"`
+}
+
+// AlertSLSDiff 单个 Alert 在数据库与 SLS 之间的字段级差异
+type AlertSLSDiff struct {
+	Name        string           `json:"name"`
+	InDB        bool             `json:"in_db"`
+	InSLS       bool             `json:"in_sls"`
+	Identical   bool             `json:"identical"`
+	Differences []AlertFieldDiff `json:"differences"`
+}
+
+// alertDiffView 参与比较的字段，只包含用户可见的配置，不包含 ID 和时间戳
+type alertDiffView struct {
+	DisplayName string             `json:"display_name"`
+	Description *string            `json:"description"`
+	Status      string             `json:"status"`
+	Schedule    *scheduleDiffView  `json:"schedule"`
+	Threshold   *int32             `json:"threshold"`
+	Condition   *conditionDiffView `json:"condition"`
+	Severities  []severityDiffView `json:"severities"`
+	Queries     []queryDiffView    `json:"queries"`
+}
+
+// scheduleDiffView 参与比较的调度字段
+type scheduleDiffView struct {
+	Type           string  `json:"type"`
+	CronExpression *string `json:"cron_expression"`
+	Interval       *string `json:"interval"`
+	Delay          *int32  `json:"delay"`
+	RunImmediately *bool   `json:"run_immediately"`
+	TimeZone       *string `json:"time_zone"`
+}
+
+// conditionDiffView 参与比较的触发条件字段
+type conditionDiffView struct {
+	Condition      *string `json:"condition"`
+	CountCondition *string `json:"count_condition"`
+}
+
+// severityDiffView 参与比较的严重程度字段
+type severityDiffView struct {
+	Severity  *int32             `json:"severity"`
+	Condition *conditionDiffView `json:"condition"`
+}
+
+// queryDiffView 参与比较的查询字段
+type queryDiffView struct {
+	Query        string  `json:"query"`
+	ChartTitle   *string `json:"chart_title"`
+	Project      *string `json:"project"`
+	Region       *string `json:"region"`
+	RoleArn      *string `json:"role_arn"`
+	Store        *string `json:"store"`
+	StoreType    *string `json:"store_type"`
+	Start        *string `json:"start"`
+	End          *string `json:"end"`
+	TimeSpanType *string `json:"time_span_type"`
+	PowerSqlMode *string `json:"power_sql_mode"`
+}
+
+// DiffAlertWithSLS 比较数据库与 SLS 中同名 Alert 的显示名称、描述、状态、调度、阈值、触发条件、严重程度和查询列表。
+// 两侧都不存在时返回 ErrAlertNotFound
+func (s *syncService) DiffAlertWithSLS(ctx context.Context, name string) (*AlertSLSDiff, error) {
+	dbAlert, err := s.alertStore.GetByName(ctx, name)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get alert from database: %w", err)
+		}
+		dbAlert = nil
+	}
+
+	slsAlert, err := s.slsService.GetAlertByName(ctx, name)
+	if err != nil {
+		if !errors.Is(err, ErrSLSNotFound) {
+			return nil, fmt.Errorf("failed to get alert from SLS: %w", err)
+		}
+		slsAlert = nil
+	}
+
+	if dbAlert == nil && slsAlert == nil {
+		return nil, fmt.Errorf("%w: %s is missing in both database and SLS", ErrAlertNotFound, name)
+	}
+
+	return diffAlertPair(name, dbAlert, slsAlert)
+}
+
+// diffAlertPair 比较同名 Alert 在两侧的字段，任一侧可以为 nil
+func diffAlertPair(name string, dbAlert, slsAlert *models.Alert) (*AlertSLSDiff, error) {
+	dbView, err := toDiffValue(dbAlert)
+	if err != nil {
+		return nil, err
+	}
+	slsView, err := toDiffValue(slsAlert)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &AlertSLSDiff{
+		Name:        name,
+		InDB:        dbAlert != nil,
+		InSLS:       slsAlert != nil,
+		Differences: []AlertFieldDiff{},
+	}
+	diffFieldValues("", dbView, slsView, &diff.Differences)
+	sort.Slice(diff.Differences, func(i, j int) bool {
+		return diff.Differences[i].Path < diff.Differences[j].Path
+	})
+	diff.Identical = len(diff.Differences) == 0
+
+	return diff, nil
+}
+
+// newAlertDiffView 提取参与比较的字段
+func newAlertDiffView(alert *models.Alert) *alertDiffView {
+	view := &alertDiffView{
+		DisplayName: alert.DisplayName,
+		Description: alert.Description,
+		Status:      alert.Status,
+	}
+
+	if alert.Schedule != nil {
+		view.Schedule = &scheduleDiffView{
+			Type:           alert.Schedule.Type,
+			CronExpression: alert.Schedule.CronExpression,
+			Interval:       alert.Schedule.Interval,
+			Delay:          alert.Schedule.Delay,
+			RunImmediately: trueOrNil(alert.Schedule.RunImmediately),
+			TimeZone:       alert.Schedule.TimeZone,
+		}
+	}
+
+	if alert.Configuration != nil {
+		view.Threshold = alert.Configuration.Threshold
+		view.Condition = newConditionDiffView(alert.Configuration.ConditionConfig)
+		for _, severity := range alert.Configuration.SeverityConfigs {
+			view.Severities = append(view.Severities, severityDiffView{
+				Severity:  severity.Severity,
+				Condition: newConditionDiffView(severity.EvalCondition),
+			})
+		}
+	}
+
+	for _, query := range alert.Queries {
+		view.Queries = append(view.Queries, queryDiffView{
+			Query:        query.Query,
+			ChartTitle:   query.ChartTitle,
+			Project:      query.Project,
+			Region:       query.Region,
+			RoleArn:      query.RoleArn,
+			Store:        query.Store,
+			StoreType:    query.StoreType,
+			Start:        query.Start,
+			End:          query.End,
+			TimeSpanType: query.TimeSpanType,
+			PowerSqlMode: query.PowerSqlMode,
+		})
+	}
+
+	return view
+}
+
+// trueOrNil 未设置的布尔值在数据库中存为 false、SLS 中为空，比较时将 false 视为未设置
+func trueOrNil(value *bool) *bool {
+	if value == nil || !*value {
+		return nil
+	}
+	return value
+}
+
+// newConditionDiffView 提取触发条件
+func newConditionDiffView(condition *models.ConditionConfiguration) *conditionDiffView {
+	if condition == nil {
+		return nil
+	}
+	return &conditionDiffView{
+		Condition:      condition.Condition,
+		CountCondition: condition.CountCondition,
+	}
+}
+
+// toDiffValue 将参与比较的字段经 JSON 转换为通用结构，Alert 不存在时返回空对象
+func toDiffValue(alert *models.Alert) (interface{}, error) {
+	if alert == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	data, err := json.Marshal(newAlertDiffView(alert))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal alert: %w", err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal alert: %w", err)
+	}
+	return value, nil
+}
+
+// diffFieldValues 递归比较对象和数组，记录不同的叶子路径及差异类型
+func diffFieldValues(path string, dbValue, slsValue interface{}, diffs *[]AlertFieldDiff) {
+	dbMap, dbIsMap := dbValue.(map[string]interface{})
+	slsMap, slsIsMap := slsValue.(map[string]interface{})
+	if dbIsMap && slsIsMap {
+		keys := make(map[string]bool, len(dbMap)+len(slsMap))
+		for key := range dbMap {
+			keys[key] = true
+		}
+		for key := range slsMap {
+			keys[key] = true
+		}
+		for key := range keys {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			diffFieldValues(childPath, dbMap[key], slsMap[key], diffs)
+		}
+		return
+	}
+
+	dbList, dbIsList := dbValue.([]interface{})
+	slsList, slsIsList := slsValue.([]interface{})
+	if dbIsList && slsIsList {
+		size := len(dbList)
+		if len(slsList) > size {
+			size = len(slsList)
+		}
+		for i := 0; i < size; i++ {
+			var dbItem, slsItem interface{}
+			if i < len(dbList) {
+				dbItem = dbList[i]
+			}
+			if i < len(slsList) {
+				slsItem = slsList[i]
+			}
+			diffFieldValues(path+"["+strconv.Itoa(i)+"]", dbItem, slsItem, diffs)
+		}
+		return
+	}
+
+	// SLS 不返回未设置的布尔值和列表，数据库中读回为 false 和空列表，视为相同
+	if reflect.DeepEqual(dbValue, slsValue) || (isEmptyDiffValue(dbValue) && isEmptyDiffValue(slsValue)) {
+		return
+	}
+
+	diff := AlertFieldDiff{Path: path, DBValue: dbValue, SLSValue: slsValue}
+	switch {
+	case dbValue == nil:
+		diff.Kind = DiffKindOnlyInSLS
+	case slsValue == nil:
+		diff.Kind = DiffKindOnlyInDB
+	default:
+		diff.Kind = DiffKindChanged
+	}
+	*diffs = append(*diffs, diff)
+}
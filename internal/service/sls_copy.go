@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+// ErrSLSAlertExists 目标 Project 中已存在同名 Alert 且未允许覆盖
+var ErrSLSAlertExists = newError(ErrConflict, "alert already exists in target project")
+
+// 复制结果
+const (
+	CopyActionCreated = "created"
+	CopyActionUpdated = "updated"
+)
+
+// CopyAlertResult 跨 Project 复制 Alert 的结果
+type CopyAlertResult struct {
+	Name          string    `json:"name"`
+	SourceProject string    `json:"source_project"`
+	TargetProject string    `json:"target_project"`
+	Action        string    `json:"action"`
+	Fields        []string  `json:"fields,omitempty"`
+	Warnings      []Warning `json:"warnings,omitempty"`
+}
+
+// CopyAlertBetweenProjects 将 Alert 从源 Project 直接复制到目标 Project，不经过数据库。
+// 目标中不存在时创建；已存在时 overwrite 为 true 则只推送有差异的字段，否则返回 ErrSLSAlertExists
+func CopyAlertBetweenProjects(ctx context.Context, slsService SLSService, sourceProject, targetProject, name string, overwrite bool) (*CopyAlertResult, error) {
+	if sourceProject == targetProject {
+		return nil, fmt.Errorf("%w: source and target project are the same", ErrInvalidSLSTarget)
+	}
+
+	source, err := slsService.WithTarget(SLSTarget{Project: sourceProject})
+	if err != nil {
+		return nil, err
+	}
+	target, err := slsService.WithTarget(SLSTarget{Project: targetProject})
+	if err != nil {
+		return nil, err
+	}
+
+	alert, err := source.GetAlertByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert from source project: %w", err)
+	}
+
+	result := &CopyAlertResult{
+		Name:          name,
+		SourceProject: sourceProject,
+		TargetProject: targetProject,
+	}
+
+	existing, err := target.GetAlertByName(ctx, name)
+	switch {
+	case err == nil:
+		return overwriteTargetAlert(ctx, target, result, alert, existing, overwrite)
+	case !errors.Is(err, ErrSLSNotFound):
+		return nil, fmt.Errorf("failed to get alert from target project: %w", err)
+	}
+
+	warnings, err := target.CreateAlert(ctx, alert)
+	result.Warnings = warnings
+	if errors.Is(err, ErrSLSAlreadyExists) {
+		// 检查与创建之间目标中出现了同名 Alert，与检查时已存在的处理相同
+		existing, err := target.GetAlertByName(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get alert from target project: %w", err)
+		}
+		return overwriteTargetAlert(ctx, target, result, alert, existing, overwrite)
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to create alert in target project: %w", err)
+	}
+	result.Action = CopyActionCreated
+
+	// CreateAlert 不设置状态，新建的 Alert 状态与源不一致时再单独同步
+	created, err := target.GetAlertByName(ctx, name)
+	if err != nil {
+		return result, fmt.Errorf("failed to get created alert from target project: %w", err)
+	}
+	if created.Status != alert.Status {
+		if _, _, err := target.PatchAlert(ctx, alert, created); err != nil {
+			return result, fmt.Errorf("failed to set alert status in target project: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// overwriteTargetAlert 处理目标中已存在的同名 Alert：overwrite 为 true 时只推送有差异的字段，否则返回 ErrSLSAlertExists
+func overwriteTargetAlert(ctx context.Context, target SLSService, result *CopyAlertResult, alert, existing *models.Alert, overwrite bool) (*CopyAlertResult, error) {
+	if !overwrite {
+		return nil, fmt.Errorf("%w: %s", ErrSLSAlertExists, alert.Name)
+	}
+
+	fields, warnings, err := target.PatchAlert(ctx, alert, existing)
+	result.Warnings = warnings
+	if err != nil {
+		return result, fmt.Errorf("failed to update alert in target project: %w", err)
+	}
+	result.Action = CopyActionUpdated
+	result.Fields = fields
+	return result, nil
+}
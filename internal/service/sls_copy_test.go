@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/mapper"
+)
+
+// copyRaceStub 源 Project 中有 Alert；目标 Project 第一次查询时不存在，创建时已被并发创建
+func copyRaceStub(t *testing.T) *slsStub {
+	t.Helper()
+
+	slsAlert, _, err := mapper.ModelToSLS(newTestAlert("copied"))
+	if err != nil {
+		t.Fatalf("ModelToSLS: %v", err)
+	}
+
+	var targetGets int32
+	return &slsStub{handler: func(req stubRequest) (int, interface{}) {
+		switch {
+		case req.Method == http.MethodGet && req.Project == "source":
+			return http.StatusOK, listAlertsBody(slsAlert)
+		case req.Method == http.MethodGet && req.Project == "target":
+			if atomic.AddInt32(&targetGets, 1) == 1 {
+				return http.StatusOK, listAlertsBody()
+			}
+			return http.StatusOK, listAlertsBody(slsAlert)
+		case req.Method == http.MethodPost:
+			return http.StatusBadRequest, slsErrorBody("AlertAlreadyExist", "alert already exists")
+		}
+		return http.StatusOK, nil
+	}}
+}
+
+func TestCopyAlertCreatedConcurrently(t *testing.T) {
+	tests := []struct {
+		name       string
+		overwrite  bool
+		wantErr    error
+		wantAction string
+	}{
+		{name: "without overwrite", overwrite: false, wantErr: ErrSLSAlertExists},
+		{name: "with overwrite", overwrite: true, wantAction: CopyActionUpdated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := copyRaceStub(t)
+			svc := newStubSLSService(t, stub)
+
+			result, err := CopyAlertBetweenProjects(context.Background(), svc, "source", "target", "copied", tt.overwrite)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !errors.Is(err, ErrConflict) {
+					t.Fatalf("error = %v, want %v (conflict)", err, tt.wantErr)
+				}
+				for _, call := range stub.calls() {
+					if call.Method == http.MethodPut {
+						t.Errorf("target was overwritten: %s %s", call.Method, call.Path)
+					}
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CopyAlertBetweenProjects: %v", err)
+			}
+			if result.Action != tt.wantAction {
+				t.Errorf("action = %q, want %q", result.Action, tt.wantAction)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/pkg/metrics"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// SLS 错误分类
+var (
+	ErrSLSAuth      = errors.New("SLS authentication failed")
+	ErrSLSNotFound  = newError(ErrNotFound, "SLS resource not found")
+	ErrSLSThrottled = errors.New("SLS request throttled")
+	ErrSLSInvalid   = errors.New("SLS rejected invalid configuration")
+	// ErrSLSUnavailable SLS 服务端临时不可用（ServiceUnavailable、内部错误或 5xx）
+	ErrSLSUnavailable = errors.New("SLS service unavailable")
+	// ErrSLSAlreadyExists 要创建的资源在 SLS 中已存在（如 AlertAlreadyExists）
+	ErrSLSAlreadyExists = errors.New("SLS resource already exists")
+)
+
+// SLS 错误分类名称，用于状态接口和同步结果
+const (
+	SLSErrorKindAuth        = "auth"
+	SLSErrorKindNotFound    = "not_found"
+	SLSErrorKindThrottled   = "throttled"
+	SLSErrorKindInvalid     = "invalid"
+	SLSErrorKindUnavailable = "unavailable"
+	SLSErrorKindExists      = "already_exists"
+)
+
+// slsMaxRetryBackoff 单次重试退避的上限
+const slsMaxRetryBackoff = 10 * time.Second
+
+// slsRetryPolicy SLS 临时错误（限流、服务不可用）的重试策略
+type slsRetryPolicy struct {
+	maxRetries int
+	backoff    time.Duration
+}
+
+// newSLSRetryPolicy 根据配置创建重试策略，负值按 0 处理
+func newSLSRetryPolicy(maxRetries int, backoff time.Duration) slsRetryPolicy {
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	if backoff < 0 {
+		backoff = 0
+	}
+	return slsRetryPolicy{maxRetries: maxRetries, backoff: backoff}
+}
+
+// delay 返回第 attempt 次重试（从 0 开始）前的等待时间：指数退避并加入随机抖动，
+// 实际等待时间在 [d/2, d) 之间，避免大量请求同时重试
+func (p slsRetryPolicy) delay(attempt int) time.Duration {
+	d := p.backoff
+	for i := 0; i < attempt && d < slsMaxRetryBackoff; i++ {
+		d *= 2
+	}
+	if d > slsMaxRetryBackoff {
+		d = slsMaxRetryBackoff
+	}
+	if d <= 1 {
+		return d
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// SLSError 已分类的 SLS SDK 错误，errors.Is 可同时匹配分类错误和原始错误
+type SLSError struct {
+	Kind       error
+	Code       string
+	StatusCode int
+	Err        error
+}
+
+// Error 实现 error 接口
+func (e *SLSError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+// Is 匹配错误分类
+func (e *SLSError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Unwrap 返回原始错误
+func (e *SLSError) Unwrap() error {
+	return e.Err
+}
+
+// slsErrorCodePatterns 按 SLS 错误码关键字分类，状态码不足以区分时使用
+var slsErrorCodePatterns = []struct {
+	pattern string
+	kind    error
+}{
+	{"unauthorized", ErrSLSAuth},
+	{"accessdenied", ErrSLSAuth},
+	{"invalidaccesskeyid", ErrSLSAuth},
+	{"signaturenotmatch", ErrSLSAuth},
+	{"securitytoken", ErrSLSAuth},
+	{"forbidden", ErrSLSAuth},
+	{"alreadyexist", ErrSLSAlreadyExists},
+	{"notexist", ErrSLSNotFound},
+	{"notfound", ErrSLSNotFound},
+	{"quotaexceed", ErrSLSThrottled},
+	{"exceedquota", ErrSLSThrottled},
+	{"throttl", ErrSLSThrottled},
+	{"toomanyrequests", ErrSLSThrottled},
+	{"serviceunavailable", ErrSLSUnavailable},
+	{"internalservererror", ErrSLSUnavailable},
+	{"internalerror", ErrSLSUnavailable},
+	{"requesttimeout", ErrSLSUnavailable},
+	{"parameterinvalid", ErrSLSInvalid},
+	{"invalidparameter", ErrSLSInvalid},
+	{"invalid", ErrSLSInvalid},
+}
+
+// ClassifySLSError 根据 SLS SDK 错误的错误码和 HTTP 状态码返回分类后的错误，无法分类时原样返回
+func ClassifySLSError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var slsErr *SLSError
+	if errors.As(err, &slsErr) {
+		return err
+	}
+
+	var sdkErr *tea.SDKError
+	if !errors.As(err, &sdkErr) {
+		return err
+	}
+
+	code := tea.StringValue(sdkErr.Code)
+	statusCode := tea.IntValue(sdkErr.StatusCode)
+	kind := classifySLSCode(code)
+	if kind == nil {
+		kind = classifySLSStatus(statusCode)
+	}
+	if kind == nil {
+		return err
+	}
+
+	return &SLSError{Kind: kind, Code: code, StatusCode: statusCode, Err: err}
+}
+
+// classifySLSCode 按错误码关键字分类
+func classifySLSCode(code string) error {
+	normalized := strings.ToLower(strings.ReplaceAll(code, ".", ""))
+	if normalized == "" {
+		return nil
+	}
+	for _, item := range slsErrorCodePatterns {
+		if strings.Contains(normalized, item.pattern) {
+			return item.kind
+		}
+	}
+	return nil
+}
+
+// classifySLSStatus 按 HTTP 状态码分类
+func classifySLSStatus(statusCode int) error {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrSLSAuth
+	case http.StatusNotFound:
+		return ErrSLSNotFound
+	case http.StatusConflict:
+		return ErrSLSAlreadyExists
+	case http.StatusTooManyRequests:
+		return ErrSLSThrottled
+	case http.StatusBadRequest:
+		return ErrSLSInvalid
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return ErrSLSUnavailable
+	}
+	return nil
+}
+
+// SLSErrorKind 返回错误的分类名称，未分类的错误返回空字符串
+func SLSErrorKind(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrSLSAuth):
+		return SLSErrorKindAuth
+	case errors.Is(err, ErrSLSNotFound):
+		return SLSErrorKindNotFound
+	case errors.Is(err, ErrSLSThrottled):
+		return SLSErrorKindThrottled
+	case errors.Is(err, ErrSLSInvalid):
+		return SLSErrorKindInvalid
+	case errors.Is(err, ErrSLSUnavailable):
+		return SLSErrorKindUnavailable
+	case errors.Is(err, ErrSLSAlreadyExists):
+		return SLSErrorKindExists
+	}
+	return ""
+}
+
+// IsRetryableSLSError 判断是否为可以重试的临时错误（限流或服务不可用），
+// 资源不存在、鉴权失败和参数错误等不会重试
+func IsRetryableSLSError(err error) bool {
+	return errors.Is(err, ErrSLSThrottled) || errors.Is(err, ErrSLSUnavailable)
+}
+
+// callSLS 执行 SLS SDK 调用并对错误分类，临时错误按指数退避加抖动重试，最多重试 retry.maxRetries 次；
+// 每次尝试的耗时按 operation（SDK 接口名）记录到指标。ctx 被取消或超时时立即返回 ctx.Err()，不再重试
+func callSLS(ctx context.Context, retry slsRetryPolicy, operation string, fn func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		err = ClassifySLSError(callWithContext(ctx, fn))
+		metrics.ObserveSLSRequest(operation, time.Since(start), err)
+		if err == nil || !IsRetryableSLSError(err) || attempt >= retry.maxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(retry.delay(attempt)):
+		}
+	}
+}
+
+// callWithContext 在后台执行一次 SDK 调用，ctx 先结束时立即返回 ctx.Err()。
+// SDK 不接受 ctx，调用方通过 runtimeOptions(ctx) 把截止时间传给 SDK 的读/连接超时，
+// 后台请求随之结束；ctx 没有截止时间而被取消时，后台请求在配置的读超时内结束，其结果被丢弃
+func callWithContext(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+func TestCallWithContext(t *testing.T) {
+	callErr := errors.New("call failed")
+
+	tests := []struct {
+		name       string
+		ctx        func() (context.Context, context.CancelFunc)
+		fn         func(release <-chan struct{}) error
+		wantErr    error
+		wantCalled bool
+	}{
+		{
+			name: "call finishes first",
+			ctx:  func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			fn: func(release <-chan struct{}) error {
+				return callErr
+			},
+			wantErr:    callErr,
+			wantCalled: true,
+		},
+		{
+			name: "cancelled before the call",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			fn: func(release <-chan struct{}) error {
+				return nil
+			},
+			wantErr:    context.Canceled,
+			wantCalled: false,
+		},
+		{
+			name: "cancelled while the call runs",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(20*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			fn: func(release <-chan struct{}) error {
+				<-release
+				return nil
+			},
+			wantErr:    context.Canceled,
+			wantCalled: true,
+		},
+		{
+			name: "deadline exceeded while the call runs",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			fn: func(release <-chan struct{}) error {
+				<-release
+				return nil
+			},
+			wantErr:    context.DeadlineExceeded,
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			release := make(chan struct{})
+			defer close(release)
+
+			var called int32
+			start := time.Now()
+			err := callWithContext(ctx, func() error {
+				atomic.StoreInt32(&called, 1)
+				return tt.fn(release)
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("callWithContext returned after %v, want it to return when ctx ends", elapsed)
+			}
+			// 调用在后台 goroutine 中启动，ctx 结束时可能尚未开始执行
+			if !tt.wantCalled && atomic.LoadInt32(&called) == 1 {
+				t.Errorf("fn was called although ctx was already done")
+			}
+		})
+	}
+}
+
+// sdkError 构造 SLS SDK 返回的错误
+func sdkError(code string, statusCode int) error {
+	return tea.NewSDKError(map[string]interface{}{
+		"code":    code,
+		"message": "sls error",
+		"data":    map[string]interface{}{"statusCode": statusCode},
+	})
+}
+
+func TestClassifySLSError(t *testing.T) {
+	plain := errors.New("connection reset")
+
+	tests := []struct {
+		name     string
+		err      error
+		wantKind error // nil 表示原样返回
+		wantName string
+	}{
+		{name: "unauthorized", err: sdkError("Unauthorized", http.StatusUnauthorized), wantKind: ErrSLSAuth, wantName: SLSErrorKindAuth},
+		{name: "invalid access key is auth, not invalid", err: sdkError("InvalidAccessKeyId", http.StatusBadRequest), wantKind: ErrSLSAuth, wantName: SLSErrorKindAuth},
+		{name: "signature mismatch", err: sdkError("SignatureNotMatch", http.StatusBadRequest), wantKind: ErrSLSAuth, wantName: SLSErrorKindAuth},
+		{name: "expired security token", err: sdkError("SecurityToken.Expired", http.StatusUnauthorized), wantKind: ErrSLSAuth, wantName: SLSErrorKindAuth},
+		{name: "forbidden status without code", err: sdkError("", http.StatusForbidden), wantKind: ErrSLSAuth, wantName: SLSErrorKindAuth},
+		{name: "alert not exist", err: sdkError("AlertNotExist", http.StatusNotFound), wantKind: ErrSLSNotFound, wantName: SLSErrorKindNotFound},
+		{name: "project not exist", err: sdkError("ProjectNotExist", http.StatusNotFound), wantKind: ErrSLSNotFound, wantName: SLSErrorKindNotFound},
+		{name: "alert already exists", err: sdkError("AlertAlreadyExist", http.StatusBadRequest), wantKind: ErrSLSAlreadyExists, wantName: SLSErrorKindExists},
+		{name: "quota exceeded", err: sdkError("WriteQuotaExceed", http.StatusForbidden), wantKind: ErrSLSThrottled, wantName: SLSErrorKindThrottled},
+		{name: "too many requests status", err: sdkError("", http.StatusTooManyRequests), wantKind: ErrSLSThrottled, wantName: SLSErrorKindThrottled},
+		{name: "invalid parameter", err: sdkError("ParameterInvalid", http.StatusBadRequest), wantKind: ErrSLSInvalid, wantName: SLSErrorKindInvalid},
+		{name: "bad request status with unknown code", err: sdkError("SomethingWrong", http.StatusBadRequest), wantKind: ErrSLSInvalid, wantName: SLSErrorKindInvalid},
+		{name: "internal server error", err: sdkError("InternalServerError", http.StatusInternalServerError), wantKind: ErrSLSUnavailable, wantName: SLSErrorKindUnavailable},
+		{name: "bad gateway status", err: sdkError("", http.StatusBadGateway), wantKind: ErrSLSUnavailable, wantName: SLSErrorKindUnavailable},
+		{name: "wrapped sdk error", err: fmt.Errorf("failed to get alert: %w", sdkError("AlertNotExist", http.StatusNotFound)), wantKind: ErrSLSNotFound, wantName: SLSErrorKindNotFound},
+		{name: "unknown code and status", err: sdkError("Teapot", http.StatusTeapot)},
+		{name: "not an sdk error", err: plain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifySLSError(tt.err)
+			if tt.wantKind == nil {
+				if got != tt.err {
+					t.Fatalf("ClassifySLSError = %v, want the error unchanged", got)
+				}
+				if kind := SLSErrorKind(got); kind != "" {
+					t.Errorf("SLSErrorKind = %q, want empty", kind)
+				}
+				return
+			}
+
+			if !errors.Is(got, tt.wantKind) {
+				t.Fatalf("ClassifySLSError = %v, want %v", got, tt.wantKind)
+			}
+			// 分类后仍可取到原始的 SDK 错误
+			var sdkErr *tea.SDKError
+			if !errors.As(got, &sdkErr) {
+				t.Errorf("classified error %v does not wrap the SDK error", got)
+			}
+			if kind := SLSErrorKind(got); kind != tt.wantName {
+				t.Errorf("SLSErrorKind = %q, want %q", kind, tt.wantName)
+			}
+			// 已分类的错误再次分类保持不变
+			if again := ClassifySLSError(got); again != got {
+				t.Errorf("classifying twice = %v, want %v", again, got)
+			}
+		})
+	}
+
+	if ClassifySLSError(nil) != nil {
+		t.Error("ClassifySLSError(nil) != nil")
+	}
+}
+
+func TestCallSLSRetriesOnlyTemporaryErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      []error // 依次返回的错误，用完后返回 nil
+		retries   int
+		wantErr   error
+		wantCalls int
+	}{
+		{name: "success", wantCalls: 1},
+		{name: "throttled then success", errs: []error{sdkError("QuotaExceed", http.StatusForbidden)}, retries: 3, wantCalls: 2},
+		{name: "unavailable then success", errs: []error{sdkError("", http.StatusServiceUnavailable)}, retries: 3, wantCalls: 2},
+		{name: "throttled beyond retries", errs: []error{
+			sdkError("", http.StatusTooManyRequests), sdkError("", http.StatusTooManyRequests), sdkError("", http.StatusTooManyRequests),
+		}, retries: 2, wantErr: ErrSLSThrottled, wantCalls: 3},
+		{name: "not found is not retried", errs: []error{sdkError("AlertNotExist", http.StatusNotFound)}, retries: 3, wantErr: ErrSLSNotFound, wantCalls: 1},
+		{name: "auth is not retried", errs: []error{sdkError("Unauthorized", http.StatusUnauthorized)}, retries: 3, wantErr: ErrSLSAuth, wantCalls: 1},
+		{name: "invalid is not retried", errs: []error{sdkError("ParameterInvalid", http.StatusBadRequest)}, retries: 3, wantErr: ErrSLSInvalid, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := callSLS(context.Background(), newSLSRetryPolicy(tt.retries, time.Millisecond), "Test", func() error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			})
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("callSLS: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestSLSServiceErrorsAreClassified(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		code     string
+		wantKind error
+	}{
+		{name: "auth", status: http.StatusUnauthorized, code: "Unauthorized", wantKind: ErrSLSAuth},
+		{name: "not found", status: http.StatusNotFound, code: "AlertNotExist", wantKind: ErrSLSNotFound},
+		{name: "invalid", status: http.StatusBadRequest, code: "ParameterInvalid", wantKind: ErrSLSInvalid},
+		{name: "throttled", status: http.StatusForbidden, code: "ExceedQuota", wantKind: ErrSLSThrottled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &slsStub{handler: func(req stubRequest) (int, interface{}) {
+				return tt.status, slsErrorBody(tt.code, "sls error")
+			}}
+			svc := newStubSLSService(t, stub)
+
+			_, err := svc.GetAlertByName(context.Background(), "missing")
+			if !errors.Is(err, tt.wantKind) {
+				t.Fatalf("GetAlertByName error = %v, want %v", err, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestSLSServiceRetriesTransientErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(svc *slsService) error
+	}{
+		{name: "list", call: func(svc *slsService) error {
+			_, err := svc.GetAlerts(context.Background())
+			return err
+		}},
+		{name: "create", call: func(svc *slsService) error {
+			_, err := svc.CreateAlert(context.Background(), newTestAlert("retry"))
+			return err
+		}},
+		{name: "update", call: func(svc *slsService) error {
+			_, err := svc.UpdateAlert(context.Background(), newTestAlert("retry"))
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 前两次分别返回限流和服务不可用，之后成功
+			var calls atomic.Int32
+			stub := &slsStub{handler: func(req stubRequest) (int, interface{}) {
+				switch calls.Add(1) {
+				case 1:
+					return http.StatusForbidden, slsErrorBody("Throttling", "too many requests")
+				case 2:
+					return http.StatusServiceUnavailable, slsErrorBody("ServiceUnavailable", "try again later")
+				}
+				return http.StatusOK, listAlertsBody()
+			}}
+			svc := newStubSLSService(t, stub)
+			svc.retry = newSLSRetryPolicy(3, time.Millisecond)
+
+			if err := tt.call(svc); err != nil {
+				t.Fatalf("call: %v", err)
+			}
+			if got := len(stub.calls()); got != 3 {
+				t.Errorf("requests = %d, want 2 failures and 1 success", got)
+			}
+		})
+	}
+}
+
+func TestSLSServiceDoesNotRetryPermanentErrors(t *testing.T) {
+	stub := &slsStub{handler: func(req stubRequest) (int, interface{}) {
+		return http.StatusNotFound, slsErrorBody("AlertNotExist", "alert not exist")
+	}}
+	svc := newStubSLSService(t, stub)
+	svc.retry = newSLSRetryPolicy(3, time.Millisecond)
+
+	if _, err := svc.UpdateAlert(context.Background(), newTestAlert("missing")); !errors.Is(err, ErrSLSNotFound) {
+		t.Fatalf("UpdateAlert error = %v, want ErrSLSNotFound", err)
+	}
+	if got := len(stub.calls()); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestSLSRetryDelay(t *testing.T) {
+	policy := newSLSRetryPolicy(5, 100*time.Millisecond)
+	tests := []struct {
+		attempt int
+		max     time.Duration // 抖动后的等待时间在 [max/2, max) 之间
+	}{
+		{attempt: 0, max: 100 * time.Millisecond},
+		{attempt: 1, max: 200 * time.Millisecond},
+		{attempt: 3, max: 800 * time.Millisecond},
+		{attempt: 20, max: slsMaxRetryBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("attempt %d", tt.attempt), func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				if d := policy.delay(tt.attempt); d < tt.max/2 || d >= tt.max {
+					t.Fatalf("delay = %v, want within [%v, %v)", d, tt.max/2, tt.max)
+				}
+			}
+		})
+	}
+
+	if policy := newSLSRetryPolicy(-1, -time.Second); policy.maxRetries != 0 || policy.delay(0) != 0 {
+		t.Errorf("negative policy = %+v, want no retries and no delay", policy)
+	}
+}
+
+func TestSLSServiceCallsReturnWhenContextCancelled(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(ctx context.Context, svc *slsService) error
+	}{
+		{
+			name: "GetAlerts",
+			call: func(ctx context.Context, svc *slsService) error {
+				_, err := svc.GetAlerts(ctx)
+				return err
+			},
+		},
+		{
+			name: "CreateAlert",
+			call: func(ctx context.Context, svc *slsService) error {
+				_, err := svc.CreateAlert(ctx, newTestAlert("cancelled"))
+				return err
+			},
+		},
+		{
+			name: "UpdateAlert",
+			call: func(ctx context.Context, svc *slsService) error {
+				_, err := svc.UpdateAlert(ctx, newTestAlert("cancelled"))
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := make(chan struct{}, 1)
+			release := make(chan struct{})
+			defer close(release)
+			// SLS 请求一直挂起，直到测试结束
+			svc := newStubSLSService(t, &slsStub{handler: func(req stubRequest) (int, interface{}) {
+				select {
+				case started <- struct{}{}:
+				default:
+				}
+				<-release
+				return http.StatusOK, listAlertsBody()
+			}})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			done := make(chan error, 1)
+			go func() { done <- tt.call(ctx, svc) }()
+
+			select {
+			case <-started:
+			case <-time.After(5 * time.Second):
+				t.Fatal("SLS request was not sent")
+			}
+			cancel()
+
+			select {
+			case err := <-done:
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("error = %v, want context.Canceled", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("call did not return after the context was cancelled")
+			}
+		})
+	}
+}
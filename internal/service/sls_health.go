@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// SLSHealthChecker 缓存 SLS 连通性状态，供 SLS 接口在不可用时快速失败
+type SLSHealthChecker interface {
+	Start(ctx context.Context)
+	Available() bool
+	LastError() string
+	MarkAvailable()
+}
+
+// slsHealthChecker SLS 连通性检查实现，后台定期探测并缓存结果
+type slsHealthChecker struct {
+	slsService SLSService
+	interval   time.Duration
+
+	mu        sync.RWMutex
+	available bool
+	lastError string
+}
+
+// NewSLSHealthChecker 创建新的 SLSHealthChecker 实例，在第一次探测前假定 SLS 可用
+func NewSLSHealthChecker(slsService SLSService, interval time.Duration) SLSHealthChecker {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	return &slsHealthChecker{
+		slsService: slsService,
+		interval:   interval,
+		available:  true,
+	}
+}
+
+// Start 立即探测一次，之后按间隔在后台探测，直到 ctx 被取消
+func (h *slsHealthChecker) Start(ctx context.Context) {
+	h.probe(ctx)
+
+	go func() {
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				h.probe(ctx)
+			}
+		}
+	}()
+}
+
+// Available 返回缓存的 SLS 连通性状态
+func (h *slsHealthChecker) Available() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.available
+}
+
+// LastError 返回最近一次探测失败的错误信息
+func (h *slsHealthChecker) LastError() string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.lastError
+}
+
+// MarkAvailable 在 SLS 调用成功后刷新状态为可用
+func (h *slsHealthChecker) MarkAvailable() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.available = true
+	h.lastError = ""
+}
+
+// probe 探测一次 SLS 连通性，每次探测的超时不超过检查间隔
+func (h *slsHealthChecker) probe(ctx context.Context) {
+	probeCtx, cancel := context.WithTimeout(ctx, h.interval)
+	defer cancel()
+
+	err := h.slsService.Ping(probeCtx)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	// 被限流说明 SLS 可访问，不标记为不可用
+	if err != nil && !errors.Is(err, ErrSLSThrottled) {
+		if h.available {
+			log.Printf("SLS health check failed, marking SLS unavailable: %v", err)
+		}
+		h.available = false
+		h.lastError = err.Error()
+		return
+	}
+
+	if !h.available {
+		log.Println("SLS health check succeeded, marking SLS available")
+	}
+	h.available = true
+	h.lastError = ""
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pingSLS 只实现 Ping 的 SLSService，返回预设的错误
+type pingSLS struct {
+	SLSService
+	err error
+}
+
+func (p *pingSLS) Ping(ctx context.Context) error {
+	return p.err
+}
+
+func TestSLSHealthCheckerProbe(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantAvailable bool
+		wantLastError string
+	}{
+		{name: "reachable", wantAvailable: true},
+		{name: "unreachable", err: fmt.Errorf("%w: dial tcp: i/o timeout", ErrSLSUnavailable), wantAvailable: false, wantLastError: "dial tcp: i/o timeout"},
+		{name: "throttled counts as reachable", err: fmt.Errorf("%w: too many requests", ErrSLSThrottled), wantAvailable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewSLSHealthChecker(&pingSLS{err: tt.err}, time.Hour).(*slsHealthChecker)
+			if !checker.Available() {
+				t.Fatalf("checker should assume SLS is available before the first probe")
+			}
+
+			checker.probe(context.Background())
+			if got := checker.Available(); got != tt.wantAvailable {
+				t.Errorf("Available = %v, want %v", got, tt.wantAvailable)
+			}
+			if tt.wantLastError == "" && checker.LastError() != "" {
+				t.Errorf("LastError = %q, want empty", checker.LastError())
+			}
+			if tt.wantLastError != "" && !strings.Contains(checker.LastError(), tt.wantLastError) {
+				t.Errorf("LastError = %q, want containing %q", checker.LastError(), tt.wantLastError)
+			}
+		})
+	}
+}
+
+func TestSLSHealthCheckerRecovers(t *testing.T) {
+	sls := &pingSLS{err: errors.New("connection refused")}
+	checker := NewSLSHealthChecker(sls, time.Hour).(*slsHealthChecker)
+
+	checker.probe(context.Background())
+	if checker.Available() {
+		t.Fatalf("Available = true after a failed probe")
+	}
+
+	// 业务调用成功后立即恢复，不等待下一次探测
+	checker.MarkAvailable()
+	if !checker.Available() || checker.LastError() != "" {
+		t.Errorf("after MarkAvailable: available=%v last_error=%q", checker.Available(), checker.LastError())
+	}
+
+	// 再次探测失败重新标记为不可用，之后探测成功恢复
+	checker.probe(context.Background())
+	if checker.Available() {
+		t.Fatalf("Available = true after a second failed probe")
+	}
+	sls.err = nil
+	checker.probe(context.Background())
+	if !checker.Available() {
+		t.Errorf("Available = false after a successful probe")
+	}
+}
+
+func TestSLSHealthCheckerStartProbesImmediately(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checker := NewSLSHealthChecker(&pingSLS{err: errors.New("connection refused")}, time.Hour)
+	checker.Start(ctx)
+	if checker.Available() {
+		t.Errorf("Available = true, Start should probe before returning")
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+// 全量差异分类
+const (
+	InventoryActionSLSOnly   = "sls_only"
+	InventoryActionDBOnly    = "db_only"
+	InventoryActionDivergent = "divergent"
+)
+
+// inventoryDBPageSize 读取数据库全部 Alert 时的页大小
+const inventoryDBPageSize = 500
+
+// InventoryDiffItem 全量差异中的单个 Alert，divergent 时附带字段差异
+type InventoryDiffItem struct {
+	Name        string           `json:"name"`
+	Differences []AlertFieldDiff `json:"differences,omitempty"`
+}
+
+// InventoryDiff 数据库与 SLS 全部 Alert 的差异，各列表按名称排序
+type InventoryDiff struct {
+	SLSOnly   []InventoryDiffItem `json:"sls_only"`
+	DBOnly    []InventoryDiffItem `json:"db_only"`
+	Divergent []InventoryDiffItem `json:"divergent"`
+}
+
+// IsInventoryAction 是否为合法的差异分类
+func IsInventoryAction(action string) bool {
+	switch action {
+	case InventoryActionSLSOnly, InventoryActionDBOnly, InventoryActionDivergent:
+		return true
+	}
+	return false
+}
+
+// Items 返回指定分类的 Alert 列表
+func (d *InventoryDiff) Items(action string) []InventoryDiffItem {
+	switch action {
+	case InventoryActionSLSOnly:
+		return d.SLSOnly
+	case InventoryActionDBOnly:
+		return d.DBOnly
+	case InventoryActionDivergent:
+		return d.Divergent
+	}
+	return nil
+}
+
+// DiffInventory 按名称比较数据库与 SLS 中的全部 Alert，分为只在 SLS、只在数据库和两侧不一致三类。
+// 两侧一致的 Alert 不出现在结果中，字段比较规则与 DiffAlertWithSLS 相同
+func (s *syncService) DiffInventory(ctx context.Context) (*InventoryDiff, error) {
+	slsAlerts, err := s.slsService.GetAlerts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts from SLS: %w", err)
+	}
+	slsByName := make(map[string]*models.Alert, len(slsAlerts))
+	for _, alert := range slsAlerts {
+		slsByName[alert.Name] = alert
+	}
+
+	diff := &InventoryDiff{
+		SLSOnly:   []InventoryDiffItem{},
+		DBOnly:    []InventoryDiffItem{},
+		Divergent: []InventoryDiffItem{},
+	}
+
+	seen := make(map[string]bool)
+	var afterID uint
+	for {
+		dbAlerts, err := s.alertStore.ListAfterID(ctx, afterID, "", inventoryDBPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get alerts from database: %w", err)
+		}
+		for _, dbAlert := range dbAlerts {
+			seen[dbAlert.Name] = true
+			slsAlert, ok := slsByName[dbAlert.Name]
+			if !ok {
+				diff.DBOnly = append(diff.DBOnly, InventoryDiffItem{Name: dbAlert.Name})
+				continue
+			}
+
+			pair, err := diffAlertPair(dbAlert.Name, dbAlert, slsAlert)
+			if err != nil {
+				return nil, err
+			}
+			if !pair.Identical {
+				diff.Divergent = append(diff.Divergent, InventoryDiffItem{Name: dbAlert.Name, Differences: pair.Differences})
+			}
+		}
+		if len(dbAlerts) < inventoryDBPageSize {
+			break
+		}
+		afterID = dbAlerts[len(dbAlerts)-1].ID
+	}
+
+	for name := range slsByName {
+		if !seen[name] {
+			diff.SLSOnly = append(diff.SLSOnly, InventoryDiffItem{Name: name})
+		}
+	}
+
+	for _, items := range [][]InventoryDiffItem{diff.SLSOnly, diff.DBOnly, diff.Divergent} {
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].Name < items[j].Name
+		})
+	}
+
+	return diff, nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// inventoryNames 返回差异条目的名称
+func inventoryNames(items []InventoryDiffItem) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+func TestDiffInventoryCategories(t *testing.T) {
+	ctx := context.Background()
+
+	divergent := newTestAlert("divergent")
+	divergent.Configuration.Threshold = tea.Int32(5)
+	sls := newFakeSLS(t, newTestAlert("same"), divergent, newTestAlert("sls-b"), newTestAlert("sls-a"))
+	syncSvc, alertStore := newTestSyncService(t, sls, &config.SyncConfig{})
+	for _, name := range []string{"same", "divergent", "db-only"} {
+		if err := alertStore.CreateWithTransaction(ctx, newTestAlert(name)); err != nil {
+			t.Fatalf("CreateWithTransaction %s: %v", name, err)
+		}
+	}
+
+	diff, err := syncSvc.DiffInventory(ctx)
+	if err != nil {
+		t.Fatalf("DiffInventory: %v", err)
+	}
+
+	want := map[string][]string{
+		InventoryActionSLSOnly:   {"sls-a", "sls-b"},
+		InventoryActionDBOnly:    {"db-only"},
+		InventoryActionDivergent: {"divergent"},
+	}
+	for action, names := range want {
+		got := inventoryNames(diff.Items(action))
+		if len(got) != len(names) {
+			t.Errorf("%s = %v, want %v", action, got, names)
+			continue
+		}
+		for i := range names {
+			if got[i] != names[i] {
+				t.Errorf("%s = %v, want %v", action, got, names)
+				break
+			}
+		}
+	}
+	if differences := diff.Divergent[0].Differences; len(differences) == 0 {
+		t.Errorf("divergent differences = %v, want the threshold difference", differences)
+	}
+	if items := diff.Items("identical"); items != nil {
+		t.Errorf("Items(identical) = %v, want nil for an unknown action", items)
+	}
+}
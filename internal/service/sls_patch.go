@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/Ghostbaby/sls-migrate/internal/mapper"
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	sls20201230 "github.com/alibabacloud-go/sls-20201230/v6/client"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// 推送到 SLS 时可单独比较的字段
+const (
+	AlertFieldDisplayName   = "display_name"
+	AlertFieldDescription   = "description"
+	AlertFieldStatus        = "status"
+	AlertFieldConfiguration = "configuration"
+	AlertFieldSchedule      = "schedule"
+)
+
+// diffSLSAlerts 比较两个 SLS 模型，返回发生变化的字段
+func diffSLSAlerts(existing, desired *sls20201230.Alert) []string {
+	var changed []string
+	if tea.StringValue(existing.DisplayName) != tea.StringValue(desired.DisplayName) {
+		changed = append(changed, AlertFieldDisplayName)
+	}
+	if tea.StringValue(existing.Description) != tea.StringValue(desired.Description) {
+		changed = append(changed, AlertFieldDescription)
+	}
+	if tea.StringValue(existing.Status) != tea.StringValue(desired.Status) {
+		changed = append(changed, AlertFieldStatus)
+	}
+	if !jsonEqual(existing.Configuration, desired.Configuration) {
+		changed = append(changed, AlertFieldConfiguration)
+	}
+	if !jsonEqual(existing.Schedule, desired.Schedule) {
+		changed = append(changed, AlertFieldSchedule)
+	}
+	return changed
+}
+
+// containsString 判断字符串列表是否包含指定值
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonEqual 按 JSON 序列化结果比较两个值，忽略指针地址差异
+func jsonEqual(a, b interface{}) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+
+	var aValue, bValue interface{}
+	if json.Unmarshal(aJSON, &aValue) != nil || json.Unmarshal(bJSON, &bValue) != nil {
+		return false
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+// PatchAlert 只把与 SLS 现有规则不同的部分推送到 SLS，返回实际推送的字段。
+// SLS 的 UpdateAlert 需要完整的 configuration 和 schedule，因此未变化的部分使用 SLS 当前的值（fetch-merge-push），
+// 状态变化通过 EnableAlert/DisableAlert 单独推送
+func (s *slsService) PatchAlert(ctx context.Context, alert, existing *models.Alert) ([]string, []Warning, error) {
+	desired, warnings, err := s.convertForPush(alert)
+	if err != nil {
+		return nil, nil, err
+	}
+	current, _, err := mapper.ModelToSLS(existing)
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	changed := diffSLSAlerts(current, desired)
+	if len(changed) == 0 {
+		return nil, warnings, nil
+	}
+
+	request := &sls20201230.UpdateAlertRequest{
+		DisplayName:   current.DisplayName,
+		Description:   current.Description,
+		Configuration: current.Configuration,
+		Schedule:      current.Schedule,
+	}
+	needsUpdate := false
+	for _, field := range changed {
+		switch field {
+		case AlertFieldDisplayName:
+			request.DisplayName = desired.DisplayName
+			needsUpdate = true
+		case AlertFieldDescription:
+			request.Description = desired.Description
+			needsUpdate = true
+		case AlertFieldConfiguration:
+			request.Configuration = desired.Configuration
+			needsUpdate = true
+		case AlertFieldSchedule:
+			request.Schedule = desired.Schedule
+			needsUpdate = true
+		}
+	}
+
+	if needsUpdate {
+		// SLS 当前的原始配置最新，没有时使用本地保存的原始配置
+		raw := rawConfigOf(existing)
+		if raw == nil || *raw == "" {
+			raw = rawConfigOf(alert)
+		}
+		configuration, rawWarnings := s.mergeRawConfig(alert.Name, raw, request.Configuration)
+		warnings = append(warnings, rawWarnings...)
+
+		err := callSLS(ctx, s.retry, "UpdateAlert", func() error {
+			if configuration != nil {
+				return s.updateAlertWithBody(ctx, alert.Name, request, configuration)
+			}
+			_, err := s.slsClient.UpdateAlertWithOptions(tea.String(s.project), tea.String(alert.Name), request, make(map[string]*string), s.runtimeOptions(ctx))
+			return err
+		})
+		if err != nil {
+			return nil, warnings, fmt.Errorf("failed to update alert in SLS: %w", err)
+		}
+	}
+
+	if containsString(changed, AlertFieldStatus) {
+		if err := s.SetAlertStatus(ctx, alert.Name, alert.Status); err != nil {
+			return nil, warnings, err
+		}
+	}
+
+	return changed, warnings, nil
+}
+
+// SetAlertStatus 通过 EnableAlert/DisableAlert 只设置 SLS 中 Alert 的状态，配置和调度保持不变
+func (s *slsService) SetAlertStatus(ctx context.Context, name, status string) error {
+	var operation string
+	var call func() error
+	switch status {
+	case "ENABLED":
+		operation = "EnableAlert"
+		call = func() error {
+			_, err := s.slsClient.EnableAlertWithOptions(tea.String(s.project), tea.String(name), make(map[string]*string), s.runtimeOptions(ctx))
+			return err
+		}
+	case "DISABLED":
+		operation = "DisableAlert"
+		call = func() error {
+			_, err := s.slsClient.DisableAlertWithOptions(tea.String(s.project), tea.String(name), make(map[string]*string), s.runtimeOptions(ctx))
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported alert status '%s'", status)
+	}
+	err := callSLS(ctx, s.retry, operation, call)
+	if err != nil {
+		return fmt.Errorf("failed to set alert status in SLS: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/mapper"
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	sls20201230 "github.com/alibabacloud-go/sls-20201230/v6/client"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+func TestPatchAlertSendsOnlyChangedFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(alert *models.Alert)
+		wantFields  []string
+		wantAction  string // 状态变化时的 EnableAlert/DisableAlert 请求，为空表示不发送
+		wantUpdate  bool
+		description string // UpdateAlert 请求中的 description
+		threshold   float64
+	}{
+		{
+			name:   "unchanged",
+			modify: func(alert *models.Alert) {},
+		},
+		{
+			name:       "status only",
+			modify:     func(alert *models.Alert) { alert.Status = AlertStatusDisabled },
+			wantFields: []string{AlertFieldStatus},
+			wantAction: "disable",
+		},
+		{
+			name:        "description only",
+			modify:      func(alert *models.Alert) { alert.Description = tea.String("new description") },
+			wantFields:  []string{AlertFieldDescription},
+			wantUpdate:  true,
+			description: "new description",
+			threshold:   1,
+		},
+		{
+			name: "status and description",
+			modify: func(alert *models.Alert) {
+				alert.Status = AlertStatusDisabled
+				alert.Description = tea.String("new description")
+			},
+			wantFields:  []string{AlertFieldDescription, AlertFieldStatus},
+			wantAction:  "disable",
+			wantUpdate:  true,
+			description: "new description",
+			threshold:   1,
+		},
+		{
+			name:       "configuration",
+			modify:     func(alert *models.Alert) { alert.Configuration.Threshold = tea.Int32(5) },
+			wantFields: []string{AlertFieldConfiguration},
+			wantUpdate: true,
+			threshold:  5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &slsStub{handler: func(req stubRequest) (int, interface{}) {
+				return http.StatusOK, nil
+			}}
+			svc := newStubSLSService(t, stub)
+
+			existing := newTestAlert("patched")
+			alert := newTestAlert("patched")
+			tt.modify(alert)
+
+			fields, _, err := svc.PatchAlert(context.Background(), alert, existing)
+			if err != nil {
+				t.Fatalf("PatchAlert: %v", err)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", fields, tt.wantFields)
+			}
+
+			var update *stubRequest
+			action := ""
+			calls := stub.calls()
+			for i, call := range calls {
+				if call.Method != http.MethodPut || call.Path != "/alerts/patched" {
+					t.Errorf("unexpected request %s %s", call.Method, call.Path)
+					continue
+				}
+				if call.Query["action"] != "" {
+					action = call.Query["action"]
+				} else {
+					update = &calls[i]
+				}
+			}
+
+			if action != tt.wantAction {
+				t.Errorf("status action = %q, want %q", action, tt.wantAction)
+			}
+			if !tt.wantUpdate {
+				if update != nil {
+					t.Errorf("UpdateAlert was sent: %v", update.Body)
+				}
+				return
+			}
+			if update == nil {
+				t.Fatalf("UpdateAlert was not sent")
+			}
+			if got, _ := update.Body["description"].(string); got != tt.description {
+				t.Errorf("description = %q, want %q", got, tt.description)
+			}
+			// 未变化的部分沿用 SLS 当前的值
+			if update.Body["displayName"] != "Alert patched" {
+				t.Errorf("displayName = %v, want the current value", update.Body["displayName"])
+			}
+			configuration, _ := update.Body["configuration"].(map[string]interface{})
+			if configuration["threshold"] != tt.threshold {
+				t.Errorf("threshold = %v, want %v", configuration["threshold"], tt.threshold)
+			}
+			if schedule, _ := update.Body["schedule"].(map[string]interface{}); schedule["interval"] != "1m" {
+				t.Errorf("schedule = %v, want the current schedule", update.Body["schedule"])
+			}
+		})
+	}
+}
+
+func TestSyncDatabaseToSLSRecordsSentFields(t *testing.T) {
+	ctx := context.Background()
+	var slsAlert *sls20201230.Alert
+	stub := &slsStub{handler: func(req stubRequest) (int, interface{}) {
+		if req.Method == http.MethodGet {
+			return http.StatusOK, listAlertsBody(slsAlert)
+		}
+		return http.StatusOK, nil
+	}}
+	syncSvc, alertStore := newTestSyncService(t, newStubSLSService(t, stub), nil)
+
+	if err := syncSvc.alertService.CreateAlert(ctx, newTestAlert("patched")); err != nil {
+		t.Fatalf("CreateAlert: %v", err)
+	}
+	local, err := alertStore.GetByName(ctx, "patched")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	// SLS 中是之前从数据库推送的版本，之后只在本地修改了描述
+	if slsAlert, _, err = mapper.ModelToSLS(local); err != nil {
+		t.Fatalf("ModelToSLS: %v", err)
+	}
+	local.Description = tea.String("only the description changed")
+	if err := syncSvc.alertService.UpdateAlert(ctx, local); err != nil {
+		t.Fatalf("UpdateAlert: %v", err)
+	}
+
+	result, err := syncSvc.SyncDatabaseToSLS(ctx)
+	if err != nil {
+		t.Fatalf("SyncDatabaseToSLS: %v", err)
+	}
+	if result.Updated != 1 || len(result.Alerts) != 1 {
+		t.Fatalf("result = %+v, want one update", result)
+	}
+	if got := result.Alerts[0].Fields; !reflect.DeepEqual(got, []string{AlertFieldDescription}) {
+		t.Errorf("recorded fields = %v, want [description]", got)
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/Ghostbaby/sls-migrate/internal/mapper"
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	openapi "github.com/alibabacloud-go/darabonba-openapi/v2/client"
+	sls20201230 "github.com/alibabacloud-go/sls-20201230/v6/client"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// alertAPIParams 与 SDK 相同的 Alert 接口调用参数
+func alertAPIParams(action, method, pathname, bodyType string) *openapi.Params {
+	return &openapi.Params{
+		Action:      tea.String(action),
+		Version:     tea.String("2020-12-30"),
+		Protocol:    tea.String("HTTPS"),
+		Pathname:    tea.String(pathname),
+		Method:      tea.String(method),
+		AuthType:    tea.String("AK"),
+		Style:       tea.String("ROA"),
+		ReqBodyType: tea.String("json"),
+		BodyType:    tea.String(bodyType),
+	}
+}
+
+// listAlerts 调用 ListAlerts 获取一页 Alert。开启 PreserveRawConfig 时直接调用接口，
+// 同时返回每条结果中 SLS 返回的原始 configuration JSON（与 Results 一一对应），否则 raws 为 nil
+func (s *slsService) listAlerts(ctx context.Context, request *sls20201230.ListAlertsRequest) (*sls20201230.ListAlertsResponse, []*string, error) {
+	if !s.config.PreserveRawConfig {
+		response, err := s.slsClient.ListAlertsWithOptions(tea.String(s.project), request, make(map[string]*string), s.runtimeOptions(ctx))
+		return response, nil, err
+	}
+
+	query := make(map[string]*string)
+	if request.Logstore != nil {
+		query["logstore"] = request.Logstore
+	}
+	if request.Offset != nil {
+		query["offset"] = tea.String(strconv.Itoa(int(*request.Offset)))
+	}
+	if request.Size != nil {
+		query["size"] = tea.String(strconv.Itoa(int(*request.Size)))
+	}
+
+	result, err := s.slsClient.Execute(alertAPIParams("ListAlerts", "GET", "/alerts", "json"), &openapi.OpenApiRequest{
+		HostMap: map[string]*string{"project": tea.String(s.project)},
+		Headers: make(map[string]*string),
+		Query:   query,
+	}, s.runtimeOptions(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := &sls20201230.ListAlertsResponse{}
+	if err := tea.Convert(result, &response); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse ListAlerts response: %w", err)
+	}
+	return response, rawConfigurations(result["body"]), nil
+}
+
+// rawConfigurations 从 ListAlerts 的原始响应体中取出每条结果的 configuration，序列化为 JSON；
+// 结果没有 configuration 时对应位置为 nil
+func rawConfigurations(body interface{}) []*string {
+	bodyMap, _ := body.(map[string]interface{})
+	results, _ := bodyMap["results"].([]interface{})
+
+	raws := make([]*string, len(results))
+	for i, result := range results {
+		resultMap, _ := result.(map[string]interface{})
+		configuration, ok := resultMap["configuration"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		data, err := json.Marshal(configuration)
+		if err != nil {
+			continue
+		}
+		raw := string(data)
+		raws[i] = &raw
+	}
+	return raws
+}
+
+// rawConfigAt 返回第 i 条结果的原始配置，raws 为 nil（未开启 PreserveRawConfig）时返回 nil
+func rawConfigAt(raws []*string, i int) *string {
+	if i < len(raws) {
+		return raws[i]
+	}
+	return nil
+}
+
+// mergeRawConfig 开启 PreserveRawConfig 且 raw 非空时以 raw 为基线合并 configuration，返回请求体中使用的 configuration；
+// 未开启、没有原始配置或原始配置无法解析时返回 nil，调用方改用 SDK 请求，无法解析时同时返回警告
+func (s *slsService) mergeRawConfig(name string, raw *string, configuration *sls20201230.AlertConfiguration) (map[string]interface{}, []Warning) {
+	if !s.config.PreserveRawConfig || raw == nil || *raw == "" || configuration == nil {
+		return nil, nil
+	}
+
+	merged, err := mapper.MergeRawConfiguration(*raw, configuration)
+	if err != nil {
+		return nil, []Warning{{Alert: name, Field: "configuration.raw_config", Message: fmt.Sprintf("ignored: %v", err)}}
+	}
+	return merged, nil
+}
+
+// rawConfigOf 返回 Alert 保存的原始配置，没有配置时返回 nil
+func rawConfigOf(alert *models.Alert) *string {
+	if alert == nil || alert.Configuration == nil {
+		return nil
+	}
+	return alert.Configuration.RawConfig
+}
+
+// updateAlertWithBody 以合并了原始配置的 configuration 调用 UpdateAlert，其余字段取自 request
+func (s *slsService) updateAlertWithBody(ctx context.Context, name string, request *sls20201230.UpdateAlertRequest, configuration map[string]interface{}) error {
+	return s.executeAlertWrite(ctx, "UpdateAlert", "PUT", "/alerts/"+name, map[string]interface{}{
+		"displayName":   request.DisplayName,
+		"description":   request.Description,
+		"configuration": configuration,
+		"schedule":      request.Schedule,
+	})
+}
+
+// executeAlertWrite 以 body 直接调用 CreateAlert/UpdateAlert，请求参数与 SDK 相同，
+// 用于发送 SDK 结构体无法表示的 configuration 字段
+func (s *slsService) executeAlertWrite(ctx context.Context, action, method, pathname string, body map[string]interface{}) error {
+	// 与 SDK 一样先把请求体转换为普通的 map，值为 nil 的字段不发送
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s body: %w", action, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var parsed map[string]interface{}
+	if err := decoder.Decode(&parsed); err != nil {
+		return fmt.Errorf("failed to unmarshal %s body: %w", action, err)
+	}
+	for key, value := range parsed {
+		if value == nil {
+			delete(parsed, key)
+		}
+	}
+
+	_, err = s.slsClient.Execute(alertAPIParams(action, method, pathname, "none"), &openapi.OpenApiRequest{
+		HostMap: map[string]*string{"project": tea.String(s.project)},
+		Headers: make(map[string]*string),
+		Body:    parsed,
+	}, s.runtimeOptions(ctx))
+	return err
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// rawListAlertsBody ListAlerts 的响应体，configuration 中带有 SDK 不认识的顶层字段和嵌套字段
+func rawListAlertsBody() map[string]interface{} {
+	return map[string]interface{}{
+		"results": []interface{}{map[string]interface{}{
+			"name":        "raw",
+			"displayName": "Raw",
+			"status":      AlertStatusEnabled,
+			"configuration": map[string]interface{}{
+				"type":                   "default",
+				"version":                "2.0",
+				"threshold":              1,
+				"futureField":            map[string]interface{}{"mode": "strict"},
+				"conditionConfiguration": map[string]interface{}{"condition": "cnt > 1", "futureMode": "any"},
+			},
+			"schedule": map[string]interface{}{"type": "FixedRate", "interval": "1m"},
+		}},
+		"count": 1,
+		"total": 1,
+	}
+}
+
+// pushedConfiguration 返回 stub 收到的第一个 method 请求中的 configuration
+func pushedConfiguration(t *testing.T, stub *slsStub, method string) map[string]interface{} {
+	t.Helper()
+
+	for _, call := range stub.calls() {
+		if call.Method == method {
+			configuration, _ := call.Body["configuration"].(map[string]interface{})
+			return configuration
+		}
+	}
+	t.Fatalf("no %s request sent to SLS", method)
+	return nil
+}
+
+func TestRawConfigRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		preserve bool
+	}{
+		{name: "preserve", preserve: true},
+		{name: "disabled", preserve: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &slsStub{handler: func(req stubRequest) (int, interface{}) {
+				if req.Method == http.MethodGet {
+					return http.StatusOK, rawListAlertsBody()
+				}
+				return http.StatusOK, nil
+			}}
+			svc := newStubSLSService(t, stub)
+			svc.config.PreserveRawConfig = tt.preserve
+			ctx := context.Background()
+
+			alerts, err := svc.GetAlerts(ctx)
+			if err != nil {
+				t.Fatalf("GetAlerts: %v", err)
+			}
+			if len(alerts) != 1 {
+				t.Fatalf("alerts = %d, want 1", len(alerts))
+			}
+			existing := alerts[0]
+			raw := existing.Configuration.RawConfig
+			if tt.preserve {
+				if raw == nil || !strings.Contains(*raw, `"futureField"`) || !strings.Contains(*raw, `"futureMode"`) {
+					t.Fatalf("raw_config = %v, want the SLS configuration with unknown fields", tea.StringValue(raw))
+				}
+			} else if raw != nil {
+				t.Fatalf("raw_config = %s, want nil when disabled", *raw)
+			}
+
+			// 本地修改模型表示的字段
+			alert := cloneAlert(t, existing)
+			alert.Configuration.Threshold = tea.Int32(5)
+			alert.Configuration.ConditionConfig.Condition = tea.String("cnt > 5")
+
+			if _, err := svc.UpdateAlert(ctx, alert); err != nil {
+				t.Fatalf("UpdateAlert: %v", err)
+			}
+			if _, err := svc.CreateAlert(ctx, alert); err != nil {
+				t.Fatalf("CreateAlert: %v", err)
+			}
+
+			for _, method := range []string{http.MethodPut, http.MethodPost} {
+				configuration := pushedConfiguration(t, stub, method)
+				if got := configuration["threshold"]; got != 5.0 {
+					t.Errorf("%s threshold = %v, want the model value 5", method, got)
+				}
+				condition, _ := configuration["conditionConfiguration"].(map[string]interface{})
+				if got := condition["condition"]; got != "cnt > 5" {
+					t.Errorf("%s condition = %v, want the model value", method, got)
+				}
+
+				_, hasFuture := configuration["futureField"]
+				_, hasNested := condition["futureMode"]
+				if hasFuture != tt.preserve || hasNested != tt.preserve {
+					t.Errorf("%s futureField sent = %v, futureMode sent = %v, want %v", method, hasFuture, hasNested, tt.preserve)
+				}
+			}
+		})
+	}
+}
+
+func TestPatchAlertKeepsRawConfig(t *testing.T) {
+	stub := &slsStub{handler: func(req stubRequest) (int, interface{}) {
+		if req.Method == http.MethodGet {
+			return http.StatusOK, rawListAlertsBody()
+		}
+		return http.StatusOK, nil
+	}}
+	svc := newStubSLSService(t, stub)
+	svc.config.PreserveRawConfig = true
+	ctx := context.Background()
+
+	alerts, err := svc.GetAlerts(ctx)
+	if err != nil {
+		t.Fatalf("GetAlerts: %v", err)
+	}
+	existing := alerts[0]
+
+	// 只修改描述，configuration 取自 SLS 当前的值，仍要保留 SDK 不认识的字段
+	alert := cloneAlert(t, existing)
+	alert.Description = tea.String("patched")
+
+	changed, _, err := svc.PatchAlert(ctx, alert, existing)
+	if err != nil {
+		t.Fatalf("PatchAlert: %v", err)
+	}
+	if len(changed) != 1 || changed[0] != AlertFieldDescription {
+		t.Fatalf("changed = %v, want only %s", changed, AlertFieldDescription)
+	}
+
+	configuration := pushedConfiguration(t, stub, http.MethodPut)
+	if _, ok := configuration["futureField"]; !ok {
+		t.Errorf("configuration = %v, want futureField kept from SLS", configuration)
+	}
+}
+
+func TestUpdateAlertInvalidRawConfig(t *testing.T) {
+	stub := &slsStub{handler: func(req stubRequest) (int, interface{}) {
+		return http.StatusOK, nil
+	}}
+	svc := newStubSLSService(t, stub)
+	svc.config.PreserveRawConfig = true
+
+	alert := newTestAlert("raw")
+	alert.Configuration.RawConfig = tea.String("not json")
+
+	warnings, err := svc.UpdateAlert(context.Background(), alert)
+	if err != nil {
+		t.Fatalf("UpdateAlert: %v", err)
+	}
+	found := false
+	for _, w := range warnings {
+		if w.Alert == "raw" && w.Field == "configuration.raw_config" && strings.Contains(w.Message, "ignored:") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("warnings = %+v, want configuration.raw_config ignored", warnings)
+	}
+
+	// 原始配置无法解析时仍按模型推送
+	if configuration := pushedConfiguration(t, stub, http.MethodPut); configuration == nil {
+		t.Error("configuration not sent, want the modeled configuration")
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+// ReconcileDrift 两侧都存在但需要同步的 Alert，附带变化的字段
+type ReconcileDrift struct {
+	Name    string        `json:"name"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// ReconcileReport 数据库与 SLS 的对账结果，各列表按名称排序
+type ReconcileReport struct {
+	OnlyInSLS []string         `json:"only_in_sls"`
+	OnlyInDB  []string         `json:"only_in_db"`
+	InSync    []string         `json:"in_sync"`
+	Drifted   []ReconcileDrift `json:"drifted"`
+}
+
+// Reconcile 按名称对账数据库与 SLS 中的全部 Alert，不做任何写入。
+// 两侧都存在的 Alert 使用与实际同步相同的 needsUpdate 判断是否漂移，漂移条目列出变化的字段
+func (s *syncService) Reconcile(ctx context.Context) (*ReconcileReport, error) {
+	slsAlerts, err := s.slsService.GetAlerts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts from SLS: %w", err)
+	}
+	slsByName := make(map[string]*models.Alert, len(slsAlerts))
+	for _, alert := range slsAlerts {
+		slsByName[alert.Name] = alert
+	}
+
+	report := &ReconcileReport{
+		OnlyInSLS: []string{},
+		OnlyInDB:  []string{},
+		InSync:    []string{},
+		Drifted:   []ReconcileDrift{},
+	}
+
+	seen := make(map[string]bool)
+	var afterID uint
+	for {
+		dbAlerts, err := s.alertStore.ListAfterID(ctx, afterID, "", inventoryDBPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get alerts from database: %w", err)
+		}
+		for _, dbAlert := range dbAlerts {
+			seen[dbAlert.Name] = true
+			slsAlert, ok := slsByName[dbAlert.Name]
+			if !ok {
+				report.OnlyInDB = append(report.OnlyInDB, dbAlert.Name)
+				continue
+			}
+
+			if !s.needsUpdate(dbAlert, slsAlert) {
+				report.InSync = append(report.InSync, dbAlert.Name)
+				continue
+			}
+			changes, err := DiffAlerts(dbAlert, slsAlert)
+			if err != nil {
+				return nil, fmt.Errorf("failed to diff alert %s: %w", dbAlert.Name, err)
+			}
+			if changes == nil {
+				changes = []FieldChange{}
+			}
+			report.Drifted = append(report.Drifted, ReconcileDrift{Name: dbAlert.Name, Changes: changes})
+		}
+		if len(dbAlerts) < inventoryDBPageSize {
+			break
+		}
+		afterID = dbAlerts[len(dbAlerts)-1].ID
+	}
+
+	for name := range slsByName {
+		if !seen[name] {
+			report.OnlyInSLS = append(report.OnlyInSLS, name)
+		}
+	}
+
+	sort.Strings(report.OnlyInSLS)
+	sort.Strings(report.OnlyInDB)
+	sort.Strings(report.InSync)
+	sort.Slice(report.Drifted, func(i, j int) bool {
+		return report.Drifted[i].Name < report.Drifted[j].Name
+	})
+
+	return report, nil
+}
@@ -2,12 +2,15 @@ package service
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
+	"log/slog"
+	"time"
 
 	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/Ghostbaby/sls-migrate/internal/mapper"
 	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/pkg/metrics"
 	sls20201230 "github.com/alibabacloud-go/sls-20201230/v6/client"
 	"github.com/alibabacloud-go/tea-utils/v2/service"
 	"github.com/alibabacloud-go/tea/tea"
@@ -16,10 +19,37 @@ import (
 // SLSService SLS 服务接口
 type SLSService interface {
 	GetAlerts(ctx context.Context) ([]*models.Alert, error)
+	ListAlertsPage(ctx context.Context, query SLSAlertPageQuery) (*SLSAlertPage, error)
 	GetAlertByName(ctx context.Context, name string) (*models.Alert, error)
-	CreateAlert(ctx context.Context, alert *models.Alert) error
-	UpdateAlert(ctx context.Context, alert *models.Alert) error
+	GetAlertsByNames(ctx context.Context, names []string) (map[string]*models.Alert, error)
+	CreateAlert(ctx context.Context, alert *models.Alert) ([]Warning, error)
+	CreateOrUpdateAlert(ctx context.Context, alert *models.Alert) (bool, []Warning, error)
+	UpdateAlert(ctx context.Context, alert *models.Alert) ([]Warning, error)
+	DeleteAlert(ctx context.Context, project, name string) error
+	PatchAlert(ctx context.Context, alert, existing *models.Alert) ([]string, []Warning, error)
+	SetAlertStatus(ctx context.Context, name, status string) error
+	ValidateAlert(ctx context.Context, alert *models.Alert) ([]Warning, error)
 	SyncAlertsToDatabase(ctx context.Context) error
+	Ping(ctx context.Context) error
+	Project() string
+	WithTarget(target SLSTarget) (SLSService, error)
+}
+
+// SLSAlertPageQuery SLS 原生分页查询参数，直接透传给 ListAlerts
+type SLSAlertPageQuery struct {
+	Offset   int
+	Size     int
+	Logstore string // SLS 服务端按日志库过滤，ListAlerts 不支持按名称过滤
+}
+
+// SLSAlertPage SLS 返回的一页 Alert 及其报告的总数
+type SLSAlertPage struct {
+	Alerts []*models.Alert `json:"data"`
+	Count  int             `json:"count"`
+	Total  int             `json:"total"`
+	Offset int             `json:"offset"`
+	Size   int             `json:"size"`
+	Limit  int             `json:"limit"` // 与 size 相同，对应请求中的 limit 参数
 }
 
 // slsService SLS 服务实现
@@ -27,10 +57,19 @@ type slsService struct {
 	slsClient *sls20201230.Client
 	project   string
 	logStore  string
+	region    string
+	retry     slsRetryPolicy
+	logger    *slog.Logger
+	// config 启动时的 SLS 配置，切换 Endpoint 时使用其中的凭据创建新客户端
+	config config.SLSConfig
 }
 
-// NewSLSService 创建新的 SLSService 实例
-func NewSLSService(slsConfig *config.SLSConfig) (SLSService, error) {
+// NewSLSService 创建新的 SLSService 实例，logger 为 nil 时使用 slog 默认日志器
+func NewSLSService(slsConfig *config.SLSConfig, logger *slog.Logger) (SLSService, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
 	client, err := config.CreateSLSClient(slsConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SLS client: %w", err)
@@ -46,28 +85,162 @@ func NewSLSService(slsConfig *config.SLSConfig) (SLSService, error) {
 		slsClient: slsClient,
 		project:   slsConfig.Project,
 		logStore:  slsConfig.LogStore,
+		region:    slsConfig.Region(),
+		retry:     newSLSRetryPolicy(slsConfig.MaxRetries, time.Duration(slsConfig.RetryBackoffMS)*time.Millisecond),
+		logger:    logger,
+		config:    *slsConfig,
 	}, nil
 }
 
-// GetAlerts 从阿里云 SLS 获取所有 Alert 规则
-func (s *slsService) GetAlerts(ctx context.Context) ([]*models.Alert, error) {
-	request := &sls20201230.ListAlertsRequest{}
+// Project 返回当前同步的 SLS Project
+func (s *slsService) Project() string {
+	return s.project
+}
+
+// runtimeOptions 按配置的读超时和连接超时生成 SDK 调用参数，未配置的超时使用 SDK 默认值。
+// ctx 带截止时间时两个超时都不超过剩余时间，使 SDK 请求本身在 ctx 超时后结束，
+// 而不是在后台一直运行到配置的超时；每次尝试前调用，重试和翻页都按当时的剩余时间计算
+func (s *slsService) runtimeOptions(ctx context.Context) *service.RuntimeOptions {
+	readTimeout, connectTimeout := s.config.ReadTimeoutMS, s.config.ConnectTimeoutMS
+	if deadline, ok := ctx.Deadline(); ok {
+		// 超时为 0 时 SDK 会使用默认值，剩余时间不足 1ms 时按 1ms 处理
+		remaining := int(time.Until(deadline).Milliseconds())
+		if remaining < 1 {
+			remaining = 1
+		}
+		if readTimeout <= 0 || remaining < readTimeout {
+			readTimeout = remaining
+		}
+		if connectTimeout <= 0 || remaining < connectTimeout {
+			connectTimeout = remaining
+		}
+	}
+
 	runtime := &service.RuntimeOptions{}
+	if readTimeout > 0 {
+		runtime.SetReadTimeout(readTimeout)
+	}
+	if connectTimeout > 0 {
+		runtime.SetConnectTimeout(connectTimeout)
+	}
+	return runtime
+}
+
+// SLSListAlertsMaxSize SLS ListAlerts 单页最多返回的条数
+const SLSListAlertsMaxSize = 200
+
+// GetAlerts 从阿里云 SLS 获取所有 Alert 规则，按 offset/size 逐页读取直到取完
+func (s *slsService) GetAlerts(ctx context.Context) ([]*models.Alert, error) {
+
+	var alerts []*models.Alert
+	offset := 0
+	for {
+		request := &sls20201230.ListAlertsRequest{
+			Offset: tea.Int32(int32(offset)),
+			Size:   tea.Int32(SLSListAlertsMaxSize),
+		}
 
-	response, err := s.slsClient.ListAlertsWithOptions(tea.String(s.project), request, make(map[string]*string), runtime)
+		var response *sls20201230.ListAlertsResponse
+		var raws []*string
+		err := callSLS(ctx, s.retry, "ListAlerts", func() (err error) {
+			response, raws, err = s.listAlerts(ctx, request)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list alerts from SLS at offset %d: %w", offset, err)
+		}
+
+		if response.Body == nil || len(response.Body.Results) == 0 {
+			break
+		}
+		for i, slsAlert := range response.Body.Results {
+			alerts = append(alerts, s.convertSLSAlertToModel(slsAlert, rawConfigAt(raws, i)))
+		}
+
+		count := len(response.Body.Results)
+		if response.Body.Count != nil && int(*response.Body.Count) > 0 {
+			count = int(*response.Body.Count)
+		}
+		offset += count
+		// 只有 SLS 返回了 total 时才据此判断是否取完；未返回时以不满一页作为最后一页
+		if response.Body.Total != nil {
+			if offset >= int(*response.Body.Total) {
+				break
+			}
+		} else if len(response.Body.Results) < SLSListAlertsMaxSize {
+			break
+		}
+	}
+
+	return alerts, nil
+}
+
+// ListAlertsPage 使用 SLS 原生的 offset/size 分页获取 Alert，返回 SLS 报告的总数；
+// Size 小于 1 时使用默认值 10，超过 SLSListAlertsMaxSize 时按上限处理
+func (s *slsService) ListAlertsPage(ctx context.Context, query SLSAlertPageQuery) (*SLSAlertPage, error) {
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+	// 未提供时使用默认值 10，超过 SLS 上限时按上限返回，不静默改为默认值
+	if query.Size < 1 {
+		query.Size = 10
+	} else if query.Size > SLSListAlertsMaxSize {
+		query.Size = SLSListAlertsMaxSize
+	}
+
+	request := &sls20201230.ListAlertsRequest{
+		Offset: tea.Int32(int32(query.Offset)),
+		Size:   tea.Int32(int32(query.Size)),
+	}
+	if query.Logstore != "" {
+		request.Logstore = tea.String(query.Logstore)
+	}
+
+	var response *sls20201230.ListAlertsResponse
+	var raws []*string
+	err := callSLS(ctx, s.retry, "ListAlerts", func() (err error) {
+		response, raws, err = s.listAlerts(ctx, request)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list alerts from SLS: %w", err)
 	}
 
-	var alerts []*models.Alert
-	if response.Body != nil && response.Body.Results != nil {
-		for _, slsAlert := range response.Body.Results {
-			alert := s.convertSLSAlertToModel(slsAlert)
-			alerts = append(alerts, alert)
+	page := &SLSAlertPage{
+		Alerts: []*models.Alert{},
+		Offset: query.Offset,
+		Size:   query.Size,
+		Limit:  query.Size,
+	}
+	if response.Body != nil {
+		for i, slsAlert := range response.Body.Results {
+			page.Alerts = append(page.Alerts, s.convertSLSAlertToModel(slsAlert, rawConfigAt(raws, i)))
 		}
+		page.Total = int(tea.Int32Value(response.Body.Total))
 	}
+	page.Count = len(page.Alerts)
 
-	return alerts, nil
+	return page, nil
+}
+
+// Ping 以最小的列表请求检查 SLS 是否可访问
+func (s *slsService) Ping(ctx context.Context) error {
+	request := &sls20201230.ListAlertsRequest{
+		Size: tea.Int32(1),
+	}
+
+	// 探测不重试，限流同样说明 SLS 可访问，由调用方根据分类判断
+	start := time.Now()
+	err := callWithContext(ctx, func() error {
+		_, err := s.slsClient.ListAlertsWithOptions(tea.String(s.project), request, make(map[string]*string), s.runtimeOptions(ctx))
+		return err
+	})
+	metrics.ObserveSLSRequest("ListAlerts", time.Since(start), err)
+	if err != nil {
+		return fmt.Errorf("failed to reach SLS: %w", ClassifySLSError(err))
+	}
+
+	return nil
 }
 
 // GetAlertByName 根据名称从阿里云 SLS 获取特定 Alert 规则
@@ -84,7 +257,34 @@ func (s *slsService) GetAlertByName(ctx context.Context, name string) (*models.A
 		}
 	}
 
-	return nil, fmt.Errorf("alert with name '%s' not found in SLS", name)
+	return nil, fmt.Errorf("alert with name '%s' not found in SLS: %w", name, ErrSLSNotFound)
+}
+
+// GetAlertsByNames 一次性列出 SLS 中的所有 Alert，返回名称在 names 中的部分（以名称为键），
+// 需要查找多个 Alert 时避免逐个调用 GetAlertByName 重复列出；SLS 中不存在的名称不出现在结果中
+func (s *slsService) GetAlertsByNames(ctx context.Context, names []string) (map[string]*models.Alert, error) {
+	result := make(map[string]*models.Alert, len(names))
+	if len(names) == 0 {
+		return result, nil
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	alerts, err := s.GetAlerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, alert := range alerts {
+		if wanted[alert.Name] {
+			result[alert.Name] = alert
+		}
+	}
+
+	return result, nil
 }
 
 // SyncAlertsToDatabase 同步阿里云 SLS 的 Alert 规则到本地数据库
@@ -98,249 +298,87 @@ func (s *slsService) SyncAlertsToDatabase(ctx context.Context) error {
 	// 注意：这个方法现在主要用于获取 SLS 数据
 	// 实际的数据库保存逻辑由 SyncService 处理
 	// 这里返回获取到的数据，供调用方使用
-	fmt.Printf("Found %d alerts in SLS\n", len(slsAlerts))
+	s.logger.InfoContext(ctx, "fetched alerts from SLS", "count", len(slsAlerts))
 	for _, alert := range slsAlerts {
-		fmt.Printf("Alert: %s (%s)\n", alert.Name, alert.DisplayName)
+		s.logger.DebugContext(ctx, "fetched SLS alert", "alert", alert.Name, "display_name", alert.DisplayName)
 	}
 
 	return nil
 }
 
-// convertSLSAlertToModel 将阿里云 SLS 的 Alert 转换为本地模型
-func (s *slsService) convertSLSAlertToModel(slsAlert *sls20201230.Alert) *models.Alert {
-	alert := &models.Alert{
-		Name:             tea.StringValue(slsAlert.Name),
-		DisplayName:      tea.StringValue(slsAlert.DisplayName),
-		Description:      slsAlert.Description,
-		Status:           tea.StringValue(slsAlert.Status),
-		CreateTime:       slsAlert.CreateTime,
-		LastModifiedTime: slsAlert.LastModifiedTime,
-	}
-
-	// 调试输出
-	fmt.Printf("DEBUG: Converting SLS alert %s\n", tea.StringValue(slsAlert.Name))
-	fmt.Printf("DEBUG: slsAlert.Configuration is nil: %v\n", slsAlert.Configuration == nil)
-
-	// 转换 Configuration
-	if slsAlert.Configuration != nil {
-		alert.Configuration = &models.AlertConfiguration{
-			AutoAnnotation: slsAlert.Configuration.AutoAnnotation,
-			Dashboard:      slsAlert.Configuration.Dashboard,
-			MuteUntil:      slsAlert.Configuration.MuteUntil,
-			NoDataFire:     slsAlert.Configuration.NoDataFire,
-			NoDataSeverity: slsAlert.Configuration.NoDataSeverity,
-			Threshold:      slsAlert.Configuration.Threshold,
-			Type:           slsAlert.Configuration.Type,
-			Version:        slsAlert.Configuration.Version,
-			SendResolved:   slsAlert.Configuration.SendResolved,
-		}
-
-		// 转换 ConditionConfiguration
-		if slsAlert.Configuration.ConditionConfiguration.Condition != nil ||
-			slsAlert.Configuration.ConditionConfiguration.CountCondition != nil {
-			conditionConfig := &models.ConditionConfiguration{
-				Condition:      slsAlert.Configuration.ConditionConfiguration.Condition,
-				CountCondition: slsAlert.Configuration.ConditionConfiguration.CountCondition,
-			}
-			alert.Configuration.ConditionConfig = conditionConfig
-		}
-
-		// 转换 GroupConfiguration
-		if slsAlert.Configuration.GroupConfiguration != nil {
-			// 将 Fields 数组转换为字符串
-			var fieldsStr *string
-			if len(slsAlert.Configuration.GroupConfiguration.Fields) > 0 {
-				fields := make([]string, 0, len(slsAlert.Configuration.GroupConfiguration.Fields))
-				for _, field := range slsAlert.Configuration.GroupConfiguration.Fields {
-					if field != nil {
-						fields = append(fields, *field)
-					}
-				}
-				if len(fields) > 0 {
-					fieldsStr = tea.String(strings.Join(fields, ","))
-				}
-			}
-
-			groupConfig := &models.GroupConfiguration{
-				Fields: fieldsStr,
-				Type:   slsAlert.Configuration.GroupConfiguration.Type,
-			}
-			alert.Configuration.GroupConfig = groupConfig
-		}
-
-		// 转换 PolicyConfiguration
-		if slsAlert.Configuration.PolicyConfiguration != nil {
-			policyConfig := &models.PolicyConfiguration{
-				AlertPolicyId:  slsAlert.Configuration.PolicyConfiguration.AlertPolicyId,
-				ActionPolicyId: slsAlert.Configuration.PolicyConfiguration.ActionPolicyId,
-				RepeatInterval: slsAlert.Configuration.PolicyConfiguration.RepeatInterval,
-			}
-			alert.Configuration.PolicyConfig = policyConfig
-		}
-
-		// 转换 TemplateConfiguration
-		if slsAlert.Configuration.TemplateConfiguration != nil {
-			// 处理 Aonotations 和 Tokens 的 JSON 转换
-			var aonotationsJSON, tokensJSON *string
-
-			if slsAlert.Configuration.TemplateConfiguration.Aonotations != nil {
-				if aonotationsBytes, err := json.Marshal(slsAlert.Configuration.TemplateConfiguration.Aonotations); err == nil {
-					aonotationsJSON = tea.String(string(aonotationsBytes))
-				}
-			}
-
-			if slsAlert.Configuration.TemplateConfiguration.Tokens != nil {
-				if tokensBytes, err := json.Marshal(slsAlert.Configuration.TemplateConfiguration.Tokens); err == nil {
-					tokensJSON = tea.String(string(tokensBytes))
-				}
-			}
-
-			templateConfig := &models.TemplateConfiguration{
-				TemplateId:  slsAlert.Configuration.TemplateConfiguration.Id,
-				Lang:        slsAlert.Configuration.TemplateConfiguration.Lang,
-				Type:        slsAlert.Configuration.TemplateConfiguration.Type,
-				Version:     slsAlert.Configuration.TemplateConfiguration.Version,
-				Aonotations: aonotationsJSON,
-				Tokens:      tokensJSON,
-			}
-			alert.Configuration.TemplateConfig = templateConfig
-		}
-
-		// 转换 SeverityConfigurations
-		if slsAlert.Configuration.SeverityConfigurations != nil {
-			for _, slsSeverity := range slsAlert.Configuration.SeverityConfigurations {
-				severityConfig := &models.SeverityConfiguration{
-					Severity: slsSeverity.Severity,
-				}
-
-				// 处理 EvalCondition
-				if slsSeverity.EvalCondition != nil {
-					evalCondition := &models.ConditionConfiguration{
-						Condition:      slsSeverity.EvalCondition.Condition,
-						CountCondition: slsSeverity.EvalCondition.CountCondition,
-					}
-					severityConfig.EvalCondition = evalCondition
-				}
-
-				alert.Configuration.SeverityConfigs = append(alert.Configuration.SeverityConfigs, *severityConfig)
-			}
-		}
-
-		// 转换 QueryList
-		if slsAlert.Configuration.QueryList != nil {
-			for _, slsQuery := range slsAlert.Configuration.QueryList {
-				query := &models.AlertQuery{
-					ChartTitle:   slsQuery.ChartTitle,
-					DashboardId:  slsQuery.DashboardId,
-					End:          slsQuery.End,
-					PowerSqlMode: slsQuery.PowerSqlMode,
-					Project:      slsQuery.Project,
-					Query:        tea.StringValue(slsQuery.Query),
-					Region:       slsQuery.Region,
-					RoleArn:      slsQuery.RoleArn,
-					Start:        slsQuery.Start,
-					Store:        slsQuery.Store,
-					StoreType:    slsQuery.StoreType,
-					TimeSpanType: slsQuery.TimeSpanType,
-					Ui:           slsQuery.Ui,
-				}
-				alert.Queries = append(alert.Queries, *query)
-			}
-		}
-
-		// 转换 Tags
-		if slsAlert.Configuration.Tags != nil {
-			for _, slsTag := range slsAlert.Configuration.Tags {
-				tag := &models.AlertTag{
-					TagType:  "label", // 默认为 label 类型
-					TagKey:   tea.StringValue(slsTag),
-					TagValue: nil, // SLS 中 Tags 是字符串数组
-				}
-				alert.Tags = append(alert.Tags, *tag)
-			}
+// convertSLSAlertToModel 将阿里云 SLS 的 Alert 转换为本地模型，按配置合并重复标签、保存原始配置并记录内容哈希；
+// rawConfig 为 SLS 返回的原始 configuration JSON，未开启 PreserveRawConfig 时为 nil
+func (s *slsService) convertSLSAlertToModel(slsAlert *sls20201230.Alert, rawConfig *string) *models.Alert {
+	s.logger.Debug("converting SLS alert",
+		"alert", tea.StringValue(slsAlert.Name),
+		"has_configuration", slsAlert.Configuration != nil,
+	)
+
+	alert := mapper.SLSToModel(slsAlert)
+
+	// SLS 中可能存在重复的标签键，合并后避免产生重复的标签行
+	if s.config.DeduplicateTags {
+		var duplicates []string
+		alert.Tags, duplicates = dedupeAlertTags(alert.Tags)
+		if len(duplicates) > 0 {
+			s.logger.Warn("collapsed duplicate SLS tags, keeping the last value",
+				"alert", alert.Name,
+				"keys", duplicates,
+			)
 		}
+	}
 
-		// 转换 Sink 配置
-		if slsAlert.Configuration.SinkAlerthub != nil {
-			sinkAlerthubConfig := &models.SinkAlerthubConfiguration{
-				Enabled: slsAlert.Configuration.SinkAlerthub.Enabled,
-			}
-			alert.Configuration.SinkAlerthubConfig = sinkAlerthubConfig
-		}
+	// 原始配置参与内容哈希，SDK 未表示的字段变化也能发现
+	if alert.Configuration != nil {
+		alert.Configuration.RawConfig = rawConfig
+	}
 
-		if slsAlert.Configuration.SinkCms != nil {
-			sinkCmsConfig := &models.SinkCmsConfiguration{
-				Enabled: slsAlert.Configuration.SinkCms.Enabled,
-			}
-			alert.Configuration.SinkCmsConfig = sinkCmsConfig
-		}
+	// 记录 SLS 侧内容的哈希，同步时与上次同步的哈希比较
+	if hash, err := alertContentHash(alert); err != nil {
+		s.logger.Warn("failed to compute alert content hash", "alert", alert.Name, "error", err)
+	} else {
+		alert.ContentHash = &hash
+	}
 
-		if slsAlert.Configuration.SinkEventStore != nil {
-			sinkEventStoreConfig := &models.SinkEventStoreConfiguration{
-				Enabled:    slsAlert.Configuration.SinkEventStore.Enabled,
-				Endpoint:   slsAlert.Configuration.SinkEventStore.Endpoint,
-				EventStore: slsAlert.Configuration.SinkEventStore.EventStore,
-				Project:    slsAlert.Configuration.SinkEventStore.Project,
-				RoleArn:    slsAlert.Configuration.SinkEventStore.RoleArn,
-			}
-			alert.Configuration.SinkEventStoreConfig = sinkEventStoreConfig
-		}
+	return alert
+}
 
-		// 转换 JoinConfigurations
-		if slsAlert.Configuration.JoinConfigurations != nil {
-			for _, slsJoinConfig := range slsAlert.Configuration.JoinConfigurations {
-				// 将 Condition 和 Type 组合到 JoinConfig 字段中
-				var joinConfigStr *string
-				if slsJoinConfig.Condition != nil || slsJoinConfig.Type != nil {
-					joinData := map[string]interface{}{
-						"condition": slsJoinConfig.Condition,
-						"type":      slsJoinConfig.Type,
-					}
-					if joinBytes, err := json.Marshal(joinData); err == nil {
-						joinConfigStr = tea.String(string(joinBytes))
-					}
-				}
-
-				joinConfig := &models.JoinConfiguration{
-					JoinType:   slsJoinConfig.Type,
-					JoinConfig: joinConfigStr,
-				}
-				alert.Configuration.JoinConfigs = append(alert.Configuration.JoinConfigs, *joinConfig)
-			}
-		}
+// dedupeAlertTags 按 (类型, 键) 合并重复的标签：保留第一次出现的位置和最后一次出现的值，
+// 返回合并后的标签和被合并的 "类型:键" 列表
+func dedupeAlertTags(tags []models.AlertTag) ([]models.AlertTag, []string) {
+	if len(tags) == 0 {
+		return tags, nil
+	}
 
-		// 转换 Annotations
-		if slsAlert.Configuration.Annotations != nil {
-			for _, slsAnnotation := range slsAlert.Configuration.Annotations {
-				annotation := &models.AlertTag{
-					TagType:  "annotation",
-					TagKey:   tea.StringValue(slsAnnotation.Key),
-					TagValue: slsAnnotation.Value,
-				}
-				alert.Tags = append(alert.Tags, *annotation)
-			}
-		}
+	type tagKey struct {
+		tagType string
+		key     string
 	}
 
-	// 转换 Schedule
-	if slsAlert.Schedule != nil {
-		alert.Schedule = &models.AlertSchedule{
-			CronExpression: slsAlert.Schedule.CronExpression,
-			Delay:          slsAlert.Schedule.Delay,
-			Interval:       slsAlert.Schedule.Interval,
-			RunImmediately: slsAlert.Schedule.RunImmediately,
-			TimeZone:       slsAlert.Schedule.TimeZone,
-			Type:           tea.StringValue(slsAlert.Schedule.Type),
+	index := make(map[tagKey]int, len(tags))
+	result := make([]models.AlertTag, 0, len(tags))
+	var duplicates []string
+	for _, tag := range tags {
+		key := tagKey{tag.TagType, tag.TagKey}
+		if i, ok := index[key]; ok {
+			result[i].TagValue = tag.TagValue
+			duplicates = append(duplicates, tag.TagType+":"+tag.TagKey)
+			continue
 		}
+		index[key] = len(result)
+		result = append(result, tag)
 	}
-
-	return alert
+	return result, duplicates
 }
 
-// CreateAlert 在阿里云 SLS 中创建新的 Alert 规则
-func (s *slsService) CreateAlert(ctx context.Context, alert *models.Alert) error {
+// CreateAlert 在阿里云 SLS 中创建新的 Alert 规则，返回转换过程中的有损警告；
+// 同名 Alert 已存在时返回包装了 ErrSLSAlreadyExists 的错误，由调用方决定是否覆盖
+func (s *slsService) CreateAlert(ctx context.Context, alert *models.Alert) ([]Warning, error) {
 	// 将本地模型转换为 SLS SDK 模型
-	slsAlert := s.convertModelToSLSAlert(alert)
+	slsAlert, warnings, err := s.convertForPush(alert)
+	if err != nil {
+		return nil, err
+	}
 
 	// 创建请求
 	request := &sls20201230.CreateAlertRequest{
@@ -351,21 +389,51 @@ func (s *slsService) CreateAlert(ctx context.Context, alert *models.Alert) error
 		Schedule:      slsAlert.Schedule,
 	}
 
-	runtime := &service.RuntimeOptions{}
+	// 以导入时保存的原始配置为基线，保留 SDK 未表示的字段
+	configuration, rawWarnings := s.mergeRawConfig(alert.Name, rawConfigOf(alert), slsAlert.Configuration)
+	warnings = append(warnings, rawWarnings...)
 
 	// 调用 SLS API 创建 Alert
-	_, err := s.slsClient.CreateAlertWithOptions(tea.String(s.project), request, make(map[string]*string), runtime)
+	err = callSLS(ctx, s.retry, "CreateAlert", func() error {
+		if configuration != nil {
+			return s.executeAlertWrite(ctx, "CreateAlert", "POST", "/alerts", map[string]interface{}{
+				"name":          request.Name,
+				"displayName":   request.DisplayName,
+				"description":   request.Description,
+				"configuration": configuration,
+				"schedule":      request.Schedule,
+			})
+		}
+		_, err := s.slsClient.CreateAlertWithOptions(tea.String(s.project), request, make(map[string]*string), s.runtimeOptions(ctx))
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create alert in SLS: %w", err)
+		return warnings, fmt.Errorf("failed to create alert in SLS: %w", err)
 	}
 
-	return nil
+	return warnings, nil
+}
+
+// CreateOrUpdateAlert 创建 Alert，SLS 返回已存在（检查存在与创建之间被并发创建）时改为更新同名 Alert，
+// 使推送保持幂等；返回值 updated 表示实际执行的是更新
+func (s *slsService) CreateOrUpdateAlert(ctx context.Context, alert *models.Alert) (bool, []Warning, error) {
+	warnings, err := s.CreateAlert(ctx, alert)
+	if !errors.Is(err, ErrSLSAlreadyExists) {
+		return false, warnings, err
+	}
+
+	s.logger.InfoContext(ctx, "alert already exists in SLS, updating instead", "alert", alert.Name, "project", s.project)
+	warnings, err = s.UpdateAlert(ctx, alert)
+	return true, warnings, err
 }
 
-// UpdateAlert 在阿里云 SLS 中更新现有的 Alert 规则
-func (s *slsService) UpdateAlert(ctx context.Context, alert *models.Alert) error {
+// UpdateAlert 在阿里云 SLS 中更新现有的 Alert 规则，返回转换过程中的有损警告
+func (s *slsService) UpdateAlert(ctx context.Context, alert *models.Alert) ([]Warning, error) {
 	// 将本地模型转换为 SLS SDK 模型
-	slsAlert := s.convertModelToSLSAlert(alert)
+	slsAlert, warnings, err := s.convertForPush(alert)
+	if err != nil {
+		return nil, err
+	}
 
 	// 创建请求
 	request := &sls20201230.UpdateAlertRequest{
@@ -375,191 +443,68 @@ func (s *slsService) UpdateAlert(ctx context.Context, alert *models.Alert) error
 		Schedule:      slsAlert.Schedule,
 	}
 
-	runtime := &service.RuntimeOptions{}
+	// 以导入时保存的原始配置为基线，保留 SDK 未表示的字段
+	configuration, rawWarnings := s.mergeRawConfig(alert.Name, rawConfigOf(alert), slsAlert.Configuration)
+	warnings = append(warnings, rawWarnings...)
 
 	// 调用 SLS API 更新 Alert
-	_, err := s.slsClient.UpdateAlertWithOptions(tea.String(s.project), tea.String(alert.Name), request, make(map[string]*string), runtime)
+	err = callSLS(ctx, s.retry, "UpdateAlert", func() error {
+		if configuration != nil {
+			return s.updateAlertWithBody(ctx, alert.Name, request, configuration)
+		}
+		_, err := s.slsClient.UpdateAlertWithOptions(tea.String(s.project), tea.String(alert.Name), request, make(map[string]*string), s.runtimeOptions(ctx))
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to update alert in SLS: %w", err)
+		return warnings, fmt.Errorf("failed to update alert in SLS: %w", err)
 	}
 
-	return nil
+	return warnings, nil
 }
 
-// convertModelToSLSAlert 将本地模型转换为 SLS SDK 模型
-func (s *slsService) convertModelToSLSAlert(alert *models.Alert) *sls20201230.Alert {
-	slsAlert := &sls20201230.Alert{
-		Name:             tea.String(alert.Name),
-		DisplayName:      tea.String(alert.DisplayName),
-		Description:      alert.Description,
-		Status:           tea.String(alert.Status),
-		CreateTime:       alert.CreateTime,
-		LastModifiedTime: alert.LastModifiedTime,
+// DeleteAlert 删除 SLS 中的 Alert，project 为空时使用当前 Project；
+// Alert 在 SLS 中不存在时返回包装了 ErrSLSNotFound 的错误，由调用方决定是否忽略
+func (s *slsService) DeleteAlert(ctx context.Context, project, name string) error {
+	if project == "" {
+		project = s.project
 	}
 
-	// 转换 Configuration
-	if alert.Configuration != nil {
-		slsConfig := &sls20201230.AlertConfiguration{
-			AutoAnnotation: alert.Configuration.AutoAnnotation,
-			Dashboard:      alert.Configuration.Dashboard,
-			MuteUntil:      alert.Configuration.MuteUntil,
-			NoDataFire:     alert.Configuration.NoDataFire,
-			NoDataSeverity: alert.Configuration.NoDataSeverity,
-			Threshold:      alert.Configuration.Threshold,
-			Type:           alert.Configuration.Type,
-			Version:        alert.Configuration.Version,
-			SendResolved:   alert.Configuration.SendResolved,
-		}
-
-		// 转换 ConditionConfiguration
-		if alert.Configuration.ConditionConfig != nil {
-			slsConfig.ConditionConfiguration = &sls20201230.ConditionConfiguration{
-				Condition:      alert.Configuration.ConditionConfig.Condition,
-				CountCondition: alert.Configuration.ConditionConfig.CountCondition,
-			}
-		}
-
-		// 转换 GroupConfiguration
-		if alert.Configuration.GroupConfig != nil {
-			var fields []*string
-			if alert.Configuration.GroupConfig.Fields != nil {
-				fieldList := strings.Split(*alert.Configuration.GroupConfig.Fields, ",")
-				for _, field := range fieldList {
-					fields = append(fields, tea.String(strings.TrimSpace(field)))
-				}
-			}
-
-			slsConfig.GroupConfiguration = &sls20201230.GroupConfiguration{
-				Fields: fields,
-				Type:   alert.Configuration.GroupConfig.Type,
-			}
-		}
-
-		// 转换 PolicyConfiguration
-		if alert.Configuration.PolicyConfig != nil {
-			slsConfig.PolicyConfiguration = &sls20201230.PolicyConfiguration{
-				ActionPolicyId: alert.Configuration.PolicyConfig.ActionPolicyId,
-				AlertPolicyId:  alert.Configuration.PolicyConfig.AlertPolicyId,
-				RepeatInterval: alert.Configuration.PolicyConfig.RepeatInterval,
-			}
-		}
-
-		// 转换 TemplateConfiguration
-		if alert.Configuration.TemplateConfig != nil {
-			var aonotations map[string]interface{}
-			var tokens map[string]interface{}
-
-			if alert.Configuration.TemplateConfig.Aonotations != nil {
-				if err := json.Unmarshal([]byte(*alert.Configuration.TemplateConfig.Aonotations), &aonotations); err != nil {
-					// 如果解析失败，使用空 map
-					aonotations = make(map[string]interface{})
-				}
-			}
-
-			if alert.Configuration.TemplateConfig.Tokens != nil {
-				if err := json.Unmarshal([]byte(*alert.Configuration.TemplateConfig.Tokens), &tokens); err != nil {
-					// 如果解析失败，使用空 map
-					tokens = make(map[string]interface{})
-				}
-			}
-
-			slsConfig.TemplateConfiguration = &sls20201230.TemplateConfiguration{
-				Id:          alert.Configuration.TemplateConfig.TemplateId,
-				Lang:        alert.Configuration.TemplateConfig.Lang,
-				Type:        alert.Configuration.TemplateConfig.Type,
-				Version:     alert.Configuration.TemplateConfig.Version,
-				Aonotations: aonotations,
-				Tokens:      tokens,
-			}
-		}
-
-		// 转换 QueryList
-		if len(alert.Queries) > 0 {
-			var slsQueries []*sls20201230.AlertQuery
-			for _, query := range alert.Queries {
-				slsQuery := &sls20201230.AlertQuery{
-					ChartTitle:   query.ChartTitle,
-					DashboardId:  query.DashboardId,
-					End:          query.End,
-					PowerSqlMode: query.PowerSqlMode,
-					Project:      query.Project,
-					Query:        tea.String(query.Query),
-					Region:       query.Region,
-					RoleArn:      query.RoleArn,
-					Start:        query.Start,
-					Store:        query.Store,
-					StoreType:    query.StoreType,
-					TimeSpanType: query.TimeSpanType,
-					Ui:           query.Ui,
-				}
-				slsQueries = append(slsQueries, slsQuery)
-			}
-			slsConfig.QueryList = slsQueries
-		}
-
-		// 转换 Tags
-		if len(alert.Tags) > 0 {
-			var slsTags []*string
-			for _, tag := range alert.Tags {
-				if tag.TagType == "label" {
-					slsTags = append(slsTags, tea.String(tag.TagKey))
-				}
-			}
-			slsConfig.Tags = slsTags
-		}
-
-		// 转换 Annotations
-		if len(alert.Tags) > 0 {
-			var slsAnnotations []*sls20201230.AlertTag
-			for _, tag := range alert.Tags {
-				if tag.TagType == "annotation" {
-					slsAnnotation := &sls20201230.AlertTag{
-						Key:   tea.String(tag.TagKey),
-						Value: tag.TagValue,
-					}
-					slsAnnotations = append(slsAnnotations, slsAnnotation)
-				}
-			}
-			slsConfig.Annotations = slsAnnotations
-		}
+	// 调用 SLS API 删除 Alert
+	err := callSLS(ctx, s.retry, "DeleteAlert", func() error {
+		_, err := s.slsClient.DeleteAlertWithOptions(tea.String(project), tea.String(name), make(map[string]*string), s.runtimeOptions(ctx))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete alert %s from SLS: %w", name, err)
+	}
 
-		// 转换 Sink 配置
-		if alert.Configuration.SinkAlerthubConfig != nil {
-			slsConfig.SinkAlerthub = &sls20201230.SinkAlerthubConfiguration{
-				Enabled: alert.Configuration.SinkAlerthubConfig.Enabled,
-			}
-		}
+	return nil
+}
 
-		if alert.Configuration.SinkCmsConfig != nil {
-			slsConfig.SinkCms = &sls20201230.SinkCmsConfiguration{
-				Enabled: alert.Configuration.SinkCmsConfig.Enabled,
-			}
-		}
+// ValidateAlert 试运行本地模型到 SLS 模型的转换，返回有损转换和疑似错误查询的警告，不调用 SLS API
+func (s *slsService) ValidateAlert(ctx context.Context, alert *models.Alert) ([]Warning, error) {
+	_, warnings, err := s.convertForPush(alert)
+	if err != nil {
+		return nil, err
+	}
 
-		if alert.Configuration.SinkEventStoreConfig != nil {
-			slsConfig.SinkEventStore = &sls20201230.SinkEventStoreConfiguration{
-				Enabled:    alert.Configuration.SinkEventStoreConfig.Enabled,
-				Endpoint:   alert.Configuration.SinkEventStoreConfig.Endpoint,
-				EventStore: alert.Configuration.SinkEventStoreConfig.EventStore,
-				Project:    alert.Configuration.SinkEventStoreConfig.Project,
-				RoleArn:    alert.Configuration.SinkEventStoreConfig.RoleArn,
-			}
-		}
+	warnings = append(warnings, checkAlertQueries(NewLenientQueryValidator(), alert)...)
+	warnings = append(warnings, checkGroupFields(alert)...)
+	return warnings, nil
+}
 
-		slsAlert.Configuration = slsConfig
+// convertForPush 校验跨账号、跨地域查询配置后转换本地模型，配置不自洽时拒绝推送。
+// SLS 中已有的规则只需 mapper.ModelToSLS 转换，不做该校验
+func (s *slsService) convertForPush(alert *models.Alert) (*sls20201230.Alert, []Warning, error) {
+	if alert == nil {
+		return nil, nil, fmt.Errorf("alert is nil")
 	}
 
-	// 转换 Schedule
-	if alert.Schedule != nil {
-		slsAlert.Schedule = &sls20201230.Schedule{
-			CronExpression: alert.Schedule.CronExpression,
-			Delay:          alert.Schedule.Delay,
-			Interval:       alert.Schedule.Interval,
-			RunImmediately: alert.Schedule.RunImmediately,
-			TimeZone:       alert.Schedule.TimeZone,
-			Type:           tea.String(alert.Schedule.Type),
-		}
+	scopeWarnings, err := checkQueryScopes(alert, s.region)
+	if err != nil {
+		return nil, scopeWarnings, err
 	}
 
-	return slsAlert
+	slsAlert, warnings, err := mapper.ModelToSLS(alert)
+	return slsAlert, append(scopeWarnings, warnings...), err
 }
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/Ghostbaby/sls-migrate/internal/mapper"
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	sls20201230 "github.com/alibabacloud-go/sls-20201230/v6/client"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// alreadyExistsStub 创建返回 AlertAlreadyExist，更新成功
+func alreadyExistsStub() *slsStub {
+	return &slsStub{handler: func(req stubRequest) (int, interface{}) {
+		if req.Method == http.MethodPost && req.Path == "/alerts" {
+			return http.StatusBadRequest, slsErrorBody("AlertAlreadyExist", "alert already exists")
+		}
+		return http.StatusOK, nil
+	}}
+}
+
+func TestCreateAlertReturnsAlreadyExists(t *testing.T) {
+	stub := alreadyExistsStub()
+	svc := newStubSLSService(t, stub)
+
+	_, err := svc.CreateAlert(context.Background(), newTestAlert("dup"))
+	if !errors.Is(err, ErrSLSAlreadyExists) {
+		t.Fatalf("CreateAlert error = %v, want ErrSLSAlreadyExists", err)
+	}
+	if calls := stub.calls(); len(calls) != 1 {
+		t.Errorf("CreateAlert sent %d requests, want only the create", len(calls))
+	}
+}
+
+func TestCreateOrUpdateAlertUpdatesOnAlreadyExists(t *testing.T) {
+	stub := alreadyExistsStub()
+	svc := newStubSLSService(t, stub)
+
+	updated, _, err := svc.CreateOrUpdateAlert(context.Background(), newTestAlert("dup"))
+	if err != nil {
+		t.Fatalf("CreateOrUpdateAlert: %v", err)
+	}
+	if !updated {
+		t.Errorf("updated = false, want true")
+	}
+
+	calls := stub.calls()
+	if len(calls) != 2 {
+		t.Fatalf("requests = %+v, want create then update", calls)
+	}
+	update := calls[1]
+	if update.Method != http.MethodPut || update.Path != "/alerts/dup" || update.Project != "test-project" {
+		t.Errorf("second request = %s %s (project %s), want PUT /alerts/dup on test-project", update.Method, update.Path, update.Project)
+	}
+	if update.Body["displayName"] != "Alert dup" {
+		t.Errorf("update body displayName = %v, want %q", update.Body["displayName"], "Alert dup")
+	}
+}
+
+func TestCreateOrUpdateAlertCreates(t *testing.T) {
+	stub := &slsStub{handler: func(req stubRequest) (int, interface{}) {
+		return http.StatusOK, nil
+	}}
+	svc := newStubSLSService(t, stub)
+
+	updated, _, err := svc.CreateOrUpdateAlert(context.Background(), newTestAlert("new"))
+	if err != nil {
+		t.Fatalf("CreateOrUpdateAlert: %v", err)
+	}
+	if updated {
+		t.Errorf("updated = true, want false")
+	}
+	if calls := stub.calls(); len(calls) != 1 || calls[0].Method != http.MethodPost {
+		t.Errorf("requests = %+v, want a single create", calls)
+	}
+}
+
+func TestCreateOrUpdateAlertPropagatesOtherErrors(t *testing.T) {
+	stub := &slsStub{handler: func(req stubRequest) (int, interface{}) {
+		return http.StatusForbidden, slsErrorBody("Unauthorized", "denied")
+	}}
+	svc := newStubSLSService(t, stub)
+
+	if _, _, err := svc.CreateOrUpdateAlert(context.Background(), newTestAlert("x")); !errors.Is(err, ErrSLSAuth) {
+		t.Fatalf("CreateOrUpdateAlert error = %v, want ErrSLSAuth", err)
+	}
+	if calls := stub.calls(); len(calls) != 1 {
+		t.Errorf("requests = %d, want 1 (no update after a non-exists error)", len(calls))
+	}
+}
+
+func TestListAlertsPageSendsQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     SLSAlertPageQuery
+		wantQuery map[string]string
+	}{
+		{
+			name:      "offset size and logstore",
+			query:     SLSAlertPageQuery{Offset: 20, Size: 50, Logstore: "app-log"},
+			wantQuery: map[string]string{"offset": "20", "size": "50", "logstore": "app-log"},
+		},
+		{
+			name:      "defaults without logstore",
+			query:     SLSAlertPageQuery{Offset: -1, Size: 0},
+			wantQuery: map[string]string{"offset": "0", "size": "10"},
+		},
+		{
+			name:      "minimum size",
+			query:     SLSAlertPageQuery{Size: 1},
+			wantQuery: map[string]string{"offset": "0", "size": "1"},
+		},
+		{
+			name:      "maximum size",
+			query:     SLSAlertPageQuery{Size: SLSListAlertsMaxSize},
+			wantQuery: map[string]string{"offset": "0", "size": "200"},
+		},
+		{
+			name:      "size above maximum clamped",
+			query:     SLSAlertPageQuery{Size: SLSListAlertsMaxSize + 1},
+			wantQuery: map[string]string{"offset": "0", "size": "200"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &slsStub{handler: func(req stubRequest) (int, interface{}) {
+				return http.StatusOK, listAlertsBody()
+			}}
+			svc := newStubSLSService(t, stub)
+
+			if _, err := svc.ListAlertsPage(context.Background(), tt.query); err != nil {
+				t.Fatalf("ListAlertsPage: %v", err)
+			}
+
+			calls := stub.calls()
+			if len(calls) != 1 || calls[0].Method != http.MethodGet || calls[0].Path != "/alerts" {
+				t.Fatalf("requests = %+v, want a single GET /alerts", calls)
+			}
+			for key, want := range tt.wantQuery {
+				if got := calls[0].Query[key]; got != want {
+					t.Errorf("query %s = %q, want %q", key, got, want)
+				}
+			}
+			if _, ok := tt.wantQuery["logstore"]; !ok {
+				if got, sent := calls[0].Query["logstore"]; sent {
+					t.Errorf("logstore = %q sent without a filter", got)
+				}
+			}
+		})
+	}
+}
+
+// pagedAlertsStub 按请求的 offset/size 从 n 个 Alert 中返回一页，withTotal 为 false 时不返回 total
+func pagedAlertsStub(t *testing.T, n int, withTotal bool) *slsStub {
+	t.Helper()
+
+	all := make([]*sls20201230.Alert, 0, n)
+	for i := 0; i < n; i++ {
+		slsAlert, _, err := mapper.ModelToSLS(newTestAlert(fmt.Sprintf("alert-%03d", i)))
+		if err != nil {
+			t.Fatalf("ModelToSLS: %v", err)
+		}
+		all = append(all, slsAlert)
+	}
+
+	return &slsStub{handler: func(req stubRequest) (int, interface{}) {
+		offset, _ := strconv.Atoi(req.Query["offset"])
+		size, _ := strconv.Atoi(req.Query["size"])
+		start, end := offset, offset+size
+		if start > n {
+			start = n
+		}
+		if end > n {
+			end = n
+		}
+		body := map[string]interface{}{"results": all[start:end], "count": end - start}
+		if withTotal {
+			body["total"] = n
+		}
+		return http.StatusOK, body
+	}}
+}
+
+func TestGetAlertsPagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		alerts       int
+		withTotal    bool
+		wantRequests int
+	}{
+		{name: "total set, partial last page", alerts: 450, withTotal: true, wantRequests: 3},
+		{name: "total set, exact pages", alerts: 400, withTotal: true, wantRequests: 2},
+		{name: "total unset, short last page", alerts: 450, withTotal: false, wantRequests: 3},
+		{name: "total unset, exact pages end on empty page", alerts: 400, withTotal: false, wantRequests: 3},
+		{name: "total set, empty project", alerts: 0, withTotal: true, wantRequests: 1},
+		{name: "total unset, single short page", alerts: 5, withTotal: false, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := pagedAlertsStub(t, tt.alerts, tt.withTotal)
+			svc := newStubSLSService(t, stub)
+
+			alerts, err := svc.GetAlerts(context.Background())
+			if err != nil {
+				t.Fatalf("GetAlerts: %v", err)
+			}
+			if len(alerts) != tt.alerts {
+				t.Errorf("alerts = %d, want %d", len(alerts), tt.alerts)
+			}
+			if calls := stub.calls(); len(calls) != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", len(calls), tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestRuntimeOptionsFollowsDeadline(t *testing.T) {
+	tests := []struct {
+		name        string
+		readMS      int
+		connectMS   int
+		timeout     time.Duration // 0 表示 ctx 没有截止时间
+		wantRead    [2]int        // 允许的区间 [min, max]
+		wantConnect [2]int
+	}{
+		{name: "no deadline uses config", readMS: 10000, connectMS: 5000, wantRead: [2]int{10000, 10000}, wantConnect: [2]int{5000, 5000}},
+		{name: "deadline shorter than config", readMS: 10000, connectMS: 5000, timeout: 2 * time.Second, wantRead: [2]int{1500, 2000}, wantConnect: [2]int{1500, 2000}},
+		{name: "deadline between timeouts", readMS: 10000, connectMS: 5000, timeout: 8 * time.Second, wantRead: [2]int{7500, 8000}, wantConnect: [2]int{5000, 5000}},
+		{name: "deadline longer than config", readMS: 10000, connectMS: 5000, timeout: time.Minute, wantRead: [2]int{10000, 10000}, wantConnect: [2]int{5000, 5000}},
+		{name: "deadline without configured timeouts", timeout: 2 * time.Second, wantRead: [2]int{1500, 2000}, wantConnect: [2]int{1500, 2000}},
+		{name: "expired deadline", readMS: 10000, connectMS: 5000, timeout: -time.Second, wantRead: [2]int{1, 1}, wantConnect: [2]int{1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &slsService{}
+			svc.config.ReadTimeoutMS = tt.readMS
+			svc.config.ConnectTimeoutMS = tt.connectMS
+
+			ctx := context.Background()
+			if tt.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			runtime := svc.runtimeOptions(ctx)
+			if got := tea.IntValue(runtime.ReadTimeout); got < tt.wantRead[0] || got > tt.wantRead[1] {
+				t.Errorf("read timeout = %d, want within %v", got, tt.wantRead)
+			}
+			if got := tea.IntValue(runtime.ConnectTimeout); got < tt.wantConnect[0] || got > tt.wantConnect[1] {
+				t.Errorf("connect timeout = %d, want within %v", got, tt.wantConnect)
+			}
+		})
+	}
+}
+
+func TestRuntimeOptionsUnsetWithoutConfigOrDeadline(t *testing.T) {
+	runtime := (&slsService{}).runtimeOptions(context.Background())
+	if runtime.ReadTimeout != nil || runtime.ConnectTimeout != nil {
+		t.Errorf("timeouts = %v/%v, want SDK defaults (nil)", runtime.ReadTimeout, runtime.ConnectTimeout)
+	}
+}
+
+func TestConversionWarningsSurfaced(t *testing.T) {
+	lossy := func() *models.Alert {
+		alert := newTestAlert("lossy")
+		alert.Tags = append(alert.Tags, models.AlertTag{TagType: "foo", TagKey: "k"})
+		return alert
+	}
+
+	tests := []struct {
+		name string
+		call func(svc *slsService) ([]Warning, error)
+	}{
+		{name: "validate", call: func(svc *slsService) ([]Warning, error) {
+			return svc.ValidateAlert(context.Background(), lossy())
+		}},
+		{name: "create", call: func(svc *slsService) ([]Warning, error) {
+			return svc.CreateAlert(context.Background(), lossy())
+		}},
+		{name: "update", call: func(svc *slsService) ([]Warning, error) {
+			return svc.UpdateAlert(context.Background(), lossy())
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &slsStub{handler: func(req stubRequest) (int, interface{}) {
+				return http.StatusOK, nil
+			}}
+			warnings, err := tt.call(newStubSLSService(t, stub))
+			if err != nil {
+				t.Fatalf("call: %v", err)
+			}
+			found := false
+			for _, w := range warnings {
+				if w.Field == "tags[1].tag_type" && w.Alert == "lossy" {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("warnings = %+v, want the dropped tag type", warnings)
+			}
+		})
+	}
+}
+
+func TestGetAlertsByNamesListsOnce(t *testing.T) {
+	stub := pagedAlertsStub(t, 5, true)
+	svc := newStubSLSService(t, stub)
+
+	alerts, err := svc.GetAlertsByNames(context.Background(), []string{"alert-001", "alert-003", "missing"})
+	if err != nil {
+		t.Fatalf("GetAlertsByNames: %v", err)
+	}
+	if len(alerts) != 2 || alerts["alert-001"] == nil || alerts["alert-003"] == nil {
+		t.Errorf("alerts = %v, want alert-001 and alert-003 only", alerts)
+	}
+	if alert := alerts["alert-003"]; alert != nil && alert.Name != "alert-003" {
+		t.Errorf("alerts[alert-003].Name = %q", alert.Name)
+	}
+	if calls := stub.calls(); len(calls) != 1 {
+		t.Errorf("requests = %d, want a single list for all names", len(calls))
+	}
+
+	if _, err := svc.GetAlertsByNames(context.Background(), nil); err != nil {
+		t.Fatalf("GetAlertsByNames without names: %v", err)
+	}
+	if calls := stub.calls(); len(calls) != 1 {
+		t.Errorf("requests = %d, want no list without names", len(calls))
+	}
+}
+
+func TestSyncDatabaseToSLSListsOnce(t *testing.T) {
+	ctx := context.Background()
+	stub := pagedAlertsStub(t, 2, true)
+	svc := newStubSLSService(t, stub)
+	alertStore := newTestStore(t)
+	alertService := NewAlertService(alertStore, &config.DefaultSinkConfig{}, AlertStatusEnabled)
+	syncSvc := NewSyncService(svc, alertStore, alertService, &config.SyncConfig{})
+
+	// alert-000 和 alert-001 已在 SLS 中，alert-100 和 alert-101 需要创建
+	for _, name := range []string{"alert-000", "alert-001", "alert-100", "alert-101"} {
+		if err := alertStore.CreateWithTransaction(ctx, newTestAlert(name)); err != nil {
+			t.Fatalf("CreateWithTransaction %s: %v", name, err)
+		}
+	}
+
+	if _, err := syncSvc.SyncDatabaseToSLS(ctx); err != nil {
+		t.Fatalf("SyncDatabaseToSLS: %v", err)
+	}
+	lists := 0
+	for _, call := range stub.calls() {
+		if call.Method == http.MethodGet && call.Path == "/alerts" {
+			lists++
+		}
+	}
+	if lists != 1 {
+		t.Errorf("list requests = %d, want 1 for the whole sync", lists)
+	}
+}
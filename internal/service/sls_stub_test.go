@@ -0,0 +1,111 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	sls20201230 "github.com/alibabacloud-go/sls-20201230/v6/client"
+)
+
+// stubRequest SLS SDK 发出的一次请求
+type stubRequest struct {
+	Method  string
+	Project string // 请求 Host 中的 Project
+	Path    string
+	Query   map[string]string
+	Body    map[string]interface{}
+}
+
+// slsStub 记录 SLS SDK 发出的请求，并由 handler 生成响应，不访问网络
+type slsStub struct {
+	mu       sync.Mutex
+	requests []stubRequest
+	handler  func(req stubRequest) (int, interface{})
+}
+
+// Call 实现 SDK 的 HttpClient 接口：在进程内调用 handler 生成响应
+func (s *slsStub) Call(request *http.Request, transport *http.Transport) (*http.Response, error) {
+	req := stubRequest{
+		Method:  request.Method,
+		Project: strings.SplitN(request.URL.Host, ".", 2)[0],
+		Path:    request.URL.Path,
+		Query:   map[string]string{},
+	}
+	for key := range request.URL.Query() {
+		req.Query[key] = request.URL.Query().Get(key)
+	}
+	if request.Body != nil {
+		data, _ := io.ReadAll(request.Body)
+		if len(data) > 0 {
+			json.Unmarshal(data, &req.Body)
+		}
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+
+	status, body := s.handler(req)
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set("Content-Type", "application/json")
+	recorder.Header().Set("x-log-requestid", "stub-request")
+	recorder.WriteHeader(status)
+	if body != nil {
+		json.NewEncoder(recorder).Encode(body)
+	}
+	return recorder.Result(), nil
+}
+
+// calls 返回已记录请求的副本
+func (s *slsStub) calls() []stubRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]stubRequest(nil), s.requests...)
+}
+
+// slsErrorBody SLS 错误响应体
+func slsErrorBody(code, message string) map[string]string {
+	return map[string]string{"errorCode": code, "errorMessage": message}
+}
+
+// listAlertsBody ListAlerts 的响应体，total 为给定 Alert 的数量
+func listAlertsBody(alerts ...*sls20201230.Alert) map[string]interface{} {
+	return map[string]interface{}{"results": alerts, "count": len(alerts), "total": len(alerts)}
+}
+
+// newStubSLSService 创建请求由 stub 处理的 slsService，不重试
+func newStubSLSService(t *testing.T, stub *slsStub) *slsService {
+	t.Helper()
+
+	slsConfig := &config.SLSConfig{
+		AccessKeyID:     "test-ak",
+		AccessKeySecret: "test-sk",
+		Endpoint:        "cn-hangzhou.log.aliyuncs.com",
+		Project:         "test-project",
+	}
+	clientConfig, err := config.CreateSLSClient(slsConfig)
+	if err != nil {
+		t.Fatalf("CreateSLSClient: %v", err)
+	}
+	clientConfig.HttpClient = stub
+	client, err := sls20201230.NewClient(clientConfig)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	return &slsService{
+		slsClient: client,
+		project:   slsConfig.Project,
+		region:    slsConfig.Region(),
+		retry:     newSLSRetryPolicy(0, 0),
+		logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+		config:    *slsConfig,
+	}
+}
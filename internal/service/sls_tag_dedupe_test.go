@@ -0,0 +1,94 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	sls20201230 "github.com/alibabacloud-go/sls-20201230/v6/client"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// duplicateTagsAlert 返回 labels 与 annotations 中有重复键的 SLS Alert
+func duplicateTagsAlert() *sls20201230.Alert {
+	return &sls20201230.Alert{
+		Name:        tea.String("dup"),
+		DisplayName: tea.String("Dup"),
+		Status:      tea.String(AlertStatusEnabled),
+		Configuration: &sls20201230.AlertConfiguration{
+			Labels: []*sls20201230.AlertTag{
+				{Key: tea.String("team"), Value: tea.String("ops")},
+				{Key: tea.String("env"), Value: tea.String("prod")},
+				{Key: tea.String("team"), Value: tea.String("sre")},
+			},
+			Annotations: []*sls20201230.AlertTag{
+				{Key: tea.String("summary"), Value: tea.String("old")},
+				{Key: tea.String("summary"), Value: tea.String("new")},
+			},
+		},
+	}
+}
+
+// tagValues 按顺序返回 "类型:键=值" 形式的标签
+func tagValues(tags []models.AlertTag) []string {
+	var values []string
+	for _, tag := range tags {
+		values = append(values, tag.TagType+":"+tag.TagKey+"="+tea.StringValue(tag.TagValue))
+	}
+	return values
+}
+
+func TestGetAlertsDeduplicatesTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		deduplicate bool
+		wantTags    []string
+		wantWarning bool
+	}{
+		{
+			name:        "deduplicate",
+			deduplicate: true,
+			wantTags:    []string{"label:team=sre", "label:env=prod", "annotation:summary=new"},
+			wantWarning: true,
+		},
+		{
+			name:     "disabled",
+			wantTags: []string{"label:team=ops", "label:env=prod", "label:team=sre", "annotation:summary=old", "annotation:summary=new"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newStubSLSService(t, &slsStub{handler: func(req stubRequest) (int, interface{}) {
+				return http.StatusOK, listAlertsBody(duplicateTagsAlert())
+			}})
+			svc.config.DeduplicateTags = tt.deduplicate
+			var logs bytes.Buffer
+			svc.logger = slog.New(slog.NewTextHandler(&logs, nil))
+
+			alerts, err := svc.GetAlerts(context.Background())
+			if err != nil {
+				t.Fatalf("GetAlerts: %v", err)
+			}
+			if len(alerts) != 1 {
+				t.Fatalf("alerts = %d, want 1", len(alerts))
+			}
+			if got := tagValues(alerts[0].Tags); !reflect.DeepEqual(got, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", got, tt.wantTags)
+			}
+
+			warned := strings.Contains(logs.String(), "collapsed duplicate SLS tags")
+			if warned != tt.wantWarning {
+				t.Errorf("duplicate warning logged = %v, want %v: %s", warned, tt.wantWarning, logs.String())
+			}
+			if warned && (!strings.Contains(logs.String(), "label:team") || !strings.Contains(logs.String(), "annotation:summary")) {
+				t.Errorf("warning = %s, want the collapsed keys", logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	sls20201230 "github.com/alibabacloud-go/sls-20201230/v6/client"
+)
+
+// ErrInvalidSLSTarget 请求指定的 SLS Project 或 Endpoint 不合法
+var ErrInvalidSLSTarget = newError(ErrValidation, "invalid SLS target")
+
+var (
+	// slsProjectPattern SLS Project 命名规则：小写字母、数字和连字符，3-63 个字符，首尾为字母或数字
+	slsProjectPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$`)
+	// slsEndpointPattern 只允许阿里云 SLS 的 Endpoint，避免请求把签名后的调用发往任意地址
+	slsEndpointPattern = regexp.MustCompile(`^[a-z0-9-]+\.log\.aliyuncs\.com$`)
+)
+
+// SLSTarget 单个请求访问的 SLS Project 和 Endpoint，空字段沿用启动时的配置
+type SLSTarget struct {
+	Project  string
+	Endpoint string
+}
+
+// IsZero 是否没有指定任何字段
+func (t SLSTarget) IsZero() bool {
+	return t.Project == "" && t.Endpoint == ""
+}
+
+// WithTarget 返回访问指定 Project/Endpoint 的 SLSService，凭据、日志库和重试策略与当前实例相同；
+// Endpoint 不变时复用现有客户端
+func (s *slsService) WithTarget(target SLSTarget) (SLSService, error) {
+	if target.IsZero() {
+		return s, nil
+	}
+
+	scoped := *s
+	if target.Project != "" {
+		if !slsProjectPattern.MatchString(target.Project) {
+			return nil, fmt.Errorf("%w: project %q", ErrInvalidSLSTarget, target.Project)
+		}
+		scoped.project = target.Project
+	}
+
+	endpoint := strings.TrimPrefix(strings.TrimPrefix(target.Endpoint, "https://"), "http://")
+	if endpoint != "" && endpoint != s.config.Endpoint {
+		if !slsEndpointPattern.MatchString(endpoint) {
+			return nil, fmt.Errorf("%w: endpoint %q", ErrInvalidSLSTarget, target.Endpoint)
+		}
+
+		cfg := s.config
+		cfg.Endpoint = endpoint
+		clientConfig, err := config.CreateSLSClient(&cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SLS client: %w", err)
+		}
+		client, err := sls20201230.NewClient(clientConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SLS client: %w", err)
+		}
+
+		scoped.slsClient = client
+		scoped.config = cfg
+		scoped.region = cfg.Region()
+	}
+
+	return &scoped, nil
+}
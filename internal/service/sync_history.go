@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/pkg/logger"
+)
+
+// 同步执行结果
+const (
+	SyncRunStatusSuccess  = "success"
+	SyncRunStatusFailed   = "failed"
+	SyncRunStatusTimedOut = "timed_out"
+)
+
+// 同步历史查询的默认和最大条数
+const (
+	defaultSyncHistoryLimit = 20
+	maxSyncHistoryLimit     = 100
+)
+
+// finishSync 同步结束时记录指标、写入执行记录并发送 Webhook 通知；失败只记录日志，不影响同步结果
+func (s *syncService) finishSync(ctx context.Context, direction string, started time.Time, result *SyncResult, syncErr error) {
+	finished := time.Now()
+	if result != nil {
+		s.observeSync(ctx, result, finished.Sub(started))
+	}
+
+	run := &models.SyncRun{
+		Direction:  direction,
+		StartedAt:  started,
+		FinishedAt: finished,
+		Status:     SyncRunStatusSuccess,
+	}
+	if result != nil {
+		run.Created = result.Created
+		run.Updated = result.Updated
+		run.Skipped = result.Skipped
+		run.Failed = result.Failed
+	}
+	if syncErr != nil {
+		message := syncErr.Error()
+		run.Error = &message
+		run.Status = SyncRunStatusFailed
+		if errors.Is(syncErr, ErrSyncTimedOut) {
+			run.Status = SyncRunStatusTimedOut
+		}
+	}
+
+	// 同步可能已超时或请求已取消，执行记录使用独立的上下文写入
+	if err := s.alertStore.CreateSyncRun(context.WithoutCancel(ctx), run); err != nil {
+		logger.Printf(ctx, "Failed to record %s sync run: %v", direction, err)
+	}
+
+	s.notifier.Notify(ctx, newSyncNotification(direction, run.Status, started, finished, result, syncErr))
+}
+
+// GetSyncHistory 按开始时间倒序获取最近 limit 次同步的执行记录，limit 不合法时使用默认值
+func (s *syncService) GetSyncHistory(ctx context.Context, limit int) ([]models.SyncRun, error) {
+	if limit < 1 || limit > maxSyncHistoryLimit {
+		limit = defaultSyncHistoryLimit
+	}
+
+	runs, err := s.alertStore.ListSyncRuns(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sync runs: %w", err)
+	}
+	return runs, nil
+}
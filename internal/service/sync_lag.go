@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// syncLagMetricName 同步延迟指标名称
+const syncLagMetricName = "sync_lag_seconds"
+
+// ProjectSyncLag 单个 SLS Project 的同步延迟
+type ProjectSyncLag struct {
+	Project           string     `json:"project"`
+	LastSyncedAt      *time.Time `json:"last_synced_at"`
+	SyncLagSeconds    *float64   `json:"sync_lag_seconds"` // 从未同步过时为 null
+	NeverSyncedAlerts int64      `json:"never_synced_alerts"`
+}
+
+// SyncLag 各 SLS Project 数据库相对 SLS 的同步延迟
+type SyncLag struct {
+	GeneratedAt time.Time        `json:"generated_at"`
+	Projects    []ProjectSyncLag `json:"projects"`
+}
+
+// GetSyncLag 按 Project 计算距最近一次成功同步的时间。
+// 同步时间来自各 Alert 的 last_synced_at；当前只同步 SLS_PROJECT 一个 Project，因此只返回一项
+func (s *syncService) GetSyncLag(ctx context.Context) (*SyncLag, error) {
+	lastSynced, err := s.alertStore.LastSynced(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last sync time: %w", err)
+	}
+	neverSynced, err := s.alertStore.CountNeverSynced(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count never synced alerts: %w", err)
+	}
+
+	now := time.Now()
+	project := ProjectSyncLag{
+		Project:           s.slsService.Project(),
+		LastSyncedAt:      lastSynced,
+		NeverSyncedAlerts: neverSynced,
+	}
+	if lastSynced != nil {
+		lag := now.Sub(*lastSynced).Seconds()
+		if lag < 0 {
+			lag = 0
+		}
+		project.SyncLagSeconds = &lag
+	}
+
+	return &SyncLag{GeneratedAt: now, Projects: []ProjectSyncLag{project}}, nil
+}
+
+// Prometheus 以 Prometheus 文本格式返回同步延迟，从未同步过的 Project 不输出样本
+func (l *SyncLag) Prometheus() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s Seconds since the last successful sync of the project.\n", syncLagMetricName)
+	fmt.Fprintf(&b, "# TYPE %s gauge\n", syncLagMetricName)
+	for _, project := range l.Projects {
+		if project.SyncLagSeconds == nil {
+			continue
+		}
+		fmt.Fprintf(&b, "%s{project=%s} %s\n", syncLagMetricName,
+			strconv.Quote(project.Project), strconv.FormatFloat(*project.SyncLagSeconds, 'f', 3, 64))
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+)
+
+func TestGetSyncLagReflectsLastSync(t *testing.T) {
+	ctx := context.Background()
+	syncSvc, alertStore := newTestSyncService(t, newFakeSLS(t), &config.SyncConfig{})
+	for _, name := range []string{"old", "recent", "never"} {
+		if err := alertStore.CreateWithTransaction(ctx, newTestAlert(name)); err != nil {
+			t.Fatalf("CreateWithTransaction %s: %v", name, err)
+		}
+	}
+
+	lag, err := syncSvc.GetSyncLag(ctx)
+	if err != nil {
+		t.Fatalf("GetSyncLag: %v", err)
+	}
+	if len(lag.Projects) != 1 || lag.Projects[0].Project != "test-project" {
+		t.Fatalf("projects = %+v, want test-project only", lag.Projects)
+	}
+	if project := lag.Projects[0]; project.LastSyncedAt != nil || project.SyncLagSeconds != nil || project.NeverSyncedAlerts != 3 {
+		t.Errorf("before any sync = %+v, want no lag and 3 never synced alerts", project)
+	}
+	if metrics := lag.Prometheus(); strings.Contains(metrics, "sync_lag_seconds{") {
+		t.Errorf("prometheus output = %q, want no sample before any sync", metrics)
+	}
+
+	// 延迟取最近一次同步的时间
+	now := time.Now()
+	if err := alertStore.MarkSynced(ctx, "old", SyncDirectionSLSToDB, now.Add(-10*time.Minute)); err != nil {
+		t.Fatalf("MarkSynced old: %v", err)
+	}
+	if err := alertStore.MarkSynced(ctx, "recent", SyncDirectionDBToSLS, now.Add(-90*time.Second)); err != nil {
+		t.Fatalf("MarkSynced recent: %v", err)
+	}
+
+	lag, err = syncSvc.GetSyncLag(ctx)
+	if err != nil {
+		t.Fatalf("GetSyncLag: %v", err)
+	}
+	project := lag.Projects[0]
+	if project.LastSyncedAt == nil || project.LastSyncedAt.Sub(now.Add(-90*time.Second)).Abs() > time.Second {
+		t.Errorf("last synced at = %v, want %v", project.LastSyncedAt, now.Add(-90*time.Second))
+	}
+	if project.SyncLagSeconds == nil || *project.SyncLagSeconds < 90 || *project.SyncLagSeconds > 95 {
+		t.Errorf("sync lag = %v, want about 90 seconds", project.SyncLagSeconds)
+	}
+	if project.NeverSyncedAlerts != 1 {
+		t.Errorf("never synced alerts = %d, want 1", project.NeverSyncedAlerts)
+	}
+	if metrics := lag.Prometheus(); !strings.Contains(metrics, `sync_lag_seconds{project="test-project"} 9`) {
+		t.Errorf("prometheus output = %q, want a sample of about 90 seconds", metrics)
+	}
+}
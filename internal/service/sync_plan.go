@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/pkg/logger"
+)
+
+// 同步计划动作
+const (
+	PlanActionCreate = "create"
+	PlanActionUpdate = "update"
+	PlanActionSkip   = "skip"
+)
+
+// syncPlanVersion 同步计划文档格式版本
+const syncPlanVersion = 1
+
+// ErrPlanChecksumMismatch 同步计划签名不匹配（计划被篡改、格式不正确或由其他密钥签名）
+var ErrPlanChecksumMismatch = newError(ErrValidation, "sync plan checksum mismatch")
+
+// PlanDriftError 计划生成后 SLS 或数据库状态发生了变化
+type PlanDriftError struct {
+	Names []string
+}
+
+// Error 实现 error 接口
+func (e *PlanDriftError) Error() string {
+	return fmt.Sprintf("state drifted since plan was created: %s", strings.Join(e.Names, ", "))
+}
+
+// Is 状态漂移属于 ErrConflict
+func (e *PlanDriftError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// SyncPlanItem 同步计划中单个 Alert 的动作
+type SyncPlanItem struct {
+	Name           string        `json:"name"`
+	Action         string        `json:"action"`
+	SLSFingerprint string        `json:"sls_fingerprint"`
+	DBFingerprint  string        `json:"db_fingerprint,omitempty"`
+	Changes        []FieldChange `json:"changes,omitempty"` // update 时数据库与 SLS 之间不同的字段
+}
+
+// SyncPlan 同步计划文档，可保存后再通过 apply-plan 执行
+type SyncPlan struct {
+	Version   int            `json:"version"`
+	Direction string         `json:"direction"`
+	CreatedAt time.Time      `json:"created_at"`
+	Items     []SyncPlanItem `json:"items"`
+	Checksum  string         `json:"checksum"` // HMAC-SHA256 签名，密钥为 SYNC_PLAN_SECRET
+}
+
+// PlanSLSToDatabase 生成 SLS 到数据库的同步计划，不做任何写入。
+// 与实际同步使用相同的 needsUpdate 判断，update 条目附带变化的字段
+func (s *syncService) PlanSLSToDatabase(ctx context.Context) (*SyncPlan, error) {
+	slsAlerts, err := s.slsService.GetAlerts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts from SLS: %w", err)
+	}
+
+	plan := &SyncPlan{
+		Version:   syncPlanVersion,
+		Direction: SyncDirectionSLSToDB,
+		CreatedAt: time.Now().UTC(),
+		Items:     make([]SyncPlanItem, 0, len(slsAlerts)),
+	}
+
+	for _, slsAlert := range slsAlerts {
+		item := SyncPlanItem{
+			Name:           slsAlert.Name,
+			SLSFingerprint: fingerprintAlert(slsAlert),
+		}
+
+		existingAlert, err := s.alertStore.GetByName(ctx, slsAlert.Name)
+		if err == nil && existingAlert != nil {
+			item.DBFingerprint = fingerprintAlert(existingAlert)
+			if s.needsUpdate(existingAlert, slsAlert) {
+				item.Action = PlanActionUpdate
+				changes, err := DiffAlerts(existingAlert, slsAlert)
+				if err != nil {
+					return nil, fmt.Errorf("failed to diff alert %s: %w", slsAlert.Name, err)
+				}
+				item.Changes = changes
+			} else {
+				item.Action = PlanActionSkip
+			}
+		} else {
+			item.Action = PlanActionCreate
+		}
+
+		plan.Items = append(plan.Items, item)
+	}
+
+	checksum, err := plan.computeChecksum(s.planKey)
+	if err != nil {
+		return nil, err
+	}
+	plan.Checksum = checksum
+
+	return plan, nil
+}
+
+// ApplySyncPlan 重新校验同步计划并执行，如果状态已漂移则拒绝执行；校验通过后结束时写入同步执行记录
+func (s *syncService) ApplySyncPlan(ctx context.Context, plan *SyncPlan) (*SyncResult, error) {
+	started := time.Now()
+	result, err := s.applySyncPlan(ctx, plan)
+	if result != nil {
+		s.finishSync(ctx, SyncDirectionSLSToDB, started, result, err)
+	}
+	return result, err
+}
+
+// applySyncPlan 校验并执行同步计划，被拒绝时返回 nil 结果
+func (s *syncService) applySyncPlan(ctx context.Context, plan *SyncPlan) (*SyncResult, error) {
+	if plan == nil || plan.Version != syncPlanVersion || plan.Direction != SyncDirectionSLSToDB {
+		return nil, fmt.Errorf("unsupported sync plan")
+	}
+
+	checksum, err := plan.computeChecksum(s.planKey)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(checksum), []byte(plan.Checksum)) {
+		return nil, ErrPlanChecksumMismatch
+	}
+
+	ctx, cancel := s.withMaxDuration(ctx)
+	defer cancel()
+
+	slsAlerts, err := s.slsService.GetAlerts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts from SLS: %w", err)
+	}
+	slsByName := make(map[string]*models.Alert, len(slsAlerts))
+	for _, alert := range slsAlerts {
+		slsByName[alert.Name] = alert
+	}
+
+	// 先校验全部条目，任何一条漂移都拒绝整个计划
+	existingByName := make(map[string]*models.Alert, len(plan.Items))
+	var drifted []string
+	for _, item := range plan.Items {
+		slsAlert, ok := slsByName[item.Name]
+		if !ok || fingerprintAlert(slsAlert) != item.SLSFingerprint {
+			drifted = append(drifted, item.Name)
+			continue
+		}
+
+		existingAlert, err := s.alertStore.GetByName(ctx, item.Name)
+		dbFingerprint := ""
+		if err == nil && existingAlert != nil {
+			dbFingerprint = fingerprintAlert(existingAlert)
+			existingByName[item.Name] = existingAlert
+		}
+		if dbFingerprint != item.DBFingerprint {
+			drifted = append(drifted, item.Name)
+		}
+	}
+	if len(drifted) > 0 {
+		return nil, &PlanDriftError{Names: drifted}
+	}
+
+	result := NewSyncResult(plan.Direction)
+	for _, item := range plan.Items {
+		if s.stopIfTimedOut(ctx, result) {
+			break
+		}
+
+		slsAlert := slsByName[item.Name]
+		switch item.Action {
+		case PlanActionCreate:
+			if err := s.alertService.CreateAlert(withSLSImport(ctx), slsAlert); err != nil {
+				logger.Printf(ctx, "Failed to create alert %s: %v", item.Name, err)
+				result.RecordFailed(item.Name, err)
+				continue
+			}
+			s.backfillCreatedAt(ctx, slsAlert)
+			result.RecordCreated(item.Name)
+			s.markSynced(ctx, item.Name, result.Direction)
+		case PlanActionUpdate:
+			if frozenErr := checkNotFrozen(existingByName[item.Name]); frozenErr != nil {
+				logger.Printf(ctx, "Alert %s is frozen, skipping", item.Name)
+				result.RecordSkippedReason(item.Name, frozenErr.Error())
+				continue
+			}
+			slsAlert.ID = existingByName[item.Name].ID
+			if err := s.alertService.UpdateAlertSections(ctx, slsAlert, s.updateSections(existingByName[item.Name], slsAlert)); err != nil {
+				logger.Printf(ctx, "Failed to update alert %s: %v", item.Name, err)
+				result.RecordFailed(item.Name, err)
+				continue
+			}
+			s.backfillCreatedAt(ctx, slsAlert)
+			result.RecordUpdated(item.Name)
+			s.markSynced(ctx, item.Name, result.Direction)
+		default:
+			result.RecordSkipped(item.Name)
+			s.markSynced(ctx, item.Name, result.Direction)
+		}
+	}
+
+	logger.Printf(ctx, "Sync plan applied. Total: %d, Created: %d, Updated: %d, Skipped: %d, Failed: %d",
+		result.Total, result.Created, result.Updated, result.Skipped, result.Failed)
+
+	if result.TimedOut {
+		return result, fmt.Errorf("%w: processed %d of %d plan items", ErrSyncTimedOut, result.Total, len(plan.Items))
+	}
+
+	if result.Failed > 0 {
+		return result, fmt.Errorf("sync plan applied with %d failures. Last error: %s", result.Failed, result.LastError)
+	}
+
+	return result, nil
+}
+
+// newPlanKey 返回同步计划签名密钥：配置了 SYNC_PLAN_SECRET 时使用该值，否则使用进程内随机密钥，
+// 此时计划只能由生成它的进程执行，重启后需要重新生成
+func newPlanKey(secret string) []byte {
+	if secret != "" {
+		return []byte(secret)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("failed to generate sync plan key: %v", err))
+	}
+	return key
+}
+
+// computeChecksum 以 key 计算同步计划内容的 HMAC-SHA256 签名（不包含 Checksum 字段本身），
+// 没有密钥时无法在修改条目后重新生成有效签名
+func (p *SyncPlan) computeChecksum(key []byte) (string, error) {
+	payload, err := json.Marshal(struct {
+		Version   int            `json:"version"`
+		Direction string         `json:"direction"`
+		CreatedAt time.Time      `json:"created_at"`
+		Items     []SyncPlanItem `json:"items"`
+	}{p.Version, p.Direction, p.CreatedAt, p.Items})
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize sync plan: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// fingerprintAlert 计算 Alert 内容的指纹，用于检测计划生成后的状态漂移；与内容哈希相同，
+// 不包含 last_synced_at、updated_at、content_hash 等记录同步状态的字段，同步本身不会让计划失效
+func fingerprintAlert(alert *models.Alert) string {
+	hash, err := alertContentHash(alert)
+	if err != nil {
+		return ""
+	}
+	return hash
+}
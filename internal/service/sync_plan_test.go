@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// planFixture 数据库中已有 existing（与 SLS 中的阈值不同），SLS 中另有一个待创建的 fresh
+func planFixture(t *testing.T) (*syncService, *fakeSLS, context.Context) {
+	t.Helper()
+	ctx := context.Background()
+
+	existing := newTestAlert("existing")
+	slsExisting := newTestAlert("existing")
+	slsExisting.Configuration.Threshold = tea.Int32(5)
+	sls := newFakeSLS(t, slsExisting, newTestAlert("fresh"))
+
+	syncSvc, _ := newTestSyncService(t, sls, &config.SyncConfig{DeepCompare: true, PlanSecret: "test-secret"})
+	if err := syncSvc.alertService.CreateAlert(ctx, existing); err != nil {
+		t.Fatalf("CreateAlert: %v", err)
+	}
+	return syncSvc, sls, ctx
+}
+
+func TestPlanSLSToDatabaseActions(t *testing.T) {
+	syncSvc, _, ctx := planFixture(t)
+
+	plan, err := syncSvc.PlanSLSToDatabase(ctx)
+	if err != nil {
+		t.Fatalf("PlanSLSToDatabase: %v", err)
+	}
+
+	actions := map[string]string{}
+	for _, item := range plan.Items {
+		actions[item.Name] = item.Action
+	}
+	want := map[string]string{"existing": PlanActionUpdate, "fresh": PlanActionCreate}
+	if !reflect.DeepEqual(actions, want) {
+		t.Fatalf("actions = %v, want %v", actions, want)
+	}
+}
+
+func TestApplySyncPlanRejectsDBDrift(t *testing.T) {
+	syncSvc, _, ctx := planFixture(t)
+
+	plan, err := syncSvc.PlanSLSToDatabase(ctx)
+	if err != nil {
+		t.Fatalf("PlanSLSToDatabase: %v", err)
+	}
+
+	// 计划生成后修改数据库中的 Alert
+	changed, err := syncSvc.alertStore.GetByName(ctx, "existing")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	changed.DisplayName = "changed after plan"
+	if err := syncSvc.alertService.UpdateAlert(ctx, changed); err != nil {
+		t.Fatalf("UpdateAlert: %v", err)
+	}
+
+	result, err := syncSvc.ApplySyncPlan(ctx, plan)
+	var driftErr *PlanDriftError
+	if !errors.As(err, &driftErr) {
+		t.Fatalf("ApplySyncPlan error = %v, want *PlanDriftError", err)
+	}
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("drift error should be ErrConflict")
+	}
+	if !reflect.DeepEqual(driftErr.Names, []string{"existing"}) {
+		t.Errorf("drifted names = %v, want [existing]", driftErr.Names)
+	}
+	if result != nil {
+		t.Errorf("result = %+v, want nil", result)
+	}
+
+	// 整个计划被拒绝：没有创建 fresh，existing 保持修改后的状态，也没有写入同步记录
+	if _, err := syncSvc.alertStore.GetByName(ctx, "fresh"); err == nil {
+		t.Errorf("fresh was created although the plan was rejected")
+	}
+	current, err := syncSvc.alertStore.GetByName(ctx, "existing")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	if current.DisplayName != "changed after plan" || tea.Int32Value(current.Configuration.Threshold) != 1 {
+		t.Errorf("existing was modified by rejected plan: display_name=%q threshold=%d",
+			current.DisplayName, tea.Int32Value(current.Configuration.Threshold))
+	}
+	runs, err := syncSvc.alertStore.ListSyncRuns(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListSyncRuns: %v", err)
+	}
+	if len(runs) != 0 {
+		t.Errorf("sync runs = %d, want 0", len(runs))
+	}
+}
+
+func TestApplySyncPlanRejectsSLSDrift(t *testing.T) {
+	syncSvc, sls, ctx := planFixture(t)
+
+	plan, err := syncSvc.PlanSLSToDatabase(ctx)
+	if err != nil {
+		t.Fatalf("PlanSLSToDatabase: %v", err)
+	}
+
+	fresh := newTestAlert("fresh")
+	fresh.Configuration.Threshold = tea.Int32(9)
+	sls.set(fresh)
+
+	if _, err := syncSvc.ApplySyncPlan(ctx, plan); !errors.As(err, new(*PlanDriftError)) {
+		t.Fatalf("ApplySyncPlan error = %v, want *PlanDriftError", err)
+	}
+	if _, err := syncSvc.alertStore.GetByName(ctx, "fresh"); err == nil {
+		t.Errorf("fresh was created although the plan was rejected")
+	}
+}
+
+func TestApplySyncPlanIgnoresSyncBookkeeping(t *testing.T) {
+	syncSvc, _, ctx := planFixture(t)
+
+	plan, err := syncSvc.PlanSLSToDatabase(ctx)
+	if err != nil {
+		t.Fatalf("PlanSLSToDatabase: %v", err)
+	}
+
+	// 只有同步时间和内容哈希变化，内容没有变化，不算漂移
+	existing, err := syncSvc.alertStore.GetByName(ctx, "existing")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	if err := syncSvc.alertStore.MarkSynced(ctx, "existing", SyncDirectionSLSToDB, time.Now()); err != nil {
+		t.Fatalf("MarkSynced: %v", err)
+	}
+	if err := syncSvc.alertStore.SetContentHash(ctx, existing.ID, "unrelated"); err != nil {
+		t.Fatalf("SetContentHash: %v", err)
+	}
+
+	result, err := syncSvc.ApplySyncPlan(ctx, plan)
+	if err != nil {
+		t.Fatalf("ApplySyncPlan: %v", err)
+	}
+	if result.Created != 1 || result.Updated != 1 {
+		t.Errorf("created=%d updated=%d, want 1 and 1", result.Created, result.Updated)
+	}
+}
+
+func TestApplySyncPlanRejectsTamperedPlan(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(t *testing.T, syncSvc *syncService, plan *SyncPlan)
+	}{
+		{
+			name: "changed action",
+			tamper: func(t *testing.T, syncSvc *syncService, plan *SyncPlan) {
+				plan.Items[0].Action = PlanActionSkip
+			},
+		},
+		{
+			name: "recomputed without the secret",
+			tamper: func(t *testing.T, syncSvc *syncService, plan *SyncPlan) {
+				plan.Items[0].Action = PlanActionSkip
+				checksum, err := plan.computeChecksum([]byte("guessed"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				plan.Checksum = checksum
+			},
+		},
+		{
+			name: "signed by another key",
+			tamper: func(t *testing.T, syncSvc *syncService, plan *SyncPlan) {
+				syncSvc.planKey = newPlanKey("")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			syncSvc, _, ctx := planFixture(t)
+			plan, err := syncSvc.PlanSLSToDatabase(ctx)
+			if err != nil {
+				t.Fatalf("PlanSLSToDatabase: %v", err)
+			}
+
+			tt.tamper(t, syncSvc, plan)
+			if _, err := syncSvc.ApplySyncPlan(ctx, plan); !errors.Is(err, ErrPlanChecksumMismatch) {
+				t.Fatalf("ApplySyncPlan error = %v, want ErrPlanChecksumMismatch", err)
+			}
+		})
+	}
+}
+
+func TestNewPlanKey(t *testing.T) {
+	if string(newPlanKey("secret")) != "secret" {
+		t.Errorf("configured secret should be used as the key")
+	}
+	if a, b := newPlanKey(""), newPlanKey(""); len(a) != 32 || reflect.DeepEqual(a, b) {
+		t.Errorf("random keys should be 32 bytes and distinct")
+	}
+}
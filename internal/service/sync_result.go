@@ -0,0 +1,146 @@
+package service
+
+import (
+	"sort"
+	"sync"
+)
+
+// 同步方向
+const (
+	SyncDirectionSLSToDB = "sls_to_db"
+	SyncDirectionDBToSLS = "db_to_sls"
+)
+
+// 单个 Alert 的同步动作
+const (
+	SyncActionCreated = "created"
+	SyncActionUpdated = "updated"
+	SyncActionSkipped = "skipped"
+	SyncActionFailed  = "failed"
+)
+
+// AlertSyncResult 单个 Alert 的同步结果
+type AlertSyncResult struct {
+	Name   string   `json:"name"`
+	Action string   `json:"action"`
+	Fields []string `json:"fields,omitempty"` // 实际推送的字段，仅在部分更新时记录
+	Reason string   `json:"reason,omitempty"` // 跳过原因，如 Alert 处于冻结期
+	Error  string   `json:"error,omitempty"`
+	// ErrorKind SLS 错误分类（auth、not_found、throttled、invalid），未分类时为空
+	ErrorKind string `json:"error_kind,omitempty"`
+}
+
+// SyncResult 同步执行结果
+//
+// 并发模型：同步过程中可能有多个 goroutine 同时处理不同的 Alert，
+// 所有写入都必须通过 Record* 方法完成，由内部互斥锁串行化计数器和
+// Alerts 切片的修改；调用方需在所有 goroutine 结束后（如 WaitGroup.Wait）
+// 再读取字段或序列化结果。
+type SyncResult struct {
+	mu sync.Mutex
+
+	Direction string            `json:"direction"`
+	Total     int               `json:"total"`
+	Created   int               `json:"created"`
+	Updated   int               `json:"updated"`
+	Skipped   int               `json:"skipped"`
+	Failed    int               `json:"failed"`
+	LastError string            `json:"last_error,omitempty"`
+	TimedOut  bool              `json:"timed_out"`
+	Alerts    []AlertSyncResult `json:"alerts"`
+	Warnings  []Warning         `json:"warnings,omitempty"`
+}
+
+// NewSyncResult 创建新的 SyncResult 实例
+func NewSyncResult(direction string) *SyncResult {
+	return &SyncResult{
+		Direction: direction,
+		Alerts:    []AlertSyncResult{},
+	}
+}
+
+// RecordCreated 记录一个已创建的 Alert
+func (r *SyncResult) RecordCreated(name string) {
+	r.record(AlertSyncResult{Name: name, Action: SyncActionCreated})
+}
+
+// RecordUpdated 记录一个已更新的 Alert
+func (r *SyncResult) RecordUpdated(name string) {
+	r.record(AlertSyncResult{Name: name, Action: SyncActionUpdated})
+}
+
+// RecordUpdatedFields 记录一个已部分更新的 Alert 及实际推送的字段
+func (r *SyncResult) RecordUpdatedFields(name string, fields []string) {
+	r.record(AlertSyncResult{Name: name, Action: SyncActionUpdated, Fields: fields})
+}
+
+// RecordSkipped 记录一个无需变更的 Alert
+func (r *SyncResult) RecordSkipped(name string) {
+	r.record(AlertSyncResult{Name: name, Action: SyncActionSkipped})
+}
+
+// RecordSkippedReason 记录一个因特定原因被跳过的 Alert
+func (r *SyncResult) RecordSkippedReason(name, reason string) {
+	r.record(AlertSyncResult{Name: name, Action: SyncActionSkipped, Reason: reason})
+}
+
+// RecordFailed 记录一个同步失败的 Alert
+func (r *SyncResult) RecordFailed(name string, err error) {
+	r.record(AlertSyncResult{Name: name, Action: SyncActionFailed, Error: err.Error(), ErrorKind: SLSErrorKind(err)})
+}
+
+// RecordWarnings 记录同步过程中产生的警告
+func (r *SyncResult) RecordWarnings(warnings ...Warning) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Warnings = append(r.Warnings, warnings...)
+}
+
+// MarkTimedOut 标记同步因超过最长执行时间而中止，已处理的结果保持不变
+func (r *SyncResult) MarkTimedOut() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.TimedOut = true
+}
+
+// record 在锁保护下更新计数器和明细
+func (r *SyncResult) record(item AlertSyncResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Total++
+	switch item.Action {
+	case SyncActionCreated:
+		r.Created++
+	case SyncActionUpdated:
+		r.Updated++
+	case SyncActionSkipped:
+		r.Skipped++
+	case SyncActionFailed:
+		r.Failed++
+		r.LastError = item.Error
+	}
+	r.Alerts = append(r.Alerts, item)
+}
+
+// orderAlerts 按 names 中的顺序稳定排序明细，不在 names 中的条目排在最后
+func (r *SyncResult) orderAlerts(names []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		index[name] = i
+	}
+	position := func(name string) int {
+		if i, ok := index[name]; ok {
+			return i
+		}
+		return len(names)
+	}
+	sort.SliceStable(r.Alerts, func(i, j int) bool {
+		return position(r.Alerts[i].Name) < position(r.Alerts[j].Name)
+	})
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSyncResultConcurrentRecord(t *testing.T) {
+	const perAction = 50
+
+	result := NewSyncResult(SyncDirectionSLSToDB)
+	record := []func(name string){
+		result.RecordCreated,
+		result.RecordUpdated,
+		func(name string) { result.RecordUpdatedFields(name, []string{"configuration"}) },
+		result.RecordSkipped,
+		func(name string) { result.RecordSkippedReason(name, "frozen") },
+		func(name string) { result.RecordFailed(name, errors.New("boom")) },
+	}
+
+	var wg sync.WaitGroup
+	for i, fn := range record {
+		for j := 0; j < perAction; j++ {
+			wg.Add(1)
+			go func(fn func(string), name string) {
+				defer wg.Done()
+				fn(name)
+				result.RecordWarnings(Warning{Field: name})
+			}(fn, fmt.Sprintf("alert-%d-%d", i, j))
+		}
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		result.MarkTimedOut()
+	}()
+	wg.Wait()
+
+	total := perAction * len(record)
+	if result.Total != total || len(result.Alerts) != total || len(result.Warnings) != total {
+		t.Fatalf("total=%d alerts=%d warnings=%d, want %d", result.Total, len(result.Alerts), len(result.Warnings), total)
+	}
+	counts := map[string]int{"created": result.Created, "updated": result.Updated, "skipped": result.Skipped, "failed": result.Failed}
+	want := map[string]int{"created": perAction, "updated": 2 * perAction, "skipped": 2 * perAction, "failed": perAction}
+	for action, n := range want {
+		if counts[action] != n {
+			t.Errorf("%s = %d, want %d", action, counts[action], n)
+		}
+	}
+	if result.LastError != "boom" || !result.TimedOut {
+		t.Errorf("last_error=%q timed_out=%v", result.LastError, result.TimedOut)
+	}
+}
+
+func TestSyncResultOrderAlerts(t *testing.T) {
+	tests := []struct {
+		name     string
+		recorded []string
+		order    []string
+		want     []string
+	}{
+		{name: "reorders by names", recorded: []string{"c", "a", "b"}, order: []string{"a", "b", "c"}, want: []string{"a", "b", "c"}},
+		{name: "unknown names last, stable", recorded: []string{"x", "b", "y", "a"}, order: []string{"a", "b"}, want: []string{"a", "b", "x", "y"}},
+		{name: "empty order keeps recorded order", recorded: []string{"b", "a"}, want: []string{"b", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewSyncResult(SyncDirectionSLSToDB)
+			for _, name := range tt.recorded {
+				result.RecordSkipped(name)
+			}
+			result.orderAlerts(tt.order)
+
+			got := make([]string, len(result.Alerts))
+			for i, alert := range result.Alerts {
+				got[i] = alert.Name
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/pkg/logger"
+)
+
+// SyncScheduler 后台按固定间隔执行 SLS 到数据库的同步
+type SyncScheduler interface {
+	Start(ctx context.Context)
+	Wait()
+}
+
+// syncScheduler 定时同步实现，同一时间最多只有一次同步在执行
+type syncScheduler struct {
+	syncService SyncService
+	interval    time.Duration
+	running     atomic.Bool
+	wg          sync.WaitGroup // 正在执行的同步，只在调度循环中 Add
+	done        chan struct{}  // 调度循环退出后关闭
+}
+
+// NewSyncScheduler 创建新的 SyncScheduler 实例，interval 必须大于 0
+func NewSyncScheduler(syncService SyncService, interval time.Duration) SyncScheduler {
+	return &syncScheduler{
+		syncService: syncService,
+		interval:    interval,
+		done:        make(chan struct{}),
+	}
+}
+
+// Start 在后台每隔 interval 执行一次同步（启动时不立即执行），直到 ctx 被取消；
+// 上一次同步仍在执行时跳过本次
+func (j *syncScheduler) Start(ctx context.Context) {
+	log.Printf("Scheduled sync enabled, interval %s", j.interval)
+
+	go func() {
+		defer close(j.done)
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !j.running.CompareAndSwap(false, true) {
+					log.Println("Scheduled sync skipped: previous run is still in progress")
+					continue
+				}
+				j.wg.Add(1)
+				go func() {
+					defer j.wg.Done()
+					defer j.running.Store(false)
+					j.run(ctx)
+				}()
+			}
+		}
+	}()
+}
+
+// Wait 在 Start 的 ctx 被取消后等待调度循环退出和正在执行的同步结束，用于退出前让被取消的同步写完执行记录
+func (j *syncScheduler) Wait() {
+	<-j.done
+	j.wg.Wait()
+}
+
+// run 执行一次同步，每次使用独立的可取消上下文和请求 ID，失败时只记录日志，等待下一个间隔
+func (j *syncScheduler) run(ctx context.Context) {
+	runCtx, cancel := context.WithCancel(logger.WithRequestID(ctx, logger.NewRequestID()))
+	defer cancel()
+
+	logger.Printf(runCtx, "Scheduled sync started")
+	result, err := j.syncService.SyncSLSToDatabase(runCtx)
+	if err != nil {
+		logger.Printf(runCtx, "Scheduled sync failed: %v", err)
+		return
+	}
+	logger.Printf(runCtx, "Scheduled sync finished. Total: %d, Created: %d, Updated: %d, Skipped: %d, Failed: %d",
+		result.Total, result.Created, result.Updated, result.Skipped, result.Failed)
+}
@@ -2,18 +2,35 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"reflect"
+	"sync"
+	"time"
 
+	"github.com/Ghostbaby/sls-migrate/internal/config"
 	"github.com/Ghostbaby/sls-migrate/internal/models"
 	"github.com/Ghostbaby/sls-migrate/internal/store"
+	"github.com/Ghostbaby/sls-migrate/pkg/logger"
+	"github.com/Ghostbaby/sls-migrate/pkg/metrics"
 )
 
+// ErrSyncTimedOut 同步超过 SYNC_MAX_DURATION 被中止，结果中只包含已处理的部分
+var ErrSyncTimedOut = errors.New("sync exceeded maximum duration")
+
 // SyncService 同步服务接口
 type SyncService interface {
-	SyncSLSToDatabase(ctx context.Context) error
-	SyncDatabaseToSLS(ctx context.Context) error
+	SyncSLSToDatabase(ctx context.Context) (*SyncResult, error)
+	SyncDatabaseToSLS(ctx context.Context) (*SyncResult, error)
 	GetSyncStatus(ctx context.Context) (*SyncStatus, error)
+	GetSyncHistory(ctx context.Context, limit int) ([]models.SyncRun, error)
+	GetSyncLag(ctx context.Context) (*SyncLag, error)
+	DiffAlertWithSLS(ctx context.Context, name string) (*AlertSLSDiff, error)
+	DiffInventory(ctx context.Context) (*InventoryDiff, error)
+	Reconcile(ctx context.Context) (*ReconcileReport, error)
+	PlanSLSToDatabase(ctx context.Context) (*SyncPlan, error)
+	ApplySyncPlan(ctx context.Context, plan *SyncPlan) (*SyncResult, error)
+	WithSLSService(slsService SLSService) SyncService
 }
 
 // SyncStatus 同步状态
@@ -29,133 +46,295 @@ type SyncStatus struct {
 
 // syncService 同步服务实现
 type syncService struct {
-	slsService   SLSService
-	alertStore   store.AlertStore
-	alertService AlertService
+	slsService     SLSService
+	alertStore     store.AlertStore
+	alertService   AlertService
+	queryValidator QueryValidator
+	syncConfig     *config.SyncConfig
+	notifier       *WebhookNotifier
+	planKey        []byte // 同步计划签名密钥
 }
 
 // NewSyncService 创建新的 SyncService 实例
-func NewSyncService(slsService SLSService, alertStore store.AlertStore, alertService AlertService) SyncService {
+func NewSyncService(slsService SLSService, alertStore store.AlertStore, alertService AlertService, syncConfig *config.SyncConfig) SyncService {
+	if syncConfig == nil {
+		syncConfig = &config.SyncConfig{}
+	}
+
 	return &syncService{
-		slsService:   slsService,
-		alertStore:   alertStore,
-		alertService: alertService,
+		slsService:     slsService,
+		alertStore:     alertStore,
+		alertService:   alertService,
+		queryValidator: NewLenientQueryValidator(),
+		syncConfig:     syncConfig,
+		notifier:       NewWebhookNotifier(syncConfig.WebhookURL, syncConfig.WebhookTimeout),
+		planKey:        newPlanKey(syncConfig.PlanSecret),
 	}
 }
 
-// SyncSLSToDatabase 从阿里云 SLS 同步 Alert 规则到本地数据库
-func (s *syncService) SyncSLSToDatabase(ctx context.Context) error {
-	log.Println("Starting SLS to Database sync...")
+// WithSLSService 返回使用指定 SLSService（如另一个 Project）的 SyncService，数据库和其余配置不变
+func (s *syncService) WithSLSService(slsService SLSService) SyncService {
+	scoped := *s
+	scoped.slsService = slsService
+	return &scoped
+}
+
+// SyncSLSToDatabase 从阿里云 SLS 同步 Alert 规则到本地数据库，结束时写入同步执行记录
+func (s *syncService) SyncSLSToDatabase(ctx context.Context) (*SyncResult, error) {
+	started := time.Now()
+	result, err := s.syncSLSToDatabase(ctx)
+	s.finishSync(ctx, SyncDirectionSLSToDB, started, result, err)
+	return result, err
+}
+
+// syncSLSToDatabase 执行 SLS 到数据库的同步
+func (s *syncService) syncSLSToDatabase(ctx context.Context) (*SyncResult, error) {
+	logger.Printf(ctx, "Starting SLS to Database sync...")
+
+	ctx, cancel := s.withMaxDuration(ctx)
+	defer cancel()
 
 	// 获取 SLS 中的所有 alerts
 	slsAlerts, err := s.slsService.GetAlerts(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get alerts from SLS: %w", err)
+		return nil, fmt.Errorf("failed to get alerts from SLS: %w", err)
 	}
 
-	log.Printf("Found %d alerts in SLS", len(slsAlerts))
-
-	var syncedCount, failedCount, updatedCount, createdCount int
-	var lastError string
+	logger.Printf(ctx, "Found %d alerts in SLS", len(slsAlerts))
+	metrics.SetAlertCount(metrics.SourceSLS, len(slsAlerts))
+
+	result := NewSyncResult(SyncDirectionSLSToDB)
+
+	// 有限的 worker 并发处理，每个 Alert 只由一个 worker 处理，结果通过 SyncResult 的锁汇总
+	workers := s.syncConcurrency(len(slsAlerts))
+	jobs := make(chan *models.Alert)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for slsAlert := range jobs {
+				s.syncSLSAlert(ctx, slsAlert, result)
+			}
+		}()
+	}
 
 	for _, slsAlert := range slsAlerts {
-		// 检查是否已存在
-		existingAlert, err := s.alertStore.GetByName(ctx, slsAlert.Name)
-		if err == nil && existingAlert != nil {
-			// 检查是否需要更新（比较关键字段）
-			if s.needsUpdate(existingAlert, slsAlert) {
-				// 更新现有记录
-				slsAlert.ID = existingAlert.ID
-				if err := s.alertService.UpdateAlert(ctx, slsAlert); err != nil {
-					log.Printf("Failed to update alert %s: %v", slsAlert.Name, err)
-					failedCount++
-					lastError = err.Error()
-					continue
-				}
-				log.Printf("Updated alert: %s", slsAlert.Name)
-				updatedCount++
-			} else {
-				log.Printf("Alert %s is up to date, skipping", slsAlert.Name)
+		if s.stopIfTimedOut(ctx, result) {
+			break
+		}
+		jobs <- slsAlert
+	}
+	close(jobs)
+	wg.Wait()
+
+	// 并发时完成顺序不确定，按 SLS 返回的顺序输出明细
+	names := make([]string, len(slsAlerts))
+	for i, slsAlert := range slsAlerts {
+		names[i] = slsAlert.Name
+	}
+	result.orderAlerts(names)
+
+	logger.Printf(ctx, "Sync completed. Total: %d, Created: %d, Updated: %d, Skipped: %d, Failed: %d",
+		result.Total, result.Created, result.Updated, result.Skipped, result.Failed)
+
+	if result.TimedOut {
+		return result, fmt.Errorf("%w: processed %d of %d alerts", ErrSyncTimedOut, result.Total, len(slsAlerts))
+	}
+
+	if result.Failed > 0 {
+		return result, fmt.Errorf("sync completed with %d failures. Last error: %s", result.Failed, result.LastError)
+	}
+
+	return result, nil
+}
+
+// syncSLSAlert 将单个 SLS Alert 同步到数据库（创建、更新或跳过），结果记录到 result，可并发调用
+func (s *syncService) syncSLSAlert(ctx context.Context, slsAlert *models.Alert, result *SyncResult) {
+	// 检查是否已存在
+	existingAlert, err := s.alertStore.GetByName(ctx, slsAlert.Name)
+	if err == nil && existingAlert != nil {
+		// 冻结期内不覆盖本地记录
+		if frozenErr := checkNotFrozen(existingAlert); frozenErr != nil {
+			logger.Printf(ctx, "Alert %s is frozen, skipping", slsAlert.Name)
+			result.RecordSkippedReason(slsAlert.Name, frozenErr.Error())
+			return
+		}
+
+		// 检查是否需要更新（比较关键字段）
+		if s.needsUpdate(existingAlert, slsAlert) {
+			// 更新现有记录
+			slsAlert.ID = existingAlert.ID
+			if err := s.alertService.UpdateAlertSections(ctx, slsAlert, s.updateSections(existingAlert, slsAlert)); err != nil {
+				logger.Printf(ctx, "Failed to update alert %s: %v", slsAlert.Name, err)
+				result.RecordFailed(slsAlert.Name, err)
+				return
 			}
+			s.backfillCreatedAt(ctx, slsAlert)
+			logger.Printf(ctx, "Updated alert: %s", slsAlert.Name)
+			result.RecordUpdated(slsAlert.Name)
+			s.markSynced(ctx, slsAlert.Name, result.Direction)
+			s.recordContentHash(ctx, existingAlert.ID, slsAlert)
 		} else {
-			// 创建新记录
-			if err := s.alertService.CreateAlert(ctx, slsAlert); err != nil {
-				log.Printf("Failed to create alert %s: %v", slsAlert.Name, err)
-				failedCount++
-				lastError = err.Error()
-				continue
-			}
-			log.Printf("Created alert: %s", slsAlert.Name)
-			createdCount++
+			logger.Printf(ctx, "Alert %s is up to date, skipping", slsAlert.Name)
+			result.RecordSkipped(slsAlert.Name)
+			s.markSynced(ctx, slsAlert.Name, result.Direction)
+			s.recordContentHash(ctx, existingAlert.ID, slsAlert)
+		}
+	} else {
+		// 创建新记录
+		if err := s.alertService.CreateAlert(withSLSImport(ctx), slsAlert); err != nil {
+			logger.Printf(ctx, "Failed to create alert %s: %v", slsAlert.Name, err)
+			result.RecordFailed(slsAlert.Name, err)
+			return
 		}
-		syncedCount++
+		s.backfillCreatedAt(ctx, slsAlert)
+		logger.Printf(ctx, "Created alert: %s", slsAlert.Name)
+		result.RecordCreated(slsAlert.Name)
+		s.markSynced(ctx, slsAlert.Name, result.Direction)
+		s.recordContentHash(ctx, slsAlert.ID, slsAlert)
 	}
+}
+
+// observeSync 将同步结果计数、耗时和数据库中的 Alert 数量写入指标，统计数量失败只记录日志
+func (s *syncService) observeSync(ctx context.Context, result *SyncResult, duration time.Duration) {
+	metrics.ObserveSync(result.Direction, result.Created, result.Updated, result.Skipped, result.Failed, duration)
 
-	log.Printf("Sync completed. Total: %d, Created: %d, Updated: %d, Skipped: %d, Failed: %d",
-		syncedCount, createdCount, updatedCount, syncedCount-createdCount-updatedCount, failedCount)
+	// 同步可能已超时，数量统计使用独立的上下文
+	count, err := s.alertStore.Count(context.WithoutCancel(ctx))
+	if err != nil {
+		logger.Printf(ctx, "Failed to count database alerts for metrics: %v", err)
+		return
+	}
+	metrics.SetAlertCount(metrics.SourceDatabase, int(count))
+}
 
-	if failedCount > 0 {
-		return fmt.Errorf("sync completed with %d failures. Last error: %s", failedCount, lastError)
+// syncConcurrency 返回 SLS→数据库同步的 worker 数量：SYNC_CONCURRENCY 未设置或小于 1 时为 1，且不超过 Alert 数量
+func (s *syncService) syncConcurrency(total int) int {
+	workers := s.syncConfig.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if total > 0 && workers > total {
+		workers = total
 	}
+	return workers
+}
 
-	return nil
+// SyncDatabaseToSLS 从本地数据库同步 Alert 规则到阿里云 SLS，结束时写入同步执行记录
+func (s *syncService) SyncDatabaseToSLS(ctx context.Context) (*SyncResult, error) {
+	started := time.Now()
+	result, err := s.syncDatabaseToSLS(ctx)
+	s.finishSync(ctx, SyncDirectionDBToSLS, started, result, err)
+	return result, err
 }
 
-// SyncDatabaseToSLS 从本地数据库同步 Alert 规则到阿里云 SLS
-func (s *syncService) SyncDatabaseToSLS(ctx context.Context) error {
-	log.Println("Starting Database to SLS sync...")
+// syncDatabaseToSLS 执行数据库到 SLS 的同步
+func (s *syncService) syncDatabaseToSLS(ctx context.Context) (*SyncResult, error) {
+	logger.Printf(ctx, "Starting Database to SLS sync...")
+
+	ctx, cancel := s.withMaxDuration(ctx)
+	defer cancel()
 
 	// 获取数据库中的所有 alerts
-	dbAlerts, _, err := s.alertStore.List(ctx, 0, 1000) // 获取所有记录
+	dbAlerts, _, err := s.alertStore.List(ctx, 0, 1000, false) // 获取所有记录
 	if err != nil {
-		return fmt.Errorf("failed to get alerts from database: %w", err)
+		return nil, fmt.Errorf("failed to get alerts from database: %w", err)
 	}
 
-	log.Printf("Found %d alerts in database", len(dbAlerts))
+	logger.Printf(ctx, "Found %d alerts in database", len(dbAlerts))
+
+	// 一次性读取 SLS 中对应的 Alert，避免每个 Alert 都重新列出一遍
+	names := make([]string, len(dbAlerts))
+	for i, dbAlert := range dbAlerts {
+		names[i] = dbAlert.Name
+	}
+	existingSLSAlerts, err := s.slsService.GetAlertsByNames(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts from SLS: %w", err)
+	}
 
-	var syncedCount, failedCount int
-	var lastError string
+	result := NewSyncResult(SyncDirectionDBToSLS)
 
 	for _, dbAlert := range dbAlerts {
+		if s.stopIfTimedOut(ctx, result) {
+			break
+		}
+
+		// 冻结期内不推送
+		if frozenErr := checkNotFrozen(dbAlert); frozenErr != nil {
+			logger.Printf(ctx, "Alert %s is frozen, skipping push", dbAlert.Name)
+			result.RecordSkippedReason(dbAlert.Name, frozenErr.Error())
+			continue
+		}
+
+		// 推送前检查查询语句和分组字段，疑似错误只记录警告不阻止推送
+		warnings := checkAlertQueries(s.queryValidator, dbAlert)
+		warnings = append(warnings, checkGroupFields(dbAlert)...)
+		if len(warnings) > 0 {
+			for _, warning := range warnings {
+				logger.Printf(ctx, "Validation warning for alert %s (%s): %s", dbAlert.Name, warning.Field, warning.Message)
+			}
+			result.RecordWarnings(warnings...)
+		}
+
 		// 检查 SLS 中是否已存在
-		existingSLSAlert, err := s.slsService.GetAlertByName(ctx, dbAlert.Name)
-		if err == nil && existingSLSAlert != nil {
-			// 更新现有的 SLS Alert
-			if err := s.slsService.UpdateAlert(ctx, dbAlert); err != nil {
-				log.Printf("Failed to update alert %s in SLS: %v", dbAlert.Name, err)
-				failedCount++
-				lastError = err.Error()
+		if existingSLSAlert, ok := existingSLSAlerts[dbAlert.Name]; ok {
+			// 只推送与 SLS 现有规则不同的字段
+			fields, warnings, err := s.slsService.PatchAlert(ctx, dbAlert, existingSLSAlert)
+			s.recordConvertWarnings(ctx, result, warnings)
+			if err != nil {
+				logger.Printf(ctx, "Failed to update alert %s in SLS: %v", dbAlert.Name, err)
+				result.RecordFailed(dbAlert.Name, err)
 				continue
 			}
-			log.Printf("Updated alert in SLS: %s", dbAlert.Name)
+			if len(fields) == 0 {
+				logger.Printf(ctx, "Alert %s is up to date in SLS, skipping", dbAlert.Name)
+				result.RecordSkipped(dbAlert.Name)
+			} else {
+				logger.Printf(ctx, "Updated alert in SLS: %s (fields: %v)", dbAlert.Name, fields)
+				result.RecordUpdatedFields(dbAlert.Name, fields)
+			}
+			s.markSynced(ctx, dbAlert.Name, result.Direction)
 		} else {
-			// 创建新的 SLS Alert
-			if err := s.slsService.CreateAlert(ctx, dbAlert); err != nil {
-				log.Printf("Failed to create alert %s in SLS: %v", dbAlert.Name, err)
-				failedCount++
-				lastError = err.Error()
+			// 创建新的 SLS Alert，列出之后才在 SLS 中出现的同名 Alert 改为更新
+			updated, warnings, err := s.slsService.CreateOrUpdateAlert(ctx, dbAlert)
+			s.recordConvertWarnings(ctx, result, warnings)
+			if err != nil {
+				logger.Printf(ctx, "Failed to create alert %s in SLS: %v", dbAlert.Name, err)
+				result.RecordFailed(dbAlert.Name, err)
 				continue
 			}
-			log.Printf("Created alert in SLS: %s", dbAlert.Name)
+			if updated {
+				logger.Printf(ctx, "Updated alert in SLS: %s (already existed)", dbAlert.Name)
+				result.RecordUpdated(dbAlert.Name)
+			} else {
+				logger.Printf(ctx, "Created alert in SLS: %s", dbAlert.Name)
+				result.RecordCreated(dbAlert.Name)
+			}
+			s.markSynced(ctx, dbAlert.Name, result.Direction)
 		}
-		syncedCount++
 	}
 
-	log.Printf("Database to SLS sync completed. Synced: %d, Failed: %d", syncedCount, failedCount)
+	logger.Printf(ctx, "Database to SLS sync completed. Synced: %d, Failed: %d", result.Created+result.Updated, result.Failed)
+
+	if result.TimedOut {
+		return result, fmt.Errorf("%w: processed %d of %d alerts", ErrSyncTimedOut, result.Total, len(dbAlerts))
+	}
 
-	if failedCount > 0 {
-		return fmt.Errorf("sync completed with %d failures. Last error: %s", failedCount, lastError)
+	if result.Failed > 0 {
+		return result, fmt.Errorf("sync completed with %d failures. Last error: %s", result.Failed, result.LastError)
 	}
 
-	return nil
+	return result, nil
 }
 
 // GetSyncStatus 获取同步状态
 func (s *syncService) GetSyncStatus(ctx context.Context) (*SyncStatus, error) {
 	// 获取 SLS 中的 alert 数量
-	slsAlerts, err := s.slsService.GetAlerts(ctx)
+	slsAlerts, slsErr := s.slsService.GetAlerts(ctx)
 	slsCount := 0
-	if err == nil {
+	if slsErr == nil {
 		slsCount = len(slsAlerts)
 	}
 
@@ -171,9 +350,23 @@ func (s *syncService) GetSyncStatus(ctx context.Context) (*SyncStatus, error) {
 		Status:        "unknown",
 	}
 
+	// 最近一次同步的时间、计数和错误来自同步执行记录
+	lastRun, err := s.alertStore.LatestSyncRun(ctx)
 	if err != nil {
+		return nil, fmt.Errorf("failed to get last sync run: %w", err)
+	}
+	if lastRun != nil {
+		status.LastSyncTime = lastRun.FinishedAt.UTC().Format(time.RFC3339)
+		status.SyncedCount = lastRun.Created + lastRun.Updated + lastRun.Skipped
+		status.FailedCount = lastRun.Failed
+		if lastRun.Error != nil {
+			status.LastError = *lastRun.Error
+		}
+	}
+
+	if slsErr != nil {
 		status.Status = "sls_connection_failed"
-		status.LastError = err.Error()
+		status.LastError = slsErr.Error()
 	} else {
 		status.Status = "healthy"
 	}
@@ -181,18 +374,103 @@ func (s *syncService) GetSyncStatus(ctx context.Context) (*SyncStatus, error) {
 	return status, nil
 }
 
-// needsUpdate 检查是否需要更新 Alert
-func (s *syncService) needsUpdate(existing, new *models.Alert) bool {
-	// 比较关键字段，决定是否需要更新
-	if existing.LastModifiedTime == nil || new.LastModifiedTime == nil {
-		return true // 如果时间戳缺失，保守地选择更新
+// recordConvertWarnings 记录推送到 SLS 时的有损转换警告
+func (s *syncService) recordConvertWarnings(ctx context.Context, result *SyncResult, warnings []Warning) {
+	if len(warnings) == 0 {
+		return
+	}
+	for _, warning := range warnings {
+		logger.Printf(ctx, "Conversion warning for alert %s (%s): %s", warning.Alert, warning.Field, warning.Message)
+	}
+	result.RecordWarnings(warnings...)
+}
+
+// withMaxDuration 按 SYNC_MAX_DURATION 为一次同步设置截止时间，未配置时只返回可取消的 ctx
+func (s *syncService) withMaxDuration(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.syncConfig.MaxDuration > 0 {
+		return context.WithTimeout(ctx, s.syncConfig.MaxDuration)
+	}
+	return context.WithCancel(ctx)
+}
+
+// stopIfTimedOut 同步已超过截止时间时标记结果并返回 true
+func (s *syncService) stopIfTimedOut(ctx context.Context, result *SyncResult) bool {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return false
+	}
+
+	logger.Printf(ctx, "Sync exceeded maximum duration %s, stopping after %d alerts", s.syncConfig.MaxDuration, result.Total)
+	result.MarkTimedOut()
+	return true
+}
+
+// backfillCreatedAt 开启 PreserveCreatedAt 时使用 SLS 的创建时间覆盖 created_at，失败只记录日志
+func (s *syncService) backfillCreatedAt(ctx context.Context, alert *models.Alert) {
+	if !s.syncConfig.PreserveCreatedAt || alert.CreateTime == nil || *alert.CreateTime <= 0 || alert.ID == 0 {
+		return
 	}
 
-	// 比较最后修改时间
-	if *existing.LastModifiedTime != *new.LastModifiedTime {
+	if err := s.alertStore.SetCreatedAt(ctx, alert.ID, time.Unix(*alert.CreateTime, 0)); err != nil {
+		logger.Printf(ctx, "Failed to backfill created_at for alert %s: %v", alert.Name, err)
+	}
+}
+
+// markSynced 记录 Alert 的最后同步时间和方向，失败只记录日志不影响同步结果
+func (s *syncService) markSynced(ctx context.Context, name, direction string) {
+	if err := s.alertStore.MarkSynced(ctx, name, direction, time.Now()); err != nil {
+		logger.Printf(ctx, "Failed to record sync time for alert %s: %v", name, err)
+	}
+}
+
+// recordContentHash 保存本次同步的 SLS 侧内容哈希，失败只记录日志不影响同步结果
+func (s *syncService) recordContentHash(ctx context.Context, id uint, slsAlert *models.Alert) {
+	if slsAlert.ContentHash == nil || id == 0 {
+		return
+	}
+	if err := s.alertStore.SetContentHash(ctx, id, *slsAlert.ContentHash); err != nil {
+		logger.Printf(ctx, "Failed to record content hash for alert %s: %v", slsAlert.Name, err)
+	}
+}
+
+// updateSections 返回同步更新需要写入的分区：SLS 最后修改时间和内容哈希未变且嵌套配置相同时只有主记录字段可能不同，
+// 只更新 base 以避免重写嵌套配置表
+func (s *syncService) updateSections(existing, new *models.Alert) []string {
+	if existing.LastModifiedTime != nil && new.LastModifiedTime != nil &&
+		*existing.LastModifiedTime == *new.LastModifiedTime &&
+		!contentHashChanged(existing, new) &&
+		!nestedSectionsDiffer(existing, new) {
+		return []string{store.SectionBase}
+	}
+	return store.AllSections
+}
+
+// nestedSectionsDiffer 与 configurationDiffers 相同，但不比较主记录字段
+func nestedSectionsDiffer(existing, new *models.Alert) bool {
+	existingView, newView := newAlertDiffView(existing), newAlertDiffView(new)
+	existingView.DisplayName, existingView.Description, existingView.Status = newView.DisplayName, newView.Description, newView.Status
+	return !reflect.DeepEqual(existingView, newView)
+}
+
+// needsUpdate 检查是否需要更新 Alert。
+// SLS 侧内容哈希与上次同步时不同时直接更新；默认 SLS 最后修改时间缺失或变化时直接更新；时间戳未变时再比较主记录字段和完整配置，
+// 因为 SLS 的最后修改时间并不总是可靠。SYNC_DEEP_COMPARE 开启时完全忽略时间戳，只按内容判断
+func (s *syncService) needsUpdate(existing, new *models.Alert) bool {
+	// SLS 侧内容哈希变化时，即使最后修改时间没有变化也需要更新
+	if contentHashChanged(existing, new) {
 		return true
 	}
 
+	if !s.syncConfig.DeepCompare {
+		if existing.LastModifiedTime == nil || new.LastModifiedTime == nil {
+			return true // 如果时间戳缺失，保守地选择更新
+		}
+
+		// 比较最后修改时间
+		if *existing.LastModifiedTime != *new.LastModifiedTime {
+			return true
+		}
+	}
+
 	// 比较其他关键字段
 	if existing.DisplayName != new.DisplayName {
 		return true
@@ -213,5 +491,10 @@ func (s *syncService) needsUpdate(existing, new *models.Alert) bool {
 		return true
 	}
 
-	return false
+	return configurationDiffers(existing, new)
+}
+
+// configurationDiffers 比较与差异接口相同的字段：主记录字段、阈值、触发条件、严重程度、调度和有序的查询列表
+func configurationDiffers(existing, new *models.Alert) bool {
+	return !reflect.DeepEqual(newAlertDiffView(existing), newAlertDiffView(new))
 }
@@ -0,0 +1,700 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/internal/store"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+func TestSyncDatabaseToSLSCountsConcurrentCreateAsUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	// raced 在列出 SLS 之后才被并发创建，推送时创建返回已存在并改为更新
+	sls := newFakeSLS(t, newTestAlert("raced"))
+	sls.hidden["raced"] = true
+	syncSvc, _ := newTestSyncService(t, sls, nil)
+	for _, name := range []string{"raced", "new"} {
+		if err := syncSvc.alertService.CreateAlert(ctx, newTestAlert(name)); err != nil {
+			t.Fatalf("CreateAlert(%s): %v", name, err)
+		}
+	}
+
+	result, err := syncSvc.SyncDatabaseToSLS(ctx)
+	if err != nil {
+		t.Fatalf("SyncDatabaseToSLS: %v", err)
+	}
+	if result.Created != 1 || result.Updated != 1 {
+		t.Errorf("created=%d updated=%d, want 1 and 1", result.Created, result.Updated)
+	}
+
+	actions := map[string]string{}
+	for _, alert := range result.Alerts {
+		actions[alert.Name] = alert.Action
+	}
+	want := map[string]string{"raced": SyncActionUpdated, "new": SyncActionCreated}
+	if !reflect.DeepEqual(actions, want) {
+		t.Errorf("actions = %v, want %v", actions, want)
+	}
+	if !reflect.DeepEqual(sls.updated, []string{"raced"}) || !reflect.DeepEqual(sls.created, []string{"new"}) {
+		t.Errorf("SLS created=%v updated=%v", sls.created, sls.updated)
+	}
+}
+
+// parallelSyncFixture SLS 中有 n 个 Alert，其中偶数号已存在于数据库且阈值不同（需要更新），奇数号只在 SLS 中（需要创建）
+func parallelSyncFixture(t testing.TB, n, concurrency int) (*syncService, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	names := make([]string, n)
+	slsAlerts := make([]*models.Alert, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("alert-%03d", i)
+		slsAlerts[i] = newTestAlert(names[i])
+		slsAlerts[i].Configuration.Threshold = tea.Int32(5)
+	}
+
+	syncSvc, _ := newTestSyncService(t, newFakeSLS(t, slsAlerts...), &config.SyncConfig{Concurrency: concurrency})
+	for i := 0; i < n; i += 2 {
+		if err := syncSvc.alertService.CreateAlert(ctx, newTestAlert(names[i])); err != nil {
+			t.Fatalf("CreateAlert(%s): %v", names[i], err)
+		}
+	}
+	return syncSvc, names
+}
+
+// 使用 go test -race 运行时检查并发 worker 汇总结果没有数据竞争
+func TestSyncSLSToDatabaseParallel(t *testing.T) {
+	const n = 40
+	syncSvc, names := parallelSyncFixture(t, n, 8)
+
+	result, err := syncSvc.SyncSLSToDatabase(context.Background())
+	if err != nil {
+		t.Fatalf("SyncSLSToDatabase: %v", err)
+	}
+	if result.Total != n || result.Created != n/2 || result.Updated != n/2 || result.Failed != 0 {
+		t.Errorf("total=%d created=%d updated=%d failed=%d, want %d/%d/%d/0",
+			result.Total, result.Created, result.Updated, result.Failed, n, n/2, n/2)
+	}
+
+	// 明细按 SLS 返回的顺序输出
+	got := make([]string, len(result.Alerts))
+	for i, alert := range result.Alerts {
+		got[i] = alert.Name
+	}
+	if !reflect.DeepEqual(got, names) {
+		t.Errorf("alert order = %v, want %v", got, names)
+	}
+
+	for _, name := range names {
+		alert, err := syncSvc.alertStore.GetByName(context.Background(), name)
+		if err != nil {
+			t.Fatalf("GetByName(%s): %v", name, err)
+		}
+		if tea.Int32Value(alert.Configuration.Threshold) != 5 {
+			t.Errorf("%s threshold = %d, want 5", name, tea.Int32Value(alert.Configuration.Threshold))
+		}
+	}
+}
+
+// instrumentedStore 为 AlertStore 的读取增加固定延迟以模拟数据库往返，记录同时进行的读取数，
+// 并让 failNames 中的 Alert 创建失败
+type instrumentedStore struct {
+	store.AlertStore
+
+	delay     time.Duration
+	failNames map[string]bool
+	inFlight  int32
+	maxFlight int32
+}
+
+func (s *instrumentedStore) GetByName(ctx context.Context, name string) (*models.Alert, error) {
+	current := atomic.AddInt32(&s.inFlight, 1)
+	defer atomic.AddInt32(&s.inFlight, -1)
+	for {
+		max := atomic.LoadInt32(&s.maxFlight)
+		if current <= max || atomic.CompareAndSwapInt32(&s.maxFlight, max, current) {
+			break
+		}
+	}
+
+	time.Sleep(s.delay)
+	return s.AlertStore.GetByName(ctx, name)
+}
+
+func (s *instrumentedStore) CreateWithTransaction(ctx context.Context, alert *models.Alert) error {
+	if s.failNames[alert.Name] {
+		return fmt.Errorf("injected failure for %s", alert.Name)
+	}
+	return s.AlertStore.CreateWithTransaction(ctx, alert)
+}
+
+// newInstrumentedSyncService 创建数据库访问经过 instrumentedStore 的 SyncService，SLS 中有 n 个只在 SLS 中的 Alert
+func newInstrumentedSyncService(tb testing.TB, n, concurrency int, delay time.Duration, failNames ...string) (*syncService, *fakeSLS, *instrumentedStore) {
+	tb.Helper()
+
+	slsAlerts := make([]*models.Alert, n)
+	for i := range slsAlerts {
+		slsAlerts[i] = newTestAlert(fmt.Sprintf("alert-%03d", i))
+	}
+	sls := newFakeSLS(tb, slsAlerts...)
+
+	instrumented := &instrumentedStore{AlertStore: newTestStore(tb), delay: delay, failNames: map[string]bool{}}
+	for _, name := range failNames {
+		instrumented.failNames[name] = true
+	}
+	alertService := NewAlertService(instrumented, &config.DefaultSinkConfig{}, AlertStatusEnabled)
+	syncSvc := NewSyncService(sls, instrumented, alertService, &config.SyncConfig{Concurrency: concurrency}).(*syncService)
+	return syncSvc, sls, instrumented
+}
+
+func TestSyncSLSToDatabaseBoundedConcurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		wantMax     int32
+	}{
+		{name: "unset runs sequentially", concurrency: 0, wantMax: 1},
+		{name: "sequential", concurrency: 1, wantMax: 1},
+		{name: "four workers", concurrency: 4, wantMax: 4},
+		{name: "more workers than alerts", concurrency: 50, wantMax: 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			syncSvc, _, instrumented := newInstrumentedSyncService(t, 12, tt.concurrency, 5*time.Millisecond)
+
+			result, err := syncSvc.SyncSLSToDatabase(context.Background())
+			if err != nil {
+				t.Fatalf("SyncSLSToDatabase: %v", err)
+			}
+			if result.Created != 12 {
+				t.Errorf("created = %d, want 12", result.Created)
+			}
+			if got := atomic.LoadInt32(&instrumented.maxFlight); got > tt.wantMax {
+				t.Errorf("max concurrent store reads = %d, want at most %d", got, tt.wantMax)
+			}
+			if tt.wantMax > 1 && atomic.LoadInt32(&instrumented.maxFlight) < 2 {
+				t.Errorf("alerts were processed sequentially with SYNC_CONCURRENCY=%d", tt.concurrency)
+			}
+		})
+	}
+}
+
+func TestSyncSLSToDatabaseConcurrentFailure(t *testing.T) {
+	syncSvc, _, _ := newInstrumentedSyncService(t, 12, 4, time.Millisecond, "alert-007")
+
+	result, err := syncSvc.SyncSLSToDatabase(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "injected failure for alert-007") {
+		t.Fatalf("error = %v, want the last error from alert-007", err)
+	}
+	if result.Created != 11 || result.Failed != 1 || result.Total != 12 {
+		t.Errorf("created=%d failed=%d total=%d, want 11/1/12", result.Created, result.Failed, result.Total)
+	}
+	if result.Alerts[7].Name != "alert-007" || result.Alerts[7].Action != SyncActionFailed {
+		t.Errorf("alerts[7] = %+v, want alert-007 failed in SLS order", result.Alerts[7])
+	}
+}
+
+// BenchmarkSyncSLSToDatabase 比较顺序与并发同步：每次迭代修改 SLS 中所有 Alert 的阈值，使每个 Alert 都需要更新
+func BenchmarkSyncSLSToDatabase(b *testing.B) {
+	// 同步日志会淹没基准结果
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	for _, concurrency := range []int{1, 4} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			const n = 50
+			syncSvc, sls, _ := newInstrumentedSyncService(b, n, concurrency, time.Millisecond)
+			ctx := context.Background()
+			if _, err := syncSvc.SyncSLSToDatabase(ctx); err != nil {
+				b.Fatalf("initial sync: %v", err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				for j := 0; j < n; j++ {
+					alert := newTestAlert(fmt.Sprintf("alert-%03d", j))
+					alert.Configuration.Threshold = tea.Int32(int32(i + 2))
+					sls.set(alert)
+				}
+				b.StartTimer()
+
+				result, err := syncSvc.SyncSLSToDatabase(ctx)
+				if err != nil {
+					b.Fatalf("SyncSLSToDatabase: %v", err)
+				}
+				if result.Updated != n {
+					b.Fatalf("updated = %d, want %d", result.Updated, n)
+				}
+			}
+		})
+	}
+}
+
+func TestSyncRecordsLastSynced(t *testing.T) {
+	tests := []struct {
+		direction string
+		sync      func(s *syncService, ctx context.Context) (*SyncResult, error)
+	}{
+		{direction: SyncDirectionSLSToDB, sync: (*syncService).SyncSLSToDatabase},
+		{direction: SyncDirectionDBToSLS, sync: (*syncService).SyncDatabaseToSLS},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.direction, func(t *testing.T) {
+			ctx := context.Background()
+			syncSvc, alertStore := newTestSyncService(t, newFakeSLS(t, newTestAlert("synced")), nil)
+			if err := syncSvc.alertService.CreateAlert(ctx, newTestAlert("synced")); err != nil {
+				t.Fatalf("CreateAlert: %v", err)
+			}
+			before, err := alertStore.GetByName(ctx, "synced")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+			if before.LastSyncedAt != nil || before.LastSyncDirection != nil {
+				t.Fatalf("new alert already has sync info: %v %v", before.LastSyncedAt, before.LastSyncDirection)
+			}
+
+			start := time.Now().Add(-time.Second)
+			if _, err := tt.sync(syncSvc, ctx); err != nil {
+				t.Fatalf("sync: %v", err)
+			}
+
+			after, err := alertStore.GetByName(ctx, "synced")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+			if after.LastSyncedAt == nil || after.LastSyncedAt.Before(start) || after.LastSyncedAt.After(time.Now()) {
+				t.Errorf("last_synced_at = %v, want the sync time", after.LastSyncedAt)
+			}
+			if tea.StringValue(after.LastSyncDirection) != tt.direction {
+				t.Errorf("last_sync_direction = %q, want %q", tea.StringValue(after.LastSyncDirection), tt.direction)
+			}
+		})
+	}
+}
+
+func TestSyncDoesNotMarkFailedAlerts(t *testing.T) {
+	syncSvc, _, instrumented := newInstrumentedSyncService(t, 2, 1, 0, "alert-001")
+
+	if _, err := syncSvc.SyncSLSToDatabase(context.Background()); err == nil {
+		t.Fatalf("sync should report the injected failure")
+	}
+	ok, err := instrumented.GetByName(context.Background(), "alert-000")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	if ok.LastSyncedAt == nil {
+		t.Errorf("successfully synced alert has no last_synced_at")
+	}
+	if _, err := instrumented.GetByName(context.Background(), "alert-001"); err == nil {
+		t.Errorf("failed alert was persisted")
+	}
+}
+
+func TestSyncPreserveCreatedAt(t *testing.T) {
+	older, newer := newTestAlert("older"), newTestAlert("newer")
+	older.CreateTime = tea.Int64(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Unix())
+	newer.CreateTime = tea.Int64(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix())
+
+	tests := []struct {
+		name     string
+		preserve bool
+	}{
+		{name: "enabled", preserve: true},
+		{name: "disabled", preserve: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			// fakeSLS 按名称列出，newer 先于 older 导入，不回填时 created_at 的顺序与源环境相反
+			sls := newFakeSLS(t, older, newer)
+			syncSvc, alertStore := newTestSyncService(t, sls, &config.SyncConfig{PreserveCreatedAt: tt.preserve})
+
+			start := time.Now().Add(-time.Second)
+			if _, err := syncSvc.SyncSLSToDatabase(ctx); err != nil {
+				t.Fatalf("SyncSLSToDatabase: %v", err)
+			}
+
+			for _, source := range []*models.Alert{older, newer} {
+				imported, err := alertStore.GetByName(ctx, source.Name)
+				if err != nil {
+					t.Fatalf("GetByName(%s): %v", source.Name, err)
+				}
+				if tt.preserve {
+					if got := imported.CreatedAt.Unix(); got != *source.CreateTime {
+						t.Errorf("%s created_at = %v, want %v", source.Name, imported.CreatedAt, time.Unix(*source.CreateTime, 0))
+					}
+				} else if imported.CreatedAt.Before(start) {
+					t.Errorf("%s created_at = %v, want the import time", source.Name, imported.CreatedAt)
+				}
+			}
+
+			if !tt.preserve {
+				return
+			}
+			alerts, _, err := syncSvc.alertService.ListAlerts(ctx, 1, 10, false)
+			if err != nil {
+				t.Fatalf("ListAlerts: %v", err)
+			}
+			if got := alertNames(alerts); !reflect.DeepEqual(got, []string{"newer", "older"}) {
+				t.Errorf("created_at DESC order = %v, want [newer older]", got)
+			}
+		})
+	}
+}
+
+func TestSyncUpdateSections(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(slsAlert *models.Alert)
+		want   []string
+	}{
+		{
+			name:   "only base fields changed",
+			modify: func(slsAlert *models.Alert) { slsAlert.DisplayName = "renamed" },
+			want:   []string{store.SectionBase},
+		},
+		{
+			name:   "configuration changed",
+			modify: func(slsAlert *models.Alert) { slsAlert.Configuration.Threshold = tea.Int32(5) },
+			want:   store.AllSections,
+		},
+		{
+			name:   "last modified time changed",
+			modify: func(slsAlert *models.Alert) { slsAlert.LastModifiedTime = tea.Int64(200) },
+			want:   store.AllSections,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := newTestAlert("sections")
+			existing.LastModifiedTime = tea.Int64(100)
+			slsAlert := cloneAlert(t, existing)
+			tt.modify(slsAlert)
+
+			if got := (&syncService{}).updateSections(existing, slsAlert); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("updateSections = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncSkipsFrozenAlerts(t *testing.T) {
+	tests := []struct {
+		direction string
+		sync      func(s *syncService, ctx context.Context) (*SyncResult, error)
+	}{
+		{direction: SyncDirectionSLSToDB, sync: (*syncService).SyncSLSToDatabase},
+		{direction: SyncDirectionDBToSLS, sync: (*syncService).SyncDatabaseToSLS},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.direction, func(t *testing.T) {
+			ctx := context.Background()
+			// 两侧内容不同，未冻结时会被更新
+			slsAlert := newTestAlert("frozen")
+			slsAlert.Configuration.Threshold = tea.Int32(5)
+			sls := newFakeSLS(t, slsAlert)
+			syncSvc, alertStore := newTestSyncService(t, sls, &config.SyncConfig{DeepCompare: true})
+
+			if err := syncSvc.alertService.CreateAlert(ctx, newTestAlert("frozen")); err != nil {
+				t.Fatalf("CreateAlert: %v", err)
+			}
+			local, err := alertStore.GetByName(ctx, "frozen")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+			if _, err := syncSvc.alertService.FreezeAlert(ctx, local.ID, time.Now().Add(time.Hour).Unix()); err != nil {
+				t.Fatalf("FreezeAlert: %v", err)
+			}
+
+			result, err := tt.sync(syncSvc, ctx)
+			if err != nil {
+				t.Fatalf("sync: %v", err)
+			}
+			if result.Skipped != 1 || result.Updated != 0 || len(result.Alerts) != 1 {
+				t.Fatalf("result = %+v, want the frozen alert skipped", result)
+			}
+			if reason := result.Alerts[0].Reason; !strings.Contains(reason, "is frozen until") {
+				t.Errorf("skip reason = %q, want the freeze reason", reason)
+			}
+
+			if len(sls.updated) != 0 || len(sls.created) != 0 {
+				t.Errorf("frozen alert was pushed: created=%v updated=%v", sls.created, sls.updated)
+			}
+			current, err := alertStore.GetByName(ctx, "frozen")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+			if tea.Int32Value(current.Configuration.Threshold) != 1 {
+				t.Errorf("frozen alert threshold = %d, want unchanged 1", tea.Int32Value(current.Configuration.Threshold))
+			}
+		})
+	}
+}
+
+// slowSLS 每次推送前等待 delay，ctx 结束时立即返回 ctx 的错误
+type slowSLS struct {
+	*fakeSLS
+	delay time.Duration
+}
+
+func (s *slowSLS) CreateOrUpdateAlert(ctx context.Context, alert *models.Alert) (bool, []Warning, error) {
+	select {
+	case <-ctx.Done():
+		return false, nil, ctx.Err()
+	case <-time.After(s.delay):
+	}
+	return s.fakeSLS.CreateOrUpdateAlert(ctx, alert)
+}
+
+func TestSyncStopsAtMaxDuration(t *testing.T) {
+	const n = 20
+
+	tests := []struct {
+		direction string
+		setup     func(t *testing.T) (*syncService, func() int)
+		sync      func(s *syncService, ctx context.Context) (*SyncResult, error)
+	}{
+		{
+			direction: SyncDirectionSLSToDB,
+			// 每次读取数据库耗时 20ms，串行处理 20 个 Alert 需要 400ms
+			setup: func(t *testing.T) (*syncService, func() int) {
+				syncSvc, _, instrumented := newInstrumentedSyncService(t, n, 1, 20*time.Millisecond)
+				return syncSvc, func() int {
+					_, total, err := instrumented.List(context.Background(), 0, 100, false)
+					if err != nil {
+						t.Fatalf("List: %v", err)
+					}
+					return int(total)
+				}
+			},
+			sync: (*syncService).SyncSLSToDatabase,
+		},
+		{
+			direction: SyncDirectionDBToSLS,
+			// 每次推送耗时 20ms
+			setup: func(t *testing.T) (*syncService, func() int) {
+				sls := &slowSLS{fakeSLS: newFakeSLS(t), delay: 20 * time.Millisecond}
+				syncSvc, _ := newTestSyncService(t, sls, &config.SyncConfig{})
+				for i := 0; i < n; i++ {
+					if err := syncSvc.alertService.CreateAlert(context.Background(), newTestAlert(fmt.Sprintf("alert-%03d", i))); err != nil {
+						t.Fatalf("CreateAlert: %v", err)
+					}
+				}
+				return syncSvc, func() int {
+					sls.mu.Lock()
+					defer sls.mu.Unlock()
+					return len(sls.created)
+				}
+			},
+			sync: (*syncService).SyncDatabaseToSLS,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.direction, func(t *testing.T) {
+			syncSvc, persisted := tt.setup(t)
+			syncSvc.syncConfig.MaxDuration = 100 * time.Millisecond
+
+			start := time.Now()
+			result, err := tt.sync(syncSvc, context.Background())
+			elapsed := time.Since(start)
+
+			if !errors.Is(err, ErrSyncTimedOut) {
+				t.Fatalf("error = %v, want ErrSyncTimedOut", err)
+			}
+			if elapsed > time.Second {
+				t.Errorf("sync took %s, want it to stop near the 100ms deadline", elapsed)
+			}
+			if result == nil || !result.TimedOut {
+				t.Fatalf("result = %+v, want a partial result marked as timed out", result)
+			}
+			if result.Total == 0 || result.Total >= n {
+				t.Errorf("processed %d of %d alerts, want a partial run", result.Total, n)
+			}
+			// 截止前完成的部分已经保存并计入结果
+			if got := persisted(); got == 0 || got != result.Created {
+				t.Errorf("persisted = %d, result created = %d, want the same non-zero count", got, result.Created)
+			}
+
+			runs, err := syncSvc.alertStore.ListSyncRuns(context.Background(), 1)
+			if err != nil {
+				t.Fatalf("ListSyncRuns: %v", err)
+			}
+			if len(runs) != 1 || runs[0].Status != SyncRunStatusTimedOut || runs[0].Created != result.Created {
+				t.Errorf("sync runs = %+v, want one timed_out run with created=%d", runs, result.Created)
+			}
+		})
+	}
+}
+
+func TestNeedsUpdate(t *testing.T) {
+	tests := []struct {
+		name        string
+		deepCompare bool
+		modify      func(alert *models.Alert)
+		want        bool
+	}{
+		{name: "identical", modify: func(alert *models.Alert) {}},
+		{
+			name:   "only query SQL differs",
+			modify: func(alert *models.Alert) { alert.Queries[0].Query = "error | select count(*) as cnt" },
+			want:   true,
+		},
+		{
+			name:        "only query SQL differs with deep compare",
+			deepCompare: true,
+			modify:      func(alert *models.Alert) { alert.Queries[0].Query = "error | select count(*) as cnt" },
+			want:        true,
+		},
+		{
+			name:   "threshold differs",
+			modify: func(alert *models.Alert) { alert.Configuration.Threshold = tea.Int32(5) },
+			want:   true,
+		},
+		{
+			name:   "condition differs",
+			modify: func(alert *models.Alert) { alert.Configuration.ConditionConfig.Condition = tea.String("cnt > 1") },
+			want:   true,
+		},
+		{
+			name: "severity tier added",
+			modify: func(alert *models.Alert) {
+				alert.Configuration.SeverityConfigs = append(alert.Configuration.SeverityConfigs, models.SeverityConfiguration{
+					Severity: tea.Int32(8), EvalCondition: &models.ConditionConfiguration{Condition: tea.String("cnt > 100")},
+				})
+			},
+			want: true,
+		},
+		{
+			name:   "schedule interval differs",
+			modify: func(alert *models.Alert) { alert.Schedule.Interval = tea.String("5m") },
+			want:   true,
+		},
+		{
+			name: "query order differs",
+			modify: func(alert *models.Alert) {
+				alert.Queries = []models.AlertQuery{alert.Queries[1], alert.Queries[0]}
+			},
+			want: true,
+		},
+		{
+			name:   "timestamp differs",
+			modify: func(alert *models.Alert) { alert.LastModifiedTime = tea.Int64(2000) },
+			want:   true,
+		},
+		{
+			name:        "timestamp differs with deep compare",
+			deepCompare: true,
+			modify:      func(alert *models.Alert) { alert.LastModifiedTime = tea.Int64(2000) },
+		},
+		{
+			// SLS 没有返回最后修改时间（0 经 mapper 规范化为 nil）时无法判断，保守地更新
+			name:   "timestamp missing",
+			modify: func(alert *models.Alert) { alert.LastModifiedTime = nil },
+			want:   true,
+		},
+		{
+			name:        "timestamp missing with deep compare",
+			deepCompare: true,
+			modify:      func(alert *models.Alert) { alert.LastModifiedTime = nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			syncSvc, alertStore := newTestSyncService(t, newFakeSLS(t), &config.SyncConfig{DeepCompare: tt.deepCompare})
+
+			// 数据库中的 Alert 经过存取，未设置的布尔值读回为 false
+			alert := newTestAlert("compare")
+			alert.LastModifiedTime = tea.Int64(1000)
+			alert.Queries = append(alert.Queries, models.AlertQuery{Query: "* | select avg(latency) as lat", Store: tea.String("app-log"), StoreType: tea.String("log")})
+			if err := alertStore.CreateWithTransaction(ctx, cloneAlert(t, alert)); err != nil {
+				t.Fatalf("CreateWithTransaction: %v", err)
+			}
+			existing, err := alertStore.GetByName(ctx, "compare")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+
+			tt.modify(alert)
+			if got := syncSvc.needsUpdate(existing, alert); got != tt.want {
+				t.Errorf("needsUpdate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// hashedSLSAlert 返回带 SLS 最后修改时间和内容哈希的 Alert，与 convertSLSAlertToModel 的结果一致
+func hashedSLSAlert(t *testing.T, name, team string) *models.Alert {
+	t.Helper()
+
+	alert := newTestAlert(name)
+	alert.LastModifiedTime = tea.Int64(1700000000)
+	alert.Tags[0].TagValue = tea.String(team)
+	hash, err := alertContentHash(alert)
+	if err != nil {
+		t.Fatalf("alertContentHash: %v", err)
+	}
+	alert.ContentHash = &hash
+	return alert
+}
+
+func TestSyncContentChangeWithUnchangedTimestamp(t *testing.T) {
+	ctx := context.Background()
+	sls := newFakeSLS(t, hashedSLSAlert(t, "hashed", "ops"))
+	syncSvc, alertStore := newTestSyncService(t, sls, &config.SyncConfig{})
+
+	if _, err := syncSvc.SyncSLSToDatabase(ctx); err != nil {
+		t.Fatalf("initial SyncSLSToDatabase: %v", err)
+	}
+
+	// 标签不在字段比较范围内，SLS 也没有更新最后修改时间，只有内容哈希变化
+	changed := hashedSLSAlert(t, "hashed", "sre")
+	sls.set(changed)
+	result, err := syncSvc.SyncSLSToDatabase(ctx)
+	if err != nil {
+		t.Fatalf("SyncSLSToDatabase: %v", err)
+	}
+	if result.Updated != 1 || result.Skipped != 0 {
+		t.Errorf("updated=%d skipped=%d, want the content change applied", result.Updated, result.Skipped)
+	}
+	stored, err := alertStore.GetByName(ctx, "hashed")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	if len(stored.Tags) != 1 || tea.StringValue(stored.Tags[0].TagValue) != "sre" {
+		t.Errorf("stored tags = %+v, want team=sre", stored.Tags)
+	}
+	if tea.StringValue(stored.ContentHash) != tea.StringValue(changed.ContentHash) {
+		t.Errorf("stored content hash = %q, want the new SLS hash", tea.StringValue(stored.ContentHash))
+	}
+
+	// 内容和时间戳都没有变化时跳过
+	result, err = syncSvc.SyncSLSToDatabase(ctx)
+	if err != nil {
+		t.Fatalf("SyncSLSToDatabase: %v", err)
+	}
+	if result.Updated != 0 || result.Skipped != 1 {
+		t.Errorf("updated=%d skipped=%d, want the unchanged alert skipped", result.Updated, result.Skipped)
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/pkg/logger"
+)
+
+// defaultWebhookTimeout 未配置超时时单次 Webhook 请求的超时
+const defaultWebhookTimeout = 5 * time.Second
+
+// SyncNotification 同步结束后发送给 Webhook 的内容
+type SyncNotification struct {
+	Text       string    `json:"text"` // 一行摘要，兼容 Slack Incoming Webhook
+	Direction  string    `json:"direction"`
+	Status     string    `json:"status"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	DurationMS int64     `json:"duration_ms"`
+	Total      int       `json:"total"`
+	Created    int       `json:"created"`
+	Updated    int       `json:"updated"`
+	Skipped    int       `json:"skipped"`
+	Failed     int       `json:"failed"`
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+// WebhookNotifier 同步结束后将结果 POST 到 Webhook，url 为空时不做任何处理
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier 创建新的 WebhookNotifier 实例，timeout 小于等于 0 时使用 5 秒
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Notify 在后台发送通知后立即返回，失败只记录日志；请求不受 ctx 取消的影响，只受超时限制
+func (n *WebhookNotifier) Notify(ctx context.Context, notification *SyncNotification) {
+	if n == nil || n.url == "" || notification == nil {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := n.send(ctx, notification); err != nil {
+			logger.Printf(ctx, "Failed to send %s sync webhook: %v", notification.Direction, err)
+		}
+	}()
+}
+
+// send 同步发送一次通知，非 2xx 响应视为失败
+func (n *WebhookNotifier) send(ctx context.Context, notification *SyncNotification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// newSyncNotification 根据同步执行记录和结果生成通知内容
+func newSyncNotification(direction, status string, started, finished time.Time, result *SyncResult, syncErr error) *SyncNotification {
+	notification := &SyncNotification{
+		Direction:  direction,
+		Status:     status,
+		StartedAt:  started,
+		FinishedAt: finished,
+		DurationMS: finished.Sub(started).Milliseconds(),
+	}
+	if result != nil {
+		notification.Total = result.Total
+		notification.Created = result.Created
+		notification.Updated = result.Updated
+		notification.Skipped = result.Skipped
+		notification.Failed = result.Failed
+		notification.LastError = result.LastError
+	}
+	if syncErr != nil {
+		notification.LastError = syncErr.Error()
+	}
+
+	notification.Text = fmt.Sprintf("sls-migrate %s sync %s in %s: total %d, created %d, updated %d, skipped %d, failed %d",
+		direction, status, finished.Sub(started).Round(time.Millisecond),
+		notification.Total, notification.Created, notification.Updated, notification.Skipped, notification.Failed)
+	if notification.LastError != "" {
+		notification.Text += "; last error: " + notification.LastError
+	}
+	return notification
+}
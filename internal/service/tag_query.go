@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/internal/store"
+)
+
+// ErrInvalidTagQuery 标签查询语法错误或超出限制
+var ErrInvalidTagQuery = newError(ErrValidation, "invalid tag query")
+
+// 标签查询的限制，避免构造过多的子查询
+const (
+	maxTagQueryClauses = 10
+	maxTagQueryValues  = 20
+	maxTagQueryLength  = 255
+)
+
+// ParseTagQuery 解析标签查询，子句之间用分号分隔且全部需要满足：
+//
+//	owner=*          存在 owner 标签
+//	env=prod         值等于 prod
+//	team in (a,b)    值为 a 或 b
+//	name=web-*       值以 web- 开头
+//
+// 同时匹配 label 和 annotation
+func ParseTagQuery(query string) ([]store.TagFilter, error) {
+	var filters []store.TagFilter
+	for _, clause := range strings.Split(query, ";") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+		if len(filters) == maxTagQueryClauses {
+			return nil, fmt.Errorf("%w: at most %d clauses are allowed", ErrInvalidTagQuery, maxTagQueryClauses)
+		}
+
+		filter, err := parseTagClause(clause)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+
+	if len(filters) == 0 {
+		return nil, fmt.Errorf("%w: empty query", ErrInvalidTagQuery)
+	}
+	return filters, nil
+}
+
+// parseTagClause 解析单个子句
+func parseTagClause(clause string) (store.TagFilter, error) {
+	if key, values, ok := cutInClause(clause); ok {
+		if err := checkTagQueryKey(key, clause); err != nil {
+			return store.TagFilter{}, err
+		}
+		if !strings.HasPrefix(values, "(") || !strings.HasSuffix(values, ")") {
+			return store.TagFilter{}, fmt.Errorf("%w: %q: in requires a parenthesised list", ErrInvalidTagQuery, clause)
+		}
+
+		var set []string
+		for _, value := range strings.Split(values[1:len(values)-1], ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			if len(value) > maxTagQueryLength {
+				return store.TagFilter{}, fmt.Errorf("%w: %q: value is too long", ErrInvalidTagQuery, clause)
+			}
+			set = append(set, value)
+		}
+		if len(set) == 0 {
+			return store.TagFilter{}, fmt.Errorf("%w: %q: in requires at least one value", ErrInvalidTagQuery, clause)
+		}
+		if len(set) > maxTagQueryValues {
+			return store.TagFilter{}, fmt.Errorf("%w: %q: at most %d values are allowed", ErrInvalidTagQuery, clause, maxTagQueryValues)
+		}
+		return store.TagFilter{Key: key, Operator: store.TagOpIn, Values: set}, nil
+	}
+
+	key, value, ok := strings.Cut(clause, "=")
+	if !ok {
+		return store.TagFilter{}, fmt.Errorf("%w: %q: expected key=value, key=* or key in (...)", ErrInvalidTagQuery, clause)
+	}
+	key = strings.TrimSpace(key)
+	value = strings.TrimSpace(value)
+	if err := checkTagQueryKey(key, clause); err != nil {
+		return store.TagFilter{}, err
+	}
+	if len(value) > maxTagQueryLength {
+		return store.TagFilter{}, fmt.Errorf("%w: %q: value is too long", ErrInvalidTagQuery, clause)
+	}
+
+	switch {
+	case value == "*":
+		return store.TagFilter{Key: key, Operator: store.TagOpExists}, nil
+	case strings.HasSuffix(value, "*"):
+		prefix := strings.TrimSuffix(value, "*")
+		if strings.Contains(prefix, "*") {
+			return store.TagFilter{}, fmt.Errorf("%w: %q: only a trailing * is supported", ErrInvalidTagQuery, clause)
+		}
+		return store.TagFilter{Key: key, Operator: store.TagOpPrefix, Values: []string{prefix}}, nil
+	case strings.Contains(value, "*"):
+		return store.TagFilter{}, fmt.Errorf("%w: %q: only a trailing * is supported", ErrInvalidTagQuery, clause)
+	}
+	return store.TagFilter{Key: key, Operator: store.TagOpEquals, Values: []string{value}}, nil
+}
+
+// cutInClause 拆分 "key in (...)" 形式的子句，in 不区分大小写
+func cutInClause(clause string) (string, string, bool) {
+	lower := strings.ToLower(clause)
+	idx := strings.Index(lower, " in ")
+	if idx < 0 || strings.Contains(clause[:idx], "=") {
+		return "", "", false
+	}
+	return strings.TrimSpace(clause[:idx]), strings.TrimSpace(clause[idx+len(" in "):]), true
+}
+
+// checkTagQueryKey 检查子句中的标签键
+func checkTagQueryKey(key, clause string) error {
+	if key == "" {
+		return fmt.Errorf("%w: %q: tag key is required", ErrInvalidTagQuery, clause)
+	}
+	if len(key) > maxTagQueryLength || strings.ContainsAny(key, "*() ") {
+		return fmt.Errorf("%w: %q: invalid tag key", ErrInvalidTagQuery, clause)
+	}
+	return nil
+}
+
+// ListAlertsByTags 按标签查询分页获取 Alert
+func (s *alertService) ListAlertsByTags(ctx context.Context, query string, page, pageSize int) ([]*models.Alert, int64, error) {
+	filters, err := ParseTagQuery(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+	return s.alertStore.ListByTags(ctx, filters, offset, pageSize)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/store"
+)
+
+func TestParseTagQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    []store.TagFilter
+		wantErr string // 为空表示应解析成功
+	}{
+		{
+			name:  "exists",
+			query: "owner=*",
+			want:  []store.TagFilter{{Key: "owner", Operator: store.TagOpExists}},
+		},
+		{
+			name:  "equals",
+			query: " env = prod ",
+			want:  []store.TagFilter{{Key: "env", Operator: store.TagOpEquals, Values: []string{"prod"}}},
+		},
+		{
+			name:  "in set",
+			query: "team IN (a, b,,c)",
+			want:  []store.TagFilter{{Key: "team", Operator: store.TagOpIn, Values: []string{"a", "b", "c"}}},
+		},
+		{
+			name:  "prefix",
+			query: "name=web-*",
+			want:  []store.TagFilter{{Key: "name", Operator: store.TagOpPrefix, Values: []string{"web-"}}},
+		},
+		{
+			name:  "combined clauses",
+			query: "team in (a,b);env=prod;owner=*;",
+			want: []store.TagFilter{
+				{Key: "team", Operator: store.TagOpIn, Values: []string{"a", "b"}},
+				{Key: "env", Operator: store.TagOpEquals, Values: []string{"prod"}},
+				{Key: "owner", Operator: store.TagOpExists},
+			},
+		},
+		{name: "empty", query: " ; ", wantErr: "empty query"},
+		{name: "missing operator", query: "env", wantErr: "expected key=value"},
+		{name: "missing key", query: "=prod", wantErr: "tag key is required"},
+		{name: "inner wildcard", query: "name=w*b", wantErr: "only a trailing *"},
+		{name: "double wildcard", query: "name=web**", wantErr: "only a trailing *"},
+		{name: "in without parentheses", query: "team in a,b", wantErr: "parenthesised list"},
+		{name: "empty in set", query: "team in ( , )", wantErr: "at least one value"},
+		{name: "too many values", query: "team in (" + strings.Repeat("v,", maxTagQueryValues+1) + ")", wantErr: "values are allowed"},
+		{name: "too many clauses", query: strings.Repeat("env=prod;", maxTagQueryClauses+1), wantErr: "clauses are allowed"},
+		{name: "value too long", query: "env=" + strings.Repeat("x", maxTagQueryLength+1), wantErr: "value is too long"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := ParseTagQuery(tt.query)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidTagQuery) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want ErrInvalidTagQuery containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTagQuery: %v", err)
+			}
+			if !reflect.DeepEqual(filters, tt.want) {
+				t.Errorf("filters = %+v, want %+v", filters, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"gorm.io/gorm"
+)
+
+// 审计记录的操作类型
+const (
+	AuditActionCreate     = "create"
+	AuditActionUpdate     = "update"
+	AuditActionSoftDelete = "soft_delete"
+	AuditActionRestore    = "restore"
+	AuditActionDelete     = "delete"
+	AuditActionRollback   = "rollback"
+)
+
+// SystemActor 上下文中没有操作人时（如后台定时同步）使用的操作人
+const SystemActor = "system"
+
+// actorContextKey 上下文中保存操作人的键
+type actorContextKey struct{}
+
+// WithActor 返回携带操作人的上下文，审计记录从中读取操作人
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext 读取上下文中的操作人，未设置时返回 SystemActor
+func ActorFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if actor, ok := ctx.Value(actorContextKey{}).(string); ok && actor != "" {
+			return actor
+		}
+	}
+	return SystemActor
+}
+
+// snapshotAlert 在事务中读取 Alert 的完整快照（包含已软删除的 Alert），Alert 不存在时返回 nil
+func snapshotAlert(tx *gorm.DB, alertID uint) (*string, error) {
+	var alert models.Alert
+	err := preloadAlertDetails(tx.Unscoped()).First(&alert, alertID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load alert snapshot: %w", err)
+	}
+
+	data, err := json.Marshal(&alert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal alert snapshot: %w", err)
+	}
+	snapshot := string(data)
+	return &snapshot, nil
+}
+
+// writeAuditLog 在事务中写入审计记录，变更后的快照读取 Alert 当前的状态（永久删除后为空）
+func writeAuditLog(tx *gorm.DB, alertID uint, action string, before *string) error {
+	after, err := snapshotAlert(tx, alertID)
+	if err != nil {
+		return err
+	}
+
+	entry := models.AlertAuditLog{
+		AlertID:    alertID,
+		Action:     action,
+		Actor:      ActorFromContext(tx.Statement.Context),
+		BeforeJSON: before,
+		AfterJSON:  after,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// ListAuditLogs 分页获取 Alert 的审计记录，按时间倒序排列；Alert 永久删除后仍可查询
+func (s *alertStore) ListAuditLogs(ctx context.Context, alertID uint, offset, limit int) ([]models.AlertAuditLog, int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.AlertAuditLog{}).Where("alert_id = ?", alertID)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []models.AlertAuditLog
+	err := query.
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&logs).Error
+	return logs, total, err
+}
+
+// GetAuditLog 根据 ID 获取单条审计记录，不存在时返回 gorm.ErrRecordNotFound
+func (s *alertStore) GetAuditLog(ctx context.Context, id uint) (*models.AlertAuditLog, error) {
+	var entry models.AlertAuditLog
+	if err := s.db.WithContext(ctx).First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
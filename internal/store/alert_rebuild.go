@@ -0,0 +1,354 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/pkg/database"
+	"gorm.io/gorm"
+)
+
+// RebuildAssociations 在事务中重建 Alert 的全部关联数据：
+// 读取当前的配置、调度、标签和查询，删除该 Alert 名下的所有关联记录（包括孤立的旧配置），
+// 再重新创建一份干净的关联数据，并回填 alert_configurations 上的子配置外键
+func (s *alertStore) RebuildAssociations(ctx context.Context, id uint) error {
+	return database.WithRetry(ctx, func() error {
+		return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			alert, err := loadAlertForRebuild(tx, id)
+			if err != nil {
+				return err
+			}
+
+			if err := deleteAlertAssociations(tx, id); err != nil {
+				return err
+			}
+
+			alert.ConfigurationID = nil
+			alert.ScheduleID = nil
+			if err := s.recreateConfiguration(tx, alert); err != nil {
+				return fmt.Errorf("failed to recreate configuration: %w", err)
+			}
+
+			if alert.Schedule != nil {
+				schedule := *alert.Schedule
+				schedule.ID = 0
+				schedule.AlertID = id
+				schedule.Alert = models.Alert{}
+				if err := tx.Create(&schedule).Error; err != nil {
+					return fmt.Errorf("failed to create schedule: %w", err)
+				}
+				alert.ScheduleID = &schedule.ID
+			}
+
+			if err := syncTags(tx, id, uniqueTags(alert.Tags)); err != nil {
+				return err
+			}
+			if err := syncQueries(tx, id, alert.Queries); err != nil {
+				return err
+			}
+
+			return tx.Model(&models.Alert{}).Where("id = ?", id).Updates(map[string]interface{}{
+				"configuration_id": alert.ConfigurationID,
+				"schedule_id":      alert.ScheduleID,
+			}).Error
+		})
+	})
+}
+
+// loadAlertForRebuild 读取 Alert 及其关联数据。
+// 子配置通过 alert_config_id 直接查询，不依赖 alert_configurations 上可能缺失的外键
+func loadAlertForRebuild(tx *gorm.DB, id uint) (*models.Alert, error) {
+	var alert models.Alert
+	if err := tx.Preload("Tags", orderByID).Preload("Queries", orderByID).First(&alert, id).Error; err != nil {
+		return nil, err
+	}
+
+	var schedule models.AlertSchedule
+	if err := latestByAlert(tx, &schedule, alert.ScheduleID, id); err == nil {
+		alert.Schedule = &schedule
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to load schedule: %w", err)
+	}
+
+	var configuration models.AlertConfiguration
+	if err := latestByAlert(tx, &configuration, alert.ConfigurationID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &alert, nil
+		}
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := loadConfigurationChildren(tx, &configuration); err != nil {
+		return nil, err
+	}
+	alert.Configuration = &configuration
+
+	return &alert, nil
+}
+
+// uniqueTags 去掉类型和键重复的标签，保留最早的一条
+func uniqueTags(tags []models.AlertTag) []models.AlertTag {
+	seen := make(map[tagKey]bool, len(tags))
+	unique := make([]models.AlertTag, 0, len(tags))
+	for _, tag := range tags {
+		key := tagKey{tag.TagType, tag.TagKey}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, tag)
+	}
+	return unique
+}
+
+// orderByID 按 ID 升序预加载，保持记录的写入顺序
+func orderByID(db *gorm.DB) *gorm.DB {
+	return db.Order("id ASC")
+}
+
+// latestByAlert 优先按主记录上的外键读取，外键缺失或悬空时读取该 Alert 最新的一条记录
+func latestByAlert(tx *gorm.DB, dest interface{}, refID *uint, alertID uint) error {
+	if refID != nil {
+		err := tx.Where("id = ? AND alert_id = ?", *refID, alertID).First(dest).Error
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+	return tx.Where("alert_id = ?", alertID).Order("id DESC").First(dest).Error
+}
+
+// loadConfigurationChildren 按 alert_config_id 读取配置的所有子记录，并清空 ID 以便重新创建
+func loadConfigurationChildren(tx *gorm.DB, configuration *models.AlertConfiguration) error {
+	configID := configuration.ID
+
+	var severities []models.SeverityConfiguration
+	if err := tx.Preload("EvalCondition").Where("alert_config_id = ?", configID).Order("id ASC").Find(&severities).Error; err != nil {
+		return fmt.Errorf("failed to load severity configurations: %w", err)
+	}
+	evalConditionIDs := []uint{0}
+	for i := range severities {
+		if severities[i].EvalConditionID != nil {
+			evalConditionIDs = append(evalConditionIDs, *severities[i].EvalConditionID)
+		}
+		severities[i].ID = 0
+		severities[i].EvalConditionID = nil
+		if severities[i].EvalCondition != nil {
+			severities[i].EvalCondition.ID = 0
+		}
+	}
+	configuration.SeverityConfigs = severities
+
+	// 严重程度的评估条件也保存在 condition_configurations 中，需要排除
+	var condition models.ConditionConfiguration
+	if err := firstChild(tx, &condition, configID, configuration.ConditionConfigID, "id NOT IN ?", evalConditionIDs); err != nil {
+		return err
+	}
+	if condition.ID != 0 {
+		condition.ID = 0
+		configuration.ConditionConfig = &condition
+	}
+
+	var group models.GroupConfiguration
+	if err := firstChild(tx, &group, configID, configuration.GroupConfigID); err != nil {
+		return err
+	}
+	if group.ID != 0 {
+		group.ID = 0
+		configuration.GroupConfig = &group
+	}
+
+	var policy models.PolicyConfiguration
+	if err := firstChild(tx, &policy, configID, configuration.PolicyConfigID); err != nil {
+		return err
+	}
+	if policy.ID != 0 {
+		policy.ID = 0
+		configuration.PolicyConfig = &policy
+	}
+
+	var template models.TemplateConfiguration
+	if err := firstChild(tx, &template, configID, configuration.TemplateConfigID); err != nil {
+		return err
+	}
+	if template.ID != 0 {
+		template.ID = 0
+		configuration.TemplateConfig = &template
+	}
+
+	var alerthub models.SinkAlerthubConfiguration
+	if err := firstChild(tx, &alerthub, configID, configuration.SinkAlerthubConfigID); err != nil {
+		return err
+	}
+	if alerthub.ID != 0 {
+		alerthub.ID = 0
+		configuration.SinkAlerthubConfig = &alerthub
+	}
+
+	var cms models.SinkCmsConfiguration
+	if err := firstChild(tx, &cms, configID, configuration.SinkCmsConfigID); err != nil {
+		return err
+	}
+	if cms.ID != 0 {
+		cms.ID = 0
+		configuration.SinkCmsConfig = &cms
+	}
+
+	var eventStore models.SinkEventStoreConfiguration
+	if err := firstChild(tx, &eventStore, configID, configuration.SinkEventStoreConfigID); err != nil {
+		return err
+	}
+	if eventStore.ID != 0 {
+		eventStore.ID = 0
+		configuration.SinkEventStoreConfig = &eventStore
+	}
+
+	var joins []models.JoinConfiguration
+	if err := tx.Where("alert_config_id = ?", configID).Order("id ASC").Find(&joins).Error; err != nil {
+		return fmt.Errorf("failed to load join configurations: %w", err)
+	}
+	configuration.JoinConfigs = joins
+
+	return nil
+}
+
+// firstChild 读取配置的单个子记录：优先使用外键指向的记录，否则取最早的一条，不存在时保持零值
+func firstChild(tx *gorm.DB, dest interface{}, configID uint, refID *uint, extra ...interface{}) error {
+	query := tx.Where("alert_config_id = ?", configID)
+	if len(extra) > 0 {
+		query = query.Where(extra[0], extra[1:]...)
+	}
+	query = query.Session(&gorm.Session{})
+	if refID != nil {
+		err := query.Where("id = ?", *refID).First(dest).Error
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to load configuration child: %w", err)
+		}
+	}
+
+	err := query.Order("id ASC").First(dest).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to load configuration child: %w", err)
+	}
+	return nil
+}
+
+// deleteAlertAssociations 删除 Alert 名下的所有配置（包括孤立的旧配置）、调度、标签和查询
+func deleteAlertAssociations(tx *gorm.DB, alertID uint) error {
+	if err := clearAlertReferences(tx, alertID, "configuration_id", "schedule_id"); err != nil {
+		return err
+	}
+
+	var configIDs []uint
+	if err := tx.Model(&models.AlertConfiguration{}).Where("alert_id = ?", alertID).Pluck("id", &configIDs).Error; err != nil {
+		return fmt.Errorf("failed to list configurations: %w", err)
+	}
+	if err := deleteConfigurations(tx, configIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Where("alert_id = ?", alertID).Delete(&models.AlertSchedule{}).Error; err != nil {
+		return fmt.Errorf("failed to delete schedules: %w", err)
+	}
+	if err := tx.Where("alert_id = ?", alertID).Delete(&models.AlertTag{}).Error; err != nil {
+		return fmt.Errorf("failed to delete tags: %w", err)
+	}
+	if err := tx.Where("alert_id = ?", alertID).Delete(&models.AlertQuery{}).Error; err != nil {
+		return fmt.Errorf("failed to delete queries: %w", err)
+	}
+
+	return nil
+}
+
+// clearAlertReferences 将 alerts 上指向配置或调度的外键置空，之后才能删除被引用的记录；
+// 包括已软删除的 Alert，否则永久删除已软删除的 Alert 时外键约束失败
+func clearAlertReferences(tx *gorm.DB, alertID uint, columns ...string) error {
+	updates := make(map[string]interface{}, len(columns))
+	for _, column := range columns {
+		updates[column] = nil
+	}
+	if err := tx.Unscoped().Model(&models.Alert{}).Where("id = ?", alertID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to clear alert references: %w", err)
+	}
+	return nil
+}
+
+// deleteConfigurations 删除配置及其全部子记录。
+// alert_configurations 与子配置互相引用，先置空配置上指向子配置的外键，再按依赖顺序删除
+func deleteConfigurations(tx *gorm.DB, configIDs []uint) error {
+	if len(configIDs) == 0 {
+		return nil
+	}
+
+	if err := tx.Model(&models.AlertConfiguration{}).Where("id IN ?", configIDs).Updates(map[string]interface{}{
+		"condition_config_id":        nil,
+		"group_config_id":            nil,
+		"policy_config_id":           nil,
+		"template_config_id":         nil,
+		"sink_alerthub_config_id":    nil,
+		"sink_cms_config_id":         nil,
+		"sink_event_store_config_id": nil,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to unlink configuration children: %w", err)
+	}
+
+	// SeverityConfiguration 引用 ConditionConfiguration，需要先删除
+	children := []interface{}{
+		&models.SeverityConfiguration{},
+		&models.JoinConfiguration{},
+		&models.ConditionConfiguration{},
+		&models.GroupConfiguration{},
+		&models.PolicyConfiguration{},
+		&models.TemplateConfiguration{},
+		&models.SinkAlerthubConfiguration{},
+		&models.SinkCmsConfiguration{},
+		&models.SinkEventStoreConfiguration{},
+	}
+	for _, child := range children {
+		if err := tx.Where("alert_config_id IN ?", configIDs).Delete(child).Error; err != nil {
+			return fmt.Errorf("failed to delete configuration children: %w", err)
+		}
+	}
+	if err := tx.Delete(&models.AlertConfiguration{}, configIDs).Error; err != nil {
+		return fmt.Errorf("failed to delete configurations: %w", err)
+	}
+
+	return nil
+}
+
+// linkConfigurationChildren 回填 alert_configurations 上指向子配置的外键
+func linkConfigurationChildren(tx *gorm.DB, configID uint, configuration *models.AlertConfiguration) error {
+	updates := map[string]interface{}{}
+	if configuration.ConditionConfig != nil {
+		updates["condition_config_id"] = configuration.ConditionConfig.ID
+	}
+	if configuration.GroupConfig != nil {
+		updates["group_config_id"] = configuration.GroupConfig.ID
+	}
+	if configuration.PolicyConfig != nil {
+		updates["policy_config_id"] = configuration.PolicyConfig.ID
+	}
+	if configuration.TemplateConfig != nil {
+		updates["template_config_id"] = configuration.TemplateConfig.ID
+	}
+	if configuration.SinkAlerthubConfig != nil {
+		updates["sink_alerthub_config_id"] = configuration.SinkAlerthubConfig.ID
+	}
+	if configuration.SinkCmsConfig != nil {
+		updates["sink_cms_config_id"] = configuration.SinkCmsConfig.ID
+	}
+	if configuration.SinkEventStoreConfig != nil {
+		updates["sink_event_store_config_id"] = configuration.SinkEventStoreConfig.ID
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	if err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", configID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to link configuration children: %w", err)
+	}
+	return nil
+}
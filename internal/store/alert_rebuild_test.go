@@ -0,0 +1,182 @@
+package store
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/alibabacloud-go/tea/tea"
+	"gorm.io/gorm"
+)
+
+// assertCleanGraph 检查 Alert 只有一份配置和调度，主记录与配置上的外键都指向属于该 Alert 的记录
+func assertCleanGraph(t *testing.T, db *gorm.DB, alertID uint) {
+	t.Helper()
+
+	var alert models.Alert
+	if err := db.First(&alert, alertID).Error; err != nil {
+		t.Fatalf("load alert: %v", err)
+	}
+	var configurations []models.AlertConfiguration
+	if err := db.Where("alert_id = ?", alertID).Find(&configurations).Error; err != nil {
+		t.Fatalf("load configurations: %v", err)
+	}
+	if len(configurations) != 1 {
+		t.Fatalf("configurations = %d, want 1", len(configurations))
+	}
+	configuration := configurations[0]
+	if alert.ConfigurationID == nil || *alert.ConfigurationID != configuration.ID {
+		t.Errorf("alerts.configuration_id = %v, want %d", alert.ConfigurationID, configuration.ID)
+	}
+
+	var schedules []models.AlertSchedule
+	if err := db.Where("alert_id = ?", alertID).Find(&schedules).Error; err != nil {
+		t.Fatalf("load schedules: %v", err)
+	}
+	if len(schedules) != 1 {
+		t.Fatalf("schedules = %d, want 1", len(schedules))
+	}
+	if alert.ScheduleID == nil || *alert.ScheduleID != schedules[0].ID {
+		t.Errorf("alerts.schedule_id = %v, want %d", alert.ScheduleID, schedules[0].ID)
+	}
+
+	children := []struct {
+		name  string
+		refID *uint
+		model interface{}
+	}{
+		{"condition_config_id", configuration.ConditionConfigID, &models.ConditionConfiguration{}},
+		{"group_config_id", configuration.GroupConfigID, &models.GroupConfiguration{}},
+		{"policy_config_id", configuration.PolicyConfigID, &models.PolicyConfiguration{}},
+		{"template_config_id", configuration.TemplateConfigID, &models.TemplateConfiguration{}},
+		{"sink_alerthub_config_id", configuration.SinkAlerthubConfigID, &models.SinkAlerthubConfiguration{}},
+		{"sink_cms_config_id", configuration.SinkCmsConfigID, &models.SinkCmsConfiguration{}},
+		{"sink_event_store_config_id", configuration.SinkEventStoreConfigID, &models.SinkEventStoreConfiguration{}},
+	}
+	for _, child := range children {
+		if child.refID == nil {
+			t.Errorf("%s is null", child.name)
+			continue
+		}
+		var count int64
+		if err := db.Model(child.model).Where("id = ? AND alert_config_id = ?", *child.refID, configuration.ID).Count(&count).Error; err != nil {
+			t.Fatalf("check %s: %v", child.name, err)
+		}
+		if count != 1 {
+			t.Errorf("%s = %d does not reference a child of configuration %d", child.name, *child.refID, configuration.ID)
+		}
+	}
+}
+
+func TestRebuildAssociationsRepairsCorruptedGraph(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(t *testing.T, db *gorm.DB, alert *models.Alert)
+	}{
+		{
+			name: "orphaned old configuration",
+			corrupt: func(t *testing.T, db *gorm.DB, alert *models.Alert) {
+				// 旧版本更新时留下的整份配置，alerts.configuration_id 仍指向当前配置
+				orphan := models.AlertConfiguration{AlertID: alert.ID, Threshold: tea.Int32(99)}
+				if err := db.Create(&orphan).Error; err != nil {
+					t.Fatalf("create orphan configuration: %v", err)
+				}
+				condition := models.ConditionConfiguration{AlertConfigID: orphan.ID, Condition: tea.String("cnt > 99")}
+				if err := db.Create(&condition).Error; err != nil {
+					t.Fatalf("create orphan condition: %v", err)
+				}
+			},
+		},
+		{
+			name: "missing configuration child references",
+			corrupt: func(t *testing.T, db *gorm.DB, alert *models.Alert) {
+				if err := db.Model(&models.AlertConfiguration{}).Where("id = ?", *alert.ConfigurationID).Updates(map[string]interface{}{
+					"condition_config_id":        nil,
+					"group_config_id":            nil,
+					"policy_config_id":           nil,
+					"template_config_id":         nil,
+					"sink_alerthub_config_id":    nil,
+					"sink_cms_config_id":         nil,
+					"sink_event_store_config_id": nil,
+				}).Error; err != nil {
+					t.Fatalf("clear child references: %v", err)
+				}
+			},
+		},
+		{
+			name: "missing alert references",
+			corrupt: func(t *testing.T, db *gorm.DB, alert *models.Alert) {
+				if err := db.Model(&models.Alert{}).Where("id = ?", alert.ID).Updates(map[string]interface{}{
+					"configuration_id": nil,
+					"schedule_id":      nil,
+				}).Error; err != nil {
+					t.Fatalf("clear alert references: %v", err)
+				}
+			},
+		},
+		{
+			name: "duplicate schedule and tags",
+			corrupt: func(t *testing.T, db *gorm.DB, alert *models.Alert) {
+				schedule := models.AlertSchedule{AlertID: alert.ID, Type: "Cron", CronExpression: tea.String("0 * * * *")}
+				if err := db.Create(&schedule).Error; err != nil {
+					t.Fatalf("create duplicate schedule: %v", err)
+				}
+				tag := models.AlertTag{AlertID: alert.ID, TagType: "label", TagKey: "team", TagValue: tea.String("ops")}
+				if err := db.Create(&tag).Error; err != nil {
+					t.Fatalf("create duplicate tag: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestStore(t)
+			if err := s.CreateWithTransaction(ctx, newFullAlert("rebuild")); err != nil {
+				t.Fatalf("CreateWithTransaction: %v", err)
+			}
+			before, err := s.GetByName(ctx, "rebuild")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+			wantContent, err := configurationContent(before.Configuration)
+			if err != nil {
+				t.Fatalf("configurationContent: %v", err)
+			}
+			cleanRows := countRows(t, s.db)
+
+			tt.corrupt(t, s.db, before)
+			if err := s.RebuildAssociations(ctx, before.ID); err != nil {
+				t.Fatalf("RebuildAssociations: %v", err)
+			}
+
+			assertCleanGraph(t, s.db, before.ID)
+			// 重建后的行数与新建时相同，孤立和重复的记录都已删除
+			for table, count := range countRows(t, s.db) {
+				if table != "alert_audit_logs" && count != cleanRows[table] {
+					t.Errorf("%s rows = %d, want %d", table, count, cleanRows[table])
+				}
+			}
+
+			after, err := s.GetByName(ctx, "rebuild")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+			gotContent, err := configurationContent(after.Configuration)
+			if err != nil {
+				t.Fatalf("configurationContent: %v", err)
+			}
+			if !reflect.DeepEqual(gotContent, wantContent) {
+				t.Errorf("configuration = %v, want %v", gotContent, wantContent)
+			}
+			if after.Schedule == nil || after.Schedule.Type != "FixedRate" {
+				t.Errorf("schedule = %+v, want the FixedRate schedule", after.Schedule)
+			}
+			if len(after.Tags) != 1 || len(after.Queries) != 1 {
+				t.Errorf("tags = %d, queries = %d, want 1 each", len(after.Tags), len(after.Queries))
+			}
+		})
+	}
+}
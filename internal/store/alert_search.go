@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"gorm.io/gorm"
+)
+
+// SearchFilter Alert 搜索条件，空字段表示不过滤，多个条件同时满足
+type SearchFilter struct {
+	Name     string // 名称子串，大小写是否敏感取决于数据库排序规则
+	Status   string
+	TagKey   string // 标签键，同时匹配 label 和 annotation
+	TagValue string // 标签值，需要和 TagKey 一起使用
+}
+
+// Search 按名称子串、状态和标签键值分页搜索 Alert，返回满足条件的总数
+func (s *alertStore) Search(ctx context.Context, filter SearchFilter, offset, limit int) ([]*models.Alert, int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.Alert{})
+	if filter.Name != "" {
+		query = query.Where("name LIKE ? ESCAPE '!'", "%"+escapeLike(filter.Name)+"%")
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.TagKey != "" {
+		// 使用子查询而不是 JOIN，同一 Alert 有多个匹配的标签时不会重复返回
+		subQuery := s.db.WithContext(ctx).Model(&models.AlertTag{}).Select("alert_id").Where("tag_key = ?", filter.TagKey)
+		if filter.TagValue != "" {
+			subQuery = subQuery.Where("tag_value = ?", filter.TagValue)
+		}
+		query = query.Where("id IN (?)", subQuery)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var alerts []*models.Alert
+	err := query.
+		Preload("Configuration").
+		Preload("Schedule").
+		Preload("Tags").
+		Preload("Queries").
+		Offset(offset).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&alerts).Error
+
+	return alerts, total, err
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// CountByStatus 按状态统计 Alert 数量
+func (s *alertStore) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := s.db.WithContext(ctx).
+		Model(&models.Alert{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// SaveCountSnapshot 写入当天的数量快照，同一天已存在时覆盖计数，重复执行结果不变
+func (s *alertStore) SaveCountSnapshot(ctx context.Context, snapshot *models.AlertCountSnapshot) error {
+	return s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "day"}},
+			DoUpdates: clause.AssignmentColumns([]string{"total", "enabled", "disabled", "updated_at"}),
+		}).
+		Create(snapshot).Error
+}
+
+// ListCountSnapshots 按日期升序获取 sinceDay（含）之后的数量快照
+func (s *alertStore) ListCountSnapshots(ctx context.Context, sinceDay string) ([]models.AlertCountSnapshot, error) {
+	var snapshots []models.AlertCountSnapshot
+	err := s.db.WithContext(ctx).
+		Where("day >= ?", sinceDay).
+		Order("day ASC").
+		Find(&snapshots).Error
+	return snapshots, err
+}
@@ -2,8 +2,15 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 
+	"github.com/Ghostbaby/sls-migrate/internal/mapper"
 	"github.com/Ghostbaby/sls-migrate/internal/models"
 	"github.com/Ghostbaby/sls-migrate/pkg/database"
 	"gorm.io/gorm"
@@ -14,24 +21,106 @@ type AlertStore interface {
 	Create(ctx context.Context, alert *models.Alert) error
 	GetByID(ctx context.Context, id uint) (*models.Alert, error)
 	GetByName(ctx context.Context, name string) (*models.Alert, error)
+	GetByNameUnscoped(ctx context.Context, name string) (*models.Alert, error)
+	GetByNames(ctx context.Context, names []string) ([]*models.Alert, error)
 	Update(ctx context.Context, alert *models.Alert) error
+	GetByIDUnscoped(ctx context.Context, id uint) (*models.Alert, error)
 	Delete(ctx context.Context, id uint) error
-	List(ctx context.Context, offset, limit int) ([]*models.Alert, int64, error)
+	ListAuditLogs(ctx context.Context, alertID uint, offset, limit int) ([]models.AlertAuditLog, int64, error)
+	GetAuditLog(ctx context.Context, id uint) (*models.AlertAuditLog, error)
+	RollbackWithTransaction(ctx context.Context, alert *models.Alert) error
+	SoftDelete(ctx context.Context, id uint) error
+	Restore(ctx context.Context, id uint) error
+	List(ctx context.Context, offset, limit int, includeDeleted bool) ([]*models.Alert, int64, error)
 	ListByStatus(ctx context.Context, status string, offset, limit int) ([]*models.Alert, int64, error)
+	ListSyncedBefore(ctx context.Context, before time.Time, offset, limit int) ([]*models.Alert, int64, error)
+	ListByTags(ctx context.Context, filters []TagFilter, offset, limit int) ([]*models.Alert, int64, error)
+	Search(ctx context.Context, filter SearchFilter, offset, limit int) ([]*models.Alert, int64, error)
+	LastModified(ctx context.Context) (*time.Time, error)
+	LastModifiedIncludingSync(ctx context.Context) (*time.Time, error)
+	LastSynced(ctx context.Context) (*time.Time, error)
+	CountNeverSynced(ctx context.Context) (int64, error)
+	ListWithRelations(ctx context.Context) ([]*models.Alert, error)
+	ListWithConditions(ctx context.Context) ([]*models.Alert, error)
+	ListAfterID(ctx context.Context, afterID uint, status string, limit int) ([]*models.Alert, error)
+	MarkSynced(ctx context.Context, name, direction string, syncedAt time.Time) error
+	SetCreatedAt(ctx context.Context, id uint, createdAt time.Time) error
+	SetFreezeUntil(ctx context.Context, id uint, freezeUntil *int64) error
+	SetStatus(ctx context.Context, id uint, status string, lastModifiedTime int64) error
+	SetMuteUntil(ctx context.Context, id uint, muteUntil *int64, lastModifiedTime int64) error
+	SetContentHash(ctx context.Context, id uint, hash string) error
+	CreateSyncRun(ctx context.Context, run *models.SyncRun) error
+	LatestSyncRun(ctx context.Context) (*models.SyncRun, error)
+	ListSyncRuns(ctx context.Context, limit int) ([]models.SyncRun, error)
+	RebuildAssociations(ctx context.Context, id uint) error
 	CreateWithTransaction(ctx context.Context, alert *models.Alert) error
+	CreateBatchWithTransaction(ctx context.Context, alerts []*models.Alert) error
 	UpdateWithTransaction(ctx context.Context, alert *models.Alert) error
+	UpdateSectionsWithTransaction(ctx context.Context, alert *models.Alert, sections []string) error
 	Count(ctx context.Context) (int64, error)
+	CountByStatus(ctx context.Context) (map[string]int64, error)
+	SaveCountSnapshot(ctx context.Context, snapshot *models.AlertCountSnapshot) error
+	ListCountSnapshots(ctx context.Context, sinceDay string) ([]models.AlertCountSnapshot, error)
+	ListTags(ctx context.Context, alertID uint, tagType string, offset, limit int) ([]models.AlertTag, int64, error)
+	GetTag(ctx context.Context, alertID uint, tagType, tagKey string) (*models.AlertTag, error)
+	CreateTag(ctx context.Context, tag *models.AlertTag) error
+	DeleteTag(ctx context.Context, alertID, tagID uint) error
+	Autocomplete(ctx context.Context, prefix string, limit int) ([]AlertSuggestion, error)
+}
+
+// AlertSuggestion 名称自动补全的精简结果
+type AlertSuggestion struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// Alert 更新分区，用于限制更新操作写入的范围
+const (
+	SectionBase          = "base"          // 主记录字段
+	SectionConfiguration = "configuration" // Configuration 及其所有子配置
+	SectionSchedule      = "schedule"
+	SectionTags          = "tags"
+	SectionQueries       = "queries"
+)
+
+// AllSections 全部更新分区
+var AllSections = []string{SectionBase, SectionConfiguration, SectionSchedule, SectionTags, SectionQueries}
+
+// ValidateSections 检查更新分区是否都可识别
+func ValidateSections(sections []string) error {
+	for _, section := range sections {
+		if !containsSection(AllSections, section) {
+			return fmt.Errorf("unknown update section '%s'", section)
+		}
+	}
+	return nil
+}
+
+// containsSection 判断分区列表是否包含指定分区
+func containsSection(sections []string, section string) bool {
+	for _, s := range sections {
+		if s == section {
+			return true
+		}
+	}
+	return false
 }
 
 // alertStore Alert 数据存储实现
 type alertStore struct {
-	db *gorm.DB
+	db     *gorm.DB
+	logger *slog.Logger
 }
 
-// NewAlertStore 创建新的 AlertStore 实例
-func NewAlertStore() AlertStore {
+// NewAlertStore 创建新的 AlertStore 实例，logger 为 nil 时使用 slog 默认日志器
+func NewAlertStore(logger *slog.Logger) AlertStore {
+	if logger == nil {
+		logger = slog.Default()
+	}
 	return &alertStore{
-		db: database.DB,
+		db:     database.DB,
+		logger: logger,
 	}
 }
 
@@ -43,16 +132,18 @@ func (s *alertStore) Create(ctx context.Context, alert *models.Alert) error {
 // GetByID 根据 ID 获取 Alert
 func (s *alertStore) GetByID(ctx context.Context, id uint) (*models.Alert, error) {
 	var alert models.Alert
-	err := s.db.WithContext(ctx).
-		Preload("Configuration").
-		Preload("Configuration.ConditionConfig").
-		Preload("Configuration.GroupConfig").
-		Preload("Configuration.PolicyConfig").
-		Preload("Configuration.TemplateConfig").
-		Preload("Configuration.SeverityConfigs").
-		Preload("Schedule").
-		Preload("Tags").
-		Preload("Queries").
+	err := preloadAlertDetails(s.db.WithContext(ctx)).
+		First(&alert, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// GetByIDUnscoped 根据 ID 获取 Alert，包含已软删除的 Alert
+func (s *alertStore) GetByIDUnscoped(ctx context.Context, id uint) (*models.Alert, error) {
+	var alert models.Alert
+	err := preloadAlertDetails(s.db.WithContext(ctx).Unscoped()).
 		First(&alert, id).Error
 	if err != nil {
 		return nil, err
@@ -63,22 +154,58 @@ func (s *alertStore) GetByID(ctx context.Context, id uint) (*models.Alert, error
 // GetByName 根据名称获取 Alert
 func (s *alertStore) GetByName(ctx context.Context, name string) (*models.Alert, error) {
 	var alert models.Alert
-	err := s.db.WithContext(ctx).
+	err := preloadAlertDetails(s.db.WithContext(ctx)).
+		Where("name = ?", name).
+		First(&alert).Error
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// GetByNameUnscoped 根据名称获取 Alert，包含已软删除的 Alert
+func (s *alertStore) GetByNameUnscoped(ctx context.Context, name string) (*models.Alert, error) {
+	var alert models.Alert
+	err := s.db.WithContext(ctx).Unscoped().
+		Where("name = ?", name).
+		First(&alert).Error
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// GetByNames 根据名称批量获取 Alert
+func (s *alertStore) GetByNames(ctx context.Context, names []string) ([]*models.Alert, error) {
+	var alerts []*models.Alert
+	if len(names) == 0 {
+		return alerts, nil
+	}
+
+	err := preloadAlertDetails(s.db.WithContext(ctx)).
+		Where("name IN ?", names).
+		Order("name ASC").
+		Find(&alerts).Error
+	return alerts, err
+}
+
+// preloadAlertDetails 预加载 Alert 详情所需的全部关联数据
+func preloadAlertDetails(db *gorm.DB) *gorm.DB {
+	return db.
 		Preload("Configuration").
 		Preload("Configuration.ConditionConfig").
 		Preload("Configuration.GroupConfig").
 		Preload("Configuration.PolicyConfig").
 		Preload("Configuration.TemplateConfig").
 		Preload("Configuration.SeverityConfigs").
+		Preload("Configuration.SeverityConfigs.EvalCondition").
+		Preload("Configuration.JoinConfigs").
+		Preload("Configuration.SinkAlerthubConfig").
+		Preload("Configuration.SinkCmsConfig").
+		Preload("Configuration.SinkEventStoreConfig").
 		Preload("Schedule").
 		Preload("Tags").
-		Preload("Queries").
-		Where("name = ?", name).
-		First(&alert).Error
-	if err != nil {
-		return nil, err
-	}
-	return &alert, nil
+		Preload("Queries")
 }
 
 // Update 更新 Alert
@@ -86,105 +213,204 @@ func (s *alertStore) Update(ctx context.Context, alert *models.Alert) error {
 	return s.db.WithContext(ctx).Save(alert).Error
 }
 
-// Delete 删除 Alert
+// Delete 永久删除 Alert 及其全部关联数据，已软删除的 Alert 同样会被删除
 func (s *alertStore) Delete(ctx context.Context, id uint) error {
 	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// 根据新的schema设计，删除操作需要正确的顺序：
-		// 1. 先删除 SeverityConfiguration（因为它引用 ConditionConfiguration）
-		// 2. 然后删除其他配置记录
-		// 3. 最后删除 AlertConfiguration 和 Alert
-
-		// 步骤1: 先获取 Configuration ID
-		var configID uint
-		if err := tx.Model(&models.AlertConfiguration{}).Where("alert_id = ?", id).Select("id").First(&configID).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				// 没有 Configuration，直接删除 Alert
-				if err := tx.Delete(&models.Alert{}, id).Error; err != nil {
-					return fmt.Errorf("failed to delete alert: %w", err)
-				}
-				return nil
-			}
-			return fmt.Errorf("failed to get configuration ID: %w", err)
+		before, err := snapshotAlert(tx, id)
+		if err != nil {
+			return err
 		}
 
-		// 步骤2: 先删除 SeverityConfiguration（因为它引用 ConditionConfiguration）
-		if err := tx.Where("alert_config_id = ?", configID).Delete(&models.SeverityConfiguration{}).Error; err != nil {
-			return fmt.Errorf("failed to delete severity configurations: %w", err)
+		if err := deleteAlertAssociations(tx, id); err != nil {
+			return err
 		}
 
-		// 步骤3: 删除其他配置记录（按照依赖顺序）
-		if err := tx.Where("alert_config_id = ?", configID).Delete(&models.JoinConfiguration{}).Error; err != nil {
-			return fmt.Errorf("failed to delete join configurations: %w", err)
+		if err := tx.Unscoped().Delete(&models.Alert{}, id).Error; err != nil {
+			return fmt.Errorf("failed to delete alert: %w", err)
 		}
 
-		// 步骤4: 删除所有配置表记录（因为它们都引用 alert_config_id）
-		if err := tx.Where("alert_config_id = ?", configID).Delete(&models.ConditionConfiguration{}).Error; err != nil {
-			return fmt.Errorf("failed to delete condition configurations: %w", err)
-		}
+		return writeAuditLog(tx, id, AuditActionDelete, before)
+	})
+}
 
-		if err := tx.Where("alert_config_id = ?", configID).Delete(&models.GroupConfiguration{}).Error; err != nil {
-			return fmt.Errorf("failed to delete group configurations: %w", err)
+// SoftDelete 软删除 Alert：只设置 deleted_at，关联数据保持不变。
+// 同时更新 updated_at，使列表的 Last-Modified 发生变化
+func (s *alertStore) SoftDelete(ctx context.Context, id uint) error {
+	now := time.Now()
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := snapshotAlert(tx, id)
+		if err != nil {
+			return err
 		}
 
-		if err := tx.Where("alert_config_id = ?", configID).Delete(&models.PolicyConfiguration{}).Error; err != nil {
-			return fmt.Errorf("failed to delete policy configurations: %w", err)
+		result := tx.Model(&models.Alert{}).
+			Where("id = ?", id).
+			UpdateColumns(map[string]interface{}{
+				"deleted_at": now,
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to soft delete alert: %w", result.Error)
 		}
-
-		if err := tx.Where("alert_config_id = ?", configID).Delete(&models.TemplateConfiguration{}).Error; err != nil {
-			return fmt.Errorf("failed to delete template configurations: %w", err)
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
 		}
 
-		if err := tx.Where("alert_config_id = ?", configID).Delete(&models.SinkAlerthubConfiguration{}).Error; err != nil {
-			return fmt.Errorf("failed to delete sink alerthub configurations: %w", err)
-		}
+		return writeAuditLog(tx, id, AuditActionSoftDelete, before)
+	})
+}
 
-		if err := tx.Where("alert_config_id = ?", configID).Delete(&models.SinkCmsConfiguration{}).Error; err != nil {
-			return fmt.Errorf("failed to delete sink cms configurations: %w", err)
+// Restore 恢复已软删除的 Alert，Alert 不存在或未被删除时返回 gorm.ErrRecordNotFound
+func (s *alertStore) Restore(ctx context.Context, id uint) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := snapshotAlert(tx, id)
+		if err != nil {
+			return err
 		}
 
-		if err := tx.Where("alert_config_id = ?", configID).Delete(&models.SinkEventStoreConfiguration{}).Error; err != nil {
-			return fmt.Errorf("failed to delete sink event store configurations: %w", err)
+		result := tx.Unscoped().
+			Model(&models.Alert{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			UpdateColumns(map[string]interface{}{
+				"deleted_at": nil,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to restore alert: %w", result.Error)
 		}
-
-		// 步骤5: 现在可以安全删除 AlertConfiguration
-		if err := tx.Delete(&models.AlertConfiguration{}, configID).Error; err != nil {
-			return fmt.Errorf("failed to delete alert configuration: %w", err)
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
 		}
 
-		// 步骤6: 删除其他关联表记录
-		if err := tx.Where("alert_id = ?", id).Delete(&models.AlertSchedule{}).Error; err != nil {
-			return fmt.Errorf("failed to delete alert schedule: %w", err)
-		}
+		return writeAuditLog(tx, id, AuditActionRestore, before)
+	})
+}
 
-		if err := tx.Where("alert_id = ?", id).Delete(&models.AlertTag{}).Error; err != nil {
-			return fmt.Errorf("failed to delete alert tags: %w", err)
-		}
+// List 分页获取 Alert 列表，includeDeleted 为 true 时包含已软删除的 Alert
+func (s *alertStore) List(ctx context.Context, offset, limit int, includeDeleted bool) ([]*models.Alert, int64, error) {
+	var alerts []*models.Alert
+	var total int64
+
+	db := s.db.WithContext(ctx)
+	if includeDeleted {
+		db = db.Unscoped()
+	}
+
+	// 获取总数
+	if err := db.Session(&gorm.Session{}).Model(&models.Alert{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 获取分页数据，包含完整嵌套配置，DB 到 SLS 的同步依赖这些数据
+	err := preloadAlertDetails(db).
+		Offset(offset).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&alerts).Error
+
+	return alerts, total, err
+}
+
+// ListWithRelations 获取所有 Alert 及其策略配置和查询语句，用于构建依赖关系图
+func (s *alertStore) ListWithRelations(ctx context.Context) ([]*models.Alert, error) {
+	var alerts []*models.Alert
+	err := s.db.WithContext(ctx).
+		Preload("Configuration").
+		Preload("Configuration.PolicyConfig").
+		Preload("Queries").
+		Find(&alerts).Error
+	return alerts, err
+}
 
-		if err := tx.Where("alert_id = ?", id).Delete(&models.AlertQuery{}).Error; err != nil {
-			return fmt.Errorf("failed to delete alert queries: %w", err)
+// ListWithConditions 获取所有 Alert 及其配置、触发条件和查询语句，用于按内容查找重复的 Alert。
+// alert_configurations 上的 condition_config_id 可能缺失，因此触发条件按 alert_config_id 读取，
+// 并排除严重程度的评估条件
+func (s *alertStore) ListWithConditions(ctx context.Context) ([]*models.Alert, error) {
+	var alerts []*models.Alert
+	if err := s.db.WithContext(ctx).
+		Preload("Configuration").
+		Preload("Queries").
+		Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+
+	var evalConditionIDs []uint
+	if err := s.db.WithContext(ctx).Model(&models.SeverityConfiguration{}).
+		Where("eval_condition_id IS NOT NULL").
+		Pluck("eval_condition_id", &evalConditionIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list eval conditions: %w", err)
+	}
+	excluded := make(map[uint]bool, len(evalConditionIDs))
+	for _, id := range evalConditionIDs {
+		excluded[id] = true
+	}
+
+	var conditions []models.ConditionConfiguration
+	if err := s.db.WithContext(ctx).Order("id ASC").Find(&conditions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list condition configurations: %w", err)
+	}
+	conditionByID := make(map[uint]*models.ConditionConfiguration, len(conditions))
+	firstByConfig := make(map[uint]*models.ConditionConfiguration)
+	for i := range conditions {
+		condition := &conditions[i]
+		if excluded[condition.ID] {
+			continue
 		}
+		conditionByID[condition.ID] = condition
+		if firstByConfig[condition.AlertConfigID] == nil {
+			firstByConfig[condition.AlertConfigID] = condition
+		}
+	}
 
-		// 步骤7: 最后删除主记录
-		if err := tx.Delete(&models.Alert{}, id).Error; err != nil {
-			return fmt.Errorf("failed to delete alert: %w", err)
+	for _, alert := range alerts {
+		configuration := alert.Configuration
+		if configuration == nil {
+			continue
+		}
+		if configuration.ConditionConfigID != nil {
+			if condition := conditionByID[*configuration.ConditionConfigID]; condition != nil && condition.AlertConfigID == configuration.ID {
+				configuration.ConditionConfig = condition
+				continue
+			}
 		}
+		configuration.ConditionConfig = firstByConfig[configuration.ID]
+	}
 
-		return nil
-	})
+	return alerts, nil
 }
 
-// List 分页获取 Alert 列表
-func (s *alertStore) List(ctx context.Context, offset, limit int) ([]*models.Alert, int64, error) {
+// ListAfterID 按 ID 升序获取 ID 大于 afterID 的 Alert（包含完整嵌套配置），用于基于游标的流式导出；
+// status 非空时只返回该状态的 Alert
+func (s *alertStore) ListAfterID(ctx context.Context, afterID uint, status string, limit int) ([]*models.Alert, error) {
+	var alerts []*models.Alert
+	query := preloadAlertDetails(s.db.WithContext(ctx)).Where("id > ?", afterID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.
+		Order("id ASC").
+		Limit(limit).
+		Find(&alerts).Error
+	return alerts, err
+}
+
+// ListByStatus 根据状态分页获取 Alert 列表，status 为空时不按状态过滤（与 List 相同）
+func (s *alertStore) ListByStatus(ctx context.Context, status string, offset, limit int) ([]*models.Alert, int64, error) {
 	var alerts []*models.Alert
 	var total int64
 
+	db := s.db.WithContext(ctx)
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+
 	// 获取总数
-	if err := s.db.WithContext(ctx).Model(&models.Alert{}).Count(&total).Error; err != nil {
+	if err := db.Session(&gorm.Session{}).Model(&models.Alert{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// 获取分页数据
-	err := s.db.WithContext(ctx).
+	err := db.
 		Preload("Configuration").
 		Preload("Schedule").
 		Preload("Tags").
@@ -197,13 +423,15 @@ func (s *alertStore) List(ctx context.Context, offset, limit int) ([]*models.Ale
 	return alerts, total, err
 }
 
-// ListByStatus 根据状态分页获取 Alert 列表
-func (s *alertStore) ListByStatus(ctx context.Context, status string, offset, limit int) ([]*models.Alert, int64, error) {
+// ListSyncedBefore 分页获取在指定时间之前同步过或从未同步过的 Alert
+func (s *alertStore) ListSyncedBefore(ctx context.Context, before time.Time, offset, limit int) ([]*models.Alert, int64, error) {
 	var alerts []*models.Alert
 	var total int64
 
+	condition := "last_synced_at IS NULL OR last_synced_at < ?"
+
 	// 获取总数
-	if err := s.db.WithContext(ctx).Model(&models.Alert{}).Where("status = ?", status).Count(&total).Error; err != nil {
+	if err := s.db.WithContext(ctx).Model(&models.Alert{}).Where(condition, before).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -213,235 +441,542 @@ func (s *alertStore) ListByStatus(ctx context.Context, status string, offset, li
 		Preload("Schedule").
 		Preload("Tags").
 		Preload("Queries").
-		Where("status = ?", status).
+		Where(condition, before).
 		Offset(offset).
 		Limit(limit).
-		Order("created_at DESC").
+		Order("last_synced_at ASC").
 		Find(&alerts).Error
 
 	return alerts, total, err
 }
 
-// CreateWithTransaction 在事务中创建 Alert 及其关联数据
-func (s *alertStore) CreateWithTransaction(ctx context.Context, alert *models.Alert) error {
-	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// 保存关联数据的引用
-		originalConfig := alert.Configuration
-		originalSchedule := alert.Schedule
-		originalTags := alert.Tags
-		originalQueries := alert.Queries
+// LastModified 获取所有 Alert 中最大的 updated_at，没有 Alert 时返回 nil
+// 永久删除的 Alert 不再有记录，用最近一次永久删除的审计时间代替，避免删除后 Last-Modified 回退
+func (s *alertStore) LastModified(ctx context.Context) (*time.Time, error) {
+	updated, err := s.maxTime(ctx, "updated_at")
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := s.lastHardDelete(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return latestTime(updated, deleted), nil
+}
+
+// LastModifiedIncludingSync 获取所有 Alert 中最大的 updated_at 或 last_synced_at
+// MarkSynced 不修改 updated_at，但会改变按同步时间过滤的结果集
+func (s *alertStore) LastModifiedIncludingSync(ctx context.Context) (*time.Time, error) {
+	updated, err := s.LastModified(ctx)
+	if err != nil {
+		return nil, err
+	}
+	synced, err := s.maxTime(ctx, "last_synced_at")
+	if err != nil {
+		return nil, err
+	}
+	return latestTime(updated, synced), nil
+}
+
+// latestTime 返回较晚的时间，nil 表示没有时间
+func latestTime(a, b *time.Time) *time.Time {
+	if b != nil && (a == nil || b.After(*a)) {
+		return b
+	}
+	return a
+}
+
+// lastHardDelete 获取最近一次永久删除 Alert 的审计时间，没有时返回 nil
+func (s *alertStore) lastHardDelete(ctx context.Context) (*time.Time, error) {
+	var result sql.NullTime
+	err := s.db.WithContext(ctx).Model(&models.AlertAuditLog{}).
+		Select("created_at").
+		Where("action = ?", AuditActionDelete).
+		Order("created_at DESC").
+		Limit(1).
+		Row().Scan(&result)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query last delete time: %w", err)
+	}
+	if !result.Valid {
+		return nil, nil
+	}
+	return &result.Time, nil
+}
+
+// LastSynced 获取所有 Alert 中最近一次成功同步的时间，从未同步过时返回 nil
+func (s *alertStore) LastSynced(ctx context.Context) (*time.Time, error) {
+	return s.maxTime(ctx, "last_synced_at")
+}
+
+// CountNeverSynced 统计从未同步过的 Alert 数量
+func (s *alertStore) CountNeverSynced(ctx context.Context) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&models.Alert{}).Where("last_synced_at IS NULL").Count(&count).Error
+	return count, err
+}
+
+// maxTime 查询时间列的最大值，包含已软删除的 Alert（软删除和恢复会更新 updated_at）。
+// 使用 ORDER BY ... LIMIT 1 而不是 MAX()，SQLite 聚合结果不带列类型，无法直接扫描为时间
+func (s *alertStore) maxTime(ctx context.Context, column string) (*time.Time, error) {
+	var result sql.NullTime
+	err := s.db.WithContext(ctx).Unscoped().Model(&models.Alert{}).
+		Select(column).
+		Where(column + " IS NOT NULL").
+		Order(column + " DESC").
+		Limit(1).
+		Row().Scan(&result)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query last modified time: %w", err)
+	}
+	if !result.Valid {
+		return nil, nil
+	}
+	return &result.Time, nil
+}
+
+// touchAlert 更新 Alert 主记录的 updated_at，用于只修改了子表的情况
+func touchAlert(tx *gorm.DB, alertID uint) error {
+	return tx.Model(&models.Alert{}).Where("id = ?", alertID).Update("updated_at", time.Now()).Error
+}
+
+// MarkSynced 记录 Alert 的最后同步时间和方向，不修改 updated_at
+func (s *alertStore) MarkSynced(ctx context.Context, name, direction string, syncedAt time.Time) error {
+	return s.db.WithContext(ctx).
+		Model(&models.Alert{}).
+		Where("name = ?", name).
+		UpdateColumns(map[string]interface{}{
+			"last_synced_at":      syncedAt,
+			"last_sync_direction": direction,
+		}).Error
+}
+
+// SetCreatedAt 显式设置 Alert 的 created_at（autoCreateTime 会在创建时覆盖该字段）
+func (s *alertStore) SetCreatedAt(ctx context.Context, id uint, createdAt time.Time) error {
+	return s.db.WithContext(ctx).
+		Model(&models.Alert{}).
+		Where("id = ?", id).
+		UpdateColumn("created_at", createdAt).Error
+}
 
-		// 调试输出
-		fmt.Printf("DEBUG: Creating alert %s\n", alert.Name)
-		fmt.Printf("DEBUG: originalConfig is nil: %v\n", originalConfig == nil)
-		if originalConfig != nil {
-			fmt.Printf("DEBUG: originalConfig has data: Type=%v, Version=%v\n",
-				originalConfig.Type, originalConfig.Version)
+// ErrAlertExists 同名 Alert 已存在，由数据库唯一索引判定
+var ErrAlertExists = errors.New("alert already exists")
+
+// ErrAlertModified 更新时存储的 last_modified_time 与 ExpectedLastModifiedTime 不一致
+var ErrAlertModified = errors.New("alert was modified concurrently")
+
+// ErrInvalidTemplateConfig 模板配置的 aonotations 或 tokens 不是合法的 JSON 对象
+var ErrInvalidTemplateConfig = errors.New("invalid template configuration")
+
+// validateTemplateConfig 在写入前检查模板配置中存储的 JSON，避免推送到 SLS 时才发现无法解析
+func validateTemplateConfig(config *models.TemplateConfiguration) error {
+	if err := mapper.ValidateTemplateConfig(config); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplateConfig, err)
+	}
+	return nil
+}
+
+// BatchCreateError 批量创建时导致整批回滚的 Alert 错误
+type BatchCreateError struct {
+	Index int
+	Name  string
+	Err   error
+}
+
+// Error 实现 error 接口
+func (e *BatchCreateError) Error() string {
+	return fmt.Sprintf("alerts[%d] (%s): %v", e.Index, e.Name, e.Err)
+}
+
+// Unwrap 返回原始错误，以便识别可重试的数据库错误
+func (e *BatchCreateError) Unwrap() error {
+	return e.Err
+}
+
+// CreateSectionRelations 创建 Alert 的最后一步：回写主记录的 configuration_id、schedule_id
+const CreateSectionRelations = "relations"
+
+// CreateSectionError 创建 Alert 时某个步骤失败，Section 为出错的分区（如 configuration.severity_configs）
+type CreateSectionError struct {
+	Alert   string
+	Section string
+	Err     error
+}
+
+// Error 实现 error 接口
+func (e *CreateSectionError) Error() string {
+	return fmt.Sprintf("failed to create alert '%s' at %s: %v", e.Alert, e.Section, e.Err)
+}
+
+// Unwrap 返回原始错误，以便识别可重试的数据库错误
+func (e *CreateSectionError) Unwrap() error {
+	return e.Err
+}
+
+// transactionWithRetry 在事务中执行 fn，遇到死锁时整体重试。
+// 事务中会回写 ID 等字段，因此每次尝试都基于原始 Alert 的深拷贝执行，只有成功的那次结果会写回 alert
+func (s *alertStore) transactionWithRetry(ctx context.Context, alert *models.Alert, fn func(tx *gorm.DB, alert *models.Alert) error) error {
+	snapshot, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot alert: %w", err)
+	}
+
+	return database.WithRetry(ctx, func() error {
+		var attempt models.Alert
+		if err := json.Unmarshal(snapshot, &attempt); err != nil {
+			return fmt.Errorf("failed to restore alert snapshot: %w", err)
 		}
 
-		// 步骤1: 创建纯净的 Alert 主记录（不包含关联数据）
-		cleanAlert := models.Alert{
-			Name:             alert.Name,
-			DisplayName:      alert.DisplayName,
-			Description:      alert.Description,
-			Status:           alert.Status,
-			CreateTime:       alert.CreateTime,
-			LastModifiedTime: alert.LastModifiedTime,
+		if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(tx, &attempt)
+		}); err != nil {
+			return err
+		}
+
+		*alert = attempt
+		return nil
+	})
+}
+
+// SetFreezeUntil 设置 Alert 的冻结截止时间，nil 表示解除冻结
+func (s *alertStore) SetFreezeUntil(ctx context.Context, id uint, freezeUntil *int64) error {
+	return s.db.WithContext(ctx).
+		Model(&models.Alert{}).
+		Where("id = ?", id).
+		UpdateColumn("freeze_until", freezeUntil).Error
+}
+
+// SetStatus 只更新 Alert 的状态和最后修改时间，不触碰关联数据，变更写入审计记录；
+// Alert 不存在时返回 gorm.ErrRecordNotFound
+func (s *alertStore) SetStatus(ctx context.Context, id uint, status string, lastModifiedTime int64) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := snapshotAlert(tx, id)
+		if err != nil {
+			return err
 		}
 
-		if err := tx.Create(&cleanAlert).Error; err != nil {
-			return fmt.Errorf("failed to create alert: %w", err)
+		result := tx.Model(&models.Alert{}).
+			Where("id = ?", id).
+			UpdateColumns(map[string]interface{}{
+				"status":             status,
+				"last_modified_time": lastModifiedTime,
+				"updated_at":         time.Now(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update alert status: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
 		}
 
-		// 更新原始alert的ID
-		alert.ID = cleanAlert.ID
+		return writeAuditLog(tx, id, AuditActionUpdate, before)
+	})
+}
 
-		// 步骤2: 先创建 alert_configurations 记录
-		if originalConfig != nil {
-			configToCreate := models.AlertConfiguration{
-				AlertID:        alert.ID,
-				AutoAnnotation: originalConfig.AutoAnnotation,
-				Dashboard:      originalConfig.Dashboard,
-				MuteUntil:      originalConfig.MuteUntil,
-				NoDataFire:     originalConfig.NoDataFire,
-				NoDataSeverity: originalConfig.NoDataSeverity,
-				Threshold:      originalConfig.Threshold,
-				Type:           originalConfig.Type,
-				Version:        originalConfig.Version,
-				SendResolved:   originalConfig.SendResolved,
-			}
+// SetMuteUntil 只更新 Alert 配置的屏蔽截止时间（nil 表示取消屏蔽）和 Alert 的最后修改时间，变更写入审计记录；
+// Alert 没有配置时返回 gorm.ErrRecordNotFound
+func (s *alertStore) SetMuteUntil(ctx context.Context, id uint, muteUntil *int64, lastModifiedTime int64) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := snapshotAlert(tx, id)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.AlertConfiguration{}).
+			Where("alert_id = ?", id).
+			UpdateColumns(map[string]interface{}{
+				"mute_until": muteUntil,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update alert mute_until: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Model(&models.Alert{}).
+			Where("id = ?", id).
+			UpdateColumns(map[string]interface{}{
+				"last_modified_time": lastModifiedTime,
+				"updated_at":         time.Now(),
+			}).Error; err != nil {
+			return fmt.Errorf("failed to update alert: %w", err)
+		}
 
-			if err := tx.Create(&configToCreate).Error; err != nil {
-				return fmt.Errorf("failed to create alert configuration: %w", err)
-			}
+		return writeAuditLog(tx, id, AuditActionUpdate, before)
+	})
+}
 
-			originalConfig.ID = configToCreate.ID
-			alert.ConfigurationID = &configToCreate.ID
+// SetContentHash 记录最近一次从 SLS 同步时 SLS 侧内容的哈希，不修改 updated_at
+func (s *alertStore) SetContentHash(ctx context.Context, id uint, hash string) error {
+	return s.db.WithContext(ctx).
+		Model(&models.Alert{}).
+		Where("id = ?", id).
+		UpdateColumn("content_hash", hash).Error
+}
 
-			// 步骤3: 创建所有配置表记录，并设置 alert_config_id
-			if originalConfig.ConditionConfig != nil {
-				originalConfig.ConditionConfig.AlertConfigID = configToCreate.ID
-				if err := tx.Create(originalConfig.ConditionConfig).Error; err != nil {
-					return fmt.Errorf("failed to create condition configuration: %w", err)
-				}
-			}
+// CreateWithTransaction 在事务中创建 Alert 及其关联数据，并在同一事务中写入审计记录
+func (s *alertStore) CreateWithTransaction(ctx context.Context, alert *models.Alert) error {
+	return s.transactionWithRetry(ctx, alert, func(tx *gorm.DB, alert *models.Alert) error {
+		if err := s.createAlertTx(tx, alert); err != nil {
+			return err
+		}
+		return writeAuditLog(tx, alert.ID, AuditActionCreate, nil)
+	})
+}
 
-			if originalConfig.GroupConfig != nil {
-				originalConfig.GroupConfig.AlertConfigID = configToCreate.ID
-				if err := tx.Create(originalConfig.GroupConfig).Error; err != nil {
-					return fmt.Errorf("failed to create group configuration: %w", err)
-				}
-			}
+// CreateBatchWithTransaction 在同一个事务中创建多个 Alert，任一 Alert 失败时整批回滚，
+// 返回的 *BatchCreateError 给出失败的序号；遇到死锁时整批重试
+func (s *alertStore) CreateBatchWithTransaction(ctx context.Context, alerts []*models.Alert) error {
+	snapshot, err := json.Marshal(alerts)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot alerts: %w", err)
+	}
 
-			if originalConfig.PolicyConfig != nil {
-				originalConfig.PolicyConfig.AlertConfigID = configToCreate.ID
-				if err := tx.Create(originalConfig.PolicyConfig).Error; err != nil {
-					return fmt.Errorf("failed to create policy configuration: %w", err)
-				}
-			}
+	return database.WithRetry(ctx, func() error {
+		var attempt []*models.Alert
+		if err := json.Unmarshal(snapshot, &attempt); err != nil {
+			return fmt.Errorf("failed to restore alerts snapshot: %w", err)
+		}
 
-			if originalConfig.TemplateConfig != nil {
-				originalConfig.TemplateConfig.AlertConfigID = configToCreate.ID
-				if err := tx.Create(originalConfig.TemplateConfig).Error; err != nil {
-					return fmt.Errorf("failed to create template configuration: %w", err)
+		if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for i, alert := range attempt {
+				if err := s.createAlertTx(tx, alert); err != nil {
+					return &BatchCreateError{Index: i, Name: alert.Name, Err: err}
 				}
-			}
-
-			// 创建 Sink 配置
-			if originalConfig.SinkAlerthubConfig != nil {
-				originalConfig.SinkAlerthubConfig.AlertConfigID = configToCreate.ID
-				if err := tx.Create(originalConfig.SinkAlerthubConfig).Error; err != nil {
-					return fmt.Errorf("failed to create sink alerthub configuration: %w", err)
+				if err := writeAuditLog(tx, alert.ID, AuditActionCreate, nil); err != nil {
+					return &BatchCreateError{Index: i, Name: alert.Name, Err: err}
 				}
 			}
+			return nil
+		}); err != nil {
+			return err
+		}
 
-			if originalConfig.SinkCmsConfig != nil {
-				originalConfig.SinkCmsConfig.AlertConfigID = configToCreate.ID
-				if err := tx.Create(originalConfig.SinkCmsConfig).Error; err != nil {
-					return fmt.Errorf("failed to create sink cms configuration: %w", err)
-				}
+		for i := range alerts {
+			*alerts[i] = *attempt[i]
+		}
+		return nil
+	})
+}
+
+// createAlertTx 在给定事务中按步骤创建 Alert 主记录及其关联数据，任一步骤失败时返回标明 Alert 名称和分区的
+// *CreateSectionError，调用方回滚事务后不会留下主记录或部分关联数据
+func (s *alertStore) createAlertTx(tx *gorm.DB, alert *models.Alert) error {
+	// 保存关联数据的引用
+	originalConfig := alert.Configuration
+	originalSchedule := alert.Schedule
+	originalTags := alert.Tags
+	originalQueries := alert.Queries
+
+	s.logger.DebugContext(tx.Statement.Context, "creating alert",
+		"alert", alert.Name,
+		"has_configuration", originalConfig != nil,
+	)
+
+	// 步骤1: 创建纯净的 Alert 主记录（不包含关联数据）
+	cleanAlert := models.Alert{
+		Name:             alert.Name,
+		DisplayName:      alert.DisplayName,
+		Description:      alert.Description,
+		Status:           alert.Status,
+		CreateTime:       alert.CreateTime,
+		LastModifiedTime: alert.LastModifiedTime,
+	}
+
+	if err := tx.Create(&cleanAlert).Error; err != nil {
+		if database.IsDuplicateKeyError(err) {
+			err = fmt.Errorf("%w: %v", ErrAlertExists, err)
+		}
+		return &CreateSectionError{Alert: alert.Name, Section: SectionBase, Err: err}
+	}
+
+	// 更新原始alert的ID
+	alert.ID = cleanAlert.ID
+
+	// 步骤2: 先创建 alert_configurations 记录
+	if originalConfig != nil {
+		configToCreate := models.AlertConfiguration{
+			AlertID:        alert.ID,
+			AutoAnnotation: originalConfig.AutoAnnotation,
+			Dashboard:      originalConfig.Dashboard,
+			MuteUntil:      originalConfig.MuteUntil,
+			NoDataFire:     originalConfig.NoDataFire,
+			NoDataSeverity: originalConfig.NoDataSeverity,
+			Threshold:      originalConfig.Threshold,
+			Type:           originalConfig.Type,
+			Version:        originalConfig.Version,
+			SendResolved:   originalConfig.SendResolved,
+			RawConfig:      originalConfig.RawConfig,
+		}
+
+		if err := tx.Create(&configToCreate).Error; err != nil {
+			return &CreateSectionError{Alert: alert.Name, Section: SectionConfiguration, Err: err}
+		}
+
+		originalConfig.ID = configToCreate.ID
+		alert.ConfigurationID = &configToCreate.ID
+
+		// 步骤3: 创建所有配置表记录，并设置 alert_config_id
+		if originalConfig.ConditionConfig != nil {
+			originalConfig.ConditionConfig.AlertConfigID = configToCreate.ID
+			if err := tx.Create(originalConfig.ConditionConfig).Error; err != nil {
+				return &CreateSectionError{Alert: alert.Name, Section: "configuration.condition_config", Err: err}
 			}
+		}
 
-			if originalConfig.SinkEventStoreConfig != nil {
-				originalConfig.SinkEventStoreConfig.AlertConfigID = configToCreate.ID
-				if err := tx.Create(originalConfig.SinkEventStoreConfig).Error; err != nil {
-					return fmt.Errorf("failed to create sink event store configuration: %w", err)
-				}
+		if originalConfig.GroupConfig != nil {
+			originalConfig.GroupConfig.AlertConfigID = configToCreate.ID
+			if err := tx.Create(originalConfig.GroupConfig).Error; err != nil {
+				return &CreateSectionError{Alert: alert.Name, Section: "configuration.group_config", Err: err}
 			}
+		}
 
-			// 步骤4: 创建依赖于alert_configurations的记录
-			if len(originalConfig.SeverityConfigs) > 0 {
-				for i := range originalConfig.SeverityConfigs {
-					// 如果有 EvalCondition，先创建它
-					if originalConfig.SeverityConfigs[i].EvalCondition != nil {
-						// EvalCondition 需要设置 alert_config_id，它应该引用 SeverityConfig 所属的 alert_config
-						originalConfig.SeverityConfigs[i].EvalCondition.AlertConfigID = configToCreate.ID
-						if err := tx.Create(originalConfig.SeverityConfigs[i].EvalCondition).Error; err != nil {
-							return fmt.Errorf("failed to create eval condition: %w", err)
-						}
-						originalConfig.SeverityConfigs[i].EvalConditionID = &originalConfig.SeverityConfigs[i].EvalCondition.ID
-					}
-
-					originalConfig.SeverityConfigs[i].AlertConfigID = configToCreate.ID
-					originalConfig.SeverityConfigs[i].ID = 0
-				}
-				if err := tx.Create(&originalConfig.SeverityConfigs).Error; err != nil {
-					return fmt.Errorf("failed to create severity configurations: %w", err)
-				}
+		if originalConfig.PolicyConfig != nil {
+			originalConfig.PolicyConfig.AlertConfigID = configToCreate.ID
+			if err := tx.Create(originalConfig.PolicyConfig).Error; err != nil {
+				return &CreateSectionError{Alert: alert.Name, Section: "configuration.policy_config", Err: err}
 			}
+		}
 
-			if len(originalConfig.JoinConfigs) > 0 {
-				for i := range originalConfig.JoinConfigs {
-					originalConfig.JoinConfigs[i].AlertConfigID = configToCreate.ID
-					originalConfig.JoinConfigs[i].ID = 0
-				}
-				if err := tx.Create(&originalConfig.JoinConfigs).Error; err != nil {
-					return fmt.Errorf("failed to create join configurations: %w", err)
-				}
+		if originalConfig.TemplateConfig != nil {
+			originalConfig.TemplateConfig.AlertConfigID = configToCreate.ID
+			if err := validateTemplateConfig(originalConfig.TemplateConfig); err != nil {
+				return &CreateSectionError{Alert: alert.Name, Section: "configuration.template_config", Err: err}
+			}
+			if err := tx.Create(originalConfig.TemplateConfig).Error; err != nil {
+				return &CreateSectionError{Alert: alert.Name, Section: "configuration.template_config", Err: err}
 			}
 		}
 
-		// 步骤5: 创建 Schedule
-		if originalSchedule != nil {
-			scheduleToCreate := models.AlertSchedule{
-				AlertID:        alert.ID,
-				CronExpression: originalSchedule.CronExpression,
-				Delay:          originalSchedule.Delay,
-				Interval:       originalSchedule.Interval,
-				RunImmediately: originalSchedule.RunImmediately,
-				TimeZone:       originalSchedule.TimeZone,
-				Type:           originalSchedule.Type,
+		// 创建 Sink 配置，未设置 enabled 的 Sink 按关闭创建
+		applySinkCreateDefaults(originalConfig)
+		if originalConfig.SinkAlerthubConfig != nil {
+			originalConfig.SinkAlerthubConfig.AlertConfigID = configToCreate.ID
+			if err := tx.Create(originalConfig.SinkAlerthubConfig).Error; err != nil {
+				return &CreateSectionError{Alert: alert.Name, Section: "configuration.sink_alerthub_config", Err: err}
 			}
+		}
 
-			if err := tx.Create(&scheduleToCreate).Error; err != nil {
-				return fmt.Errorf("failed to create alert schedule: %w", err)
+		if originalConfig.SinkCmsConfig != nil {
+			originalConfig.SinkCmsConfig.AlertConfigID = configToCreate.ID
+			if err := tx.Create(originalConfig.SinkCmsConfig).Error; err != nil {
+				return &CreateSectionError{Alert: alert.Name, Section: "configuration.sink_cms_config", Err: err}
 			}
-			alert.ScheduleID = &scheduleToCreate.ID
 		}
 
-		// 步骤6: 创建 Tags
-		if len(originalTags) > 0 {
-			tagsToCreate := make([]models.AlertTag, len(originalTags))
-			for i, tag := range originalTags {
-				tagsToCreate[i] = models.AlertTag{
-					AlertID:  alert.ID,
-					TagType:  tag.TagType,
-					TagKey:   tag.TagKey,
-					TagValue: tag.TagValue,
-				}
+		if originalConfig.SinkEventStoreConfig != nil {
+			originalConfig.SinkEventStoreConfig.AlertConfigID = configToCreate.ID
+			if err := tx.Create(originalConfig.SinkEventStoreConfig).Error; err != nil {
+				return &CreateSectionError{Alert: alert.Name, Section: "configuration.sink_event_store_config", Err: err}
 			}
-			if err := tx.Create(&tagsToCreate).Error; err != nil {
-				return fmt.Errorf("failed to create alert tags: %w", err)
+		}
+
+		// 步骤4: 创建依赖于alert_configurations的记录
+		if len(originalConfig.SeverityConfigs) > 0 {
+			if err := createSeverityConfigs(tx, configToCreate.ID, originalConfig.SeverityConfigs); err != nil {
+				return &CreateSectionError{Alert: alert.Name, Section: "configuration.severity_configs", Err: err}
 			}
 		}
 
-		// 步骤7: 创建 Queries
-		if len(originalQueries) > 0 {
-			queriesToCreate := make([]models.AlertQuery, len(originalQueries))
-			for i, query := range originalQueries {
-				queriesToCreate[i] = models.AlertQuery{
-					AlertID:      alert.ID,
-					ChartTitle:   query.ChartTitle,
-					DashboardId:  query.DashboardId,
-					End:          query.End,
-					PowerSqlMode: query.PowerSqlMode,
-					Project:      query.Project,
-					Query:        query.Query,
-					Region:       query.Region,
-					RoleArn:      query.RoleArn,
-					Start:        query.Start,
-					Store:        query.Store,
-					StoreType:    query.StoreType,
-					TimeSpanType: query.TimeSpanType,
-					Ui:           query.Ui,
-				}
+		if len(originalConfig.JoinConfigs) > 0 {
+			for i := range originalConfig.JoinConfigs {
+				originalConfig.JoinConfigs[i].AlertConfigID = configToCreate.ID
+				originalConfig.JoinConfigs[i].ID = 0
 			}
-			if err := tx.Create(&queriesToCreate).Error; err != nil {
-				return fmt.Errorf("failed to create alert queries: %w", err)
+			if err := tx.CreateInBatches(&originalConfig.JoinConfigs, childInsertBatchSize).Error; err != nil {
+				return &CreateSectionError{Alert: alert.Name, Section: "configuration.join_configs", Err: err}
 			}
 		}
 
-		// 步骤8: 最后更新主记录的关联ID
-		updateData := map[string]interface{}{}
-		if alert.ConfigurationID != nil {
-			updateData["configuration_id"] = *alert.ConfigurationID
+		// 回填子配置外键，否则读取时无法预加载子配置
+		if err := linkConfigurationChildren(tx, configToCreate.ID, originalConfig); err != nil {
+			return &CreateSectionError{Alert: alert.Name, Section: SectionConfiguration, Err: err}
 		}
-		if alert.ScheduleID != nil {
-			updateData["schedule_id"] = *alert.ScheduleID
+	}
+
+	// 步骤5: 创建 Schedule
+	if originalSchedule != nil {
+		scheduleToCreate := models.AlertSchedule{
+			AlertID:        alert.ID,
+			CronExpression: originalSchedule.CronExpression,
+			Delay:          originalSchedule.Delay,
+			Interval:       originalSchedule.Interval,
+			RunImmediately: originalSchedule.RunImmediately,
+			TimeZone:       originalSchedule.TimeZone,
+			Type:           originalSchedule.Type,
 		}
 
-		if len(updateData) > 0 {
-			if err := tx.Model(&models.Alert{}).Where("id = ?", alert.ID).Updates(updateData).Error; err != nil {
-				return fmt.Errorf("failed to update alert with relation IDs: %w", err)
+		if err := tx.Create(&scheduleToCreate).Error; err != nil {
+			return &CreateSectionError{Alert: alert.Name, Section: SectionSchedule, Err: err}
+		}
+		alert.ScheduleID = &scheduleToCreate.ID
+	}
+
+	// 步骤6: 创建 Tags
+	if len(originalTags) > 0 {
+		tagsToCreate := make([]models.AlertTag, len(originalTags))
+		for i, tag := range originalTags {
+			tagsToCreate[i] = models.AlertTag{
+				AlertID:  alert.ID,
+				TagType:  tag.TagType,
+				TagKey:   tag.TagKey,
+				TagValue: tag.TagValue,
 			}
 		}
+		if err := tx.CreateInBatches(&tagsToCreate, childInsertBatchSize).Error; err != nil {
+			return &CreateSectionError{Alert: alert.Name, Section: SectionTags, Err: err}
+		}
+	}
 
-		return nil
-	})
-}
+	// 步骤7: 创建 Queries
+	if len(originalQueries) > 0 {
+		queriesToCreate := make([]models.AlertQuery, len(originalQueries))
+		for i, query := range originalQueries {
+			queriesToCreate[i] = models.AlertQuery{
+				AlertID:      alert.ID,
+				ChartTitle:   query.ChartTitle,
+				DashboardId:  query.DashboardId,
+				End:          query.End,
+				PowerSqlMode: query.PowerSqlMode,
+				Project:      query.Project,
+				Query:        query.Query,
+				Region:       query.Region,
+				RoleArn:      query.RoleArn,
+				Start:        query.Start,
+				Store:        query.Store,
+				StoreType:    query.StoreType,
+				TimeSpanType: query.TimeSpanType,
+				Ui:           query.Ui,
+			}
+		}
+		if err := tx.CreateInBatches(&queriesToCreate, childInsertBatchSize).Error; err != nil {
+			return &CreateSectionError{Alert: alert.Name, Section: SectionQueries, Err: err}
+		}
+	}
+
+	// 步骤8: 最后更新主记录的关联ID
+	updateData := map[string]interface{}{}
+	if alert.ConfigurationID != nil {
+		updateData["configuration_id"] = *alert.ConfigurationID
+	}
+	if alert.ScheduleID != nil {
+		updateData["schedule_id"] = *alert.ScheduleID
+	}
 
-// 注意：deleteConfigurationAssociations 函数已被移除
-// 根据新的schema设计，外键约束会自动处理级联删除，不再需要手动删除关联数据
+	if err := tx.Model(&models.Alert{}).Where("id = ?", alert.ID).Updates(updateData).Error; err != nil {
+		return &CreateSectionError{Alert: alert.Name, Section: CreateSectionRelations, Err: err}
+	}
+
+	return nil
+}
 
 // recreateConfiguration 重新创建 Configuration 及其关联数据
 func (s *alertStore) recreateConfiguration(tx *gorm.DB, alert *models.Alert) error {
@@ -449,9 +984,20 @@ func (s *alertStore) recreateConfiguration(tx *gorm.DB, alert *models.Alert) err
 		return nil
 	}
 
-	// 先删除旧的 Configuration 记录（会自动级联删除所有配置表记录）
+	// 先删除旧的 Configuration 记录及其所有配置表记录（外键没有级联删除）
 	if alert.ConfigurationID != nil {
-		if err := tx.Delete(&models.AlertConfiguration{}, *alert.ConfigurationID).Error; err != nil {
+		// enabled 为 nil 的 Sink 表示保持不变，删除前沿用旧配置中的值
+		if err := inheritSinkEnabled(tx, *alert.ConfigurationID, alert.Configuration); err != nil {
+			return err
+		}
+		// 未提供 raw_config 时沿用旧配置中保存的原始配置
+		if err := inheritRawConfig(tx, *alert.ConfigurationID, alert.Configuration); err != nil {
+			return err
+		}
+		if err := clearAlertReferences(tx, alert.ID, "configuration_id"); err != nil {
+			return err
+		}
+		if err := deleteConfigurations(tx, []uint{*alert.ConfigurationID}); err != nil {
 			return fmt.Errorf("failed to delete old alert configuration: %w", err)
 		}
 	}
@@ -468,6 +1014,7 @@ func (s *alertStore) recreateConfiguration(tx *gorm.DB, alert *models.Alert) err
 		Type:           alert.Configuration.Type,
 		Version:        alert.Configuration.Version,
 		SendResolved:   alert.Configuration.SendResolved,
+		RawConfig:      alert.Configuration.RawConfig,
 	}
 
 	if err := tx.Create(&configToCreate).Error; err != nil {
@@ -501,12 +1048,16 @@ func (s *alertStore) recreateConfiguration(tx *gorm.DB, alert *models.Alert) err
 
 	if alert.Configuration.TemplateConfig != nil {
 		alert.Configuration.TemplateConfig.AlertConfigID = configToCreate.ID
+		if err := validateTemplateConfig(alert.Configuration.TemplateConfig); err != nil {
+			return err
+		}
 		if err := tx.Create(alert.Configuration.TemplateConfig).Error; err != nil {
 			return fmt.Errorf("failed to create template configuration: %w", err)
 		}
 	}
 
 	// 创建 Sink 配置
+	applySinkCreateDefaults(alert.Configuration)
 	if alert.Configuration.SinkAlerthubConfig != nil {
 		alert.Configuration.SinkAlerthubConfig.AlertConfigID = configToCreate.ID
 		if err := tx.Create(alert.Configuration.SinkAlerthubConfig).Error; err != nil {
@@ -530,22 +1081,8 @@ func (s *alertStore) recreateConfiguration(tx *gorm.DB, alert *models.Alert) err
 
 	// 创建依赖于 alert_configurations 的记录
 	if len(alert.Configuration.SeverityConfigs) > 0 {
-		for i := range alert.Configuration.SeverityConfigs {
-			// 如果有 EvalCondition，先创建它
-			if alert.Configuration.SeverityConfigs[i].EvalCondition != nil {
-				// EvalCondition 需要设置 alert_config_id
-				alert.Configuration.SeverityConfigs[i].EvalCondition.AlertConfigID = configToCreate.ID
-				if err := tx.Create(alert.Configuration.SeverityConfigs[i].EvalCondition).Error; err != nil {
-					return fmt.Errorf("failed to create eval condition: %w", err)
-				}
-				alert.Configuration.SeverityConfigs[i].EvalConditionID = &alert.Configuration.SeverityConfigs[i].EvalCondition.ID
-			}
-
-			alert.Configuration.SeverityConfigs[i].AlertConfigID = configToCreate.ID
-			alert.Configuration.SeverityConfigs[i].ID = 0
-		}
-		if err := tx.Create(&alert.Configuration.SeverityConfigs).Error; err != nil {
-			return fmt.Errorf("failed to create severity configurations: %w", err)
+		if err := createSeverityConfigs(tx, configToCreate.ID, alert.Configuration.SeverityConfigs); err != nil {
+			return err
 		}
 	}
 
@@ -554,47 +1091,117 @@ func (s *alertStore) recreateConfiguration(tx *gorm.DB, alert *models.Alert) err
 			alert.Configuration.JoinConfigs[i].AlertConfigID = configToCreate.ID
 			alert.Configuration.JoinConfigs[i].ID = 0
 		}
-		if err := tx.Create(&alert.Configuration.JoinConfigs).Error; err != nil {
+		if err := tx.CreateInBatches(&alert.Configuration.JoinConfigs, childInsertBatchSize).Error; err != nil {
 			return fmt.Errorf("failed to create join configurations: %w", err)
 		}
 	}
 
+	// 回填子配置外键，否则读取时无法预加载子配置
+	return linkConfigurationChildren(tx, configToCreate.ID, alert.Configuration)
+}
+
+// checkUnmodified 乐观锁检查：只有存储的 last_modified_time 仍等于 ExpectedLastModifiedTime 时才把它推进到当前时间，
+// 没有行被更新时返回 ErrAlertModified。新值总是大于预期值，避免同一秒内的连续更新因值不变而影响 0 行
+func checkUnmodified(tx *gorm.DB, alert *models.Alert) error {
+	expected := *alert.ExpectedLastModifiedTime
+	next := time.Now().Unix()
+	if next <= expected {
+		next = expected + 1
+	}
+
+	result := tx.Model(&models.Alert{}).
+		Where("id = ? AND (last_modified_time = ? OR (last_modified_time IS NULL AND ? = 0))", alert.ID, expected, expected).
+		UpdateColumn("last_modified_time", next)
+	if result.Error != nil {
+		return fmt.Errorf("failed to check alert last_modified_time: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: alert %d no longer has last_modified_time %d", ErrAlertModified, alert.ID, expected)
+	}
+
+	alert.LastModifiedTime = &next
 	return nil
 }
 
 // UpdateWithTransaction 在事务中更新 Alert 及其关联数据
 func (s *alertStore) UpdateWithTransaction(ctx context.Context, alert *models.Alert) error {
-	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return s.UpdateSectionsWithTransaction(ctx, alert, AllSections)
+}
+
+// UpdateSectionsWithTransaction 在事务中更新 Alert，只写入 sections 中列出的分区，其余分区保持不变，
+// 变更前后的快照在同一事务中写入审计记录；设置了 ExpectedLastModifiedTime 时先做乐观锁检查
+func (s *alertStore) UpdateSectionsWithTransaction(ctx context.Context, alert *models.Alert, sections []string) error {
+	return s.updateSections(ctx, alert, sections, AuditActionUpdate, false)
+}
+
+// RollbackWithTransaction 在事务中用审计快照整体替换 Alert 的全部分区，与 UpdateWithTransaction 相同，
+// 但快照中为空的标签和查询列表会清空现有数据，审计记录的操作为 rollback
+func (s *alertStore) RollbackWithTransaction(ctx context.Context, alert *models.Alert) error {
+	return s.updateSections(ctx, alert, AllSections, AuditActionRollback, true)
+}
+
+// updateSections 更新 Alert 的指定分区，并以 action 写入审计记录；
+// replaceEmpty 为 false 时空的标签和查询列表表示保持不变，为 true 时表示清空
+func (s *alertStore) updateSections(ctx context.Context, alert *models.Alert, sections []string, action string, replaceEmpty bool) error {
+	if err := ValidateSections(sections); err != nil {
+		return err
+	}
+
+	return s.transactionWithRetry(ctx, alert, func(tx *gorm.DB, alert *models.Alert) error {
 		// 确保 Alert ID 存在
 		if alert.ID == 0 {
 			return fmt.Errorf("alert ID is required for update")
 		}
 
-		// 步骤1: 更新主记录
-		updateData := map[string]interface{}{
-			"display_name":       alert.DisplayName,
-			"description":        alert.Description,
-			"status":             alert.Status,
-			"last_modified_time": alert.LastModifiedTime,
+		before, err := snapshotAlert(tx, alert.ID)
+		if err != nil {
+			return err
 		}
 
-		if err := tx.Model(&models.Alert{}).Where("id = ?", alert.ID).Updates(updateData).Error; err != nil {
-			return fmt.Errorf("failed to update alert: %w", err)
+		if alert.ExpectedLastModifiedTime != nil {
+			if err := checkUnmodified(tx, alert); err != nil {
+				return err
+			}
+		}
+
+		// 步骤1: 更新主记录
+		if containsSection(sections, SectionBase) {
+			updateData := map[string]interface{}{
+				"display_name":       alert.DisplayName,
+				"description":        alert.Description,
+				"status":             alert.Status,
+				"last_modified_time": alert.LastModifiedTime,
+			}
+
+			if err := tx.Model(&models.Alert{}).Where("id = ?", alert.ID).Updates(updateData).Error; err != nil {
+				return fmt.Errorf("failed to update alert: %w", err)
+			}
 		}
 
 		// 步骤2: 处理 Configuration 更新
-		if alert.Configuration != nil {
-			// 根据新的schema设计，更新操作更简单：
-			// 1. 删除旧的 AlertConfiguration 会自动级联删除所有配置表记录
-			// 2. 重新创建新的配置记录
-			if err := s.recreateConfiguration(tx, alert); err != nil {
-				return fmt.Errorf("failed to recreate configuration: %w", err)
+		if alert.Configuration != nil && containsSection(sections, SectionConfiguration) {
+			// 调用方通常不带 configuration_id，不补齐时旧配置不会被删除
+			if err := resolveConfigurationID(tx, alert); err != nil {
+				return err
+			}
+			unchanged, err := configurationUnchanged(tx, alert)
+			if err != nil {
+				return err
+			}
+			// 内容未变化时保留现有配置及其子记录；否则删除旧的 AlertConfiguration 及所有配置表记录，再重新创建
+			if !unchanged {
+				if err := s.recreateConfiguration(tx, alert); err != nil {
+					return fmt.Errorf("failed to recreate configuration: %w", err)
+				}
 			}
 		}
 
 		// 步骤3: 处理 Schedule 更新
-		if alert.Schedule != nil {
-			// 删除旧的 Schedule
+		if alert.Schedule != nil && containsSection(sections, SectionSchedule) {
+			// 删除旧的 Schedule，alerts.schedule_id 引用它，需要先置空
+			if err := clearAlertReferences(tx, alert.ID, "schedule_id"); err != nil {
+				return err
+			}
 			if err := tx.Where("alert_id = ?", alert.ID).Delete(&models.AlertSchedule{}).Error; err != nil {
 				return fmt.Errorf("failed to delete old schedule: %w", err)
 			}
@@ -616,66 +1223,27 @@ func (s *alertStore) UpdateWithTransaction(ctx context.Context, alert *models.Al
 			alert.ScheduleID = &scheduleToCreate.ID
 		}
 
-		// 步骤4: 处理 Tags 更新
-		if len(alert.Tags) > 0 {
-			// 删除旧的 Tags
-			if err := tx.Where("alert_id = ?", alert.ID).Delete(&models.AlertTag{}).Error; err != nil {
-				return fmt.Errorf("failed to delete old tags: %w", err)
-			}
-
-			// 创建新的 Tags
-			tagsToCreate := make([]models.AlertTag, len(alert.Tags))
-			for i, tag := range alert.Tags {
-				tagsToCreate[i] = models.AlertTag{
-					AlertID:  alert.ID,
-					TagType:  tag.TagType,
-					TagKey:   tag.TagKey,
-					TagValue: tag.TagValue,
-				}
-			}
-			if err := tx.Create(&tagsToCreate).Error; err != nil {
-				return fmt.Errorf("failed to create new tags: %w", err)
+		// 步骤4: 处理 Tags 更新，只写入有变化的标签
+		if (len(alert.Tags) > 0 || replaceEmpty) && containsSection(sections, SectionTags) {
+			if err := syncTags(tx, alert.ID, alert.Tags); err != nil {
+				return err
 			}
 		}
 
-		// 步骤5: 处理 Queries 更新
-		if len(alert.Queries) > 0 {
-			// 删除旧的 Queries
-			if err := tx.Where("alert_id = ?", alert.ID).Delete(&models.AlertQuery{}).Error; err != nil {
-				return fmt.Errorf("failed to delete old queries: %w", err)
-			}
-
-			// 创建新的 Queries
-			queriesToCreate := make([]models.AlertQuery, len(alert.Queries))
-			for i, query := range alert.Queries {
-				queriesToCreate[i] = models.AlertQuery{
-					AlertID:      alert.ID,
-					ChartTitle:   query.ChartTitle,
-					DashboardId:  query.DashboardId,
-					End:          query.End,
-					PowerSqlMode: query.PowerSqlMode,
-					Project:      query.Project,
-					Query:        query.Query,
-					Region:       query.Region,
-					RoleArn:      query.RoleArn,
-					Start:        query.Start,
-					Store:        query.Store,
-					StoreType:    query.StoreType,
-					TimeSpanType: query.TimeSpanType,
-					Ui:           query.Ui,
-				}
-			}
-			if err := tx.Create(&queriesToCreate).Error; err != nil {
-				return fmt.Errorf("failed to create new queries: %w", err)
+		// 步骤5: 处理 Queries 更新，内容未变化的查询保持原有行
+		if (len(alert.Queries) > 0 || replaceEmpty) && containsSection(sections, SectionQueries) {
+			if err := syncQueries(tx, alert.ID, alert.Queries); err != nil {
+				return err
 			}
 		}
 
-		// 步骤6: 更新主记录的关联ID
-		updateData = map[string]interface{}{}
-		if alert.ConfigurationID != nil {
+		// 步骤6: 更新主记录的关联ID（只更新已写入分区的关联），并始终刷新 updated_at
+		// 只修改子表时主记录的 updated_at 也需要变化，列表接口的 Last-Modified 依赖它
+		updateData := map[string]interface{}{"updated_at": time.Now()}
+		if alert.ConfigurationID != nil && containsSection(sections, SectionConfiguration) {
 			updateData["configuration_id"] = *alert.ConfigurationID
 		}
-		if alert.ScheduleID != nil {
+		if alert.ScheduleID != nil && containsSection(sections, SectionSchedule) {
 			updateData["schedule_id"] = *alert.ScheduleID
 		}
 
@@ -685,7 +1253,7 @@ func (s *alertStore) UpdateWithTransaction(ctx context.Context, alert *models.Al
 			}
 		}
 
-		return nil
+		return writeAuditLog(tx, alert.ID, action, before)
 	})
 }
 
@@ -696,370 +1264,161 @@ func (s *alertStore) Count(ctx context.Context) (int64, error) {
 	return total, err
 }
 
-// updateConfiguration 更新现有的 Configuration 及其关联数据
-func (s *alertStore) updateConfiguration(tx *gorm.DB, alert *models.Alert) error {
-	if alert.Configuration == nil {
-		return nil
-	}
-
-	// 获取现有的 Configuration ID
-	var existingConfigID uint
-	if err := tx.Model(&models.AlertConfiguration{}).Where("alert_id = ?", alert.ID).Select("id").First(&existingConfigID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			// 如果没有现有配置，则创建新的
-			return s.recreateConfiguration(tx, alert)
-		}
-		return fmt.Errorf("failed to get existing configuration ID: %w", err)
-	}
-
-	// 更新主配置记录
-	updateData := map[string]interface{}{
-		"auto_annotation":  alert.Configuration.AutoAnnotation,
-		"dashboard":        alert.Configuration.Dashboard,
-		"mute_until":       alert.Configuration.MuteUntil,
-		"no_data_fire":     alert.Configuration.NoDataFire,
-		"no_data_severity": alert.Configuration.NoDataSeverity,
-		"threshold":        alert.Configuration.Threshold,
-		"type":             alert.Configuration.Type,
-		"version":          alert.Configuration.Version,
-		"send_resolved":    alert.Configuration.SendResolved,
-	}
-
-	if err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", existingConfigID).Updates(updateData).Error; err != nil {
-		return fmt.Errorf("failed to update alert configuration: %w", err)
-	}
-
-	// 更新关联的配置记录 - 使用 upsert 逻辑避免重复
-	if alert.Configuration.ConditionConfig != nil {
-		if err := s.upsertConditionConfig(tx, existingConfigID, alert.Configuration.ConditionConfig); err != nil {
-			return fmt.Errorf("failed to upsert condition configuration: %w", err)
-		}
-	}
-
-	if alert.Configuration.GroupConfig != nil {
-		if err := s.upsertGroupConfig(tx, existingConfigID, alert.Configuration.GroupConfig); err != nil {
-			return fmt.Errorf("failed to upsert group configuration: %w", err)
-		}
-	}
-
-	if alert.Configuration.PolicyConfig != nil {
-		if err := s.upsertPolicyConfig(tx, existingConfigID, alert.Configuration.PolicyConfig); err != nil {
-			return fmt.Errorf("failed to upsert policy configuration: %w", err)
-		}
-	}
-
-	if alert.Configuration.TemplateConfig != nil {
-		if err := s.upsertTemplateConfig(tx, existingConfigID, alert.Configuration.TemplateConfig); err != nil {
-			return fmt.Errorf("failed to upsert template configuration: %w", err)
-		}
-	}
-
-	// 更新 Sink 配置 - 使用 upsert 逻辑避免重复
-	if alert.Configuration.SinkAlerthubConfig != nil {
-		if err := s.upsertSinkAlerthubConfig(tx, existingConfigID, alert.Configuration.SinkAlerthubConfig); err != nil {
-			return fmt.Errorf("failed to upsert sink alerthub configuration: %w", err)
-		}
-	}
-
-	if alert.Configuration.SinkCmsConfig != nil {
-		if err := s.upsertSinkCmsConfig(tx, existingConfigID, alert.Configuration.SinkCmsConfig); err != nil {
-			return fmt.Errorf("failed to upsert sink cms configuration: %w", err)
-		}
-	}
-
-	if alert.Configuration.SinkEventStoreConfig != nil {
-		if err := s.upsertSinkEventStoreConfig(tx, existingConfigID, alert.Configuration.SinkEventStoreConfig); err != nil {
-			return fmt.Errorf("failed to upsert sink event store configuration: %w", err)
-		}
-	}
-
-	// 更新依赖于 alert_configurations 的记录
-	if len(alert.Configuration.SeverityConfigs) > 0 {
-		// 先删除旧的严重程度配置
-		if err := tx.Where("alert_config_id = ?", existingConfigID).Delete(&models.SeverityConfiguration{}).Error; err != nil {
-			return fmt.Errorf("failed to delete old severity configurations: %w", err)
-		}
-
-		// 创建新的严重程度配置
-		for i := range alert.Configuration.SeverityConfigs {
-			// 如果有 EvalCondition，先创建它
-			if alert.Configuration.SeverityConfigs[i].EvalCondition != nil {
-				if err := tx.Create(alert.Configuration.SeverityConfigs[i].EvalCondition).Error; err != nil {
-					return fmt.Errorf("failed to create eval condition: %w", err)
-				}
-				alert.Configuration.SeverityConfigs[i].EvalConditionID = &alert.Configuration.SeverityConfigs[i].EvalCondition.ID
-			}
+// ListTags 分页获取 Alert 的标签，tagType 为空时返回所有类型
+func (s *alertStore) ListTags(ctx context.Context, alertID uint, tagType string, offset, limit int) ([]models.AlertTag, int64, error) {
+	var tags []models.AlertTag
+	var total int64
 
-			alert.Configuration.SeverityConfigs[i].AlertConfigID = existingConfigID
-			alert.Configuration.SeverityConfigs[i].ID = 0
-		}
-		if err := tx.Create(&alert.Configuration.SeverityConfigs).Error; err != nil {
-			return fmt.Errorf("failed to create severity configurations: %w", err)
-		}
+	query := s.db.WithContext(ctx).Model(&models.AlertTag{}).Where("alert_id = ?", alertID)
+	if tagType != "" {
+		query = query.Where("tag_type = ?", tagType)
 	}
 
-	if len(alert.Configuration.JoinConfigs) > 0 {
-		// 先删除旧的 Join 配置
-		if err := tx.Where("alert_config_id = ?", existingConfigID).Delete(&models.JoinConfiguration{}).Error; err != nil {
-			return fmt.Errorf("failed to delete old join configurations: %w", err)
-		}
-
-		// 创建新的 Join 配置
-		for i := range alert.Configuration.JoinConfigs {
-			alert.Configuration.JoinConfigs[i].AlertConfigID = existingConfigID
-			alert.Configuration.JoinConfigs[i].ID = 0
-		}
-		if err := tx.Create(&alert.Configuration.JoinConfigs).Error; err != nil {
-			return fmt.Errorf("failed to create join configurations: %w", err)
-		}
+	// 获取总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
 
-	// 设置主记录的配置ID
-	alert.ConfigurationID = &existingConfigID
+	// 获取分页数据
+	err := query.
+		Offset(offset).
+		Limit(limit).
+		Order("id ASC").
+		Find(&tags).Error
 
-	return nil
+	return tags, total, err
 }
 
-// upsertConditionConfig 更新或插入条件配置
-func (s *alertStore) upsertConditionConfig(tx *gorm.DB, alertConfigID uint, config *models.ConditionConfiguration) error {
-	// 查找现有的条件配置（通过主配置记录的外键引用）
-	var existingConfigID *uint
-	err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", alertConfigID).Select("condition_config_id").First(&existingConfigID).Error
+// GetTag 根据类型和键获取 Alert 的标签
+func (s *alertStore) GetTag(ctx context.Context, alertID uint, tagType, tagKey string) (*models.AlertTag, error) {
+	var tag models.AlertTag
+	err := s.db.WithContext(ctx).
+		Where("alert_id = ? AND tag_type = ? AND tag_key = ?", alertID, tagType, tagKey).
+		First(&tag).Error
 	if err != nil {
-		return fmt.Errorf("failed to get existing condition config ID: %w", err)
+		return nil, err
 	}
+	return &tag, nil
+}
 
-	if existingConfigID == nil || *existingConfigID == 0 {
-		// 不存在则创建新的
-		config.ID = 0
-		if err := tx.Create(config).Error; err != nil {
-			return fmt.Errorf("failed to create condition configuration: %w", err)
-		}
-		// 更新主配置记录中的引用ID
-		if err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", alertConfigID).Update("condition_config_id", config.ID).Error; err != nil {
-			return fmt.Errorf("failed to update condition config reference: %w", err)
-		}
-	} else {
-		// 存在则更新
-		updateData := map[string]interface{}{
-			"condition":       config.Condition,
-			"count_condition": config.CountCondition,
-		}
-		if err := tx.Model(&models.ConditionConfiguration{}).Where("id = ?", *existingConfigID).Updates(updateData).Error; err != nil {
-			return fmt.Errorf("failed to update condition configuration: %w", err)
+// CreateTag 创建单个标签
+func (s *alertStore) CreateTag(ctx context.Context, tag *models.AlertTag) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(tag).Error; err != nil {
+			return err
 		}
-		config.ID = *existingConfigID
-	}
-
-	return nil
+		return touchAlert(tx, tag.AlertID)
+	})
 }
 
-// upsertGroupConfig 更新或插入分组配置
-func (s *alertStore) upsertGroupConfig(tx *gorm.DB, alertConfigID uint, config *models.GroupConfiguration) error {
-	// 查找现有的分组配置（通过主配置记录的外键引用）
-	var existingConfigID *uint
-	err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", alertConfigID).Select("group_config_id").First(&existingConfigID).Error
-	if err != nil {
-		return fmt.Errorf("failed to get existing group config ID: %w", err)
-	}
-
-	if existingConfigID == nil || *existingConfigID == 0 {
-		// 不存在则创建新的
-		config.ID = 0
-		if err := tx.Create(config).Error; err != nil {
-			return fmt.Errorf("failed to create group configuration: %w", err)
-		}
-		// 更新主配置记录中的引用ID
-		if err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", alertConfigID).Update("group_config_id", config.ID).Error; err != nil {
-			return fmt.Errorf("failed to update group config reference: %w", err)
-		}
-	} else {
-		// 存在则更新
-		updateData := map[string]interface{}{
-			"fields": config.Fields,
-			"type":   config.Type,
+// DeleteTag 删除 Alert 的单个标签
+func (s *alertStore) DeleteTag(ctx context.Context, alertID, tagID uint) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("alert_id = ?", alertID).Delete(&models.AlertTag{}, tagID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete alert tag: %w", result.Error)
 		}
-		if err := tx.Model(&models.GroupConfiguration{}).Where("id = ?", *existingConfigID).Updates(updateData).Error; err != nil {
-			return fmt.Errorf("failed to update group configuration: %w", err)
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
 		}
-		config.ID = *existingConfigID
-	}
-
-	return nil
+		return touchAlert(tx, alertID)
+	})
 }
 
-// upsertPolicyConfig 更新或插入策略配置
-func (s *alertStore) upsertPolicyConfig(tx *gorm.DB, alertConfigID uint, config *models.PolicyConfiguration) error {
-	// 查找现有的策略配置（通过主配置记录的外键引用）
-	var existingConfigID *uint
-	err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", alertConfigID).Select("policy_config_id").First(&existingConfigID).Error
-	if err != nil {
-		return fmt.Errorf("failed to get existing policy config ID: %w", err)
-	}
-
-	if existingConfigID == nil || *existingConfigID == 0 {
-		// 不存在则创建新的
-		config.ID = 0
-		if err := tx.Create(config).Error; err != nil {
-			return fmt.Errorf("failed to create policy configuration: %w", err)
-		}
-		// 更新主配置记录中的引用ID
-		if err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", alertConfigID).Update("policy_config_id", config.ID).Error; err != nil {
-			return fmt.Errorf("failed to update policy config reference: %w", err)
-		}
-	} else {
-		// 存在则更新
-		updateData := map[string]interface{}{
-			"alert_policy_id":  config.AlertPolicyId,
-			"action_policy_id": config.ActionPolicyId,
-			"repeat_interval":  config.RepeatInterval,
-		}
-		if err := tx.Model(&models.PolicyConfiguration{}).Where("id = ?", *existingConfigID).Updates(updateData).Error; err != nil {
-			return fmt.Errorf("failed to update policy configuration: %w", err)
-		}
-		config.ID = *existingConfigID
-	}
+// Autocomplete 按名称前缀查询 Alert，只返回 id/name/display_name
+// name 列上的唯一索引可直接支持 LIKE 'prefix%' 的前缀扫描
+func (s *alertStore) Autocomplete(ctx context.Context, prefix string, limit int) ([]AlertSuggestion, error) {
+	var suggestions []AlertSuggestion
+	err := s.db.WithContext(ctx).
+		Model(&models.Alert{}).
+		Select("id", "name", "display_name").
+		Where("name LIKE ? ESCAPE '!'", escapeLike(prefix)+"%").
+		Order("name ASC").
+		Limit(limit).
+		Find(&suggestions).Error
+	return suggestions, err
+}
 
-	return nil
+// escapeLike 转义 LIKE 模式中的通配符，转义符使用 '!'：
+// 反斜杠在 MySQL 字符串字面量中本身需要转义，在 PostgreSQL 和 SQLite 中则不是默认转义符
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`!`, `!!`, `%`, `!%`, `_`, `!_`)
+	return replacer.Replace(value)
 }
 
-// upsertTemplateConfig 更新或插入模板配置
-func (s *alertStore) upsertTemplateConfig(tx *gorm.DB, alertConfigID uint, config *models.TemplateConfiguration) error {
-	// 查找现有的模板配置（通过主配置记录的外键引用）
-	var existingConfigID *uint
-	err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", alertConfigID).Select("template_config_id").First(&existingConfigID).Error
-	if err != nil {
-		return fmt.Errorf("failed to get existing template config ID: %w", err)
+// applySinkCreateDefaults 创建 Sink 配置前将未设置的 enabled 置为 false，
+// 与列默认值一致，并让调用方持有的模型反映实际写入的值
+func applySinkCreateDefaults(configuration *models.AlertConfiguration) {
+	if configuration.SinkAlerthubConfig != nil {
+		configuration.SinkAlerthubConfig.Enabled = falseIfNil(configuration.SinkAlerthubConfig.Enabled)
 	}
-
-	if existingConfigID == nil || *existingConfigID == 0 {
-		// 不存在则创建新的
-		config.ID = 0
-		if err := tx.Create(config).Error; err != nil {
-			return fmt.Errorf("failed to create template configuration: %w", err)
-		}
-		// 更新主配置记录中的引用ID
-		if err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", alertConfigID).Update("template_config_id", config.ID).Error; err != nil {
-			return fmt.Errorf("failed to update template config reference: %w", err)
-		}
-	} else {
-		// 存在则更新
-		updateData := map[string]interface{}{
-			"template_id": config.TemplateId,
-			"lang":        config.Lang,
-			"type":        config.Type,
-			"version":     config.Version,
-			"aonotations": config.Aonotations,
-			"tokens":      config.Tokens,
-		}
-		if err := tx.Model(&models.TemplateConfiguration{}).Where("id = ?", *existingConfigID).Updates(updateData).Error; err != nil {
-			return fmt.Errorf("failed to update template configuration: %w", err)
-		}
-		config.ID = *existingConfigID
+	if configuration.SinkCmsConfig != nil {
+		configuration.SinkCmsConfig.Enabled = falseIfNil(configuration.SinkCmsConfig.Enabled)
+	}
+	if configuration.SinkEventStoreConfig != nil {
+		configuration.SinkEventStoreConfig.Enabled = falseIfNil(configuration.SinkEventStoreConfig.Enabled)
 	}
-
-	return nil
 }
 
-// upsertSinkAlerthubConfig 更新或插入 Sink Alerthub 配置
-func (s *alertStore) upsertSinkAlerthubConfig(tx *gorm.DB, alertConfigID uint, config *models.SinkAlerthubConfiguration) error {
-	// 查找现有的配置（通过主配置记录的外键引用）
-	var existingConfigID *uint
-	err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", alertConfigID).Select("sink_alerthub_config_id").First(&existingConfigID).Error
-	if err != nil {
-		return fmt.Errorf("failed to get existing sink alerthub config ID: %w", err)
+// inheritSinkEnabled 对 enabled 为 nil 的 Sink，沿用 configID 对应的现有 Sink 配置中的值
+func inheritSinkEnabled(tx *gorm.DB, configID uint, configuration *models.AlertConfiguration) error {
+	var current *bool
+	load := func(model interface{}) error {
+		var values []*bool
+		err := tx.Model(model).Where("alert_config_id = ?", configID).Order("id ASC").Limit(1).Pluck("enabled", &values).Error
+		if err != nil {
+			return fmt.Errorf("failed to load existing sink configuration: %w", err)
+		}
+		current = nil
+		if len(values) > 0 {
+			current = values[0]
+		}
+		return nil
 	}
 
-	if existingConfigID == nil || *existingConfigID == 0 {
-		// 不存在则创建新的
-		config.ID = 0
-		if err := tx.Create(config).Error; err != nil {
-			return fmt.Errorf("failed to create sink alerthub configuration: %w", err)
-		}
-		// 更新主配置记录中的引用ID
-		if err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", alertConfigID).Update("sink_alerthub_config_id", config.ID).Error; err != nil {
-			return fmt.Errorf("failed to update sink alerthub config reference: %w", err)
+	if sink := configuration.SinkAlerthubConfig; sink != nil && sink.Enabled == nil {
+		if err := load(&models.SinkAlerthubConfiguration{}); err != nil {
+			return err
 		}
-	} else {
-		// 存在则更新
-		updateData := map[string]interface{}{
-			"enabled": config.Enabled,
+		sink.Enabled = current
+	}
+	if sink := configuration.SinkCmsConfig; sink != nil && sink.Enabled == nil {
+		if err := load(&models.SinkCmsConfiguration{}); err != nil {
+			return err
 		}
-		if err := tx.Model(&models.SinkAlerthubConfiguration{}).Where("id = ?", *existingConfigID).Updates(updateData).Error; err != nil {
-			return fmt.Errorf("failed to update sink alerthub configuration: %w", err)
+		sink.Enabled = current
+	}
+	if sink := configuration.SinkEventStoreConfig; sink != nil && sink.Enabled == nil {
+		if err := load(&models.SinkEventStoreConfiguration{}); err != nil {
+			return err
 		}
-		config.ID = *existingConfigID
+		sink.Enabled = current
 	}
-
 	return nil
 }
 
-// upsertSinkCmsConfig 更新或插入 Sink CMS 配置
-func (s *alertStore) upsertSinkCmsConfig(tx *gorm.DB, alertConfigID uint, config *models.SinkCmsConfiguration) error {
-	// 查找现有的配置（通过主配置记录的外键引用）
-	var existingConfigID *uint
-	err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", alertConfigID).Select("sink_cms_config_id").First(&existingConfigID).Error
-	if err != nil {
-		return fmt.Errorf("failed to get existing sink cms config ID: %w", err)
+// inheritRawConfig RawConfig 为 nil 时沿用 configID 对应的现有配置中保存的原始配置
+func inheritRawConfig(tx *gorm.DB, configID uint, configuration *models.AlertConfiguration) error {
+	if configuration.RawConfig != nil {
+		return nil
 	}
-
-	if existingConfigID == nil || *existingConfigID == 0 {
-		// 不存在则创建新的
-		config.ID = 0
-		if err := tx.Create(config).Error; err != nil {
-			return fmt.Errorf("failed to create sink cms configuration: %w", err)
-		}
-		// 更新主配置记录中的引用ID
-		if err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", alertConfigID).Update("sink_cms_config_id", config.ID).Error; err != nil {
-			return fmt.Errorf("failed to update sink cms config reference: %w", err)
-		}
-	} else {
-		// 存在则更新
-		updateData := map[string]interface{}{
-			"enabled": config.Enabled,
-		}
-		if err := tx.Model(&models.SinkCmsConfiguration{}).Where("id = ?", *existingConfigID).Updates(updateData).Error; err != nil {
-			return fmt.Errorf("failed to update sink cms configuration: %w", err)
-		}
-		config.ID = *existingConfigID
+	// 旧配置没有原始配置时 raw_config 为 NULL，Pluck 到 []*string 无法扫描 NULL，使用 sql.NullString
+	var values []sql.NullString
+	if err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", configID).Pluck("raw_config", &values).Error; err != nil {
+		return fmt.Errorf("failed to load existing raw configuration: %w", err)
+	}
+	if len(values) > 0 && values[0].Valid {
+		rawConfig := values[0].String
+		configuration.RawConfig = &rawConfig
 	}
-
 	return nil
 }
 
-// upsertSinkEventStoreConfig 更新或插入 Sink Event Store 配置
-func (s *alertStore) upsertSinkEventStoreConfig(tx *gorm.DB, alertConfigID uint, config *models.SinkEventStoreConfiguration) error {
-	// 查找现有的配置（通过主配置记录的外键引用）
-	var existingConfigID *uint
-	err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", alertConfigID).Select("sink_event_store_config_id").First(&existingConfigID).Error
-	if err != nil {
-		return fmt.Errorf("failed to get existing sink event store config ID: %w", err)
-	}
-
-	if existingConfigID == nil || *existingConfigID == 0 {
-		// 不存在则创建新的
-		config.ID = 0
-		if err := tx.Create(config).Error; err != nil {
-			return fmt.Errorf("failed to create sink event store configuration: %w", err)
-		}
-		// 更新主配置记录中的引用ID
-		if err := tx.Model(&models.AlertConfiguration{}).Where("id = ?", alertConfigID).Update("sink_event_store_config_id", config.ID).Error; err != nil {
-			return fmt.Errorf("failed to update sink event store config reference: %w", err)
-		}
-	} else {
-		// 存在则更新
-		updateData := map[string]interface{}{
-			"enabled":     config.Enabled,
-			"endpoint":    config.Endpoint,
-			"event_store": config.EventStore,
-			"project":     config.Project,
-			"role_arn":    config.RoleArn,
-		}
-		if err := tx.Model(&models.SinkEventStoreConfiguration{}).Where("id = ?", *existingConfigID).Updates(updateData).Error; err != nil {
-			return fmt.Errorf("failed to update sink event store configuration: %w", err)
-		}
-		config.ID = *existingConfigID
+// falseIfNil 可空布尔值为 nil 时返回 false
+func falseIfNil(value *bool) *bool {
+	if value != nil {
+		return value
 	}
-
-	return nil
+	disabled := false
+	return &disabled
 }
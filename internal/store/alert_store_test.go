@@ -0,0 +1,664 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/pkg/database"
+	"github.com/alibabacloud-go/tea/tea"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// errInjected 注入到指定表写入中的失败
+var errInjected = errors.New("injected failure")
+
+// failWritesTo 让之后对 table 的插入（update 为 true 时为更新）失败
+func failWritesTo(t *testing.T, db *gorm.DB, table string, update bool) {
+	t.Helper()
+
+	fail := func(tx *gorm.DB) {
+		if tx.Statement.Table == table {
+			tx.AddError(errInjected)
+		}
+	}
+	var err error
+	if update {
+		err = db.Callback().Update().Before("gorm:update").Register("test:fail_update", fail)
+	} else {
+		err = db.Callback().Create().Before("gorm:create").Register("test:fail_create", fail)
+	}
+	if err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+}
+
+// countRows 返回每张迁移表中的行数，不含 sync_runs 和快照等与 Alert 无关的表
+func countRows(t *testing.T, db *gorm.DB) map[string]int64 {
+	t.Helper()
+
+	tables := []interface{}{
+		&models.Alert{}, &models.AlertConfiguration{}, &models.AlertSchedule{}, &models.AlertTag{}, &models.AlertQuery{},
+		&models.ConditionConfiguration{}, &models.GroupConfiguration{}, &models.PolicyConfiguration{},
+		&models.TemplateConfiguration{}, &models.SeverityConfiguration{}, &models.JoinConfiguration{},
+		&models.SinkAlerthubConfiguration{}, &models.SinkCmsConfiguration{}, &models.SinkEventStoreConfiguration{},
+		&models.AlertAuditLog{},
+	}
+	counts := make(map[string]int64, len(tables))
+	for _, model := range tables {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatalf("parse model: %v", err)
+		}
+		var count int64
+		if err := db.Unscoped().Model(model).Count(&count).Error; err != nil {
+			t.Fatalf("count %s: %v", stmt.Table, err)
+		}
+		counts[stmt.Table] = count
+	}
+	return counts
+}
+
+func TestCreateWithTransactionRollsBackFailedSection(t *testing.T) {
+	tests := []struct {
+		table       string
+		update      bool
+		wantSection string
+	}{
+		{table: "alerts", wantSection: SectionBase},
+		{table: "alert_configurations", wantSection: SectionConfiguration},
+		{table: "condition_configurations", wantSection: "configuration.condition_config"},
+		{table: "group_configurations", wantSection: "configuration.group_config"},
+		{table: "policy_configurations", wantSection: "configuration.policy_config"},
+		{table: "template_configurations", wantSection: "configuration.template_config"},
+		{table: "sink_alerthub_configurations", wantSection: "configuration.sink_alerthub_config"},
+		{table: "sink_cms_configurations", wantSection: "configuration.sink_cms_config"},
+		{table: "sink_event_store_configurations", wantSection: "configuration.sink_event_store_config"},
+		{table: "severity_configurations", wantSection: "configuration.severity_configs"},
+		{table: "join_configurations", wantSection: "configuration.join_configs"},
+		{table: "alert_schedules", wantSection: SectionSchedule},
+		{table: "alert_tags", wantSection: SectionTags},
+		{table: "alert_queries", wantSection: SectionQueries},
+		{table: "alerts", update: true, wantSection: CreateSectionRelations},
+	}
+
+	for _, tt := range tests {
+		name := tt.table
+		if tt.update {
+			name += "_update"
+		}
+		t.Run(name, func(t *testing.T) {
+			s := newTestStore(t)
+			failWritesTo(t, s.db, tt.table, tt.update)
+
+			err := s.CreateWithTransaction(context.Background(), newFullAlert("broken"))
+			var sectionErr *CreateSectionError
+			if !errors.As(err, &sectionErr) {
+				t.Fatalf("error = %v, want *CreateSectionError", err)
+			}
+			if sectionErr.Alert != "broken" || sectionErr.Section != tt.wantSection {
+				t.Errorf("failed at alert=%q section=%q, want broken/%s", sectionErr.Alert, sectionErr.Section, tt.wantSection)
+			}
+			if !errors.Is(err, errInjected) {
+				t.Errorf("error %v does not wrap the injected failure", err)
+			}
+			if !strings.Contains(err.Error(), "'broken'") || !strings.Contains(err.Error(), tt.wantSection) {
+				t.Errorf("error message %q should name the alert and section", err.Error())
+			}
+
+			// 事务整体回滚：主记录和任何子表都没有残留
+			for table, count := range countRows(t, s.db) {
+				if count != 0 {
+					t.Errorf("%s has %d rows after rollback, want 0", table, count)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateWithTransactionPersistsAllSections(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.CreateWithTransaction(context.Background(), newFullAlert("complete")); err != nil {
+		t.Fatalf("CreateWithTransaction: %v", err)
+	}
+
+	// 对照组：同一个 Alert 创建成功时每张表都有数据，保证上面的回滚断言覆盖了所有子表
+	for table, count := range countRows(t, s.db) {
+		if count == 0 {
+			t.Errorf("%s is empty after a successful create", table)
+		}
+	}
+}
+
+// deadlockOnce 让对 table 的前 times 次插入（update 为 true 时为更新）返回 MySQL 死锁错误（1213），
+// 之后正常执行；返回的计数为对 table 的写入次数
+func deadlockOnce(t *testing.T, db *gorm.DB, table string, update bool, times int) *int {
+	t.Helper()
+
+	calls := 0
+	deadlock := func(tx *gorm.DB) {
+		if tx.Statement.Table != table {
+			return
+		}
+		calls++
+		if calls <= times {
+			tx.AddError(&mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"})
+		}
+	}
+	var err error
+	if update {
+		err = db.Callback().Update().Before("gorm:update").Register("test:deadlock_update", deadlock)
+	} else {
+		err = db.Callback().Create().Before("gorm:create").Register("test:deadlock_create", deadlock)
+	}
+	if err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+	return &calls
+}
+
+func TestTransactionRetriesOnDeadlock(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		deadlocks  int
+		wantErr    bool
+	}{
+		{name: "deadlock once then succeeds", maxRetries: 3, deadlocks: 1},
+		{name: "retries exhausted", maxRetries: 2, deadlocks: 3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/create", func(t *testing.T) {
+			s := newTestStore(t)
+			database.SetRetryConfig(database.RetryConfig{MaxRetries: tt.maxRetries})
+			calls := deadlockOnce(t, s.db, "alert_tags", false, tt.deadlocks)
+
+			alert := newFullAlert("retried")
+			err := s.CreateWithTransaction(context.Background(), alert)
+			if tt.wantErr {
+				if !database.IsRetryableError(err) {
+					t.Fatalf("error = %v, want the deadlock after exhausting retries", err)
+				}
+				if *calls != tt.maxRetries+1 {
+					t.Errorf("attempts = %d, want %d", *calls, tt.maxRetries+1)
+				}
+				for table, count := range countRows(t, s.db) {
+					if count != 0 {
+						t.Errorf("%s has %d rows after failed retries, want 0", table, count)
+					}
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CreateWithTransaction: %v", err)
+			}
+			// 重试前的尝试已回滚，只留下一份数据
+			counts := countRows(t, s.db)
+			if counts["alerts"] != 1 || counts["alert_tags"] != 1 || counts["alert_audit_logs"] != 1 {
+				t.Errorf("alerts=%d tags=%d audit=%d, want 1 each", counts["alerts"], counts["alert_tags"], counts["alert_audit_logs"])
+			}
+			stored, err := s.GetByName(context.Background(), "retried")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+			if alert.ID != stored.ID {
+				t.Errorf("alert.ID = %d, want the persisted ID %d", alert.ID, stored.ID)
+			}
+		})
+
+		t.Run(tt.name+"/update", func(t *testing.T) {
+			s := newTestStore(t)
+			if err := s.CreateWithTransaction(context.Background(), newFullAlert("retried")); err != nil {
+				t.Fatalf("CreateWithTransaction: %v", err)
+			}
+			database.SetRetryConfig(database.RetryConfig{MaxRetries: tt.maxRetries})
+			// 每次更新尝试都会先更新主记录
+			calls := deadlockOnce(t, s.db, "alerts", true, tt.deadlocks)
+
+			alert, err := s.GetByName(context.Background(), "retried")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+			alert.DisplayName = "updated"
+			alert.Tags = []models.AlertTag{{TagType: "label", TagKey: "team", TagValue: tea.String("sre")}}
+
+			err = s.UpdateWithTransaction(context.Background(), alert)
+			stored, getErr := s.GetByName(context.Background(), "retried")
+			if getErr != nil {
+				t.Fatalf("GetByName: %v", getErr)
+			}
+			counts := countRows(t, s.db)
+
+			if tt.wantErr {
+				if !database.IsRetryableError(err) {
+					t.Fatalf("error = %v, want the deadlock after exhausting retries", err)
+				}
+				if *calls != tt.maxRetries+1 {
+					t.Errorf("attempts = %d, want %d", *calls, tt.maxRetries+1)
+				}
+				if stored.DisplayName != "Alert retried" || len(stored.Tags) != 1 || tea.StringValue(stored.Tags[0].TagValue) != "ops" {
+					t.Errorf("failed update left changes: display_name=%q tags=%+v", stored.DisplayName, stored.Tags)
+				}
+				if counts["alert_audit_logs"] != 1 {
+					t.Errorf("audit logs = %d, want only the create", counts["alert_audit_logs"])
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("UpdateWithTransaction: %v", err)
+			}
+			if stored.DisplayName != "updated" || len(stored.Tags) != 1 || tea.StringValue(stored.Tags[0].TagValue) != "sre" {
+				t.Errorf("display_name=%q tags=%+v, want the update applied once", stored.DisplayName, stored.Tags)
+			}
+			if counts["alert_tags"] != 1 || counts["alert_audit_logs"] != 2 {
+				t.Errorf("tags=%d audit=%d, want 1 and 2", counts["alert_tags"], counts["alert_audit_logs"])
+			}
+		})
+	}
+}
+
+func TestUpdateSectionsRetriesOnDeadlock(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.CreateWithTransaction(context.Background(), newFullAlert("sections")); err != nil {
+		t.Fatalf("CreateWithTransaction: %v", err)
+	}
+	database.SetRetryConfig(database.RetryConfig{MaxRetries: 3})
+	// 只更新标签分区时，第一次插入新增的标签遇到死锁
+	calls := deadlockOnce(t, s.db, "alert_tags", false, 1)
+
+	alert, err := s.GetByName(context.Background(), "sections")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	alert.DisplayName = "not written"
+	alert.Tags = append(alert.Tags, models.AlertTag{TagType: "label", TagKey: "env", TagValue: tea.String("prod")})
+
+	if err := s.UpdateSectionsWithTransaction(context.Background(), alert, []string{SectionTags}); err != nil {
+		t.Fatalf("UpdateSectionsWithTransaction: %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("tag inserts = %d, want 2 (one deadlock, one retry)", *calls)
+	}
+
+	stored, err := s.GetByName(context.Background(), "sections")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	if stored.DisplayName != "Alert sections" {
+		t.Errorf("display_name = %q, want the base section left unchanged", stored.DisplayName)
+	}
+	if len(stored.Tags) != 2 {
+		t.Errorf("tags = %+v, want the existing tag and the added one", stored.Tags)
+	}
+	if counts := countRows(t, s.db); counts["alert_tags"] != 2 || counts["alert_audit_logs"] != 2 {
+		t.Errorf("tags=%d audit=%d, want 2 and 2 (retry applied once)", counts["alert_tags"], counts["alert_audit_logs"])
+	}
+}
+
+func TestTransactionDoesNotRetryOtherErrors(t *testing.T) {
+	s := newTestStore(t)
+	database.SetRetryConfig(database.RetryConfig{MaxRetries: 3})
+
+	calls := 0
+	if err := s.db.Callback().Create().Before("gorm:create").Register("test:count_fail", func(tx *gorm.DB) {
+		if tx.Statement.Table == "alert_tags" {
+			calls++
+			tx.AddError(errInjected)
+		}
+	}); err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+
+	if err := s.CreateWithTransaction(context.Background(), newFullAlert("once")); !errors.Is(err, errInjected) {
+		t.Fatalf("error = %v, want the injected failure", err)
+	}
+	if calls != 1 {
+		t.Errorf("attempts = %d, want 1 (non-deadlock errors are not retried)", calls)
+	}
+}
+
+// createAlerts 依次创建给定名称的 Alert
+func createAlerts(t *testing.T, s *alertStore, names ...string) {
+	t.Helper()
+
+	for _, name := range names {
+		if err := s.CreateWithTransaction(context.Background(), newFullAlert(name)); err != nil {
+			t.Fatalf("CreateWithTransaction(%s): %v", name, err)
+		}
+	}
+}
+
+func TestAutocomplete(t *testing.T) {
+	s := newTestStore(t)
+	createAlerts(t, s, "mem-high", "cpu-low", "cpu_x", "cpu-high", "cpuz", "100%-errors", "1000-errors", "disk-cpu-high")
+
+	tests := []struct {
+		name   string
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{name: "prefix ordered by name", prefix: "cpu-", limit: 10, want: []string{"cpu-high", "cpu-low"}},
+		{name: "limit applied after ordering", prefix: "cpu", limit: 2, want: []string{"cpu-high", "cpu-low"}},
+		{name: "all matches", prefix: "cpu", limit: 10, want: []string{"cpu-high", "cpu-low", "cpu_x", "cpuz"}},
+		{name: "underscore is literal", prefix: "cpu_", limit: 10, want: []string{"cpu_x"}},
+		{name: "percent is literal", prefix: "100%", limit: 10, want: []string{"100%-errors"}},
+		{name: "only prefix matches", prefix: "high", limit: 10, want: nil},
+		{name: "exact name", prefix: "mem-high", limit: 10, want: []string{"mem-high"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestions, err := s.Autocomplete(context.Background(), tt.prefix, tt.limit)
+			if err != nil {
+				t.Fatalf("Autocomplete: %v", err)
+			}
+			var got []string
+			for _, suggestion := range suggestions {
+				got = append(got, suggestion.Name)
+				if suggestion.ID == 0 || suggestion.DisplayName != "Alert "+suggestion.Name {
+					t.Errorf("suggestion = %+v, want id and display_name", suggestion)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Autocomplete(%q, %d) = %v, want %v", tt.prefix, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateSectionsLeavesUnmaskedSectionsUntouched(t *testing.T) {
+	tests := []struct {
+		name     string
+		sections []string
+	}{
+		{name: "base only", sections: []string{SectionBase}},
+		{name: "base and schedule", sections: []string{SectionBase, SectionSchedule}},
+		{name: "configuration only", sections: []string{SectionConfiguration}},
+		{name: "tags and queries", sections: []string{SectionTags, SectionQueries}},
+		{name: "all sections", sections: AllSections},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestStore(t)
+			if err := s.CreateWithTransaction(ctx, newFullAlert("masked")); err != nil {
+				t.Fatalf("CreateWithTransaction: %v", err)
+			}
+			before, err := s.GetByName(ctx, "masked")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+
+			// 每个分区都修改，只有掩码中的分区应被写入
+			changed := newFullAlert("masked")
+			changed.ID = before.ID
+			changed.DisplayName = "changed"
+			changed.Configuration.Threshold = tea.Int32(9)
+			changed.Schedule.Interval = tea.String("5m")
+			changed.Tags = []models.AlertTag{{TagType: "label", TagKey: "team", TagValue: tea.String("dev")}}
+			changed.Queries = []models.AlertQuery{{Query: "error | select count(*) as cnt", Store: tea.String("app-log"), StoreType: tea.String("log")}}
+			if err := s.UpdateSectionsWithTransaction(ctx, changed, tt.sections); err != nil {
+				t.Fatalf("UpdateSectionsWithTransaction: %v", err)
+			}
+
+			after, err := s.GetByName(ctx, "masked")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+			checks := []struct {
+				section      string
+				before, want string
+				got          string
+				sameID       bool // 未写入的分区不应被删除重建
+			}{
+				{section: SectionBase, before: before.DisplayName, want: "changed", got: after.DisplayName, sameID: true},
+				{section: SectionConfiguration, before: "1", want: "9", got: fmt.Sprint(tea.Int32Value(after.Configuration.Threshold)),
+					sameID: before.Configuration.ID == after.Configuration.ID},
+				{section: SectionSchedule, before: "1m", want: "5m", got: tea.StringValue(after.Schedule.Interval),
+					sameID: before.Schedule.ID == after.Schedule.ID},
+				{section: SectionTags, before: "ops", want: "dev", got: tea.StringValue(after.Tags[0].TagValue), sameID: true},
+				{section: SectionQueries, before: before.Queries[0].Query, want: "error | select count(*) as cnt", got: after.Queries[0].Query,
+					sameID: before.Queries[0].ID == after.Queries[0].ID},
+			}
+			for _, check := range checks {
+				if containsSection(tt.sections, check.section) {
+					if check.got != check.want {
+						t.Errorf("%s = %q, want updated %q", check.section, check.got, check.want)
+					}
+					continue
+				}
+				if check.got != check.before {
+					t.Errorf("%s = %q, want untouched %q", check.section, check.got, check.before)
+				}
+				if !check.sameID {
+					t.Errorf("%s was recreated although it is not in the mask", check.section)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateSectionsRejectsUnknownSection(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	if err := s.CreateWithTransaction(ctx, newFullAlert("masked")); err != nil {
+		t.Fatalf("CreateWithTransaction: %v", err)
+	}
+	alert, err := s.GetByName(ctx, "masked")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+
+	alert.DisplayName = "changed"
+	err = s.UpdateSectionsWithTransaction(ctx, alert, []string{SectionBase, "labels"})
+	if err == nil || !strings.Contains(err.Error(), "unknown update section 'labels'") {
+		t.Fatalf("error = %v, want unknown update section", err)
+	}
+	current, err := s.GetByName(ctx, "masked")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	if current.DisplayName != "Alert masked" {
+		t.Errorf("display_name = %q, rejected update was applied", current.DisplayName)
+	}
+}
+
+// sinkEnabled 返回三个 Sink 配置的 enabled 值，Sink 不存在时为 nil
+func sinkEnabled(configuration *models.AlertConfiguration) [3]*bool {
+	var enabled [3]*bool
+	if sink := configuration.SinkAlerthubConfig; sink != nil {
+		enabled[0] = sink.Enabled
+	}
+	if sink := configuration.SinkCmsConfig; sink != nil {
+		enabled[1] = sink.Enabled
+	}
+	if sink := configuration.SinkEventStoreConfig; sink != nil {
+		enabled[2] = sink.Enabled
+	}
+	return enabled
+}
+
+// setSinkEnabled 设置三个 Sink 配置的 enabled
+func setSinkEnabled(configuration *models.AlertConfiguration, enabled *bool) {
+	configuration.SinkAlerthubConfig = &models.SinkAlerthubConfiguration{Enabled: enabled}
+	configuration.SinkCmsConfig = &models.SinkCmsConfiguration{Enabled: enabled}
+	configuration.SinkEventStoreConfig = &models.SinkEventStoreConfiguration{Enabled: enabled}
+}
+
+func TestSinkEnabledNilVersusFalse(t *testing.T) {
+	tests := []struct {
+		name    string
+		created *bool // 创建时的 enabled
+		updated *bool // 更新时的 enabled，nil 表示未设置
+		want    bool
+	}{
+		{name: "nil on create defaults to false", created: nil, updated: nil, want: false},
+		{name: "nil on update keeps true", created: tea.Bool(true), updated: nil, want: true},
+		{name: "nil on update keeps false", created: tea.Bool(false), updated: nil, want: false},
+		{name: "false on update disables", created: tea.Bool(true), updated: tea.Bool(false), want: false},
+		{name: "true on update enables", created: tea.Bool(false), updated: tea.Bool(true), want: true},
+	}
+
+	// 更新时配置有变化则经 recreateConfiguration 重建，没有其他变化时由关联差异决定保留或重建，
+	// 两条路径对 enabled 的处理必须一致
+	paths := []struct {
+		name   string
+		modify func(configuration *models.AlertConfiguration)
+	}{
+		{name: "recreated", modify: func(configuration *models.AlertConfiguration) { configuration.Threshold = tea.Int32(2) }},
+		{name: "diffed", modify: func(configuration *models.AlertConfiguration) {}},
+	}
+
+	for _, tt := range tests {
+		for _, path := range paths {
+			t.Run(tt.name+"/"+path.name, func(t *testing.T) {
+				testSinkEnabledUpdate(t, tt.created, tt.updated, tt.want, path.modify)
+			})
+		}
+	}
+}
+
+// testSinkEnabledUpdate 以 created 创建三个 Sink，经 modify 修改配置并以 updated 更新后检查 enabled 为 want
+func testSinkEnabledUpdate(t *testing.T, created, updated *bool, want bool, modify func(configuration *models.AlertConfiguration)) {
+	t.Helper()
+
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	alert := newFullAlert("sink")
+	setSinkEnabled(alert.Configuration, created)
+	if err := s.CreateWithTransaction(ctx, alert); err != nil {
+		t.Fatalf("CreateWithTransaction: %v", err)
+	}
+	// 创建时未设置的 enabled 写入 false，而不是 NULL
+	for i, enabled := range sinkEnabled(alert.Configuration) {
+		if enabled == nil || *enabled != tea.BoolValue(created) {
+			t.Errorf("created sink %d enabled = %v, want %v", i, enabled, tea.BoolValue(created))
+		}
+	}
+	for _, table := range []string{"sink_alerthub_configurations", "sink_cms_configurations", "sink_event_store_configurations"} {
+		var nulls int64
+		if err := s.db.Table(table).Where("enabled IS NULL").Count(&nulls).Error; err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		if nulls != 0 {
+			t.Errorf("%s has %d rows with NULL enabled", table, nulls)
+		}
+	}
+
+	stored, err := s.GetByName(ctx, "sink")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	update := newFullAlert("sink")
+	update.ID = stored.ID
+	modify(update.Configuration)
+	setSinkEnabled(update.Configuration, updated)
+	if err := s.UpdateWithTransaction(ctx, update); err != nil {
+		t.Fatalf("UpdateWithTransaction: %v", err)
+	}
+
+	after, err := s.GetByName(ctx, "sink")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	for i, enabled := range sinkEnabled(after.Configuration) {
+		if enabled == nil || *enabled != want {
+			t.Errorf("updated sink %d enabled = %v, want %v", i, enabled, want)
+		}
+	}
+}
+
+func TestListByStatusEmptyMatchesList(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	for _, name := range []string{"one", "two"} {
+		alert := newFullAlert(name)
+		if name == "two" {
+			alert.Status = "DISABLED"
+		}
+		if err := s.CreateWithTransaction(ctx, alert); err != nil {
+			t.Fatalf("CreateWithTransaction(%s): %v", name, err)
+		}
+	}
+
+	byStatus, total, err := s.ListByStatus(ctx, "", 0, 10)
+	if err != nil {
+		t.Fatalf("ListByStatus: %v", err)
+	}
+	all, allTotal, err := s.List(ctx, 0, 10, false)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 2 || total != allTotal || len(byStatus) != len(all) {
+		t.Errorf("ListByStatus(\"\") = %d alerts (total %d), List = %d alerts (total %d), want both 2", len(byStatus), total, len(all), allTotal)
+	}
+
+	// 状态值原样比较，不存在的状态返回空列表
+	none, total, err := s.ListByStatus(ctx, "unknown", 0, 10)
+	if err != nil || total != 0 || len(none) != 0 {
+		t.Errorf("ListByStatus(unknown) = %d alerts, total %d, err %v; want none", len(none), total, err)
+	}
+}
+
+func TestInvalidTemplateJSONIsNotStored(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(template *models.TemplateConfiguration)
+	}{
+		{name: "aonotations array", modify: func(template *models.TemplateConfiguration) { template.Aonotations = tea.String(`["a"]`) }},
+		{name: "tokens malformed", modify: func(template *models.TemplateConfiguration) { template.Tokens = tea.String(`{"a":`) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestStore(t)
+
+			// 创建时拒绝，不留下任何行
+			alert := newFullAlert("template")
+			tt.modify(alert.Configuration.TemplateConfig)
+			if err := s.CreateWithTransaction(ctx, alert); !errors.Is(err, ErrInvalidTemplateConfig) {
+				t.Fatalf("CreateWithTransaction error = %v, want ErrInvalidTemplateConfig", err)
+			}
+			for table, count := range countRows(t, s.db) {
+				if count != 0 {
+					t.Errorf("%s rows = %d after rejected create, want 0", table, count)
+				}
+			}
+
+			// 更新时拒绝，保留原有的模板配置
+			if err := s.CreateWithTransaction(ctx, newFullAlert("template")); err != nil {
+				t.Fatalf("CreateWithTransaction: %v", err)
+			}
+			before, err := s.GetByName(ctx, "template")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+			changed := newFullAlert("template")
+			changed.ID = before.ID
+			tt.modify(changed.Configuration.TemplateConfig)
+			if err := s.UpdateWithTransaction(ctx, changed); !errors.Is(err, ErrInvalidTemplateConfig) {
+				t.Fatalf("UpdateWithTransaction error = %v, want ErrInvalidTemplateConfig", err)
+			}
+			after, err := s.GetByName(ctx, "template")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+			if !reflect.DeepEqual(after.Configuration.TemplateConfig.Aonotations, before.Configuration.TemplateConfig.Aonotations) ||
+				!reflect.DeepEqual(after.Configuration.TemplateConfig.Tokens, before.Configuration.TemplateConfig.Tokens) {
+				t.Errorf("template after rejected update = %+v, want unchanged %+v", after.Configuration.TemplateConfig, before.Configuration.TemplateConfig)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"gorm.io/gorm"
+)
+
+// tagKey 标签的唯一标识
+type tagKey struct {
+	tagType string
+	key     string
+}
+
+// syncTags 对比现有标签和目标标签，只写入新增、删除和值变化的标签，未变化的标签行保持不变
+func syncTags(tx *gorm.DB, alertID uint, tags []models.AlertTag) error {
+	var existing []models.AlertTag
+	if err := tx.Where("alert_id = ?", alertID).Order("id ASC").Find(&existing).Error; err != nil {
+		return fmt.Errorf("failed to load existing tags: %w", err)
+	}
+
+	existingByKey := make(map[tagKey][]models.AlertTag, len(existing))
+	for _, tag := range existing {
+		key := tagKey{tag.TagType, tag.TagKey}
+		existingByKey[key] = append(existingByKey[key], tag)
+	}
+
+	var toCreate []models.AlertTag
+	for _, tag := range tags {
+		key := tagKey{tag.TagType, tag.TagKey}
+		matches := existingByKey[key]
+		if len(matches) == 0 {
+			toCreate = append(toCreate, models.AlertTag{
+				AlertID:  alertID,
+				TagType:  tag.TagType,
+				TagKey:   tag.TagKey,
+				TagValue: tag.TagValue,
+			})
+			continue
+		}
+
+		current := matches[0]
+		existingByKey[key] = matches[1:]
+		if stringPtrEqual(current.TagValue, tag.TagValue) {
+			continue
+		}
+		if err := tx.Model(&models.AlertTag{}).Where("id = ?", current.ID).Update("tag_value", tag.TagValue).Error; err != nil {
+			return fmt.Errorf("failed to update tag %s: %w", tag.TagKey, err)
+		}
+	}
+
+	var toDelete []uint
+	for _, remaining := range existingByKey {
+		for _, tag := range remaining {
+			toDelete = append(toDelete, tag.ID)
+		}
+	}
+	if len(toDelete) > 0 {
+		if err := tx.Delete(&models.AlertTag{}, toDelete).Error; err != nil {
+			return fmt.Errorf("failed to delete removed tags: %w", err)
+		}
+	}
+
+	if len(toCreate) > 0 {
+		if err := tx.CreateInBatches(&toCreate, childInsertBatchSize).Error; err != nil {
+			return fmt.Errorf("failed to create new tags: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// syncQueries 对比现有查询和目标查询：内容完全相同的查询行保持不变，
+// 其余按顺序复用现有行原地更新，多出的新建，剩余的删除
+func syncQueries(tx *gorm.DB, alertID uint, queries []models.AlertQuery) error {
+	var existing []models.AlertQuery
+	if err := tx.Where("alert_id = ?", alertID).Order("id ASC").Find(&existing).Error; err != nil {
+		return fmt.Errorf("failed to load existing queries: %w", err)
+	}
+
+	used := make([]bool, len(existing))
+	var pending []models.AlertQuery
+	for _, query := range queries {
+		matched := false
+		for i := range existing {
+			if !used[i] && queryContentEqual(&existing[i], &query) {
+				used[i] = true
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			pending = append(pending, query)
+		}
+	}
+
+	var toCreate []models.AlertQuery
+	for _, query := range pending {
+		reuse := -1
+		for i := range existing {
+			if !used[i] {
+				reuse = i
+				break
+			}
+		}
+
+		if reuse < 0 {
+			toCreate = append(toCreate, newAlertQuery(alertID, &query))
+			continue
+		}
+
+		used[reuse] = true
+		if err := tx.Model(&models.AlertQuery{}).Where("id = ?", existing[reuse].ID).Updates(queryColumns(&query)).Error; err != nil {
+			return fmt.Errorf("failed to update query: %w", err)
+		}
+	}
+
+	var toDelete []uint
+	for i, query := range existing {
+		if !used[i] {
+			toDelete = append(toDelete, query.ID)
+		}
+	}
+	if len(toDelete) > 0 {
+		if err := tx.Delete(&models.AlertQuery{}, toDelete).Error; err != nil {
+			return fmt.Errorf("failed to delete removed queries: %w", err)
+		}
+	}
+
+	if len(toCreate) > 0 {
+		if err := tx.CreateInBatches(&toCreate, childInsertBatchSize).Error; err != nil {
+			return fmt.Errorf("failed to create new queries: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// newAlertQuery 复制查询内容，用于新建查询行
+func newAlertQuery(alertID uint, query *models.AlertQuery) models.AlertQuery {
+	return models.AlertQuery{
+		AlertID:      alertID,
+		ChartTitle:   query.ChartTitle,
+		DashboardId:  query.DashboardId,
+		End:          query.End,
+		PowerSqlMode: query.PowerSqlMode,
+		Project:      query.Project,
+		Query:        query.Query,
+		Region:       query.Region,
+		RoleArn:      query.RoleArn,
+		Start:        query.Start,
+		Store:        query.Store,
+		StoreType:    query.StoreType,
+		TimeSpanType: query.TimeSpanType,
+		Ui:           query.Ui,
+	}
+}
+
+// queryColumns 查询内容对应的列，用于原地更新（nil 值也需要写入）
+func queryColumns(query *models.AlertQuery) map[string]interface{} {
+	return map[string]interface{}{
+		"chart_title":    query.ChartTitle,
+		"dashboard_id":   query.DashboardId,
+		"end":            query.End,
+		"power_sql_mode": query.PowerSqlMode,
+		"project":        query.Project,
+		"query":          query.Query,
+		"region":         query.Region,
+		"role_arn":       query.RoleArn,
+		"start":          query.Start,
+		"store":          query.Store,
+		"store_type":     query.StoreType,
+		"time_span_type": query.TimeSpanType,
+		"ui":             query.Ui,
+	}
+}
+
+// queryContentEqual 比较两个查询的内容，忽略 ID 和时间戳
+func queryContentEqual(a, b *models.AlertQuery) bool {
+	return a.Query == b.Query &&
+		stringPtrEqual(a.ChartTitle, b.ChartTitle) &&
+		stringPtrEqual(a.DashboardId, b.DashboardId) &&
+		stringPtrEqual(a.End, b.End) &&
+		stringPtrEqual(a.PowerSqlMode, b.PowerSqlMode) &&
+		stringPtrEqual(a.Project, b.Project) &&
+		stringPtrEqual(a.Region, b.Region) &&
+		stringPtrEqual(a.RoleArn, b.RoleArn) &&
+		stringPtrEqual(a.Start, b.Start) &&
+		stringPtrEqual(a.Store, b.Store) &&
+		stringPtrEqual(a.StoreType, b.StoreType) &&
+		stringPtrEqual(a.TimeSpanType, b.TimeSpanType) &&
+		stringPtrEqual(a.Ui, b.Ui)
+}
+
+// stringPtrEqual 比较两个可空字符串
+func stringPtrEqual(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// resolveConfigurationID 调用方没有提供 configuration_id 时，使用数据库中该 Alert 当前的配置
+func resolveConfigurationID(tx *gorm.DB, alert *models.Alert) error {
+	if alert.ConfigurationID != nil {
+		return nil
+	}
+
+	var current models.Alert
+	if err := tx.Unscoped().Select("configuration_id").First(&current, alert.ID).Error; err != nil {
+		return fmt.Errorf("failed to load alert configuration id: %w", err)
+	}
+	var configuration models.AlertConfiguration
+	err := latestByAlert(tx, &configuration, current.ConfigurationID, alert.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load alert configuration: %w", err)
+	}
+	alert.ConfigurationID = &configuration.ID
+	return nil
+}
+
+// configurationUnchanged 比较要写入的配置与数据库中的配置（忽略 ID、外键和时间戳），
+// 只修改主记录等其他分区时配置内容相同，子记录（包括严重程度配置及其评估条件）无需重建
+func configurationUnchanged(tx *gorm.DB, alert *models.Alert) (bool, error) {
+	if alert.ConfigurationID == nil {
+		return false, nil
+	}
+	configID := *alert.ConfigurationID
+
+	// 与重建时相同：未设置 enabled 的 Sink 和未提供的 raw_config 沿用旧配置
+	if err := inheritSinkEnabled(tx, configID, alert.Configuration); err != nil {
+		return false, err
+	}
+	if err := inheritRawConfig(tx, configID, alert.Configuration); err != nil {
+		return false, err
+	}
+
+	var existing models.AlertConfiguration
+	if err := tx.First(&existing, configID).Error; err != nil {
+		return false, fmt.Errorf("failed to load existing configuration: %w", err)
+	}
+	if err := loadConfigurationChildren(tx, &existing); err != nil {
+		return false, err
+	}
+
+	existingContent, err := configurationContent(&existing)
+	if err != nil {
+		return false, err
+	}
+	newContent, err := configurationContent(alert.Configuration)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(existingContent, newContent), nil
+}
+
+// configurationContent 配置内容的通用表示，去掉 ID、外键、时间戳和反向关联；
+// 布尔列默认值为 false，因此 false、空值与空列表都视为未设置
+func configurationContent(configuration *models.AlertConfiguration) (interface{}, error) {
+	data, err := json.Marshal(configuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	var content interface{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal configuration: %w", err)
+	}
+	return stripBookkeeping(content), nil
+}
+
+// bookkeepingKeys 配置 JSON 中与内容无关的键：ID、外键、时间戳和反向关联
+var bookkeepingKeys = map[string]bool{
+	"id":                         true,
+	"created_at":                 true,
+	"updated_at":                 true,
+	"alert":                      true,
+	"alert_id":                   true,
+	"alert_config":               true,
+	"alert_config_id":            true,
+	"condition_config_id":        true,
+	"group_config_id":            true,
+	"policy_config_id":           true,
+	"template_config_id":         true,
+	"sink_alerthub_config_id":    true,
+	"sink_cms_config_id":         true,
+	"sink_event_store_config_id": true,
+	"eval_condition_id":          true,
+}
+
+// stripBookkeeping 递归删除 bookkeepingKeys 中的键以及值为 null、false 或空列表的键
+func stripBookkeeping(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if bookkeepingKeys[key] {
+				delete(v, key)
+				continue
+			}
+			item = stripBookkeeping(item)
+			if list, ok := item.([]interface{}); item == nil || item == false || (ok && len(list) == 0) {
+				delete(v, key)
+				continue
+			}
+			v[key] = item
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = stripBookkeeping(item)
+		}
+		return v
+	}
+	return value
+}
@@ -0,0 +1,184 @@
+package store
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// severity 返回严重程度配置，condition 为空时没有评估条件
+func severity(value int32, condition string) models.SeverityConfiguration {
+	severity := models.SeverityConfiguration{Severity: tea.Int32(value)}
+	if condition != "" {
+		severity.EvalCondition = &models.ConditionConfiguration{Condition: tea.String(condition)}
+	}
+	return severity
+}
+
+// newDiffAlert 返回有多个标签、查询和严重程度配置的 Alert
+func newDiffAlert(name string) *models.Alert {
+	alert := newFullAlert(name)
+	alert.Tags = append(alert.Tags, models.AlertTag{TagType: "annotation", TagKey: "summary", TagValue: tea.String("cpu high")})
+	alert.Queries = append(alert.Queries, models.AlertQuery{Query: "error | select count(*) as cnt", Store: tea.String("app-log"), StoreType: tea.String("log")})
+	alert.Configuration.SeverityConfigs = []models.SeverityConfiguration{severity(6, "cnt > 10"), severity(8, "cnt > 100")}
+	return alert
+}
+
+// childRowIDs 返回 Alert 的标签、查询、严重程度配置和评估条件的行 ID
+func childRowIDs(alert *models.Alert) map[string][]uint {
+	ids := map[string][]uint{}
+	for _, tag := range alert.Tags {
+		ids["tags"] = append(ids["tags"], tag.ID)
+	}
+	for _, query := range alert.Queries {
+		ids["queries"] = append(ids["queries"], query.ID)
+	}
+	for _, severity := range alert.Configuration.SeverityConfigs {
+		ids["severities"] = append(ids["severities"], severity.ID)
+		if severity.EvalCondition != nil {
+			ids["eval_conditions"] = append(ids["eval_conditions"], severity.EvalCondition.ID)
+		}
+	}
+	return ids
+}
+
+func TestUpdateDisplayNameLeavesChildRowsUntouched(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	if err := s.CreateWithTransaction(ctx, newDiffAlert("diff")); err != nil {
+		t.Fatalf("CreateWithTransaction: %v", err)
+	}
+	before, err := s.GetByName(ctx, "diff")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	rowsBefore := countRows(t, s.db)
+
+	changed := newDiffAlert("diff")
+	changed.ID = before.ID
+	changed.DisplayName = "renamed"
+	if err := s.UpdateWithTransaction(ctx, changed); err != nil {
+		t.Fatalf("UpdateWithTransaction: %v", err)
+	}
+
+	after, err := s.GetByName(ctx, "diff")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	if after.DisplayName != "renamed" {
+		t.Errorf("display name = %q, want renamed", after.DisplayName)
+	}
+	if got, want := childRowIDs(after), childRowIDs(before); !reflect.DeepEqual(got, want) {
+		t.Errorf("child row IDs = %v, want unchanged %v", got, want)
+	}
+
+	// 除了一条审计记录，没有新增或删除任何行
+	rowsAfter := countRows(t, s.db)
+	for table, count := range rowsAfter {
+		want := rowsBefore[table]
+		if table == "alert_audit_logs" {
+			want++
+		}
+		if count != want {
+			t.Errorf("%s rows = %d, want %d", table, count, want)
+		}
+	}
+}
+
+func TestUpdateConfigurationWithoutConfigurationID(t *testing.T) {
+	tests := []struct {
+		name       string
+		modify     func(alert *models.Alert)
+		wantKept   bool             // 配置及其子记录保留原有行
+		wantDeltas map[string]int64 // 相对更新前的行数变化，不含审计记录
+	}{
+		{
+			name:     "unchanged configuration",
+			modify:   func(alert *models.Alert) {},
+			wantKept: true,
+		},
+		{
+			name: "sink enabled left unset",
+			modify: func(alert *models.Alert) {
+				alert.Configuration.SinkAlerthubConfig.Enabled = nil
+			},
+			wantKept: true,
+		},
+		{
+			name: "threshold changed",
+			modify: func(alert *models.Alert) {
+				alert.Configuration.Threshold = tea.Int32(5)
+			},
+		},
+		{
+			name: "severity removed",
+			modify: func(alert *models.Alert) {
+				alert.Configuration.SeverityConfigs = alert.Configuration.SeverityConfigs[:1]
+			},
+			wantDeltas: map[string]int64{"severity_configurations": -1, "condition_configurations": -1},
+		},
+		{
+			name: "severity condition changed",
+			modify: func(alert *models.Alert) {
+				alert.Configuration.SeverityConfigs[1] = severity(8, "cnt > 500")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestStore(t)
+			if err := s.CreateWithTransaction(ctx, newDiffAlert("config")); err != nil {
+				t.Fatalf("CreateWithTransaction: %v", err)
+			}
+			before, err := s.GetByName(ctx, "config")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+			rowsBefore := countRows(t, s.db)
+
+			// 与 API 更新相同，请求中不带 configuration_id
+			changed := newDiffAlert("config")
+			changed.ID = before.ID
+			tt.modify(changed)
+			if err := s.UpdateWithTransaction(ctx, changed); err != nil {
+				t.Fatalf("UpdateWithTransaction: %v", err)
+			}
+
+			after, err := s.GetByName(ctx, "config")
+			if err != nil {
+				t.Fatalf("GetByName: %v", err)
+			}
+			if kept := after.Configuration.ID == before.Configuration.ID; kept != tt.wantKept {
+				t.Errorf("configuration kept = %v, want %v", kept, tt.wantKept)
+			}
+			if tt.wantKept && !reflect.DeepEqual(childRowIDs(after), childRowIDs(before)) {
+				t.Errorf("child row IDs = %v, want unchanged %v", childRowIDs(after), childRowIDs(before))
+			}
+			if got, want := tea.Int32Value(after.Configuration.Threshold), tea.Int32Value(changed.Configuration.Threshold); got != want {
+				t.Errorf("threshold = %d, want %d", got, want)
+			}
+			if got, want := len(after.Configuration.SeverityConfigs), len(changed.Configuration.SeverityConfigs); got != want {
+				t.Errorf("severities = %d, want %d", got, want)
+			}
+			if sink := after.Configuration.SinkAlerthubConfig; sink == nil || !tea.BoolValue(sink.Enabled) {
+				t.Errorf("alerthub sink = %+v, want the stored enabled=true kept", sink)
+			}
+
+			// 旧配置被整体删除，不留下孤立的配置行
+			for table, count := range countRows(t, s.db) {
+				want := rowsBefore[table] + tt.wantDeltas[table]
+				if table == "alert_audit_logs" {
+					want++
+				}
+				if count != want {
+					t.Errorf("%s rows = %d, want %d", table, count, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// childInsertBatchSize 批量插入标签、查询、严重程度和 Join 配置时每条 INSERT 的最大行数，
+// 避免查询很多的 Alert 超出数据库的占位符数量限制
+const childInsertBatchSize = 100
+
+// createSeverityConfigs 批量创建严重程度配置：先用一条批量 INSERT 创建全部 EvalCondition 并回填外键，
+// 再批量创建严重程度配置本身（EvalCondition 已创建，不再由 GORM 关联写入）
+func createSeverityConfigs(tx *gorm.DB, alertConfigID uint, severities []models.SeverityConfiguration) error {
+	var conditions []*models.ConditionConfiguration
+	for i := range severities {
+		if severities[i].EvalCondition != nil {
+			severities[i].EvalCondition.ID = 0
+			severities[i].EvalCondition.AlertConfigID = alertConfigID
+			conditions = append(conditions, severities[i].EvalCondition)
+		}
+	}
+	if len(conditions) > 0 {
+		if err := tx.CreateInBatches(conditions, childInsertBatchSize).Error; err != nil {
+			return fmt.Errorf("failed to create eval conditions: %w", err)
+		}
+	}
+
+	for i := range severities {
+		if severities[i].EvalCondition != nil {
+			severities[i].EvalConditionID = &severities[i].EvalCondition.ID
+		}
+		severities[i].AlertConfigID = alertConfigID
+		severities[i].ID = 0
+	}
+	if err := tx.Omit(clause.Associations).CreateInBatches(&severities, childInsertBatchSize).Error; err != nil {
+		return fmt.Errorf("failed to create severity configurations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/Ghostbaby/sls-migrate/pkg/database"
+	"github.com/alibabacloud-go/tea/tea"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// newTestStore 为当前测试创建独立的内存 SQLite 数据库并返回基于它的 alertStore
+func newTestStore(t testing.TB) *alertStore {
+	t.Helper()
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	dsn := fmt.Sprintf("file:store_%s?mode=memory&cache=shared", name)
+	if err := database.InitDatabase(&config.DatabaseConfig{Driver: database.DriverSQLite, Database: dsn}); err != nil {
+		t.Fatalf("InitDatabase: %v", err)
+	}
+	database.DB.Logger = gormlogger.Default.LogMode(gormlogger.Silent)
+	if err := database.AutoMigrate(); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	t.Cleanup(func() {
+		database.CloseDatabase()
+	})
+
+	return NewAlertStore(slog.New(slog.NewTextHandler(io.Discard, nil))).(*alertStore)
+}
+
+// newFullAlert 返回包含所有可选子配置的 Alert，创建时会写入每一张子表
+func newFullAlert(name string) *models.Alert {
+	return &models.Alert{
+		Name:        name,
+		DisplayName: "Alert " + name,
+		Status:      "ENABLED",
+		Configuration: &models.AlertConfiguration{
+			Threshold:       tea.Int32(1),
+			Type:            tea.String("default"),
+			Version:         tea.String("2.0"),
+			ConditionConfig: &models.ConditionConfiguration{Condition: tea.String("cnt > 0")},
+			GroupConfig:     &models.GroupConfiguration{Type: tea.String("no_group")},
+			PolicyConfig:    &models.PolicyConfiguration{AlertPolicyId: tea.String("sls.builtin.dynamic")},
+			TemplateConfig: &models.TemplateConfiguration{
+				TemplateId:  tea.String("sls.builtin.cn"),
+				Aonotations: tea.String(`{"title":"cpu"}`),
+				Tokens:      tea.String(`{}`),
+			},
+			SeverityConfigs: []models.SeverityConfiguration{
+				{Severity: tea.Int32(6), EvalCondition: &models.ConditionConfiguration{Condition: tea.String("cnt > 10")}},
+			},
+			JoinConfigs:          []models.JoinConfiguration{{JoinType: tea.String("no_join")}},
+			SinkAlerthubConfig:   &models.SinkAlerthubConfiguration{Enabled: tea.Bool(true)},
+			SinkCmsConfig:        &models.SinkCmsConfiguration{Enabled: tea.Bool(false)},
+			SinkEventStoreConfig: &models.SinkEventStoreConfiguration{Enabled: tea.Bool(false)},
+		},
+		Schedule: &models.AlertSchedule{Type: "FixedRate", Interval: tea.String("1m")},
+		Tags: []models.AlertTag{
+			{TagType: "label", TagKey: "team", TagValue: tea.String("ops")},
+		},
+		Queries: []models.AlertQuery{
+			{Query: "* | select count(*) as cnt", Store: tea.String("app-log"), StoreType: tea.String("log")},
+		},
+	}
+}
@@ -0,0 +1,37 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"gorm.io/gorm"
+)
+
+// CreateSyncRun 写入一次同步的执行记录
+func (s *alertStore) CreateSyncRun(ctx context.Context, run *models.SyncRun) error {
+	return s.db.WithContext(ctx).Create(run).Error
+}
+
+// LatestSyncRun 获取最近一次同步的执行记录，从未同步过时返回 nil
+func (s *alertStore) LatestSyncRun(ctx context.Context) (*models.SyncRun, error) {
+	var run models.SyncRun
+	err := s.db.WithContext(ctx).Order("started_at DESC, id DESC").First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// ListSyncRuns 按开始时间倒序获取最近 limit 次同步的执行记录
+func (s *alertStore) ListSyncRuns(ctx context.Context, limit int) ([]models.SyncRun, error) {
+	var runs []models.SyncRun
+	err := s.db.WithContext(ctx).
+		Order("started_at DESC, id DESC").
+		Limit(limit).
+		Find(&runs).Error
+	return runs, err
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"gorm.io/gorm"
+)
+
+// 标签过滤操作符
+const (
+	TagOpExists = "exists" // 存在该键，值任意
+	TagOpEquals = "equals" // 值完全相等
+	TagOpIn     = "in"     // 值在集合中
+	TagOpPrefix = "prefix" // 值以指定前缀开头
+)
+
+// TagFilter 单个标签过滤条件，同时匹配 label 和 annotation
+type TagFilter struct {
+	Key      string   `json:"key"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values,omitempty"`
+}
+
+// ListByTags 分页获取满足全部标签过滤条件的 Alert，每个条件对应一个 alert_tags 子查询
+func (s *alertStore) ListByTags(ctx context.Context, filters []TagFilter, offset, limit int) ([]*models.Alert, int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.Alert{})
+	for _, filter := range filters {
+		subQuery, err := tagFilterSubQuery(s.db.WithContext(ctx), filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where("id IN (?)", subQuery)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var alerts []*models.Alert
+	err := query.
+		Preload("Configuration").
+		Preload("Schedule").
+		Preload("Tags").
+		Preload("Queries").
+		Offset(offset).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&alerts).Error
+
+	return alerts, total, err
+}
+
+// tagFilterSubQuery 构造返回满足条件的 alert_id 的子查询
+func tagFilterSubQuery(db *gorm.DB, filter TagFilter) (*gorm.DB, error) {
+	subQuery := db.Model(&models.AlertTag{}).Select("alert_id").Where("tag_key = ?", filter.Key)
+
+	switch filter.Operator {
+	case TagOpExists:
+		return subQuery, nil
+	case TagOpEquals:
+		if len(filter.Values) != 1 {
+			return nil, fmt.Errorf("tag filter %s: equals requires exactly one value", filter.Key)
+		}
+		return subQuery.Where("tag_value = ?", filter.Values[0]), nil
+	case TagOpIn:
+		if len(filter.Values) == 0 {
+			return nil, fmt.Errorf("tag filter %s: in requires at least one value", filter.Key)
+		}
+		return subQuery.Where("tag_value IN ?", filter.Values), nil
+	case TagOpPrefix:
+		if len(filter.Values) != 1 {
+			return nil, fmt.Errorf("tag filter %s: prefix requires exactly one value", filter.Key)
+		}
+		return subQuery.Where("tag_value LIKE ? ESCAPE '!'", escapeLike(filter.Values[0])+"%"), nil
+	}
+
+	return nil, fmt.Errorf("tag filter %s: unsupported operator %q", filter.Key, filter.Operator)
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+func TestListByTags(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	alerts := map[string][]models.AlertTag{
+		"web-a": {
+			{TagType: "label", TagKey: "team", TagValue: tea.String("a")},
+			{TagType: "label", TagKey: "env", TagValue: tea.String("prod")},
+			{TagType: "annotation", TagKey: "owner", TagValue: tea.String("alice")},
+		},
+		"web-b": {
+			{TagType: "label", TagKey: "team", TagValue: tea.String("b")},
+			{TagType: "label", TagKey: "env", TagValue: tea.String("staging")},
+			{TagType: "label", TagKey: "service", TagValue: tea.String("web_api")},
+		},
+		"db": {
+			{TagType: "label", TagKey: "team", TagValue: tea.String("c")},
+			{TagType: "annotation", TagKey: "env", TagValue: tea.String("prod")},
+			{TagType: "label", TagKey: "service", TagValue: tea.String("webXapi")},
+		},
+	}
+	for name, tags := range alerts {
+		alert := newFullAlert(name)
+		alert.Tags = tags
+		if err := s.CreateWithTransaction(ctx, alert); err != nil {
+			t.Fatalf("CreateWithTransaction %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		filters []TagFilter
+		want    []string
+	}{
+		{name: "exists", filters: []TagFilter{{Key: "owner", Operator: TagOpExists}}, want: []string{"web-a"}},
+		{name: "equals matches labels and annotations", filters: []TagFilter{{Key: "env", Operator: TagOpEquals, Values: []string{"prod"}}}, want: []string{"db", "web-a"}},
+		{name: "in set", filters: []TagFilter{{Key: "team", Operator: TagOpIn, Values: []string{"a", "b", "x"}}}, want: []string{"web-a", "web-b"}},
+		{name: "prefix", filters: []TagFilter{{Key: "service", Operator: TagOpPrefix, Values: []string{"web"}}}, want: []string{"db", "web-b"}},
+		// _ 按字面匹配，不作为 LIKE 通配符
+		{name: "prefix escapes wildcards", filters: []TagFilter{{Key: "service", Operator: TagOpPrefix, Values: []string{"web_"}}}, want: []string{"web-b"}},
+		{
+			name: "all clauses must match",
+			filters: []TagFilter{
+				{Key: "team", Operator: TagOpIn, Values: []string{"a", "c"}},
+				{Key: "env", Operator: TagOpEquals, Values: []string{"prod"}},
+				{Key: "owner", Operator: TagOpExists},
+			},
+			want: []string{"web-a"},
+		},
+		{name: "no match", filters: []TagFilter{{Key: "missing", Operator: TagOpExists}}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, total, err := s.ListByTags(ctx, tt.filters, 0, 10)
+			if err != nil {
+				t.Fatalf("ListByTags: %v", err)
+			}
+			names := make([]string, 0, len(found))
+			for _, alert := range found {
+				names = append(names, alert.Name)
+			}
+			sort.Strings(names)
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") || total != int64(len(tt.want)) {
+				t.Errorf("alerts = %v (total %d), want %v", names, total, tt.want)
+			}
+		})
+	}
+
+	if _, _, err := s.ListByTags(ctx, []TagFilter{{Key: "team", Operator: "regex", Values: []string{".*"}}}, 0, 10); err == nil {
+		t.Error("ListByTags with an unsupported operator succeeded, want an error")
+	}
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -15,6 +17,7 @@ import (
 	"github.com/Ghostbaby/sls-migrate/internal/service"
 	"github.com/Ghostbaby/sls-migrate/internal/store"
 	"github.com/Ghostbaby/sls-migrate/pkg/database"
+	"github.com/Ghostbaby/sls-migrate/pkg/logger"
 )
 
 // @title SLS Migrate API
@@ -34,8 +37,20 @@ import (
 
 // @schemes http https
 func main() {
+	migrateOnly := flag.Bool("migrate-only", false, "只执行数据库迁移后退出，不启动 HTTP 服务和 SLS 服务")
+	flag.Parse()
+
 	// 加载配置
 	cfg := config.LoadConfig()
+	if *migrateOnly {
+		cfg.MigrateOnly = true
+	}
+	if err := cfg.Database.Validate(); err != nil {
+		log.Fatalf("Failed to load database config: %v", err)
+	}
+
+	// 初始化日志
+	appLogger := logger.InitLogger(&cfg.Log)
 
 	// 初始化数据库
 	if err := database.InitDatabase(&cfg.Database); err != nil {
@@ -43,48 +58,92 @@ func main() {
 	}
 	defer database.CloseDatabase()
 
+	// 仅迁移模式：执行迁移和 Schema 校验后退出，失败时以非零状态码退出
+	if cfg.MigrateOnly {
+		if err := runMigrations(cfg); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		log.Println("Migration completed, exiting (migrate-only mode)")
+		return
+	}
+
 	// 自动迁移数据库表结构
-	if err := database.AutoMigrate(); err != nil {
-		log.Fatalf("Failed to auto migrate database: %v", err)
+	if cfg.Database.AutoMigrate {
+		if err := database.AutoMigrate(); err != nil {
+			log.Fatalf("Failed to auto migrate database: %v", err)
+		}
+	}
+
+	// 校验模型与数据库表结构是否一致
+	if err := database.CheckSchema(cfg.Database.SchemaCheck); err != nil {
+		log.Fatalf("Failed to validate database schema: %v", err)
 	}
 
 	// 创建依赖
-	alertStore := store.NewAlertStore()
-	alertService := service.NewAlertService(alertStore)
-	alertHandler := handler.NewAlertHandler(alertService)
+	alertStore := store.NewAlertStore(appLogger)
+	if !service.IsValidAlertStatus(cfg.DefaultAlertStatus) {
+		log.Fatalf("Invalid DEFAULT_ALERT_STATUS %q: must be ENABLED or DISABLED", cfg.DefaultAlertStatus)
+	}
+	alertService := service.NewAlertService(alertStore, &cfg.DefaultSink, cfg.DefaultAlertStatus)
 
 	// 创建 SLS 服务
+	// SLS 配置不完整时不创建 SLS 服务，SLS 相关功能不可用，其余接口照常提供
+	var slsService service.SLSService
 	slsConfig := config.LoadSLSConfig()
-	slsService, err := service.NewSLSService(slsConfig)
-	if err != nil {
+	if err := slsConfig.Validate(); err != nil {
+		log.Printf("Warning: %v", err)
+		log.Println("SLS functionality will be disabled")
+	} else if slsService, err = service.NewSLSService(slsConfig, appLogger); err != nil {
 		log.Printf("Warning: Failed to create SLS service: %v", err)
 		log.Println("SLS functionality will be disabled")
 		slsService = nil
 	}
 
+	alertHandler := handler.NewAlertHandler(alertService, slsService, &cfg.API)
+
 	// 创建同步服务
 	var syncService service.SyncService
 	if slsService != nil {
-		syncService = service.NewSyncService(slsService, alertStore, alertService)
+		syncService = service.NewSyncService(slsService, alertStore, alertService, &cfg.Sync)
+	}
+
+	// 启动后台任务：SLS 连通性探测和每日 Alert 数量快照，收到退出信号时停止
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	var slsHealthChecker service.SLSHealthChecker
+	if slsService != nil {
+		slsHealthChecker = service.NewSLSHealthChecker(slsService, time.Duration(slsConfig.HealthCheckInterval)*time.Second)
+		slsHealthChecker.Start(backgroundCtx)
+	}
+
+	service.NewAlertCountSnapshotter(alertStore, time.Hour).Start(backgroundCtx)
+
+	// 定时同步：SLS 服务初始化失败时不启用
+	var syncScheduler service.SyncScheduler
+	if cfg.Sync.Interval > 0 {
+		if syncService != nil {
+			syncScheduler = service.NewSyncScheduler(syncService, cfg.Sync.Interval)
+			syncScheduler.Start(backgroundCtx)
+		} else {
+			log.Println("Warning: SYNC_INTERVAL is set but SLS service is unavailable, scheduled sync is disabled")
+		}
 	}
 
 	// 创建 SLS 处理器
 	var slsHandler *handler.SLSHandler
 	if slsService != nil {
-		slsHandler = handler.NewSLSHandler(slsService, syncService)
+		slsHandler = handler.NewSLSHandler(slsService, syncService, slsHealthChecker)
 	} else {
 		// 创建一个空的处理器，避免 panic
 		slsHandler = &handler.SLSHandler{}
 	}
 
 	// 设置路由
-	router := handler.SetupRouter(alertHandler, slsHandler)
+	router := handler.SetupRouter(alertHandler, slsHandler, cfg)
 
 	// 创建 HTTP 服务器
-	server := &http.Server{
-		Addr:    ":" + strconv.Itoa(cfg.Server.Port),
-		Handler: router,
-	}
+	server := newHTTPServer(cfg, router)
 
 	// 启动服务器
 	go func() {
@@ -100,6 +159,10 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopBackground()
+	if syncScheduler != nil {
+		syncScheduler.Wait()
+	}
 
 	// 优雅关闭服务器
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -111,3 +174,26 @@ func main() {
 
 	log.Println("Server exited")
 }
+
+// newHTTPServer 按服务器配置创建 HTTP 服务器，监听端口和各项超时均来自 cfg.Server
+func newHTTPServer(cfg *config.Config, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:           handler,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+}
+
+// runMigrations 执行数据库迁移并按 DB_SCHEMA_CHECK 校验表结构，供仅迁移模式使用
+func runMigrations(cfg *config.Config) error {
+	if err := database.AutoMigrate(); err != nil {
+		return fmt.Errorf("failed to auto migrate database: %w", err)
+	}
+	if err := database.CheckSchema(cfg.Database.SchemaCheck); err != nil {
+		return fmt.Errorf("failed to validate database schema: %w", err)
+	}
+	return nil
+}
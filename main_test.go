@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/Ghostbaby/sls-migrate/internal/handler"
+	"github.com/Ghostbaby/sls-migrate/pkg/database"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// runMainEnv 设置后测试二进制直接执行 main，用于检查进程退出码
+const runMainEnv = "SLS_MIGRATE_TEST_RUN_MAIN"
+
+func TestRunMigrations(t *testing.T) {
+	cfg := &config.Config{Database: config.DatabaseConfig{
+		Driver:      database.DriverSQLite,
+		Database:    "file:main_run_migrations?mode=memory&cache=shared",
+		SchemaCheck: database.SchemaCheckFail,
+	}}
+	if err := database.InitDatabase(&cfg.Database); err != nil {
+		t.Fatalf("InitDatabase: %v", err)
+	}
+	defer database.CloseDatabase()
+
+	if err := runMigrations(cfg); err != nil {
+		t.Fatalf("runMigrations: %v", err)
+	}
+	if !database.DB.Migrator().HasTable("alerts") {
+		t.Errorf("alerts table was not created")
+	}
+}
+
+func TestMigrateOnlyExitCode(t *testing.T) {
+	if os.Getenv(runMainEnv) == "1" {
+		os.Args = append([]string{"sls-migrate"}, strings.Fields(os.Getenv(runMainEnv+"_ARGS"))...)
+		main()
+		return
+	}
+
+	tests := []struct {
+		name     string
+		args     string
+		env      []string
+		wantCode int
+	}{
+		{name: "flag", args: "-migrate-only", wantCode: 0},
+		{name: "environment", env: []string{"MIGRATE_ONLY=true"}, wantCode: 0},
+		{name: "invalid database config", args: "-migrate-only", env: []string{"DB_DRIVER=oracle"}, wantCode: 1},
+		{name: "unreachable database", args: "-migrate-only", env: []string{"DB_DRIVER=sqlite", "DB_DATABASE=/nonexistent/dir/test.db"}, wantCode: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbPath := filepath.Join(t.TempDir(), "migrate.db")
+			cmd := exec.Command(os.Args[0], "-test.run=^TestMigrateOnlyExitCode$")
+			cmd.Env = append(os.Environ(),
+				runMainEnv+"=1",
+				runMainEnv+"_ARGS="+tt.args,
+				"DB_DRIVER=sqlite",
+				"DB_DATABASE="+dbPath,
+				"DB_SCHEMA_CHECK=fail",
+				// 配置了 SLS 也不应连接，仅迁移模式在创建 SLS 服务之前退出
+				"SLS_ENDPOINT=cn-hangzhou.log.aliyuncs.com",
+			)
+			cmd.Env = append(cmd.Env, tt.env...)
+			output, err := cmd.CombinedOutput()
+
+			code := 0
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			if code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d\n%s", code, tt.wantCode, output)
+			}
+			if tt.wantCode != 0 {
+				return
+			}
+
+			db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("open migrated database: %v", err)
+			}
+			if sqlDB, err := db.DB(); err == nil {
+				defer sqlDB.Close()
+			}
+			if !db.Migrator().HasTable("alerts") {
+				t.Errorf("alerts table was not created by migrate-only mode\n%s", output)
+			}
+		})
+	}
+}
+
+func TestNewHTTPServer(t *testing.T) {
+	cfg := &config.Config{Server: config.ServerConfig{
+		Port:              9090,
+		ReadTimeout:       11 * time.Second,
+		ReadHeaderTimeout: 3 * time.Second,
+		WriteTimeout:      17 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}}
+	mux := http.NewServeMux()
+
+	server := newHTTPServer(cfg, mux)
+	if server.Addr != ":9090" {
+		t.Errorf("Addr = %q, want :9090", server.Addr)
+	}
+	if server.Handler != mux {
+		t.Errorf("Handler = %v, want the given handler", server.Handler)
+	}
+	if server.ReadTimeout != cfg.Server.ReadTimeout ||
+		server.ReadHeaderTimeout != cfg.Server.ReadHeaderTimeout ||
+		server.WriteTimeout != cfg.Server.WriteTimeout ||
+		server.IdleTimeout != cfg.Server.IdleTimeout {
+		t.Errorf("timeouts = read %v, read header %v, write %v, idle %v; want %+v",
+			server.ReadTimeout, server.ReadHeaderTimeout, server.WriteTimeout, server.IdleTimeout, cfg.Server)
+	}
+}
+
+func TestNoWriteTimeoutOutlivesWriteTimeout(t *testing.T) {
+	const writeTimeout = 100 * time.Millisecond
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// 先写出一部分，再在写超时之后写出剩余部分
+	stream := func(c *gin.Context) {
+		c.Writer.WriteString("first\n")
+		c.Writer.Flush()
+		time.Sleep(3 * writeTimeout)
+		c.Writer.WriteString("second\n")
+	}
+	router.GET("/stream", handler.NoWriteTimeout(), stream)
+	router.GET("/limited", stream)
+
+	ts := httptest.NewUnstartedServer(nil)
+	ts.Config = newHTTPServer(&config.Config{Server: config.ServerConfig{WriteTimeout: writeTimeout}}, router)
+	ts.Start()
+	defer ts.Close()
+
+	tests := []struct {
+		path     string
+		wantBody string // 为空表示响应应被写超时截断
+	}{
+		{path: "/stream", wantBody: "first\nsecond\n"},
+		{path: "/limited"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := ts.Client().Get(ts.URL + tt.path)
+			if err != nil {
+				t.Fatalf("GET %s: %v", tt.path, err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+
+			if tt.wantBody != "" {
+				if err != nil || string(body) != tt.wantBody {
+					t.Errorf("body = %q, %v; want %q", body, err, tt.wantBody)
+				}
+				return
+			}
+			if err == nil && strings.Contains(string(body), "second") {
+				t.Errorf("body = %q, want the response cut off by the write timeout", body)
+			}
+		})
+	}
+}
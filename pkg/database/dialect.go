@@ -0,0 +1,81 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// 支持的数据库驱动
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// newDialector 根据配置的驱动构造 DSN 并返回对应的 GORM Dialector
+func newDialector(cfg *config.DatabaseConfig) (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case DriverMySQL, "":
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local",
+			cfg.Username,
+			cfg.Password,
+			cfg.Host,
+			cfg.Port,
+			cfg.Database,
+			cfg.Charset,
+		)
+		return mysql.Open(dsn), nil
+	case DriverPostgres:
+		dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+			cfg.Host,
+			cfg.Port,
+			cfg.Username,
+			cfg.Password,
+			cfg.Database,
+			cfg.SSLMode,
+		)
+		return postgres.Open(dsn), nil
+	case DriverSQLite:
+		// DB_DATABASE 为数据库文件路径，也可以是 file::memory:?cache=shared 之类的内存 DSN
+		return sqlite.Open(sqliteDSN(cfg.Database)), nil
+	}
+	return nil, fmt.Errorf("unsupported database driver: %s", cfg.Driver)
+}
+
+// sqliteDSN 在 DSN 上开启外键约束，SQLite 默认不检查外键
+func sqliteDSN(database string) string {
+	if strings.Contains(database, "_foreign_keys=") {
+		return database
+	}
+	separator := "?"
+	if strings.Contains(database, "?") {
+		separator = "&"
+	}
+	return database + separator + "_foreign_keys=1"
+}
+
+// setForeignKeyChecks 开启或关闭当前连接的外键约束检查：
+// MySQL 使用 FOREIGN_KEY_CHECKS，SQLite 使用 PRAGMA foreign_keys，其他数据库不处理
+func setForeignKeyChecks(enabled bool) {
+	if DB == nil {
+		return
+	}
+
+	value := 0
+	if enabled {
+		value = 1
+	}
+
+	switch DB.Dialector.Name() {
+	case DriverMySQL:
+		DB.Exec(fmt.Sprintf("SET FOREIGN_KEY_CHECKS = %d", value))
+	case DriverSQLite:
+		DB.Exec(fmt.Sprintf("PRAGMA foreign_keys = %d", value))
+	}
+}
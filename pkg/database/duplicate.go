@@ -0,0 +1,34 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+)
+
+// 唯一索引冲突的错误码
+const (
+	mysqlErrDupEntry     = 1062    // ER_DUP_ENTRY
+	pgErrUniqueViolation = "23505" // unique_violation
+)
+
+// IsDuplicateKeyError 判断错误是否为唯一索引冲突（MySQL 1062、PostgreSQL 23505、SQLite UNIQUE constraint）
+func IsDuplicateKeyError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrDupEntry
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgErrUniqueViolation
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+	return false
+}
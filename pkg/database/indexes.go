@@ -0,0 +1,62 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm/clause"
+)
+
+// IndexDefinition 由迁移层显式维护的索引定义
+type IndexDefinition struct {
+	Table   string
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// managedIndexes 统一维护的索引（名称与 sql/schema.sql 保持一致）
+// 单列唯一约束（如 alerts.name）仍由模型标签声明，组合索引和条件索引在这里集中定义
+var managedIndexes = []IndexDefinition{
+	{Table: "alerts", Name: "idx_alerts_composite", Columns: []string{"status", "create_time"}},
+	{Table: "alerts", Name: "idx_last_modified_time", Columns: []string{"last_modified_time"}},
+	{Table: "alert_configurations", Name: "idx_config_composite", Columns: []string{"alert_id", "type"}},
+	{Table: "alert_schedules", Name: "idx_schedule_composite", Columns: []string{"alert_id", "type"}},
+	{Table: "alert_tags", Name: "idx_tags_composite", Columns: []string{"alert_id", "tag_type"}},
+}
+
+// ManagedIndexes 返回迁移层维护的索引定义
+func ManagedIndexes() []IndexDefinition {
+	return managedIndexes
+}
+
+// EnsureIndexes 创建缺失的索引，已存在的索引保持不变
+func EnsureIndexes() error {
+	if DB == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	migrator := DB.Migrator()
+	for _, idx := range managedIndexes {
+		if migrator.HasIndex(idx.Table, idx.Name) {
+			continue
+		}
+
+		columns := make([]interface{}, len(idx.Columns))
+		for i, column := range idx.Columns {
+			columns[i] = clause.Column{Name: column}
+		}
+
+		sql := "CREATE INDEX ? ON ? ?"
+		if idx.Unique {
+			sql = "CREATE UNIQUE INDEX ? ON ? ?"
+		}
+
+		if err := DB.Exec(sql, clause.Column{Name: idx.Name}, clause.Table{Name: idx.Table}, columns).Error; err != nil {
+			return fmt.Errorf("failed to create index %s on %s: %w", idx.Name, idx.Table, err)
+		}
+		log.Printf("Created index %s on %s", idx.Name, idx.Table)
+	}
+
+	return nil
+}
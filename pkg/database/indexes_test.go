@@ -0,0 +1,89 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// initTestDB 为当前测试初始化独立的内存 SQLite 数据库并完成迁移
+func initTestDB(t *testing.T) {
+	t.Helper()
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	dsn := fmt.Sprintf("file:database_%s?mode=memory&cache=shared", name)
+	if err := InitDatabase(&config.DatabaseConfig{Driver: DriverSQLite, Database: dsn}); err != nil {
+		t.Fatalf("InitDatabase: %v", err)
+	}
+	DB.Logger = gormlogger.Default.LogMode(gormlogger.Silent)
+	t.Cleanup(func() {
+		CloseDatabase()
+	})
+	if err := AutoMigrate(); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+}
+
+// indexColumns 返回 SQLite 索引包含的列
+func indexColumns(t *testing.T, index string) []string {
+	t.Helper()
+
+	var columns []string
+	if err := DB.Raw("SELECT name FROM pragma_index_info(?) ORDER BY seqno", index).Scan(&columns).Error; err != nil {
+		t.Fatalf("index_info(%s): %v", index, err)
+	}
+	return columns
+}
+
+func TestAutoMigrateCreatesManagedIndexes(t *testing.T) {
+	initTestDB(t)
+
+	for _, idx := range ManagedIndexes() {
+		t.Run(idx.Name, func(t *testing.T) {
+			if !DB.Migrator().HasIndex(idx.Table, idx.Name) {
+				t.Fatalf("index %s missing on %s", idx.Name, idx.Table)
+			}
+			if got := indexColumns(t, idx.Name); !reflect.DeepEqual(got, idx.Columns) {
+				t.Errorf("index %s columns = %v, want %v", idx.Name, got, idx.Columns)
+			}
+		})
+	}
+}
+
+func TestEnsureIndexesRecreatesMissingIndex(t *testing.T) {
+	initTestDB(t)
+
+	idx := ManagedIndexes()[0]
+	if err := DB.Migrator().DropIndex(idx.Table, idx.Name); err != nil {
+		t.Fatalf("DropIndex: %v", err)
+	}
+	if err := EnsureIndexes(); err != nil {
+		t.Fatalf("EnsureIndexes: %v", err)
+	}
+	if !DB.Migrator().HasIndex(idx.Table, idx.Name) {
+		t.Errorf("index %s was not recreated", idx.Name)
+	}
+
+	// 再次执行时已存在的索引保持不变
+	if err := EnsureIndexes(); err != nil {
+		t.Errorf("EnsureIndexes on an up-to-date schema: %v", err)
+	}
+}
+
+func TestAutoMigrateKeepsTagDeclaredUniqueIndexes(t *testing.T) {
+	initTestDB(t)
+
+	// 单列唯一约束仍由模型标签声明
+	for _, idx := range []struct{ table, name string }{
+		{table: "alerts", name: "idx_alerts_name"},
+		{table: "alert_count_snapshots", name: "idx_alert_count_snapshots_day"},
+	} {
+		if !DB.Migrator().HasIndex(idx.table, idx.name) {
+			t.Errorf("unique index %s missing on %s", idx.name, idx.table)
+		}
+	}
+}
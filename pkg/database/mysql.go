@@ -1,32 +1,48 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/Ghostbaby/sls-migrate/internal/config"
 	"github.com/Ghostbaby/sls-migrate/internal/models"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
 var DB *gorm.DB
 
+// migrationModels 由 AutoMigrate 迁移、ValidateSchema 校验的所有模型
+var migrationModels = []interface{}{
+	&models.Alert{},
+	&models.AlertConfiguration{},
+	&models.AlertSchedule{},
+	&models.AlertTag{},
+	&models.AlertQuery{},
+	&models.ConditionConfiguration{},
+	&models.GroupConfiguration{},
+	&models.PolicyConfiguration{},
+	&models.TemplateConfiguration{},
+	&models.SeverityConfiguration{},
+	&models.JoinConfiguration{},
+	&models.SinkAlerthubConfiguration{},
+	&models.SinkCmsConfiguration{},
+	&models.SinkEventStoreConfiguration{},
+	&models.AlertCountSnapshot{},
+	&models.AlertAuditLog{},
+	&models.SyncRun{},
+}
+
 // InitDatabase 初始化数据库连接
 func InitDatabase(cfg *config.DatabaseConfig) error {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local",
-		cfg.Username,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.Database,
-		cfg.Charset,
-	)
-
-	var err error
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
+	dialector, err := newDialector(cfg)
+	if err != nil {
+		return err
+	}
+
+	DB, err = gorm.Open(dialector, &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
 	if err != nil {
@@ -42,8 +58,20 @@ func InitDatabase(cfg *config.DatabaseConfig) error {
 	// 设置连接池参数
 	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
 	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	if cfg.Driver == DriverSQLite {
+		// SQLite 只允许单个写入者，内存数据库的每个连接也是独立的库，
+		// 使用单连接避免 database is locked，并保证 PRAGMA 对后续语句生效
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetMaxOpenConns(1)
+	}
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
+	// 设置事务死锁重试参数
+	SetRetryConfig(RetryConfig{
+		MaxRetries: cfg.DeadlockMaxRetries,
+		Backoff:    time.Duration(cfg.DeadlockRetryBackoffMS) * time.Millisecond,
+	})
+
 	// 测试连接
 	if err := sqlDB.Ping(); err != nil {
 		return fmt.Errorf("failed to ping database: %w", err)
@@ -60,38 +88,43 @@ func AutoMigrate() error {
 	}
 
 	// 禁用外键约束检查
-	DB.Exec("SET FOREIGN_KEY_CHECKS = 0")
+	setForeignKeyChecks(false)
 
 	// 自动迁移所有模型
-	err := DB.AutoMigrate(
-		&models.Alert{},
-		&models.AlertConfiguration{},
-		&models.AlertSchedule{},
-		&models.AlertTag{},
-		&models.AlertQuery{},
-		&models.ConditionConfiguration{},
-		&models.GroupConfiguration{},
-		&models.PolicyConfiguration{},
-		&models.TemplateConfiguration{},
-		&models.SeverityConfiguration{},
-		&models.JoinConfiguration{},
-		&models.SinkAlerthubConfiguration{},
-		&models.SinkCmsConfiguration{},
-		&models.SinkEventStoreConfiguration{},
-	)
+	err := DB.AutoMigrate(migrationModels...)
+
+	// 重新启用外键约束检查
+	setForeignKeyChecks(true)
 	if err != nil {
-		// 重新启用外键约束检查
-		DB.Exec("SET FOREIGN_KEY_CHECKS = 1")
 		return fmt.Errorf("failed to auto migrate: %w", err)
 	}
 
-	// 重新启用外键约束检查
-	DB.Exec("SET FOREIGN_KEY_CHECKS = 1")
+	// 创建迁移层统一维护的索引
+	if err := EnsureIndexes(); err != nil {
+		return fmt.Errorf("failed to ensure indexes: %w", err)
+	}
 
 	log.Println("Database tables migrated successfully")
 	return nil
 }
 
+// Ping 检查数据库连接是否可用
+func Ping(ctx context.Context) error {
+	if DB == nil {
+		return fmt.Errorf("database is not initialized")
+	}
+
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get sql.DB: %w", err)
+	}
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	return nil
+}
+
 // CloseDatabase 关闭数据库连接
 func CloseDatabase() error {
 	if DB == nil {
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+)
+
+// MySQL 中可以通过重试整个事务解决的错误码
+const (
+	mysqlErrLockDeadlock    = 1213 // ER_LOCK_DEADLOCK，也用于序列化失败（SQLSTATE 40001）
+	mysqlErrLockWaitTimeout = 1205 // ER_LOCK_WAIT_TIMEOUT
+)
+
+// PostgreSQL 中可以通过重试整个事务解决的 SQLSTATE
+const (
+	pgErrDeadlockDetected     = "40P01" // deadlock_detected
+	pgErrSerializationFailure = "40001" // serialization_failure
+	pgErrLockNotAvailable     = "55P03" // lock_not_available
+)
+
+// RetryConfig 事务死锁重试配置
+type RetryConfig struct {
+	MaxRetries int
+	Backoff    time.Duration
+}
+
+// retryConfig 当前生效的重试配置，由 InitDatabase 根据配置设置
+var retryConfig = RetryConfig{
+	MaxRetries: 3,
+	Backoff:    50 * time.Millisecond,
+}
+
+// SetRetryConfig 设置事务死锁重试配置
+func SetRetryConfig(cfg RetryConfig) {
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.Backoff < 0 {
+		cfg.Backoff = 0
+	}
+	retryConfig = cfg
+}
+
+// IsRetryableError 判断错误是否为可重试的死锁或锁等待超时错误
+func IsRetryableError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrLockDeadlock || mysqlErr.Number == mysqlErrLockWaitTimeout
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgErrDeadlockDetected, pgErrSerializationFailure, pgErrLockNotAvailable:
+			return true
+		}
+	}
+
+	// SQLite 在其他连接持有写锁时返回 SQLITE_BUSY / SQLITE_LOCKED
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+	return false
+}
+
+// WithRetry 执行 fn，遇到死锁类错误时按线性退避重试，fn 必须可以安全地整体重新执行
+func WithRetry(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil || !IsRetryableError(err) || attempt >= retryConfig.MaxRetries {
+			return err
+		}
+
+		backoff := retryConfig.Backoff * time.Duration(attempt+1)
+		log.Printf("Retrying transaction after retryable database error (attempt %d/%d, backoff %s): %v",
+			attempt+1, retryConfig.MaxRetries, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+	}
+}
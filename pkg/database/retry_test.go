@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+)
+
+// useRetryConfig 在测试期间使用 cfg，结束后恢复原配置
+func useRetryConfig(t *testing.T, cfg RetryConfig) {
+	t.Helper()
+
+	previous := retryConfig
+	SetRetryConfig(cfg)
+	t.Cleanup(func() { retryConfig = previous })
+}
+
+var (
+	mysqlDeadlock    = &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+	mysqlLockTimeout = &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}
+	mysqlDuplicate   = &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}
+	pgDeadlock       = &pgconn.PgError{Code: "40P01", Message: "deadlock detected"}
+	pgSerialization  = &pgconn.PgError{Code: "40001", Message: "could not serialize access"}
+	pgLockNotAvail   = &pgconn.PgError{Code: "55P03", Message: "could not obtain lock"}
+	pgUnique         = &pgconn.PgError{Code: "23505", Message: "duplicate key value"}
+)
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "mysql deadlock", err: mysqlDeadlock, want: true},
+		{name: "mysql lock wait timeout", err: mysqlLockTimeout, want: true},
+		{name: "wrapped mysql deadlock", err: fmt.Errorf("failed to update alert: %w", mysqlDeadlock), want: true},
+		{name: "mysql duplicate key", err: mysqlDuplicate, want: false},
+		{name: "postgres deadlock", err: pgDeadlock, want: true},
+		{name: "postgres serialization failure", err: pgSerialization, want: true},
+		{name: "postgres lock not available", err: pgLockNotAvail, want: true},
+		{name: "postgres unique violation", err: pgUnique, want: false},
+		{name: "sqlite busy", err: sqlite3.Error{Code: sqlite3.ErrBusy}, want: true},
+		{name: "sqlite locked", err: sqlite3.Error{Code: sqlite3.ErrLocked}, want: true},
+		{name: "sqlite constraint", err: sqlite3.Error{Code: sqlite3.ErrConstraint}, want: false},
+		{name: "plain error", err: errors.New("boom"), want: false},
+		{name: "nil", err: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryableError(tt.err); got != tt.want {
+				t.Errorf("IsRetryableError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	boom := errors.New("boom")
+
+	tests := []struct {
+		name         string
+		maxRetries   int
+		errs         []error // 依次返回的错误，用完后返回 nil
+		wantErr      error
+		wantAttempts int
+	}{
+		{name: "succeeds first time", maxRetries: 3, wantAttempts: 1},
+		{name: "mysql deadlock once then succeeds", maxRetries: 3, errs: []error{mysqlDeadlock}, wantAttempts: 2},
+		{name: "postgres deadlock once then succeeds", maxRetries: 3, errs: []error{pgDeadlock}, wantAttempts: 2},
+		{name: "mixed retryable errors then succeeds", maxRetries: 3, errs: []error{mysqlLockTimeout, pgSerialization, mysqlDeadlock}, wantAttempts: 4},
+		{name: "retries exhausted", maxRetries: 2, errs: []error{mysqlDeadlock, mysqlDeadlock, mysqlDeadlock, mysqlDeadlock}, wantErr: mysqlDeadlock, wantAttempts: 3},
+		{name: "retries disabled", maxRetries: 0, errs: []error{pgDeadlock}, wantErr: pgDeadlock, wantAttempts: 1},
+		{name: "non retryable error propagates immediately", maxRetries: 3, errs: []error{boom}, wantErr: boom, wantAttempts: 1},
+		{name: "duplicate key is not retried", maxRetries: 3, errs: []error{mysqlDuplicate}, wantErr: mysqlDuplicate, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useRetryConfig(t, RetryConfig{MaxRetries: tt.maxRetries})
+
+			attempts := 0
+			err := WithRetry(context.Background(), func() error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestWithRetryStopsWhenContextDone(t *testing.T) {
+	useRetryConfig(t, RetryConfig{MaxRetries: 5, Backoff: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err := WithRetry(ctx, func() error {
+		attempts++
+		cancel()
+		return mysqlDeadlock
+	})
+	if !errors.Is(err, mysqlDeadlock) || attempts != 1 {
+		t.Errorf("error = %v after %d attempts, want the deadlock after 1 attempt", err, attempts)
+	}
+}
+
+func TestSetRetryConfigClampsNegativeValues(t *testing.T) {
+	useRetryConfig(t, RetryConfig{MaxRetries: -1, Backoff: -time.Second})
+	if retryConfig.MaxRetries != 0 || retryConfig.Backoff != 0 {
+		t.Errorf("retry config = %+v, want zero values", retryConfig)
+	}
+}
+
+func TestInitDatabaseAppliesRetryConfig(t *testing.T) {
+	useRetryConfig(t, retryConfig)
+
+	dsn := fmt.Sprintf("file:database_%s?mode=memory&cache=shared", t.Name())
+	err := InitDatabase(&config.DatabaseConfig{
+		Driver:                 DriverSQLite,
+		Database:               dsn,
+		DeadlockMaxRetries:     5,
+		DeadlockRetryBackoffMS: 20,
+	})
+	if err != nil {
+		t.Fatalf("InitDatabase: %v", err)
+	}
+	t.Cleanup(func() {
+		CloseDatabase()
+	})
+
+	want := RetryConfig{MaxRetries: 5, Backoff: 20 * time.Millisecond}
+	if retryConfig != want {
+		t.Errorf("retry config = %+v, want %+v", retryConfig, want)
+	}
+}
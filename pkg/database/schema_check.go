@@ -0,0 +1,108 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// 启动时 Schema 校验模式
+const (
+	SchemaCheckOff  = "off"  // 不校验
+	SchemaCheckWarn = "warn" // 发现差异时只记录日志
+	SchemaCheckFail = "fail" // 发现差异时启动失败
+)
+
+// SchemaDrift 模型与数据库实际结构之间的差异
+type SchemaDrift struct {
+	Table string `json:"table"`
+	Kind  string `json:"kind"` // table、column 或 index
+	Name  string `json:"name"`
+}
+
+// String 返回差异的可读描述
+func (d SchemaDrift) String() string {
+	if d.Kind == "table" {
+		return fmt.Sprintf("missing table %s", d.Table)
+	}
+	return fmt.Sprintf("missing %s %s.%s", d.Kind, d.Table, d.Name)
+}
+
+// ValidateSchema 使用 GORM Migrator 将每个模型与数据库中的表比较，返回缺失的表、列和索引
+func ValidateSchema() ([]SchemaDrift, error) {
+	if DB == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	migrator := DB.Migrator()
+	var drifts []SchemaDrift
+
+	for _, model := range migrationModels {
+		stmt := &gorm.Statement{DB: DB}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+		table := stmt.Schema.Table
+
+		if !migrator.HasTable(model) {
+			drifts = append(drifts, SchemaDrift{Table: table, Kind: "table"})
+			continue
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			if !migrator.HasColumn(model, field.DBName) {
+				drifts = append(drifts, SchemaDrift{Table: table, Kind: "column", Name: field.DBName})
+			}
+		}
+
+		indexes := stmt.Schema.ParseIndexes()
+		names := make([]string, 0, len(indexes))
+		for name := range indexes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if !migrator.HasIndex(model, name) {
+				drifts = append(drifts, SchemaDrift{Table: table, Kind: "index", Name: name})
+			}
+		}
+	}
+
+	for _, idx := range managedIndexes {
+		if migrator.HasTable(idx.Table) && !migrator.HasIndex(idx.Table, idx.Name) {
+			drifts = append(drifts, SchemaDrift{Table: idx.Table, Kind: "index", Name: idx.Name})
+		}
+	}
+
+	return drifts, nil
+}
+
+// CheckSchema 按模式校验 Schema：warn 只记录差异，fail 在有差异时返回错误
+func CheckSchema(mode string) error {
+	if mode == SchemaCheckOff {
+		return nil
+	}
+
+	drifts, err := ValidateSchema()
+	if err != nil {
+		return err
+	}
+	if len(drifts) == 0 {
+		log.Println("Database schema matches models")
+		return nil
+	}
+
+	for _, drift := range drifts {
+		log.Printf("Schema drift: %s", drift)
+	}
+
+	if mode == SchemaCheckFail {
+		return fmt.Errorf("database schema drift detected: %d differences", len(drifts))
+	}
+	return nil
+}
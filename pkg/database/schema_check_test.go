@@ -0,0 +1,95 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Ghostbaby/sls-migrate/internal/models"
+)
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(t *testing.T)
+		want   []SchemaDrift
+	}{
+		{
+			name:   "migrated schema matches",
+			mutate: func(t *testing.T) {},
+		},
+		{
+			name: "missing column",
+			mutate: func(t *testing.T) {
+				if err := DB.Migrator().DropColumn(&models.AlertQuery{}, "dashboard_id"); err != nil {
+					t.Fatalf("DropColumn: %v", err)
+				}
+			},
+			want: []SchemaDrift{{Table: "alert_queries", Kind: "column", Name: "dashboard_id"}},
+		},
+		{
+			name: "missing managed index",
+			mutate: func(t *testing.T) {
+				if err := DB.Migrator().DropIndex("alert_tags", "idx_tags_composite"); err != nil {
+					t.Fatalf("DropIndex: %v", err)
+				}
+			},
+			want: []SchemaDrift{{Table: "alert_tags", Kind: "index", Name: "idx_tags_composite"}},
+		},
+		{
+			name: "missing table",
+			mutate: func(t *testing.T) {
+				if err := DB.Migrator().DropTable(&models.SyncRun{}); err != nil {
+					t.Fatalf("DropTable: %v", err)
+				}
+			},
+			want: []SchemaDrift{{Table: "sync_runs", Kind: "table"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initTestDB(t)
+			tt.mutate(t)
+
+			drifts, err := ValidateSchema()
+			if err != nil {
+				t.Fatalf("ValidateSchema: %v", err)
+			}
+			if !reflect.DeepEqual(drifts, tt.want) {
+				t.Errorf("drifts = %v, want %v", drifts, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSchemaModes(t *testing.T) {
+	tests := []struct {
+		mode    string
+		wantErr bool
+	}{
+		{mode: SchemaCheckOff},
+		{mode: SchemaCheckWarn},
+		{mode: SchemaCheckFail, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			initTestDB(t)
+			if err := DB.Migrator().DropIndex("alert_tags", "idx_tags_composite"); err != nil {
+				t.Fatalf("DropIndex: %v", err)
+			}
+
+			err := CheckSchema(tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckSchema(%s) error = %v, want error %v", tt.mode, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckSchemaFailPassesWithoutDrift(t *testing.T) {
+	initTestDB(t)
+	if err := CheckSchema(SchemaCheckFail); err != nil {
+		t.Errorf("CheckSchema(fail) on a migrated schema: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"log/slog"
+)
+
+// requestIDKey 请求 ID 在 context 中的键
+type requestIDKey struct{}
+
+// WithRequestID 返回携带请求 ID 的 context
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID 返回 context 中的请求 ID，没有时返回空字符串
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID 生成 32 位十六进制的随机请求 ID
+func NewRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// contextHandler 为每条日志追加 context 中的 request_id
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle 实现 slog.Handler 接口
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs 实现 slog.Handler 接口
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup 实现 slog.Handler 接口
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// Printf 与 log.Printf 相同，ctx 中带有请求 ID 时同时输出 request_id（JSON 格式下为独立字段），
+// 用于串联同一请求或同一次定时同步的数据库和 SLS 操作日志
+func Printf(ctx context.Context, format string, v ...any) {
+	msg := fmt.Sprintf(format, v...)
+	id := RequestID(ctx)
+	switch {
+	case id == "":
+		log.Output(2, msg)
+	case jsonFormat:
+		slog.InfoContext(ctx, msg)
+	default:
+		log.Output(2, msg+" request_id="+id)
+	}
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/Ghostbaby/sls-migrate/internal/config"
+)
+
+// FormatJSON JSON 行格式
+const FormatJSON = "json"
+
+// jsonFormat 是否以 JSON 行输出日志，由 InitLogger 设置
+var jsonFormat bool
+
+// InitLogger 根据配置初始化应用日志，返回注入各组件使用的 slog 日志器；
+// 使用带 context 的方法（如 InfoContext）记录的日志会追加 context 中的 request_id
+func InitLogger(cfg *config.LogConfig) *slog.Logger {
+	level := ParseLevel(cfg.Level)
+
+	if cfg.Format != FormatJSON {
+		// 文本格式保持标准库 log 的默认输出，只设置 slog 默认 handler 的最低级别
+		slog.SetLogLoggerLevel(level)
+		return slog.New(contextHandler{slog.Default().Handler()})
+	}
+
+	// 设置 slog 默认 handler 后，标准库 log 的输出也会经由该 handler 以 JSON 行输出
+	jsonFormat = true
+	slog.SetDefault(slog.New(contextHandler{slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})}))
+	log.SetFlags(0)
+	return slog.Default()
+}
+
+// ParseLevel 解析日志级别（debug、info、warn、error），未知值回退为 info
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		level string
+		want  slog.Level
+	}{
+		{level: "debug", want: slog.LevelDebug},
+		{level: " DEBUG ", want: slog.LevelDebug},
+		{level: "info", want: slog.LevelInfo},
+		{level: "warn", want: slog.LevelWarn},
+		{level: "warning", want: slog.LevelWarn},
+		{level: "error", want: slog.LevelError},
+		{level: "", want: slog.LevelInfo},
+		{level: "verbose", want: slog.LevelInfo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			if got := ParseLevel(tt.level); got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.level, got, tt.want)
+			}
+		})
+	}
+}
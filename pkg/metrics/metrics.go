@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace 所有指标名称的前缀
+const namespace = "sls_migrate"
+
+// 告警数量的来源
+const (
+	SourceDatabase = "db"
+	SourceSLS      = "sls"
+)
+
+var (
+	// syncAlerts 同步处理的 Alert 数量，按方向和结果（created、updated、skipped、failed）区分
+	syncAlerts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sync_alerts_total",
+		Help:      "Alerts processed by sync, by direction and action.",
+	}, []string{"direction", "action"})
+
+	// syncLastDuration 最近一次同步的耗时
+	syncLastDuration = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sync_last_duration_seconds",
+		Help:      "Duration of the last sync run, by direction.",
+	}, []string{"direction"})
+
+	// alertCount 最近一次观察到的数据库和 SLS 中的 Alert 数量
+	alertCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "alerts",
+		Help:      "Number of alerts last observed in the database or SLS.",
+	}, []string{"source"})
+
+	// slsRequestDuration 单次 SLS API 调用的耗时（重试的每次尝试分别记录）
+	slsRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sls_request_duration_seconds",
+		Help:      "Latency of SLS API calls, by operation and outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "outcome"})
+)
+
+// Handler 返回以 Prometheus 文本格式输出全部指标的 HTTP 处理器
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveSync 记录一次同步的结果计数和耗时
+func ObserveSync(direction string, created, updated, skipped, failed int, duration time.Duration) {
+	syncAlerts.WithLabelValues(direction, "created").Add(float64(created))
+	syncAlerts.WithLabelValues(direction, "updated").Add(float64(updated))
+	syncAlerts.WithLabelValues(direction, "skipped").Add(float64(skipped))
+	syncAlerts.WithLabelValues(direction, "failed").Add(float64(failed))
+	syncLastDuration.WithLabelValues(direction).Set(duration.Seconds())
+}
+
+// SetAlertCount 记录观察到的 Alert 数量，source 为 SourceDatabase 或 SourceSLS
+func SetAlertCount(source string, count int) {
+	alertCount.WithLabelValues(source).Set(float64(count))
+}
+
+// ObserveSLSRequest 记录一次 SLS API 调用的耗时，err 非 nil 时结果为 error
+func ObserveSLSRequest(operation string, duration time.Duration, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	slsRequestDuration.WithLabelValues(operation, outcome).Observe(duration.Seconds())
+}
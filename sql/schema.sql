@@ -19,10 +19,17 @@ CREATE TABLE IF NOT EXISTS alerts (
     schedule_id BIGINT UNSIGNED COMMENT '调度ID，关联alert_schedules表',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '记录创建时间',
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '记录更新时间',
+    deleted_at TIMESTAMP NULL COMMENT '软删除时间，非空表示已删除，可通过 restore 接口恢复',
+    last_synced_at TIMESTAMP NULL COMMENT '最后同步时间',
+    last_sync_direction VARCHAR(20) COMMENT '最后同步方向: sls_to_db/db_to_sls',
+    content_hash VARCHAR(64) COMMENT '最近一次从 SLS 同步时 SLS 侧内容的 SHA-256',
+    freeze_until BIGINT COMMENT '冻结截止时间（Unix 秒），冻结期内不更新、删除或推送',
     UNIQUE KEY uk_name (name),
+    INDEX idx_last_synced_at (last_synced_at),
     INDEX idx_status (status),
     INDEX idx_create_time (create_time),
-    INDEX idx_last_modified_time (last_modified_time)
+    INDEX idx_last_modified_time (last_modified_time),
+    INDEX idx_alerts_deleted_at (deleted_at)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Alert主表';
 
 -- 2. 配置表: alert_configurations
@@ -38,6 +45,7 @@ CREATE TABLE IF NOT EXISTS alert_configurations (
     `type` VARCHAR(100) COMMENT '类型',
     version VARCHAR(50) COMMENT '版本',
     send_resolved BOOLEAN DEFAULT FALSE COMMENT '是否发送已解决的通知',
+    raw_config JSON COMMENT '导入时 SLS 返回的完整配置，推送时作为基线保留未建模字段',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '记录创建时间',
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '记录更新时间',
     FOREIGN KEY (alert_id) REFERENCES alerts(id) ON DELETE CASCADE,
@@ -68,11 +76,12 @@ CREATE TABLE IF NOT EXISTS alert_schedules (
 CREATE TABLE IF NOT EXISTS alert_tags (
     id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键ID',
     alert_id BIGINT UNSIGNED NOT NULL COMMENT '关联的Alert ID',
-    `tag_type` ENUM('annotation', 'label') NOT NULL COMMENT '标签类型: annotation/label',
+    `tag_type` VARCHAR(20) NOT NULL COMMENT '标签类型: annotation/label',
     `tag_key` VARCHAR(255) NOT NULL COMMENT '标签键',
     tag_value TEXT COMMENT '标签值',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '记录创建时间',
     FOREIGN KEY (alert_id) REFERENCES alerts(id) ON DELETE CASCADE,
+    CONSTRAINT chk_alert_tags_tag_type CHECK (`tag_type` IN ('annotation', 'label')),
     UNIQUE KEY uk_alert_tag (alert_id, `tag_type`, `tag_key`),
     INDEX idx_alert_id (alert_id),
     INDEX idx_tag_type (`tag_type`),
@@ -119,7 +128,7 @@ CREATE TABLE IF NOT EXISTS condition_configurations (
 CREATE TABLE IF NOT EXISTS group_configurations (
     id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键ID',
     alert_config_id BIGINT UNSIGNED NOT NULL COMMENT '关联的Alert配置ID',
-    fields TEXT COMMENT '分组字段，逗号分隔',
+    fields TEXT COMMENT '分组字段，JSON 字符串数组',
     `type` VARCHAR(100) COMMENT '分组类型',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '记录创建时间',
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '记录更新时间',
@@ -217,6 +226,45 @@ CREATE TABLE IF NOT EXISTS join_configurations (
     INDEX idx_join_type (join_type)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Join配置表';
 
+-- 每日 Alert 数量快照表: alert_count_snapshots
+CREATE TABLE IF NOT EXISTS alert_count_snapshots (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键ID',
+    day VARCHAR(10) NOT NULL COMMENT '快照日期（UTC），格式 YYYY-MM-DD',
+    total BIGINT NOT NULL DEFAULT 0 COMMENT 'Alert 总数',
+    enabled BIGINT NOT NULL DEFAULT 0 COMMENT 'ENABLED 状态的 Alert 数量',
+    disabled BIGINT NOT NULL DEFAULT 0 COMMENT 'DISABLED 状态的 Alert 数量',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '记录创建时间',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '记录更新时间',
+    UNIQUE INDEX idx_alert_count_snapshots_day (day)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='每日Alert数量快照表';
+
+-- Alert 变更审计表: alert_audit_logs（不设外键，Alert 永久删除后仍保留记录）
+CREATE TABLE IF NOT EXISTS alert_audit_logs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键ID',
+    alert_id BIGINT UNSIGNED NOT NULL COMMENT 'Alert ID',
+    action VARCHAR(20) NOT NULL COMMENT '操作: create/update/soft_delete/restore/delete/rollback',
+    actor VARCHAR(255) NOT NULL COMMENT '操作人，来自请求头 X-Actor',
+    before_json JSON COMMENT '变更前的 Alert 快照',
+    after_json JSON COMMENT '变更后的 Alert 快照',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '记录创建时间',
+    INDEX idx_alert_audit_logs_alert_id (alert_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Alert变更审计表';
+
+-- 同步执行记录表: sync_runs
+CREATE TABLE IF NOT EXISTS sync_runs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主键ID',
+    direction VARCHAR(20) NOT NULL COMMENT '同步方向: sls_to_db/db_to_sls',
+    started_at TIMESTAMP NOT NULL COMMENT '开始时间',
+    finished_at TIMESTAMP NOT NULL COMMENT '结束时间',
+    created INT NOT NULL DEFAULT 0 COMMENT '创建的 Alert 数量',
+    updated INT NOT NULL DEFAULT 0 COMMENT '更新的 Alert 数量',
+    skipped INT NOT NULL DEFAULT 0 COMMENT '跳过的 Alert 数量',
+    failed INT NOT NULL DEFAULT 0 COMMENT '失败的 Alert 数量',
+    status VARCHAR(20) NOT NULL COMMENT '结果: success/failed/timed_out',
+    error TEXT COMMENT '错误信息',
+    INDEX idx_sync_runs_started_at (started_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='同步执行记录表';
+
 -- 注意：现在这些配置表都有自己的 alert_config_id 字段，不再需要 alert_configurations 表中的反向引用
 -- 原来的外键约束已被移除，改为在配置表中直接引用 alert_configurations.id
 